@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/buni/wallet/internal/api/app/contract"
+	"github.com/buni/wallet/internal/api/app/entity"
 	"github.com/buni/wallet/internal/api/wallet"
 	"github.com/buni/wallet/internal/pkg/configuration"
 	"github.com/buni/wallet/internal/pkg/database/pgxtx"
-	"github.com/buni/wallet/internal/pkg/pubsub/jetstream"
+	"github.com/buni/wallet/internal/pkg/idempotency"
 	"github.com/buni/wallet/internal/pkg/pubsub/outbox"
 	"github.com/buni/wallet/internal/pkg/render/errorhandler"
 	"github.com/buni/wallet/internal/pkg/server"
@@ -41,6 +43,24 @@ func main() error {
 	errorhandler.RegisterErrorHandler("unique_constraint_error_handler", errorhandler.ConflictErrorHandler)
 	errorhandler.RegisterErrorHandler("insufficient_balance_error_handler", errorhandler.InsufficientBalanceErrorHandler)
 	errorhandler.RegisterErrorHandler("negative_amount_error_handler", errorhandler.NegativeAmountErrorHandler)
+	errorhandler.RegisterErrorHandler("same_wallet_transfer_error_handler", errorhandler.SameWalletTransferErrorHandler)
+	errorhandler.RegisterErrorHandler("stream_version_conflict_error_handler", errorhandler.StreamVersionConflictErrorHandler)
+	errorhandler.RegisterErrorHandler("transfer_not_found_error_handler", errorhandler.TransferNotFoundErrorHandler)
+	errorhandler.RegisterErrorHandler("transfer_already_settled_error_handler", errorhandler.TransferAlreadySettledErrorHandler)
+	errorhandler.RegisterErrorHandler("currency_error_handler", errorhandler.CurrencyErrorHandler)
+	errorhandler.RegisterErrorHandler("transfer_expiry_error_handler", errorhandler.TransferExpiryErrorHandler)
+	errorhandler.RegisterErrorHandler("capture_amount_error_handler", errorhandler.CaptureAmountErrorHandler)
+	errorhandler.RegisterErrorHandler("refund_error_handler", errorhandler.RefundErrorHandler)
+	errorhandler.RegisterErrorHandler("fx_error_handler", errorhandler.FXErrorHandler)
+	errorhandler.RegisterErrorHandler("statement_error_handler", errorhandler.StatementErrorHandler)
+	errorhandler.RegisterErrorHandler("wallet_status_error_handler", errorhandler.WalletStatusErrorHandler)
+	errorhandler.RegisterErrorHandler("credit_limit_error_handler", errorhandler.CreditLimitErrorHandler)
+	errorhandler.RegisterErrorHandler("limit_exceeded_error_handler", errorhandler.LimitExceededErrorHandler)
+	errorhandler.RegisterErrorHandler("wallet_tier_error_handler", errorhandler.WalletTierErrorHandler)
+	errorhandler.RegisterErrorHandler("balance_as_of_error_handler", errorhandler.BalanceAsOfErrorHandler)
+	errorhandler.RegisterErrorHandler("batch_size_error_handler", errorhandler.BatchSizeErrorHandler)
+	errorhandler.RegisterErrorHandler("scheduled_transfer_error_handler", errorhandler.ScheduledTransferErrorHandler)
+	errorhandler.RegisterErrorHandler("recurring_transfer_error_handler", errorhandler.RecurringTransferErrorHandler)
 
 	srv, err := server.NewServer(context.Background())
 	if err != nil {
@@ -74,16 +94,74 @@ func main() error {
 	txm := pgxtx.NewTransactionManager(pgxPool, pgx.TxOptions{})
 
 	outboxRepo := outbox.NewPGxRepository(txWrapper)
-	publisher := outbox.NewPublisher[any](outboxRepo, txm, jetstream.JetStreamPublisherType)
 
 	walletRepo := wallet.NewRepository(txWrapper)
 	walletEventRepo := wallet.NewEventRepository(txWrapper)
 	walletProjectionRepo := wallet.NewProjectionRepository(txWrapper)
-	walletEventPublisher := wallet.NewPublisher(publisher)
-	walletSvc := wallet.NewService(walletRepo, walletProjectionRepo, walletEventRepo, walletEventPublisher, txm)
+	walletSnapshotRepo := wallet.NewSnapshotRepository(txWrapper)
+	walletTransferRepo := wallet.NewTransferRepository(txWrapper)
+	internalTransferRepo := wallet.NewInternalTransferRepository(txWrapper)
+	ledgerRepo := wallet.NewLedgerRepository(txWrapper)
+	scheduledTransferRepo := wallet.NewScheduledTransferRepository(txWrapper)
+	recurringTransferRepo := wallet.NewRecurringTransferRepository(txWrapper)
+	refundRepo := wallet.NewRefundRepository(txWrapper)
+	fxQuoteRepo := wallet.NewFXQuoteRepository(txWrapper)
+	walletEventPublisher := wallet.NewOutboxPublisher(outboxRepo, txm)
+	velocityLimits, err := entity.ParseVelocityLimits(config.Wallet.VelocityLimits)
+	if err != nil {
+		return fmt.Errorf("failed to parse wallet velocity limits: %w", err)
+	}
+
+	feeSchedules, err := entity.ParseFeeSchedules(config.Wallet.FeeSchedules)
+	if err != nil {
+		return fmt.Errorf("failed to parse wallet fee schedules: %w", err)
+	}
+
+	feeWallets, err := entity.ParseFeeWallets(config.Wallet.FeeWallets)
+	if err != nil {
+		return fmt.Errorf("failed to parse wallet fee wallets: %w", err)
+	}
+
+	var fxRates contract.FXRateProvider
+	if config.Wallet.FXRatesFile != "" {
+		fxRates = wallet.NewFileRateProvider(config.Wallet.FXRatesFile)
+	} else {
+		rates, err := entity.ParseFXRates(config.Wallet.FXRates)
+		if err != nil {
+			return fmt.Errorf("failed to parse wallet fx rates: %w", err)
+		}
+		fxRates = wallet.NewStaticRateProvider(rates)
+	}
+
+	walletSvc, err := wallet.NewService(
+		walletRepo,
+		walletProjectionRepo,
+		walletEventRepo,
+		walletSnapshotRepo,
+		walletTransferRepo,
+		internalTransferRepo,
+		ledgerRepo,
+		refundRepo,
+		walletEventPublisher,
+		txm,
+		wallet.WithScheduledTransfers(scheduledTransferRepo),
+		wallet.WithRecurringTransfers(recurringTransferRepo),
+		wallet.WithFXQuotes(fxQuoteRepo),
+		wallet.WithSnapshotInterval(config.Wallet.SnapshotInterval),
+		wallet.WithVelocityLimits(velocityLimits),
+		wallet.WithMaxBatchSize(config.Wallet.MaxBatchSize),
+		wallet.WithFees(feeWallets, feeSchedules),
+		wallet.WithFX(fxRates, config.Wallet.FXQuoteTTL),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create wallet service: %w", err)
+	}
+
 	walletHandler := wallet.NewHandler(walletSvc)
+	idempotencyRepo := idempotency.NewPostgresRepository(txWrapper)
 
 	srv.Router.Route("/v1", func(r chi.Router) {
+		r.Use(idempotency.Middleware(idempotencyRepo))
 		walletHandler.RegisterRoutes(r)
 		r.Get("/healthz", func(http.ResponseWriter, *http.Request) {})
 	})
@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/buni/wallet/cmd/api"
+	"github.com/buni/wallet/cmd/reconcile"
 	"github.com/buni/wallet/cmd/worker"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -16,6 +17,7 @@ func main() {
 
 	root.AddCommand(api.NewCommand())
 	root.AddCommand(worker.NewCommand())
+	root.AddCommand(reconcile.NewCommand())
 
 	if err := root.Execute(); err != nil {
 		zap.L().Sugar().Fatalln("failed to execute command", err)
@@ -0,0 +1,120 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/app/response"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/buni/wallet/internal/pkg/configuration"
+	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/buni/wallet/internal/pkg/pubsub/outbox"
+	"github.com/buni/wallet/internal/pkg/render"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spf13/cobra"
+)
+
+// ErrProjectionMismatch is returned when mismatched projections were found and left as they are, so the command exits with a non-zero code.
+var ErrProjectionMismatch = errors.New("wallet projections don't match their events")
+
+func NewCommand() *cobra.Command {
+	var repair bool
+
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Check the wallet projections against their events",
+		Long: "Replays the events of every wallet and compares the result with the stored projection, " +
+			"every mismatch is printed as a JSON line and with --repair the projection is overwritten with the replayed state",
+	}
+	cmd.Flags().BoolVar(&repair, "repair", false, "overwrite the mismatched projections with the replayed state")
+	cmd.RunE = func(cmd *cobra.Command, _ []string) error {
+		return main(cmd.OutOrStdout(), repair)
+	}
+
+	return cmd
+}
+
+func main(out io.Writer, repair bool) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	config, err := configuration.NewConfiguration()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	pgxConf, err := pgxpool.ParseConfig(config.Database.ToURL())
+	if err != nil {
+		return fmt.Errorf("failed to parse pgx config: %w", err)
+	}
+
+	pgxPool, err := pgxpool.NewWithConfig(ctx, pgxConf)
+	if err != nil {
+		return fmt.Errorf("failed to create pg session: %w", err)
+	}
+	defer pgxPool.Close()
+
+	err = pgxPool.Ping(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to ping pg: %w", err)
+	}
+
+	txWrapper := pgxtx.NewTxWrapper(pgxPool, pgx.TxOptions{})
+
+	txm := pgxtx.NewTransactionManager(pgxPool, pgx.TxOptions{})
+
+	outboxRepo := outbox.NewPGxRepository(txWrapper)
+
+	walletSvc, err := wallet.NewService(
+		wallet.NewRepository(txWrapper),
+		wallet.NewProjectionRepository(txWrapper),
+		wallet.NewEventRepository(txWrapper),
+		wallet.NewSnapshotRepository(txWrapper),
+		wallet.NewTransferRepository(txWrapper),
+		wallet.NewInternalTransferRepository(txWrapper),
+		wallet.NewLedgerRepository(txWrapper),
+		wallet.NewRefundRepository(txWrapper),
+		wallet.NewOutboxPublisher(outboxRepo, txm), // nothing is published, the service just requires one
+		txm,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create wallet service: %w", err)
+	}
+
+	encoder := json.NewEncoder(out)
+
+	var reportErr error
+
+	checked, mismatched, err := wallet.ReconcileAll(ctx, walletSvc, config.Wallet.ReconcileBatchSize, config.Wallet.ReconcileGrace, repair, func(mismatch entity.ProjectionMismatch) {
+		mismatchResp, err := render.NewResponse[response.ProjectionMismatch](mismatch)
+		if err == nil {
+			err = encoder.Encode(mismatchResp)
+		}
+
+		if err != nil {
+			reportErr = errors.Join(reportErr, fmt.Errorf("failed to print mismatch of wallet %s: %w", mismatch.WalletID, err))
+		}
+	})
+
+	slog.InfoContext(ctx, "reconciled wallet projections", slog.Int("checked", checked), slog.Int("mismatched", mismatched), slog.Bool("repair", repair))
+
+	err = errors.Join(err, reportErr)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile wallet projections: %w", err)
+	}
+
+	if mismatched > 0 && !repair {
+		return ErrProjectionMismatch
+	}
+
+	return nil
+}
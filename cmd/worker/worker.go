@@ -10,6 +10,7 @@ import (
 	"github.com/buni/wallet/internal/api/wallet"
 	"github.com/buni/wallet/internal/pkg/configuration"
 	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/buni/wallet/internal/pkg/idempotency"
 	"github.com/buni/wallet/internal/pkg/pubsub/jetstream"
 	"github.com/buni/wallet/internal/pkg/pubsub/outbox"
 	"github.com/buni/wallet/internal/pkg/pubsub/router"
@@ -96,10 +97,107 @@ func main() error {
 	walletRepo := wallet.NewRepository(txWrapper)
 	walletEventRepo := wallet.NewEventRepository(txWrapper)
 	walletProjectionRepo := wallet.NewProjectionRepository(txWrapper)
-	walletEventPublisher := wallet.NewPublisher(publisher)
-	walletSvc := wallet.NewService(walletRepo, walletProjectionRepo, walletEventRepo, walletEventPublisher, txm)
+	walletSnapshotRepo := wallet.NewSnapshotRepository(txWrapper)
+	walletTransferRepo := wallet.NewTransferRepository(txWrapper)
+	internalTransferRepo := wallet.NewInternalTransferRepository(txWrapper)
+	ledgerRepo := wallet.NewLedgerRepository(txWrapper)
+	scheduledTransferRepo := wallet.NewScheduledTransferRepository(txWrapper)
+	recurringTransferRepo := wallet.NewRecurringTransferRepository(txWrapper)
+	refundRepo := wallet.NewRefundRepository(txWrapper)
+	outboxRepo := outbox.NewPGxRepository(txWrapper)
+	walletEventPublisher := wallet.NewOutboxPublisher(outboxRepo, txm) // the outbox worker below forwards the committed events
+	velocityLimits, err := entity.ParseVelocityLimits(config.Wallet.VelocityLimits)
+	if err != nil {
+		return fmt.Errorf("failed to parse wallet velocity limits: %w", err)
+	}
+
+	feeSchedules, err := entity.ParseFeeSchedules(config.Wallet.FeeSchedules)
+	if err != nil {
+		return fmt.Errorf("failed to parse wallet fee schedules: %w", err)
+	}
+
+	feeWallets, err := entity.ParseFeeWallets(config.Wallet.FeeWallets)
+	if err != nil {
+		return fmt.Errorf("failed to parse wallet fee wallets: %w", err)
+	}
+
+	walletSvc, err := wallet.NewService(
+		walletRepo,
+		walletProjectionRepo,
+		walletEventRepo,
+		walletSnapshotRepo,
+		walletTransferRepo,
+		internalTransferRepo,
+		ledgerRepo,
+		refundRepo,
+		walletEventPublisher,
+		txm,
+		wallet.WithScheduledTransfers(scheduledTransferRepo),
+		wallet.WithRecurringTransfers(recurringTransferRepo),
+		wallet.WithSnapshotInterval(config.Wallet.SnapshotInterval),
+		wallet.WithVelocityLimits(velocityLimits), // scheduled credits are limited when they're executed
+		wallet.WithFees(feeWallets, feeSchedules), // the expiry job reverts the fees of expired credits
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create wallet service: %w", err)
+	}
+
 	walletEventHandler := wallet.NewWalletEventCreatedHandler(walletSvc, txm)
 
+	expiryWorker, err := wallet.NewExpiryWorker(
+		walletSvc,
+		wallet.WithWorkerInterval(config.Wallet.ExpiryInterval),
+		wallet.WithWorkerBatchSize(config.Wallet.ExpiryBatchSize),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create expiry worker: %w", err)
+	}
+
+	scheduledTransferWorker, err := wallet.NewScheduledTransferWorker(
+		walletSvc,
+		wallet.WithWorkerInterval(config.Wallet.ScheduleInterval),
+		wallet.WithWorkerBatchSize(config.Wallet.ScheduleBatchSize),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled transfer worker: %w", err)
+	}
+
+	recurringTransferWorker, err := wallet.NewRecurringTransferWorker(
+		walletSvc,
+		wallet.WithWorkerInterval(config.Wallet.RecurringInterval),
+		wallet.WithWorkerBatchSize(config.Wallet.RecurringBatchSize),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create recurring transfer worker: %w", err)
+	}
+
+	ledgerCheckWorker, err := wallet.NewLedgerCheckWorker(walletSvc, wallet.WithWorkerInterval(config.Wallet.LedgerCheckInterval))
+	if err != nil {
+		return fmt.Errorf("failed to create ledger check worker: %w", err)
+	}
+
+	reconcileWorker, err := wallet.NewReconcileWorker(
+		walletSvc,
+		config.Wallet.ReconcileGrace,
+		config.Wallet.ReconcileRepair,
+		wallet.WithWorkerInterval(config.Wallet.ReconcileInterval),
+		wallet.WithWorkerBatchSize(config.Wallet.ReconcileBatchSize),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create reconcile worker: %w", err)
+	}
+
+	idempotencyRepo := idempotency.NewPostgresRepository(txWrapper)
+
+	idempotencyWorker, err := wallet.NewPeriodicWorker(
+		idempotencyRepo.DeleteExpired,
+		wallet.WithWorkerInterval(config.Wallet.IdempotencyInterval),
+		wallet.WithWorkerBatchSize(config.Wallet.IdempotencyBatchSize),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create idempotency cleanup worker: %w", err)
+	}
+
 	srv.Router.Route("/v1", func(r chi.Router) {
 		r.Get("/healthz", func(http.ResponseWriter, *http.Request) {})
 	})
@@ -120,7 +218,6 @@ func main() error {
 		return fmt.Errorf("failed to start pubsub router: %w", err)
 	}
 
-	outboxRepo := outbox.NewPGxRepository(txWrapper)
 	outboxWorker, err := outbox.NewOutboxWorker(outboxRepo, txm, []outbox.PublisherSettings{
 		{
 			Publisher:     publisher,
@@ -136,12 +233,42 @@ func main() error {
 		return fmt.Errorf("failed to start outbox worker: %w", err)
 	}
 
+	err = expiryWorker.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start expiry worker: %w", err)
+	}
+
+	err = scheduledTransferWorker.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start scheduled transfer worker: %w", err)
+	}
+
+	err = recurringTransferWorker.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start recurring transfer worker: %w", err)
+	}
+
+	err = ledgerCheckWorker.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start ledger check worker: %w", err)
+	}
+
+	err = reconcileWorker.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start reconcile worker: %w", err)
+	}
+
+	err = idempotencyWorker.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start idempotency cleanup worker: %w", err)
+	}
+
 	err = srv.Start()
 	if err != nil {
 		return fmt.Errorf("failed to start server: %w", err)
 	}
 
-	srv.Wait(pubsubRouter.Wait, outboxWorker.Wait)
+	srv.Wait(pubsubRouter.Wait, outboxWorker.Wait, expiryWorker.Wait, scheduledTransferWorker.Wait, recurringTransferWorker.Wait, ledgerCheckWorker.Wait, reconcileWorker.Wait, idempotencyWorker.Wait)
 
 	return nil
 }
@@ -11,9 +11,11 @@ package contract_mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/buni/wallet/internal/api/app/entity"
 	request "github.com/buni/wallet/internal/api/app/request"
+	decimal "github.com/shopspring/decimal"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -70,6 +72,111 @@ func (mr *MockWalletRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWalletRepository)(nil).Get), ctx, id)
 }
 
+// GetByReferenceID mocks base method.
+func (m *MockWalletRepository) GetByReferenceID(ctx context.Context, referenceID string) (entity.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByReferenceID", ctx, referenceID)
+	ret0, _ := ret[0].(entity.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByReferenceID indicates an expected call of GetByReferenceID.
+func (mr *MockWalletRepositoryMockRecorder) GetByReferenceID(ctx, referenceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByReferenceID", reflect.TypeOf((*MockWalletRepository)(nil).GetByReferenceID), ctx, referenceID)
+}
+
+// GetForShare mocks base method.
+func (m *MockWalletRepository) GetForShare(ctx context.Context, id string) (entity.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForShare", ctx, id)
+	ret0, _ := ret[0].(entity.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForShare indicates an expected call of GetForShare.
+func (mr *MockWalletRepositoryMockRecorder) GetForShare(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForShare", reflect.TypeOf((*MockWalletRepository)(nil).GetForShare), ctx, id)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockWalletRepository) GetForUpdate(ctx context.Context, id string) (entity.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(entity.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockWalletRepositoryMockRecorder) GetForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockWalletRepository)(nil).GetForUpdate), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockWalletRepository) List(ctx context.Context, filter entity.WalletFilter) ([]entity.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entity.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWalletRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWalletRepository)(nil).List), ctx, filter)
+}
+
+// ListIDs mocks base method.
+func (m *MockWalletRepository) ListIDs(ctx context.Context, afterID string, limit uint64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIDs", ctx, afterID, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIDs indicates an expected call of ListIDs.
+func (mr *MockWalletRepositoryMockRecorder) ListIDs(ctx, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIDs", reflect.TypeOf((*MockWalletRepository)(nil).ListIDs), ctx, afterID, limit)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockWalletRepository) UpdateStatus(ctx context.Context, wallet entity.Wallet) (entity.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, wallet)
+	ret0, _ := ret[0].(entity.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockWalletRepositoryMockRecorder) UpdateStatus(ctx, wallet any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockWalletRepository)(nil).UpdateStatus), ctx, wallet)
+}
+
+// UpdateTier mocks base method.
+func (m *MockWalletRepository) UpdateTier(ctx context.Context, wallet entity.Wallet) (entity.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTier", ctx, wallet)
+	ret0, _ := ret[0].(entity.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTier indicates an expected call of UpdateTier.
+func (mr *MockWalletRepositoryMockRecorder) UpdateTier(ctx, wallet any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTier", reflect.TypeOf((*MockWalletRepository)(nil).UpdateTier), ctx, wallet)
+}
+
 // MockWalletEventRepository is a mock of WalletEventRepository interface.
 type MockWalletEventRepository struct {
 	ctrl     *gomock.Controller
@@ -94,18 +201,63 @@ func (m *MockWalletEventRepository) EXPECT() *MockWalletEventRepositoryMockRecor
 }
 
 // Create mocks base method.
-func (m *MockWalletEventRepository) Create(ctx context.Context, event entity.WalletEvent) (entity.WalletEvent, error) {
+func (m *MockWalletEventRepository) Create(ctx context.Context, event entity.WalletEvent, expectedVersion int64) (entity.WalletEvent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, event)
+	ret := m.ctrl.Call(m, "Create", ctx, event, expectedVersion)
 	ret0, _ := ret[0].(entity.WalletEvent)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockWalletEventRepositoryMockRecorder) Create(ctx, event any) *gomock.Call {
+func (mr *MockWalletEventRepositoryMockRecorder) Create(ctx, event, expectedVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletEventRepository)(nil).Create), ctx, event, expectedVersion)
+}
+
+// GetStreamVersion mocks base method.
+func (m *MockWalletEventRepository) GetStreamVersion(ctx context.Context, walletID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamVersion", ctx, walletID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamVersion indicates an expected call of GetStreamVersion.
+func (mr *MockWalletEventRepositoryMockRecorder) GetStreamVersion(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamVersion", reflect.TypeOf((*MockWalletEventRepository)(nil).GetStreamVersion), ctx, walletID)
+}
+
+// List mocks base method.
+func (m *MockWalletEventRepository) List(ctx context.Context, filter entity.WalletEventFilter) ([]entity.WalletEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entity.WalletEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWalletEventRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWalletEventRepository)(nil).List), ctx, filter)
+}
+
+// ListByTransferID mocks base method.
+func (m *MockWalletEventRepository) ListByTransferID(ctx context.Context, walletID, transferID string) ([]entity.WalletEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByTransferID", ctx, walletID, transferID)
+	ret0, _ := ret[0].([]entity.WalletEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByTransferID indicates an expected call of ListByTransferID.
+func (mr *MockWalletEventRepositoryMockRecorder) ListByTransferID(ctx, walletID, transferID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletEventRepository)(nil).Create), ctx, event)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTransferID", reflect.TypeOf((*MockWalletEventRepository)(nil).ListByTransferID), ctx, walletID, transferID)
 }
 
 // ListByWalletID mocks base method.
@@ -123,6 +275,36 @@ func (mr *MockWalletEventRepositoryMockRecorder) ListByWalletID(ctx, walletID an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByWalletID", reflect.TypeOf((*MockWalletEventRepository)(nil).ListByWalletID), ctx, walletID)
 }
 
+// ListByWalletIDAfter mocks base method.
+func (m *MockWalletEventRepository) ListByWalletIDAfter(ctx context.Context, walletID string, afterSequence int64) ([]entity.WalletEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByWalletIDAfter", ctx, walletID, afterSequence)
+	ret0, _ := ret[0].([]entity.WalletEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByWalletIDAfter indicates an expected call of ListByWalletIDAfter.
+func (mr *MockWalletEventRepositoryMockRecorder) ListByWalletIDAfter(ctx, walletID, afterSequence any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByWalletIDAfter", reflect.TypeOf((*MockWalletEventRepository)(nil).ListByWalletIDAfter), ctx, walletID, afterSequence)
+}
+
+// ListByWalletIDSince mocks base method.
+func (m *MockWalletEventRepository) ListByWalletIDSince(ctx context.Context, walletID string, since time.Time) ([]entity.WalletEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByWalletIDSince", ctx, walletID, since)
+	ret0, _ := ret[0].([]entity.WalletEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByWalletIDSince indicates an expected call of ListByWalletIDSince.
+func (mr *MockWalletEventRepositoryMockRecorder) ListByWalletIDSince(ctx, walletID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByWalletIDSince", reflect.TypeOf((*MockWalletEventRepository)(nil).ListByWalletIDSince), ctx, walletID, since)
+}
+
 // MockWalletProjectionRepository is a mock of WalletProjectionRepository interface.
 type MockWalletProjectionRepository struct {
 	ctrl     *gomock.Controller
@@ -176,6 +358,36 @@ func (mr *MockWalletProjectionRepositoryMockRecorder) Get(ctx, walletID any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWalletProjectionRepository)(nil).Get), ctx, walletID)
 }
 
+// GetForUpdate mocks base method.
+func (m *MockWalletProjectionRepository) GetForUpdate(ctx context.Context, walletID string) (entity.WalletProjection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, walletID)
+	ret0, _ := ret[0].(entity.WalletProjection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockWalletProjectionRepositoryMockRecorder) GetForUpdate(ctx, walletID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockWalletProjectionRepository)(nil).GetForUpdate), ctx, walletID)
+}
+
+// ListByWalletIDs mocks base method.
+func (m *MockWalletProjectionRepository) ListByWalletIDs(ctx context.Context, walletIDs []string) ([]entity.WalletProjection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByWalletIDs", ctx, walletIDs)
+	ret0, _ := ret[0].([]entity.WalletProjection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByWalletIDs indicates an expected call of ListByWalletIDs.
+func (mr *MockWalletProjectionRepositoryMockRecorder) ListByWalletIDs(ctx, walletIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByWalletIDs", reflect.TypeOf((*MockWalletProjectionRepository)(nil).ListByWalletIDs), ctx, walletIDs)
+}
+
 // Update mocks base method.
 func (m *MockWalletProjectionRepository) Update(ctx context.Context, projection entity.WalletProjection) (entity.WalletProjection, error) {
 	m.ctrl.T.Helper()
@@ -191,132 +403,1307 @@ func (mr *MockWalletProjectionRepositoryMockRecorder) Update(ctx, projection any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWalletProjectionRepository)(nil).Update), ctx, projection)
 }
 
-// MockWalletService is a mock of WalletService interface.
-type MockWalletService struct {
+// MockWalletSnapshotRepository is a mock of WalletSnapshotRepository interface.
+type MockWalletSnapshotRepository struct {
 	ctrl     *gomock.Controller
-	recorder *MockWalletServiceMockRecorder
+	recorder *MockWalletSnapshotRepositoryMockRecorder
 }
 
-// MockWalletServiceMockRecorder is the mock recorder for MockWalletService.
-type MockWalletServiceMockRecorder struct {
-	mock *MockWalletService
+// MockWalletSnapshotRepositoryMockRecorder is the mock recorder for MockWalletSnapshotRepository.
+type MockWalletSnapshotRepositoryMockRecorder struct {
+	mock *MockWalletSnapshotRepository
 }
 
-// NewMockWalletService creates a new mock instance.
-func NewMockWalletService(ctrl *gomock.Controller) *MockWalletService {
-	mock := &MockWalletService{ctrl: ctrl}
-	mock.recorder = &MockWalletServiceMockRecorder{mock}
+// NewMockWalletSnapshotRepository creates a new mock instance.
+func NewMockWalletSnapshotRepository(ctrl *gomock.Controller) *MockWalletSnapshotRepository {
+	mock := &MockWalletSnapshotRepository{ctrl: ctrl}
+	mock.recorder = &MockWalletSnapshotRepositoryMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockWalletService) EXPECT() *MockWalletServiceMockRecorder {
+func (m *MockWalletSnapshotRepository) EXPECT() *MockWalletSnapshotRepositoryMockRecorder {
 	return m.recorder
 }
 
-// CompleteTransfer mocks base method.
-func (m *MockWalletService) CompleteTransfer(ctx context.Context, req *request.CompleteTransfer) (entity.WalletEvent, error) {
+// Create mocks base method.
+func (m *MockWalletSnapshotRepository) Create(ctx context.Context, snapshot entity.WalletSnapshot) (entity.WalletSnapshot, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CompleteTransfer", ctx, req)
-	ret0, _ := ret[0].(entity.WalletEvent)
+	ret := m.ctrl.Call(m, "Create", ctx, snapshot)
+	ret0, _ := ret[0].(entity.WalletSnapshot)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CompleteTransfer indicates an expected call of CompleteTransfer.
-func (mr *MockWalletServiceMockRecorder) CompleteTransfer(ctx, req any) *gomock.Call {
+// Create indicates an expected call of Create.
+func (mr *MockWalletSnapshotRepositoryMockRecorder) Create(ctx, snapshot any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTransfer", reflect.TypeOf((*MockWalletService)(nil).CompleteTransfer), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletSnapshotRepository)(nil).Create), ctx, snapshot)
 }
 
-// Create mocks base method.
-func (m *MockWalletService) Create(ctx context.Context, req *request.CreateWallet) (entity.Wallet, error) {
+// GetLatest mocks base method.
+func (m *MockWalletSnapshotRepository) GetLatest(ctx context.Context, walletID string) (entity.WalletSnapshot, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, req)
-	ret0, _ := ret[0].(entity.Wallet)
+	ret := m.ctrl.Call(m, "GetLatest", ctx, walletID)
+	ret0, _ := ret[0].(entity.WalletSnapshot)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockWalletServiceMockRecorder) Create(ctx, req any) *gomock.Call {
+// GetLatest indicates an expected call of GetLatest.
+func (mr *MockWalletSnapshotRepositoryMockRecorder) GetLatest(ctx, walletID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletService)(nil).Create), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatest", reflect.TypeOf((*MockWalletSnapshotRepository)(nil).GetLatest), ctx, walletID)
 }
 
-// CreditTransfer mocks base method.
-func (m *MockWalletService) CreditTransfer(ctx context.Context, req *request.CreditTransfer) (entity.WalletEvent, error) {
+// MockWalletTransferRepository is a mock of WalletTransferRepository interface.
+type MockWalletTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletTransferRepositoryMockRecorder
+}
+
+// MockWalletTransferRepositoryMockRecorder is the mock recorder for MockWalletTransferRepository.
+type MockWalletTransferRepositoryMockRecorder struct {
+	mock *MockWalletTransferRepository
+}
+
+// NewMockWalletTransferRepository creates a new mock instance.
+func NewMockWalletTransferRepository(ctrl *gomock.Controller) *MockWalletTransferRepository {
+	mock := &MockWalletTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockWalletTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletTransferRepository) EXPECT() *MockWalletTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockWalletTransferRepository) Get(ctx context.Context, walletID, transferID string) (entity.WalletTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreditTransfer", ctx, req)
-	ret0, _ := ret[0].(entity.WalletEvent)
+	ret := m.ctrl.Call(m, "Get", ctx, walletID, transferID)
+	ret0, _ := ret[0].(entity.WalletTransfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreditTransfer indicates an expected call of CreditTransfer.
-func (mr *MockWalletServiceMockRecorder) CreditTransfer(ctx, req any) *gomock.Call {
+// Get indicates an expected call of Get.
+func (mr *MockWalletTransferRepositoryMockRecorder) Get(ctx, walletID, transferID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditTransfer", reflect.TypeOf((*MockWalletService)(nil).CreditTransfer), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWalletTransferRepository)(nil).Get), ctx, walletID, transferID)
 }
 
-// DebitTransfer mocks base method.
-func (m *MockWalletService) DebitTransfer(ctx context.Context, req *request.DebitTransfer) (entity.WalletEvent, error) {
+// List mocks base method.
+func (m *MockWalletTransferRepository) List(ctx context.Context, filter entity.WalletTransferFilter) ([]entity.WalletTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DebitTransfer", ctx, req)
-	ret0, _ := ret[0].(entity.WalletEvent)
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entity.WalletTransfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DebitTransfer indicates an expected call of DebitTransfer.
-func (mr *MockWalletServiceMockRecorder) DebitTransfer(ctx, req any) *gomock.Call {
+// List indicates an expected call of List.
+func (mr *MockWalletTransferRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebitTransfer", reflect.TypeOf((*MockWalletService)(nil).DebitTransfer), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWalletTransferRepository)(nil).List), ctx, filter)
 }
 
-// Get mocks base method.
-func (m *MockWalletService) Get(ctx context.Context, req *request.GetWallet) (entity.WalletBalanceProjection, error) {
+// ListExpired mocks base method.
+func (m *MockWalletTransferRepository) ListExpired(ctx context.Context, before time.Time, limit uint64) ([]entity.WalletTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx, req)
-	ret0, _ := ret[0].(entity.WalletBalanceProjection)
+	ret := m.ctrl.Call(m, "ListExpired", ctx, before, limit)
+	ret0, _ := ret[0].([]entity.WalletTransfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Get indicates an expected call of Get.
-func (mr *MockWalletServiceMockRecorder) Get(ctx, req any) *gomock.Call {
+// ListExpired indicates an expected call of ListExpired.
+func (mr *MockWalletTransferRepositoryMockRecorder) ListExpired(ctx, before, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWalletService)(nil).Get), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpired", reflect.TypeOf((*MockWalletTransferRepository)(nil).ListExpired), ctx, before, limit)
 }
 
-// RebuildWalletProjection mocks base method.
-func (m *MockWalletService) RebuildWalletProjection(ctx context.Context, event *entity.WalletEvent) (entity.WalletProjection, error) {
+// Upsert mocks base method.
+func (m *MockWalletTransferRepository) Upsert(ctx context.Context, transfer entity.WalletTransfer) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RebuildWalletProjection", ctx, event)
-	ret0, _ := ret[0].(entity.WalletProjection)
+	ret := m.ctrl.Call(m, "Upsert", ctx, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockWalletTransferRepositoryMockRecorder) Upsert(ctx, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockWalletTransferRepository)(nil).Upsert), ctx, transfer)
+}
+
+// MockScheduledTransferRepository is a mock of ScheduledTransferRepository interface.
+type MockScheduledTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduledTransferRepositoryMockRecorder
+}
+
+// MockScheduledTransferRepositoryMockRecorder is the mock recorder for MockScheduledTransferRepository.
+type MockScheduledTransferRepositoryMockRecorder struct {
+	mock *MockScheduledTransferRepository
+}
+
+// NewMockScheduledTransferRepository creates a new mock instance.
+func NewMockScheduledTransferRepository(ctrl *gomock.Controller) *MockScheduledTransferRepository {
+	mock := &MockScheduledTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockScheduledTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduledTransferRepository) EXPECT() *MockScheduledTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockScheduledTransferRepository) Create(ctx context.Context, transfer entity.ScheduledTransfer) (entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, transfer)
+	ret0, _ := ret[0].(entity.ScheduledTransfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// RebuildWalletProjection indicates an expected call of RebuildWalletProjection.
-func (mr *MockWalletServiceMockRecorder) RebuildWalletProjection(ctx, event any) *gomock.Call {
+// Create indicates an expected call of Create.
+func (mr *MockScheduledTransferRepositoryMockRecorder) Create(ctx, transfer any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildWalletProjection", reflect.TypeOf((*MockWalletService)(nil).RebuildWalletProjection), ctx, event)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScheduledTransferRepository)(nil).Create), ctx, transfer)
 }
 
-// RevertTransfer mocks base method.
-func (m *MockWalletService) RevertTransfer(ctx context.Context, req *request.RevertTransfer) (entity.WalletEvent, error) {
+// GetForUpdate mocks base method.
+func (m *MockScheduledTransferRepository) GetForUpdate(ctx context.Context, walletID, transferID string) (entity.ScheduledTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RevertTransfer", ctx, req)
-	ret0, _ := ret[0].(entity.WalletEvent)
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, walletID, transferID)
+	ret0, _ := ret[0].(entity.ScheduledTransfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// RevertTransfer indicates an expected call of RevertTransfer.
-func (mr *MockWalletServiceMockRecorder) RevertTransfer(ctx, req any) *gomock.Call {
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockScheduledTransferRepositoryMockRecorder) GetForUpdate(ctx, walletID, transferID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertTransfer", reflect.TypeOf((*MockWalletService)(nil).RevertTransfer), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockScheduledTransferRepository)(nil).GetForUpdate), ctx, walletID, transferID)
+}
+
+// List mocks base method.
+func (m *MockScheduledTransferRepository) List(ctx context.Context, filter entity.ScheduledTransferFilter) ([]entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockScheduledTransferRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockScheduledTransferRepository)(nil).List), ctx, filter)
+}
+
+// ListDue mocks base method.
+func (m *MockScheduledTransferRepository) ListDue(ctx context.Context, before time.Time, limit uint64) ([]entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, before, limit)
+	ret0, _ := ret[0].([]entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockScheduledTransferRepositoryMockRecorder) ListDue(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockScheduledTransferRepository)(nil).ListDue), ctx, before, limit)
+}
+
+// Update mocks base method.
+func (m *MockScheduledTransferRepository) Update(ctx context.Context, transfer entity.ScheduledTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockScheduledTransferRepositoryMockRecorder) Update(ctx, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScheduledTransferRepository)(nil).Update), ctx, transfer)
+}
+
+// MockRecurringTransferRepository is a mock of RecurringTransferRepository interface.
+type MockRecurringTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecurringTransferRepositoryMockRecorder
+}
+
+// MockRecurringTransferRepositoryMockRecorder is the mock recorder for MockRecurringTransferRepository.
+type MockRecurringTransferRepositoryMockRecorder struct {
+	mock *MockRecurringTransferRepository
+}
+
+// NewMockRecurringTransferRepository creates a new mock instance.
+func NewMockRecurringTransferRepository(ctrl *gomock.Controller) *MockRecurringTransferRepository {
+	mock := &MockRecurringTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockRecurringTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecurringTransferRepository) EXPECT() *MockRecurringTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRecurringTransferRepository) Create(ctx context.Context, transfer entity.RecurringTransfer) (entity.RecurringTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, transfer)
+	ret0, _ := ret[0].(entity.RecurringTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRecurringTransferRepositoryMockRecorder) Create(ctx, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRecurringTransferRepository)(nil).Create), ctx, transfer)
+}
+
+// Delete mocks base method.
+func (m *MockRecurringTransferRepository) Delete(ctx context.Context, walletID, recurringID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, walletID, recurringID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRecurringTransferRepositoryMockRecorder) Delete(ctx, walletID, recurringID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRecurringTransferRepository)(nil).Delete), ctx, walletID, recurringID)
+}
+
+// Get mocks base method.
+func (m *MockRecurringTransferRepository) Get(ctx context.Context, walletID, recurringID string) (entity.RecurringTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, walletID, recurringID)
+	ret0, _ := ret[0].(entity.RecurringTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRecurringTransferRepositoryMockRecorder) Get(ctx, walletID, recurringID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRecurringTransferRepository)(nil).Get), ctx, walletID, recurringID)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockRecurringTransferRepository) GetForUpdate(ctx context.Context, walletID, recurringID string) (entity.RecurringTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, walletID, recurringID)
+	ret0, _ := ret[0].(entity.RecurringTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockRecurringTransferRepositoryMockRecorder) GetForUpdate(ctx, walletID, recurringID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockRecurringTransferRepository)(nil).GetForUpdate), ctx, walletID, recurringID)
+}
+
+// List mocks base method.
+func (m *MockRecurringTransferRepository) List(ctx context.Context, filter entity.RecurringTransferFilter) ([]entity.RecurringTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entity.RecurringTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRecurringTransferRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRecurringTransferRepository)(nil).List), ctx, filter)
+}
+
+// ListDue mocks base method.
+func (m *MockRecurringTransferRepository) ListDue(ctx context.Context, before time.Time, limit uint64) ([]entity.RecurringTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, before, limit)
+	ret0, _ := ret[0].([]entity.RecurringTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockRecurringTransferRepositoryMockRecorder) ListDue(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockRecurringTransferRepository)(nil).ListDue), ctx, before, limit)
+}
+
+// Update mocks base method.
+func (m *MockRecurringTransferRepository) Update(ctx context.Context, transfer entity.RecurringTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRecurringTransferRepositoryMockRecorder) Update(ctx, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRecurringTransferRepository)(nil).Update), ctx, transfer)
+}
+
+// MockInternalTransferRepository is a mock of InternalTransferRepository interface.
+type MockInternalTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInternalTransferRepositoryMockRecorder
+}
+
+// MockInternalTransferRepositoryMockRecorder is the mock recorder for MockInternalTransferRepository.
+type MockInternalTransferRepositoryMockRecorder struct {
+	mock *MockInternalTransferRepository
+}
+
+// NewMockInternalTransferRepository creates a new mock instance.
+func NewMockInternalTransferRepository(ctrl *gomock.Controller) *MockInternalTransferRepository {
+	mock := &MockInternalTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockInternalTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInternalTransferRepository) EXPECT() *MockInternalTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockInternalTransferRepository) Create(ctx context.Context, transfer entity.InternalTransfer) (entity.InternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, transfer)
+	ret0, _ := ret[0].(entity.InternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockInternalTransferRepositoryMockRecorder) Create(ctx, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInternalTransferRepository)(nil).Create), ctx, transfer)
+}
+
+// Get mocks base method.
+func (m *MockInternalTransferRepository) Get(ctx context.Context, transferID string) (entity.InternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, transferID)
+	ret0, _ := ret[0].(entity.InternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockInternalTransferRepositoryMockRecorder) Get(ctx, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInternalTransferRepository)(nil).Get), ctx, transferID)
+}
+
+// MockRefundRepository is a mock of RefundRepository interface.
+type MockRefundRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefundRepositoryMockRecorder
+}
+
+// MockRefundRepositoryMockRecorder is the mock recorder for MockRefundRepository.
+type MockRefundRepositoryMockRecorder struct {
+	mock *MockRefundRepository
+}
+
+// NewMockRefundRepository creates a new mock instance.
+func NewMockRefundRepository(ctrl *gomock.Controller) *MockRefundRepository {
+	mock := &MockRefundRepository{ctrl: ctrl}
+	mock.recorder = &MockRefundRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefundRepository) EXPECT() *MockRefundRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRefundRepository) Create(ctx context.Context, refund entity.TransferRefund) (entity.TransferRefund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, refund)
+	ret0, _ := ret[0].(entity.TransferRefund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRefundRepositoryMockRecorder) Create(ctx, refund any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRefundRepository)(nil).Create), ctx, refund)
+}
+
+// ListByTransferID mocks base method.
+func (m *MockRefundRepository) ListByTransferID(ctx context.Context, walletID, transferID string) ([]entity.TransferRefund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByTransferID", ctx, walletID, transferID)
+	ret0, _ := ret[0].([]entity.TransferRefund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByTransferID indicates an expected call of ListByTransferID.
+func (mr *MockRefundRepositoryMockRecorder) ListByTransferID(ctx, walletID, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTransferID", reflect.TypeOf((*MockRefundRepository)(nil).ListByTransferID), ctx, walletID, transferID)
+}
+
+// MockFXQuoteRepository is a mock of FXQuoteRepository interface.
+type MockFXQuoteRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFXQuoteRepositoryMockRecorder
+}
+
+// MockFXQuoteRepositoryMockRecorder is the mock recorder for MockFXQuoteRepository.
+type MockFXQuoteRepositoryMockRecorder struct {
+	mock *MockFXQuoteRepository
+}
+
+// NewMockFXQuoteRepository creates a new mock instance.
+func NewMockFXQuoteRepository(ctrl *gomock.Controller) *MockFXQuoteRepository {
+	mock := &MockFXQuoteRepository{ctrl: ctrl}
+	mock.recorder = &MockFXQuoteRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFXQuoteRepository) EXPECT() *MockFXQuoteRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFXQuoteRepository) Create(ctx context.Context, quote entity.FXQuote) (entity.FXQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, quote)
+	ret0, _ := ret[0].(entity.FXQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFXQuoteRepositoryMockRecorder) Create(ctx, quote any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFXQuoteRepository)(nil).Create), ctx, quote)
+}
+
+// Get mocks base method.
+func (m *MockFXQuoteRepository) Get(ctx context.Context, quoteID string) (entity.FXQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, quoteID)
+	ret0, _ := ret[0].(entity.FXQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockFXQuoteRepositoryMockRecorder) Get(ctx, quoteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockFXQuoteRepository)(nil).Get), ctx, quoteID)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockFXQuoteRepository) GetForUpdate(ctx context.Context, quoteID string) (entity.FXQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, quoteID)
+	ret0, _ := ret[0].(entity.FXQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockFXQuoteRepositoryMockRecorder) GetForUpdate(ctx, quoteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockFXQuoteRepository)(nil).GetForUpdate), ctx, quoteID)
+}
+
+// Update mocks base method.
+func (m *MockFXQuoteRepository) Update(ctx context.Context, quote entity.FXQuote) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, quote)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockFXQuoteRepositoryMockRecorder) Update(ctx, quote any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFXQuoteRepository)(nil).Update), ctx, quote)
+}
+
+// MockFXRateProvider is a mock of FXRateProvider interface.
+type MockFXRateProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockFXRateProviderMockRecorder
+}
+
+// MockFXRateProviderMockRecorder is the mock recorder for MockFXRateProvider.
+type MockFXRateProviderMockRecorder struct {
+	mock *MockFXRateProvider
+}
+
+// NewMockFXRateProvider creates a new mock instance.
+func NewMockFXRateProvider(ctrl *gomock.Controller) *MockFXRateProvider {
+	mock := &MockFXRateProvider{ctrl: ctrl}
+	mock.recorder = &MockFXRateProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFXRateProvider) EXPECT() *MockFXRateProviderMockRecorder {
+	return m.recorder
+}
+
+// Rate mocks base method.
+func (m *MockFXRateProvider) Rate(ctx context.Context, from, to entity.Currency) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rate", ctx, from, to)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rate indicates an expected call of Rate.
+func (mr *MockFXRateProviderMockRecorder) Rate(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rate", reflect.TypeOf((*MockFXRateProvider)(nil).Rate), ctx, from, to)
+}
+
+// MockWalletService is a mock of WalletService interface.
+type MockWalletService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletServiceMockRecorder
+}
+
+// MockWalletServiceMockRecorder is the mock recorder for MockWalletService.
+type MockWalletServiceMockRecorder struct {
+	mock *MockWalletService
+}
+
+// NewMockWalletService creates a new mock instance.
+func NewMockWalletService(ctrl *gomock.Controller) *MockWalletService {
+	mock := &MockWalletService{ctrl: ctrl}
+	mock.recorder = &MockWalletServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletService) EXPECT() *MockWalletServiceMockRecorder {
+	return m.recorder
+}
+
+// BatchTransfers mocks base method.
+func (m *MockWalletService) BatchTransfers(ctx context.Context, req *request.BatchTransfers) ([]entity.BatchTransferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchTransfers", ctx, req)
+	ret0, _ := ret[0].([]entity.BatchTransferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchTransfers indicates an expected call of BatchTransfers.
+func (mr *MockWalletServiceMockRecorder) BatchTransfers(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchTransfers", reflect.TypeOf((*MockWalletService)(nil).BatchTransfers), ctx, req)
+}
+
+// CancelScheduledTransfer mocks base method.
+func (m *MockWalletService) CancelScheduledTransfer(ctx context.Context, req *request.CancelScheduledTransfer) (entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduledTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelScheduledTransfer indicates an expected call of CancelScheduledTransfer.
+func (mr *MockWalletServiceMockRecorder) CancelScheduledTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduledTransfer", reflect.TypeOf((*MockWalletService)(nil).CancelScheduledTransfer), ctx, req)
+}
+
+// Close mocks base method.
+func (m *MockWalletService) Close(ctx context.Context, req *request.CloseWallet) (entity.WalletBalanceProjection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx, req)
+	ret0, _ := ret[0].(entity.WalletBalanceProjection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockWalletServiceMockRecorder) Close(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockWalletService)(nil).Close), ctx, req)
+}
+
+// CompleteInternalTransfer mocks base method.
+func (m *MockWalletService) CompleteInternalTransfer(ctx context.Context, req *request.CompleteInternalTransfer) (entity.InternalTransferEvents, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteInternalTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.InternalTransferEvents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteInternalTransfer indicates an expected call of CompleteInternalTransfer.
+func (mr *MockWalletServiceMockRecorder) CompleteInternalTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteInternalTransfer", reflect.TypeOf((*MockWalletService)(nil).CompleteInternalTransfer), ctx, req)
+}
+
+// CompleteTransfer mocks base method.
+func (m *MockWalletService) CompleteTransfer(ctx context.Context, req *request.CompleteTransfer) (entity.WalletEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.WalletEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteTransfer indicates an expected call of CompleteTransfer.
+func (mr *MockWalletServiceMockRecorder) CompleteTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTransfer", reflect.TypeOf((*MockWalletService)(nil).CompleteTransfer), ctx, req)
+}
+
+// Create mocks base method.
+func (m *MockWalletService) Create(ctx context.Context, req *request.CreateWallet) (entity.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(entity.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWalletServiceMockRecorder) Create(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletService)(nil).Create), ctx, req)
+}
+
+// CreateFXQuote mocks base method.
+func (m *MockWalletService) CreateFXQuote(ctx context.Context, req *request.CreateFXQuote) (entity.FXQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFXQuote", ctx, req)
+	ret0, _ := ret[0].(entity.FXQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFXQuote indicates an expected call of CreateFXQuote.
+func (mr *MockWalletServiceMockRecorder) CreateFXQuote(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFXQuote", reflect.TypeOf((*MockWalletService)(nil).CreateFXQuote), ctx, req)
+}
+
+// CreateRecurringTransfer mocks base method.
+func (m *MockWalletService) CreateRecurringTransfer(ctx context.Context, req *request.CreateRecurringTransfer) (entity.RecurringTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecurringTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.RecurringTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecurringTransfer indicates an expected call of CreateRecurringTransfer.
+func (mr *MockWalletServiceMockRecorder) CreateRecurringTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecurringTransfer", reflect.TypeOf((*MockWalletService)(nil).CreateRecurringTransfer), ctx, req)
+}
+
+// CreditTransfer mocks base method.
+func (m *MockWalletService) CreditTransfer(ctx context.Context, req *request.CreditTransfer) (entity.WalletEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreditTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.WalletEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreditTransfer indicates an expected call of CreditTransfer.
+func (mr *MockWalletServiceMockRecorder) CreditTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditTransfer", reflect.TypeOf((*MockWalletService)(nil).CreditTransfer), ctx, req)
+}
+
+// DebitTransfer mocks base method.
+func (m *MockWalletService) DebitTransfer(ctx context.Context, req *request.DebitTransfer) (entity.WalletEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DebitTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.WalletEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DebitTransfer indicates an expected call of DebitTransfer.
+func (mr *MockWalletServiceMockRecorder) DebitTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebitTransfer", reflect.TypeOf((*MockWalletService)(nil).DebitTransfer), ctx, req)
+}
+
+// DeleteRecurringTransfer mocks base method.
+func (m *MockWalletService) DeleteRecurringTransfer(ctx context.Context, req *request.DeleteRecurringTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecurringTransfer", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecurringTransfer indicates an expected call of DeleteRecurringTransfer.
+func (mr *MockWalletServiceMockRecorder) DeleteRecurringTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecurringTransfer", reflect.TypeOf((*MockWalletService)(nil).DeleteRecurringTransfer), ctx, req)
+}
+
+// ExecuteFXQuote mocks base method.
+func (m *MockWalletService) ExecuteFXQuote(ctx context.Context, req *request.ExecuteFXQuote) (entity.FXQuoteEvents, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteFXQuote", ctx, req)
+	ret0, _ := ret[0].(entity.FXQuoteEvents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteFXQuote indicates an expected call of ExecuteFXQuote.
+func (mr *MockWalletServiceMockRecorder) ExecuteFXQuote(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteFXQuote", reflect.TypeOf((*MockWalletService)(nil).ExecuteFXQuote), ctx, req)
+}
+
+// ExecuteRecurringTransfers mocks base method.
+func (m *MockWalletService) ExecuteRecurringTransfers(ctx context.Context, now time.Time, limit uint64) ([]entity.RecurringTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteRecurringTransfers", ctx, now, limit)
+	ret0, _ := ret[0].([]entity.RecurringTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteRecurringTransfers indicates an expected call of ExecuteRecurringTransfers.
+func (mr *MockWalletServiceMockRecorder) ExecuteRecurringTransfers(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteRecurringTransfers", reflect.TypeOf((*MockWalletService)(nil).ExecuteRecurringTransfers), ctx, now, limit)
+}
+
+// ExecuteScheduledTransfers mocks base method.
+func (m *MockWalletService) ExecuteScheduledTransfers(ctx context.Context, now time.Time, limit uint64) ([]entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteScheduledTransfers", ctx, now, limit)
+	ret0, _ := ret[0].([]entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteScheduledTransfers indicates an expected call of ExecuteScheduledTransfers.
+func (mr *MockWalletServiceMockRecorder) ExecuteScheduledTransfers(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScheduledTransfers", reflect.TypeOf((*MockWalletService)(nil).ExecuteScheduledTransfers), ctx, now, limit)
+}
+
+// ExpireTransfers mocks base method.
+func (m *MockWalletService) ExpireTransfers(ctx context.Context, now time.Time, limit uint64) ([]entity.WalletEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireTransfers", ctx, now, limit)
+	ret0, _ := ret[0].([]entity.WalletEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireTransfers indicates an expected call of ExpireTransfers.
+func (mr *MockWalletServiceMockRecorder) ExpireTransfers(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireTransfers", reflect.TypeOf((*MockWalletService)(nil).ExpireTransfers), ctx, now, limit)
+}
+
+// Freeze mocks base method.
+func (m *MockWalletService) Freeze(ctx context.Context, req *request.FreezeWallet) (entity.WalletBalanceProjection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Freeze", ctx, req)
+	ret0, _ := ret[0].(entity.WalletBalanceProjection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Freeze indicates an expected call of Freeze.
+func (mr *MockWalletServiceMockRecorder) Freeze(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Freeze", reflect.TypeOf((*MockWalletService)(nil).Freeze), ctx, req)
+}
+
+// Get mocks base method.
+func (m *MockWalletService) Get(ctx context.Context, req *request.GetWallet) (entity.WalletBalanceProjection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, req)
+	ret0, _ := ret[0].(entity.WalletBalanceProjection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWalletServiceMockRecorder) Get(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWalletService)(nil).Get), ctx, req)
+}
+
+// GetBalance mocks base method.
+func (m *MockWalletService) GetBalance(ctx context.Context, req *request.GetBalance) (entity.WalletProjection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", ctx, req)
+	ret0, _ := ret[0].(entity.WalletProjection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockWalletServiceMockRecorder) GetBalance(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockWalletService)(nil).GetBalance), ctx, req)
+}
+
+// GetByReferenceID mocks base method.
+func (m *MockWalletService) GetByReferenceID(ctx context.Context, req *request.GetWalletByReference) (entity.WalletBalanceProjection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByReferenceID", ctx, req)
+	ret0, _ := ret[0].(entity.WalletBalanceProjection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByReferenceID indicates an expected call of GetByReferenceID.
+func (mr *MockWalletServiceMockRecorder) GetByReferenceID(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByReferenceID", reflect.TypeOf((*MockWalletService)(nil).GetByReferenceID), ctx, req)
+}
+
+// GetFXQuote mocks base method.
+func (m *MockWalletService) GetFXQuote(ctx context.Context, req *request.GetFXQuote) (entity.FXQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFXQuote", ctx, req)
+	ret0, _ := ret[0].(entity.FXQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFXQuote indicates an expected call of GetFXQuote.
+func (mr *MockWalletServiceMockRecorder) GetFXQuote(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFXQuote", reflect.TypeOf((*MockWalletService)(nil).GetFXQuote), ctx, req)
+}
+
+// GetRecurringTransfer mocks base method.
+func (m *MockWalletService) GetRecurringTransfer(ctx context.Context, req *request.GetRecurringTransfer) (entity.RecurringTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecurringTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.RecurringTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecurringTransfer indicates an expected call of GetRecurringTransfer.
+func (mr *MockWalletServiceMockRecorder) GetRecurringTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurringTransfer", reflect.TypeOf((*MockWalletService)(nil).GetRecurringTransfer), ctx, req)
+}
+
+// GetStatement mocks base method.
+func (m *MockWalletService) GetStatement(ctx context.Context, req *request.GetStatement) (entity.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatement", ctx, req)
+	ret0, _ := ret[0].(entity.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatement indicates an expected call of GetStatement.
+func (mr *MockWalletServiceMockRecorder) GetStatement(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatement", reflect.TypeOf((*MockWalletService)(nil).GetStatement), ctx, req)
+}
+
+// GetTransfer mocks base method.
+func (m *MockWalletService) GetTransfer(ctx context.Context, req *request.GetTransfer) (entity.WalletTransferRefunds, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.WalletTransferRefunds)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockWalletServiceMockRecorder) GetTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockWalletService)(nil).GetTransfer), ctx, req)
+}
+
+// InternalTransfer mocks base method.
+func (m *MockWalletService) InternalTransfer(ctx context.Context, req *request.InternalTransfer) (entity.InternalTransferEvents, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InternalTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.InternalTransferEvents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InternalTransfer indicates an expected call of InternalTransfer.
+func (mr *MockWalletServiceMockRecorder) InternalTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InternalTransfer", reflect.TypeOf((*MockWalletService)(nil).InternalTransfer), ctx, req)
+}
+
+// List mocks base method.
+func (m *MockWalletService) List(ctx context.Context, req *request.ListWallets) (entity.WalletPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(entity.WalletPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWalletServiceMockRecorder) List(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWalletService)(nil).List), ctx, req)
+}
+
+// ListEvents mocks base method.
+func (m *MockWalletService) ListEvents(ctx context.Context, req *request.ListWalletEvents) (entity.WalletEventPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx, req)
+	ret0, _ := ret[0].(entity.WalletEventPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockWalletServiceMockRecorder) ListEvents(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockWalletService)(nil).ListEvents), ctx, req)
+}
+
+// ListRecurringTransfers mocks base method.
+func (m *MockWalletService) ListRecurringTransfers(ctx context.Context, req *request.ListRecurringTransfers) ([]entity.RecurringTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecurringTransfers", ctx, req)
+	ret0, _ := ret[0].([]entity.RecurringTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecurringTransfers indicates an expected call of ListRecurringTransfers.
+func (mr *MockWalletServiceMockRecorder) ListRecurringTransfers(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecurringTransfers", reflect.TypeOf((*MockWalletService)(nil).ListRecurringTransfers), ctx, req)
+}
+
+// ListScheduledTransfers mocks base method.
+func (m *MockWalletService) ListScheduledTransfers(ctx context.Context, req *request.ListScheduledTransfers) ([]entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledTransfers", ctx, req)
+	ret0, _ := ret[0].([]entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledTransfers indicates an expected call of ListScheduledTransfers.
+func (mr *MockWalletServiceMockRecorder) ListScheduledTransfers(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledTransfers", reflect.TypeOf((*MockWalletService)(nil).ListScheduledTransfers), ctx, req)
+}
+
+// ListTransfers mocks base method.
+func (m *MockWalletService) ListTransfers(ctx context.Context, req *request.ListTransfers) ([]entity.WalletTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfers", ctx, req)
+	ret0, _ := ret[0].([]entity.WalletTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockWalletServiceMockRecorder) ListTransfers(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockWalletService)(nil).ListTransfers), ctx, req)
+}
+
+// PostLedgerEntries mocks base method.
+func (m *MockWalletService) PostLedgerEntries(ctx context.Context, event *entity.WalletEvent) ([]entity.LedgerPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostLedgerEntries", ctx, event)
+	ret0, _ := ret[0].([]entity.LedgerPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostLedgerEntries indicates an expected call of PostLedgerEntries.
+func (mr *MockWalletServiceMockRecorder) PostLedgerEntries(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostLedgerEntries", reflect.TypeOf((*MockWalletService)(nil).PostLedgerEntries), ctx, event)
+}
+
+// RebuildTransferProjection mocks base method.
+func (m *MockWalletService) RebuildTransferProjection(ctx context.Context, event *entity.WalletEvent) (entity.WalletTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildTransferProjection", ctx, event)
+	ret0, _ := ret[0].(entity.WalletTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildTransferProjection indicates an expected call of RebuildTransferProjection.
+func (mr *MockWalletServiceMockRecorder) RebuildTransferProjection(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildTransferProjection", reflect.TypeOf((*MockWalletService)(nil).RebuildTransferProjection), ctx, event)
+}
+
+// RebuildWalletProjection mocks base method.
+func (m *MockWalletService) RebuildWalletProjection(ctx context.Context, event *entity.WalletEvent) (entity.WalletProjection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildWalletProjection", ctx, event)
+	ret0, _ := ret[0].(entity.WalletProjection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildWalletProjection indicates an expected call of RebuildWalletProjection.
+func (mr *MockWalletServiceMockRecorder) RebuildWalletProjection(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildWalletProjection", reflect.TypeOf((*MockWalletService)(nil).RebuildWalletProjection), ctx, event)
+}
+
+// ReconcileProjections mocks base method.
+func (m *MockWalletService) ReconcileProjections(ctx context.Context, opts entity.ReconcileOptions) (entity.ReconcileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileProjections", ctx, opts)
+	ret0, _ := ret[0].(entity.ReconcileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileProjections indicates an expected call of ReconcileProjections.
+func (mr *MockWalletServiceMockRecorder) ReconcileProjections(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileProjections", reflect.TypeOf((*MockWalletService)(nil).ReconcileProjections), ctx, opts)
+}
+
+// RefundTransfer mocks base method.
+func (m *MockWalletService) RefundTransfer(ctx context.Context, req *request.RefundTransfer) (entity.TransferRefund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.TransferRefund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundTransfer indicates an expected call of RefundTransfer.
+func (mr *MockWalletServiceMockRecorder) RefundTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundTransfer", reflect.TypeOf((*MockWalletService)(nil).RefundTransfer), ctx, req)
+}
+
+// RevertInternalTransfer mocks base method.
+func (m *MockWalletService) RevertInternalTransfer(ctx context.Context, req *request.RevertInternalTransfer) (entity.InternalTransferEvents, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertInternalTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.InternalTransferEvents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertInternalTransfer indicates an expected call of RevertInternalTransfer.
+func (mr *MockWalletServiceMockRecorder) RevertInternalTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertInternalTransfer", reflect.TypeOf((*MockWalletService)(nil).RevertInternalTransfer), ctx, req)
+}
+
+// RevertTransfer mocks base method.
+func (m *MockWalletService) RevertTransfer(ctx context.Context, req *request.RevertTransfer) (entity.WalletEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.WalletEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertTransfer indicates an expected call of RevertTransfer.
+func (mr *MockWalletServiceMockRecorder) RevertTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertTransfer", reflect.TypeOf((*MockWalletService)(nil).RevertTransfer), ctx, req)
+}
+
+// ScheduleCreditTransfer mocks base method.
+func (m *MockWalletService) ScheduleCreditTransfer(ctx context.Context, req *request.CreditTransfer) (entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleCreditTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleCreditTransfer indicates an expected call of ScheduleCreditTransfer.
+func (mr *MockWalletServiceMockRecorder) ScheduleCreditTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleCreditTransfer", reflect.TypeOf((*MockWalletService)(nil).ScheduleCreditTransfer), ctx, req)
+}
+
+// ScheduleDebitTransfer mocks base method.
+func (m *MockWalletService) ScheduleDebitTransfer(ctx context.Context, req *request.DebitTransfer) (entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleDebitTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleDebitTransfer indicates an expected call of ScheduleDebitTransfer.
+func (mr *MockWalletServiceMockRecorder) ScheduleDebitTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleDebitTransfer", reflect.TypeOf((*MockWalletService)(nil).ScheduleDebitTransfer), ctx, req)
+}
+
+// SetCreditLimit mocks base method.
+func (m *MockWalletService) SetCreditLimit(ctx context.Context, req *request.SetCreditLimit) (entity.WalletEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCreditLimit", ctx, req)
+	ret0, _ := ret[0].(entity.WalletEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCreditLimit indicates an expected call of SetCreditLimit.
+func (mr *MockWalletServiceMockRecorder) SetCreditLimit(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCreditLimit", reflect.TypeOf((*MockWalletService)(nil).SetCreditLimit), ctx, req)
+}
+
+// SetTier mocks base method.
+func (m *MockWalletService) SetTier(ctx context.Context, req *request.SetWalletTier) (entity.WalletBalanceProjection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTier", ctx, req)
+	ret0, _ := ret[0].(entity.WalletBalanceProjection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTier indicates an expected call of SetTier.
+func (mr *MockWalletServiceMockRecorder) SetTier(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTier", reflect.TypeOf((*MockWalletService)(nil).SetTier), ctx, req)
+}
+
+// TrialBalance mocks base method.
+func (m *MockWalletService) TrialBalance(ctx context.Context) (entity.TrialBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrialBalance", ctx)
+	ret0, _ := ret[0].(entity.TrialBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrialBalance indicates an expected call of TrialBalance.
+func (mr *MockWalletServiceMockRecorder) TrialBalance(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrialBalance", reflect.TypeOf((*MockWalletService)(nil).TrialBalance), ctx)
+}
+
+// Unfreeze mocks base method.
+func (m *MockWalletService) Unfreeze(ctx context.Context, req *request.UnfreezeWallet) (entity.WalletBalanceProjection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfreeze", ctx, req)
+	ret0, _ := ret[0].(entity.WalletBalanceProjection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unfreeze indicates an expected call of Unfreeze.
+func (mr *MockWalletServiceMockRecorder) Unfreeze(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfreeze", reflect.TypeOf((*MockWalletService)(nil).Unfreeze), ctx, req)
+}
+
+// UpdateRecurringTransfer mocks base method.
+func (m *MockWalletService) UpdateRecurringTransfer(ctx context.Context, req *request.UpdateRecurringTransfer) (entity.RecurringTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecurringTransfer", ctx, req)
+	ret0, _ := ret[0].(entity.RecurringTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRecurringTransfer indicates an expected call of UpdateRecurringTransfer.
+func (mr *MockWalletServiceMockRecorder) UpdateRecurringTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecurringTransfer", reflect.TypeOf((*MockWalletService)(nil).UpdateRecurringTransfer), ctx, req)
+}
+
+// MockLedgerRepository is a mock of LedgerRepository interface.
+type MockLedgerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerRepositoryMockRecorder
+}
+
+// MockLedgerRepositoryMockRecorder is the mock recorder for MockLedgerRepository.
+type MockLedgerRepositoryMockRecorder struct {
+	mock *MockLedgerRepository
+}
+
+// NewMockLedgerRepository creates a new mock instance.
+func NewMockLedgerRepository(ctrl *gomock.Controller) *MockLedgerRepository {
+	mock := &MockLedgerRepository{ctrl: ctrl}
+	mock.recorder = &MockLedgerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedgerRepository) EXPECT() *MockLedgerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AccountBalances mocks base method.
+func (m *MockLedgerRepository) AccountBalances(ctx context.Context) ([]entity.LedgerAccountBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountBalances", ctx)
+	ret0, _ := ret[0].([]entity.LedgerAccountBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountBalances indicates an expected call of AccountBalances.
+func (mr *MockLedgerRepositoryMockRecorder) AccountBalances(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountBalances", reflect.TypeOf((*MockLedgerRepository)(nil).AccountBalances), ctx)
+}
+
+// CreatePostings mocks base method.
+func (m *MockLedgerRepository) CreatePostings(ctx context.Context, postings []entity.LedgerPosting) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePostings", ctx, postings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePostings indicates an expected call of CreatePostings.
+func (mr *MockLedgerRepositoryMockRecorder) CreatePostings(ctx, postings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePostings", reflect.TypeOf((*MockLedgerRepository)(nil).CreatePostings), ctx, postings)
 }
 
 // MockWalletEventPublisher is a mock of WalletEventPublisher interface.
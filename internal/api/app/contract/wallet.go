@@ -2,37 +2,183 @@ package contract
 
 import (
 	"context"
+	"time"
 
 	"github.com/buni/wallet/internal/api/app/entity"
 	"github.com/buni/wallet/internal/api/app/request"
+	"github.com/shopspring/decimal"
 )
 
 //go:generate mockgen -source=wallet.go -destination=mock/wallet_mocks.go -package contract_mock
 
 type WalletRepository interface {
 	Get(ctx context.Context, id string) (entity.Wallet, error)
+	// GetForShare returns the wallet and share locks it until the end of the transaction, transfers use it so a status change waits for them.
+	GetForShare(ctx context.Context, id string) (entity.Wallet, error)
+	// GetForUpdate returns the wallet and locks it until the end of the transaction.
+	GetForUpdate(ctx context.Context, id string) (entity.Wallet, error)
 	Create(ctx context.Context, wallet entity.Wallet) (entity.Wallet, error)
+	UpdateStatus(ctx context.Context, wallet entity.Wallet) (entity.Wallet, error)
+	UpdateTier(ctx context.Context, wallet entity.Wallet) (entity.Wallet, error)
+	GetByReferenceID(ctx context.Context, referenceID string) (entity.Wallet, error)
+	// List returns the wallets matching the filter, newest first.
+	List(ctx context.Context, filter entity.WalletFilter) ([]entity.Wallet, error)
+	// ListIDs returns up to limit wallet ids greater than afterID in ascending order.
+	ListIDs(ctx context.Context, afterID string, limit uint64) ([]string, error)
 }
 
 type WalletEventRepository interface {
-	Create(ctx context.Context, event entity.WalletEvent) (entity.WalletEvent, error)
+	// Create appends the event to the wallet stream, expectedVersion is the stream version the caller based its decision on.
+	// If another event was appended in the meantime entity.ErrStreamVersionConflict is returned.
+	Create(ctx context.Context, event entity.WalletEvent, expectedVersion int64) (entity.WalletEvent, error)
 	ListByWalletID(ctx context.Context, walletID string) ([]entity.WalletEvent, error)
+	// ListByWalletIDAfter returns the events appended after the given stream version.
+	ListByWalletIDAfter(ctx context.Context, walletID string, afterSequence int64) ([]entity.WalletEvent, error)
+	// ListByWalletIDSince returns the events created at or after since, oldest first.
+	ListByWalletIDSince(ctx context.Context, walletID string, since time.Time) ([]entity.WalletEvent, error)
+	GetStreamVersion(ctx context.Context, walletID string) (int64, error)
+	List(ctx context.Context, filter entity.WalletEventFilter) ([]entity.WalletEvent, error)
+	ListByTransferID(ctx context.Context, walletID, transferID string) ([]entity.WalletEvent, error)
 }
 
 type WalletProjectionRepository interface {
 	Get(ctx context.Context, walletID string) (entity.WalletProjection, error)
+	// GetForUpdate returns the projection and locks it until the end of the transaction.
+	GetForUpdate(ctx context.Context, walletID string) (entity.WalletProjection, error)
+	// ListByWalletIDs returns the projections of the given wallets, the wallets without one are left out.
+	ListByWalletIDs(ctx context.Context, walletIDs []string) ([]entity.WalletProjection, error)
 	Create(ctx context.Context, projection entity.WalletProjection) (entity.WalletProjection, error)
 	Update(ctx context.Context, projection entity.WalletProjection) (entity.WalletProjection, error)
 }
 
+type WalletSnapshotRepository interface {
+	Create(ctx context.Context, snapshot entity.WalletSnapshot) (entity.WalletSnapshot, error)
+	GetLatest(ctx context.Context, walletID string) (entity.WalletSnapshot, error)
+}
+
+type WalletTransferRepository interface {
+	Get(ctx context.Context, walletID, transferID string) (entity.WalletTransfer, error)
+	List(ctx context.Context, filter entity.WalletTransferFilter) ([]entity.WalletTransfer, error)
+	// Upsert stores the transfer state, a state built from an older point of the stream doesn't override a newer one.
+	Upsert(ctx context.Context, transfer entity.WalletTransfer) error
+	// ListExpired returns up to limit pending transfers whose expiry is at or before the given time, the ones that expired first come first.
+	ListExpired(ctx context.Context, before time.Time, limit uint64) ([]entity.WalletTransfer, error)
+}
+
+type ScheduledTransferRepository interface {
+	Create(ctx context.Context, transfer entity.ScheduledTransfer) (entity.ScheduledTransfer, error)
+	// GetForUpdate returns the scheduled transfer and locks it until the end of the transaction.
+	GetForUpdate(ctx context.Context, walletID, transferID string) (entity.ScheduledTransfer, error)
+	List(ctx context.Context, filter entity.ScheduledTransferFilter) ([]entity.ScheduledTransfer, error)
+	// ListDue returns up to limit scheduled transfers whose execution time is at or before the given time, the ones due first come first.
+	ListDue(ctx context.Context, before time.Time, limit uint64) ([]entity.ScheduledTransfer, error)
+	// Update stores the status, event id and failure reason of the scheduled transfer.
+	Update(ctx context.Context, transfer entity.ScheduledTransfer) error
+}
+
+type RecurringTransferRepository interface {
+	Create(ctx context.Context, transfer entity.RecurringTransfer) (entity.RecurringTransfer, error)
+	Get(ctx context.Context, walletID, recurringID string) (entity.RecurringTransfer, error)
+	// GetForUpdate returns the recurring transfer and locks it until the end of the transaction.
+	GetForUpdate(ctx context.Context, walletID, recurringID string) (entity.RecurringTransfer, error)
+	List(ctx context.Context, filter entity.RecurringTransferFilter) ([]entity.RecurringTransfer, error)
+	// ListDue returns up to limit active recurring transfers whose next occurrence is at or before the given time, the ones due first come first.
+	ListDue(ctx context.Context, before time.Time, limit uint64) ([]entity.RecurringTransfer, error)
+	Update(ctx context.Context, transfer entity.RecurringTransfer) error
+	Delete(ctx context.Context, walletID, recurringID string) error
+}
+
+type InternalTransferRepository interface {
+	Get(ctx context.Context, transferID string) (entity.InternalTransfer, error)
+	Create(ctx context.Context, transfer entity.InternalTransfer) (entity.InternalTransfer, error)
+}
+
+type RefundRepository interface {
+	Create(ctx context.Context, refund entity.TransferRefund) (entity.TransferRefund, error)
+	// ListByTransferID returns the refunds of the transfer, oldest first.
+	ListByTransferID(ctx context.Context, walletID, transferID string) ([]entity.TransferRefund, error)
+}
+
+type FXQuoteRepository interface {
+	Create(ctx context.Context, quote entity.FXQuote) (entity.FXQuote, error)
+	Get(ctx context.Context, quoteID string) (entity.FXQuote, error)
+	// GetForUpdate returns the quote and locks it until the end of the transaction.
+	GetForUpdate(ctx context.Context, quoteID string) (entity.FXQuote, error)
+	// Update stores the status, transfer id and reference id of the quote.
+	Update(ctx context.Context, quote entity.FXQuote) error
+}
+
+// FXRateProvider is the source of the rates fx quotes are created with.
+type FXRateProvider interface {
+	// Rate returns the rate of converting an amount in from into to, entity.ErrFXRateNotFound if the pair isn't known.
+	Rate(ctx context.Context, from, to entity.Currency) (decimal.Decimal, error)
+}
+
 type WalletService interface {
 	Create(ctx context.Context, req *request.CreateWallet) (entity.Wallet, error)
 	Get(ctx context.Context, req *request.GetWallet) (entity.WalletBalanceProjection, error)
+	GetByReferenceID(ctx context.Context, req *request.GetWalletByReference) (entity.WalletBalanceProjection, error)
+	List(ctx context.Context, req *request.ListWallets) (entity.WalletPage, error)
+	// GetBalance replays the wallet events up to req.AsOf, a timestamp or an event id.
+	GetBalance(ctx context.Context, req *request.GetBalance) (entity.WalletProjection, error)
 	DebitTransfer(ctx context.Context, req *request.DebitTransfer) (entity.WalletEvent, error)
 	CreditTransfer(ctx context.Context, req *request.CreditTransfer) (entity.WalletEvent, error)
 	CompleteTransfer(ctx context.Context, req *request.CompleteTransfer) (entity.WalletEvent, error)
 	RevertTransfer(ctx context.Context, req *request.RevertTransfer) (entity.WalletEvent, error)
+	// RefundTransfer writes a completed transfer in the opposite direction of the completed req.TransferID,
+	// for the given amount or whatever is left to refund.
+	RefundTransfer(ctx context.Context, req *request.RefundTransfer) (entity.TransferRefund, error)
+	// BatchTransfers runs the items in order, an atomic batch returns an *entity.BatchItemError for the first failed item,
+	// otherwise there's a result for every item.
+	BatchTransfers(ctx context.Context, req *request.BatchTransfers) ([]entity.BatchTransferResult, error)
+	// ScheduleDebitTransfer and ScheduleCreditTransfer queue the transfer to be executed at req.ExecuteAt.
+	ScheduleDebitTransfer(ctx context.Context, req *request.DebitTransfer) (entity.ScheduledTransfer, error)
+	ScheduleCreditTransfer(ctx context.Context, req *request.CreditTransfer) (entity.ScheduledTransfer, error)
+	ListScheduledTransfers(ctx context.Context, req *request.ListScheduledTransfers) ([]entity.ScheduledTransfer, error)
+	CancelScheduledTransfer(ctx context.Context, req *request.CancelScheduledTransfer) (entity.ScheduledTransfer, error)
+	// ExecuteScheduledTransfers executes up to limit scheduled transfers due at or before now and returns the executed and failed ones.
+	ExecuteScheduledTransfers(ctx context.Context, now time.Time, limit uint64) ([]entity.ScheduledTransfer, error)
+	CreateRecurringTransfer(ctx context.Context, req *request.CreateRecurringTransfer) (entity.RecurringTransfer, error)
+	GetRecurringTransfer(ctx context.Context, req *request.GetRecurringTransfer) (entity.RecurringTransfer, error)
+	ListRecurringTransfers(ctx context.Context, req *request.ListRecurringTransfers) ([]entity.RecurringTransfer, error)
+	UpdateRecurringTransfer(ctx context.Context, req *request.UpdateRecurringTransfer) (entity.RecurringTransfer, error)
+	DeleteRecurringTransfer(ctx context.Context, req *request.DeleteRecurringTransfer) error
+	// ExecuteRecurringTransfers writes the occurrences of up to limit recurring transfers due at or before now and returns the advanced ones.
+	ExecuteRecurringTransfers(ctx context.Context, now time.Time, limit uint64) ([]entity.RecurringTransfer, error)
+	Freeze(ctx context.Context, req *request.FreezeWallet) (entity.WalletBalanceProjection, error)
+	Unfreeze(ctx context.Context, req *request.UnfreezeWallet) (entity.WalletBalanceProjection, error)
+	Close(ctx context.Context, req *request.CloseWallet) (entity.WalletBalanceProjection, error)
+	SetCreditLimit(ctx context.Context, req *request.SetCreditLimit) (entity.WalletEvent, error)
+	SetTier(ctx context.Context, req *request.SetWalletTier) (entity.WalletBalanceProjection, error)
+	ListEvents(ctx context.Context, req *request.ListWalletEvents) (entity.WalletEventPage, error)
+	// GetStatement replays the wallet events to list the transfers created in the statement period with their running balance.
+	GetStatement(ctx context.Context, req *request.GetStatement) (entity.Statement, error)
+	GetTransfer(ctx context.Context, req *request.GetTransfer) (entity.WalletTransferRefunds, error)
+	ListTransfers(ctx context.Context, req *request.ListTransfers) ([]entity.WalletTransfer, error)
+	InternalTransfer(ctx context.Context, req *request.InternalTransfer) (entity.InternalTransferEvents, error)
+	CompleteInternalTransfer(ctx context.Context, req *request.CompleteInternalTransfer) (entity.InternalTransferEvents, error)
+	RevertInternalTransfer(ctx context.Context, req *request.RevertInternalTransfer) (entity.InternalTransferEvents, error)
+	// CreateFXQuote locks in the current rate between the currencies of the two wallets for converting req.Amount.
+	CreateFXQuote(ctx context.Context, req *request.CreateFXQuote) (entity.FXQuote, error)
+	GetFXQuote(ctx context.Context, req *request.GetFXQuote) (entity.FXQuote, error)
+	// ExecuteFXQuote writes the credit in the source currency and the debit in the target currency of an open quote.
+	ExecuteFXQuote(ctx context.Context, req *request.ExecuteFXQuote) (entity.FXQuoteEvents, error)
 	RebuildWalletProjection(ctx context.Context, event *entity.WalletEvent) (entity.WalletProjection, error)
+	RebuildTransferProjection(ctx context.Context, event *entity.WalletEvent) (entity.WalletTransfer, error)
+	// PostLedgerEntries stores the journal entries of the event, posting the same event again is a no-op.
+	PostLedgerEntries(ctx context.Context, event *entity.WalletEvent) ([]entity.LedgerPosting, error)
+	TrialBalance(ctx context.Context) (entity.TrialBalance, error)
+	// ExpireTransfers reverts up to limit pending transfers that expired at or before now and returns the written status events.
+	ExpireTransfers(ctx context.Context, now time.Time, limit uint64) ([]entity.WalletEvent, error)
+	// ReconcileProjections replays the events of a page of wallets and compares the result with their stored projections.
+	ReconcileProjections(ctx context.Context, opts entity.ReconcileOptions) (entity.ReconcileResult, error)
+}
+
+type LedgerRepository interface {
+	// CreatePostings stores the postings, the ones already stored for the same event and account are skipped.
+	CreatePostings(ctx context.Context, postings []entity.LedgerPosting) error
+	// AccountBalances sums up the postings per account and currency, the wallet accounts are summed up into a single line.
+	AccountBalances(ctx context.Context) ([]entity.LedgerAccountBalance, error)
 }
 
 type WalletEventPublisher interface {
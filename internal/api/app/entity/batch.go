@@ -0,0 +1,31 @@
+package entity
+
+import "fmt"
+
+// Operations a batch item can run, each one maps to the single transfer route of the same name.
+const (
+	BatchOperationDebit    = "debit"
+	BatchOperationCredit   = "credit"
+	BatchOperationComplete = "complete"
+	BatchOperationRevert   = "revert"
+)
+
+// BatchTransferResult is the outcome of a single batch item, Err is set if the item failed.
+type BatchTransferResult struct {
+	Event WalletEvent
+	Err   error
+}
+
+// BatchItemError is returned when an item of an all-or-nothing batch fails, none of the batch items are written.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("batch item %d: %s", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
@@ -3,10 +3,50 @@ package entity
 import "errors"
 
 var (
-	ErrUnsupportedEventVersion = errors.New("unsupported event version")
-	ErrUnsupportedEventType    = errors.New("unsupported event type")
-	ErrInvalidEventType        = errors.New("invalid event type")
-	ErrEntityNotFound          = errors.New("entity not found")
-	ErrNegativeAmount          = errors.New("negative amount")
-	ErrInsufficientBalance     = errors.New("insufficient balance")
+	ErrUnsupportedEventVersion  = errors.New("unsupported event version")
+	ErrUnsupportedEventType     = errors.New("unsupported event type")
+	ErrInvalidEventType         = errors.New("invalid event type")
+	ErrEntityNotFound           = errors.New("entity not found")
+	ErrNegativeAmount           = errors.New("negative amount")
+	ErrInsufficientBalance      = errors.New("insufficient balance")
+	ErrSameWalletTransfer       = errors.New("source and destination wallet must differ")
+	ErrStreamVersionConflict    = errors.New("wallet event stream version conflict")
+	ErrTransferNotFound         = errors.New("transfer not found")
+	ErrTransferAlreadyCompleted = errors.New("transfer already completed")
+	ErrTransferAlreadyFailed    = errors.New("transfer already failed")
+	ErrUnsupportedCurrency      = errors.New("unsupported currency")
+	ErrCurrencyMismatch         = errors.New("currency doesn't match the wallet currency")
+	ErrInvalidAmountScale       = errors.New("amount has more decimal places than the currency allows")
+	ErrInvalidTransferExpiry    = errors.New("expiry must be in the future and can only be set on pending transfers")
+	ErrTransferExpired          = errors.New("transfer expired")
+	ErrInvalidCaptureAmount     = errors.New("capture amount must be positive and can't exceed the pending amount")
+	ErrWalletFrozen             = errors.New("wallet is frozen")
+	ErrWalletClosed             = errors.New("wallet is closed")
+	ErrWalletNotEmpty           = errors.New("wallet must have a zero balance and no pending transfers to be closed")
+	ErrInvalidCreditLimit       = errors.New("invalid credit limit")
+	ErrLimitExceeded            = errors.New("limit exceeded")
+	ErrInvalidVelocityLimit     = errors.New("invalid velocity limit")
+	ErrUnknownWalletTier        = errors.New("unknown wallet tier")
+	ErrInvalidBalanceAsOf       = errors.New("as of must be an RFC3339 timestamp or an event id of the wallet")
+	ErrInvalidBatchSize         = errors.New("invalid batch size")
+	ErrInvalidBatchOperation    = errors.New("invalid batch operation")
+	ErrInvalidFeeSchedule       = errors.New("invalid fee schedule")
+	ErrInvalidFeeWallet         = errors.New("invalid fee wallet")
+	ErrInvalidExecuteAt         = errors.New("execute at must be in the future and before the transfer expiry")
+	ErrTransferAlreadyExists    = errors.New("transfer already exists")
+	ErrTransferNotScheduled     = errors.New("transfer is no longer scheduled")
+	ErrInvalidRecurrenceRule    = errors.New("invalid recurrence rule")
+	ErrInvalidRecurrenceStart   = errors.New("recurrence start can't be in the past")
+	ErrInvalidRecurrenceEnd     = errors.New("recurrence end must be after the first occurrence")
+	ErrRecurrenceFinished       = errors.New("recurring transfer is finished")
+	ErrInvalidRecurringStatus   = errors.New("recurring transfer status must be active or paused")
+	ErrTransferNotRefundable    = errors.New("only completed debit and credit transfers can be refunded")
+	ErrInvalidRefundAmount      = errors.New("refund amount must be positive and can't exceed the amount left to refund")
+	ErrInvalidFXRate            = errors.New("invalid fx rate")
+	ErrFXRateNotFound           = errors.New("no fx rate for the currency pair")
+	ErrFXSameCurrency           = errors.New("fx quotes convert between wallets of different currencies")
+	ErrInvalidFXAmount          = errors.New("fx amount must be positive and convert to at least one minor unit of the target currency")
+	ErrFXQuoteExpired           = errors.New("fx quote expired")
+	ErrFXQuoteExecuted          = errors.New("fx quote already executed")
+	ErrInvalidStatementPeriod   = errors.New("statement period must end after it starts")
 )
@@ -0,0 +1,179 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// feeTransferSuffix links the fee transfer to the credit it was charged on.
+const feeTransferSuffix = ":fee"
+
+// FeeTransferID returns the id of the transfer moving the fee of the credit transfer to the fee wallet.
+func FeeTransferID(transferID string) string {
+	return transferID + feeTransferSuffix
+}
+
+// IsFeeTransferID reports if the transfer moves the fee of another transfer.
+func IsFeeTransferID(transferID string) bool {
+	return strings.HasSuffix(transferID, feeTransferSuffix)
+}
+
+// FeeSchedule is the fee charged on credits (outgoing transfers), a flat amount plus a percentage of the credited amount
+// kept between Min and Max, a zero Max means the fee isn't capped.
+type FeeSchedule struct {
+	Flat    decimal.Decimal
+	Percent decimal.Decimal
+	Min     decimal.Decimal
+	Max     decimal.Decimal
+}
+
+// FeeSchedules are the fee schedules keyed by wallet id or tier name, a wallet id takes precedence over the wallet tier.
+type FeeSchedules map[string]FeeSchedule
+
+func (f FeeSchedule) Validate() error {
+	if f.Flat.IsNegative() || f.Percent.IsNegative() || f.Min.IsNegative() || f.Max.IsNegative() {
+		return fmt.Errorf("%w: amounts can't be negative", ErrInvalidFeeSchedule)
+	}
+
+	if f.Max.IsPositive() && f.Max.LessThan(f.Min) {
+		return fmt.Errorf("%w: max can't be less than min", ErrInvalidFeeSchedule)
+	}
+
+	return nil
+}
+
+// Fee returns the fee for crediting amount, rounded to the currency minor units.
+func (f FeeSchedule) Fee(amount decimal.Decimal, currency Currency) decimal.Decimal {
+	fee := f.Flat.Add(amount.Mul(f.Percent).Div(decimal.NewFromInt(100)))
+	fee = decimal.Max(fee, f.Min)
+
+	if f.Max.IsPositive() {
+		fee = decimal.Min(fee, f.Max)
+	}
+
+	return currency.Round(fee)
+}
+
+// For returns the fee schedule of the wallet, false if the wallet isn't charged fees.
+func (f FeeSchedules) For(wallet Wallet) (FeeSchedule, bool) {
+	if schedule, ok := f[wallet.ID]; ok {
+		return schedule, true
+	}
+
+	schedule, ok := f[wallet.Tier]
+	return schedule, ok
+}
+
+// ParseFeeSchedules parses a comma separated list of key:flat:percent:min:max fee schedules where the key is a wallet id or a tier name,
+// e.g. "standard:0.30:1.5:0.50:10,premium:0:0.5:0:0". An empty amount is 0 and an empty spec means no fees.
+func ParseFeeSchedules(spec string) (FeeSchedules, error) {
+	schedules := FeeSchedules{}
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, ":")
+		if len(parts) != 5 || parts[0] == "" {
+			return nil, fmt.Errorf("%w: %q must be key:flat:percent:min:max", ErrInvalidFeeSchedule, item)
+		}
+
+		amounts := make([]decimal.Decimal, 4)
+		for i, part := range parts[1:] {
+			if part == "" {
+				continue
+			}
+
+			amount, err := decimal.NewFromString(part)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid amount %q", ErrInvalidFeeSchedule, part)
+			}
+
+			amounts[i] = amount
+		}
+
+		schedule := FeeSchedule{Flat: amounts[0], Percent: amounts[1], Min: amounts[2], Max: amounts[3]}
+
+		err := schedule.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		schedules[parts[0]] = schedule
+	}
+
+	return schedules, nil
+}
+
+// FeeWallets are the wallets the fees are moved to keyed by the currency they collect, every fee wallet has to hold its currency.
+type FeeWallets map[Currency]string
+
+// For returns the fee wallet collecting the fees of wallets in the currency, false if there is none.
+func (f FeeWallets) For(currency Currency) (string, bool) {
+	walletID, ok := f[currency]
+	return walletID, ok && walletID != ""
+}
+
+// Validate makes sure every currency is supported and has its own fee wallet, a wallet only holds a single currency.
+func (f FeeWallets) Validate() error {
+	currencies := make(map[string]Currency, len(f))
+
+	for currency, walletID := range f {
+		parsed, err := ParseCurrency(string(currency))
+		if err != nil || parsed != currency {
+			return fmt.Errorf("%w: unsupported currency %q", ErrInvalidFeeWallet, currency)
+		}
+
+		if walletID == "" {
+			return fmt.Errorf("%w: missing wallet of %s", ErrInvalidFeeWallet, currency)
+		}
+
+		if other, ok := currencies[walletID]; ok {
+			return fmt.Errorf("%w: wallet %s can't collect both %s and %s", ErrInvalidFeeWallet, walletID, other, currency)
+		}
+
+		currencies[walletID] = currency
+	}
+
+	return nil
+}
+
+// ParseFeeWallets parses a comma separated list of currency:wallet id fee wallets, e.g. "EUR:0190e7a4-...,USD:0190e7a5-...".
+// An empty spec means no fee wallets.
+func ParseFeeWallets(spec string) (FeeWallets, error) {
+	wallets := FeeWallets{}
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		code, walletID, ok := strings.Cut(item, ":")
+		if !ok || walletID == "" {
+			return nil, fmt.Errorf("%w: %q must be currency:wallet id", ErrInvalidFeeWallet, item)
+		}
+
+		currency, err := ParseCurrency(code)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unsupported currency %q", ErrInvalidFeeWallet, code)
+		}
+
+		if _, ok := wallets[currency]; ok {
+			return nil, fmt.Errorf("%w: %s has more than one fee wallet", ErrInvalidFeeWallet, currency)
+		}
+
+		wallets[currency] = walletID
+	}
+
+	err := wallets.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return wallets, nil
+}
@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	FXQuoteStatusInvalid FXQuoteStatus = iota
+	FXQuoteStatusOpen
+	FXQuoteStatusExecuted
+)
+
+// FXQuoteStatus is the state of an fx quote, an open quote can be executed until it expires.
+type FXQuoteStatus uint
+
+// FXPair is the currency pair of an fx rate, an amount in From times the rate is the amount in To.
+type FXPair struct {
+	From Currency
+	To   Currency
+}
+
+// FXRates are the conversion rates keyed by currency pair.
+type FXRates map[FXPair]decimal.Decimal
+
+// ParseFXRates parses a comma or newline separated list of from:to:rate rates, e.g. EUR:USD:1.0845,
+// the currency codes are case insensitive and the rate has to be positive.
+func ParseFXRates(spec string) (FXRates, error) {
+	rates := FXRates{}
+
+	for _, item := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == '\n' }) {
+		item = strings.TrimSpace(item)
+		if item == "" || strings.HasPrefix(item, "#") {
+			continue
+		}
+
+		parts := strings.Split(item, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("%w: %q must be from:to:rate", ErrInvalidFXRate, item)
+		}
+
+		from, err := ParseCurrency(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFXRate, err)
+		}
+
+		to, err := ParseCurrency(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFXRate, err)
+		}
+
+		rate, err := decimal.NewFromString(parts[2])
+		if err != nil || !rate.IsPositive() || from == to {
+			return nil, fmt.Errorf("%w: %q must convert between two currencies at a positive rate", ErrInvalidFXRate, item)
+		}
+
+		rates[FXPair{From: from, To: to}] = rate
+	}
+
+	return rates, nil
+}
+
+// Rate returns the rate of converting from into to, if only the opposite pair is known its inverse is used.
+func (r FXRates) Rate(from, to Currency) (decimal.Decimal, bool) {
+	if rate, ok := r[FXPair{From: from, To: to}]; ok {
+		return rate, true
+	}
+
+	if rate, ok := r[FXPair{From: to, To: from}]; ok {
+		return decimal.NewFromInt(1).Div(rate), true
+	}
+
+	return decimal.Zero, false
+}
+
+// FXQuote locks in the rate of converting SourceAmount from the source wallet currency into TargetAmount of the target wallet
+// currency until ExpiresAt. Executing it writes a credit on the source wallet and a debit on the target wallet sharing TransferID.
+type FXQuote struct {
+	ID             string          `db:"id"`
+	SourceWalletID string          `db:"source_wallet_id"`
+	TargetWalletID string          `db:"target_wallet_id"`
+	SourceCurrency Currency        `db:"source_currency"`
+	TargetCurrency Currency        `db:"target_currency"`
+	SourceAmount   decimal.Decimal `db:"source_amount"`
+	TargetAmount   decimal.Decimal `db:"target_amount"` // SourceAmount times Rate rounded to the target currency minor units
+	Rate           decimal.Decimal `db:"rate"`
+	Status         FXQuoteStatus   `db:"status"`
+	TransferID     string          `db:"transfer_id"` // set once the quote is executed
+	ReferenceID    string          `db:"reference_id"`
+	ExpiresAt      time.Time       `db:"expires_at"`
+	CreatedAt      time.Time       `db:"created_at"`
+	UpdatedAt      time.Time       `db:"updated_at"`
+}
+
+func NewFXQuote(source, target Wallet, amount, rate decimal.Decimal, ttl time.Duration) (FXQuote, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return FXQuote{}, fmt.Errorf("failed to generate fx quote id: %w", err)
+	}
+
+	targetAmount := target.Currency.Round(amount.Mul(rate))
+	if !targetAmount.IsPositive() {
+		return FXQuote{}, ErrInvalidFXAmount
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	return FXQuote{
+		ID:             id.String(),
+		SourceWalletID: source.ID,
+		TargetWalletID: target.ID,
+		SourceCurrency: source.Currency,
+		TargetCurrency: target.Currency,
+		SourceAmount:   amount,
+		TargetAmount:   targetAmount,
+		Rate:           rate,
+		Status:         FXQuoteStatusOpen,
+		ExpiresAt:      now.Add(ttl),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// IsExpired reports if the quote can't be executed anymore at the given time.
+func (q FXQuote) IsExpired(now time.Time) bool {
+	return !q.ExpiresAt.After(now)
+}
+
+// FXQuoteEvents is an executed fx quote together with the events written for each of its legs.
+type FXQuoteEvents struct {
+	FXQuote
+	CreditEvent WalletEvent
+	DebitEvent  WalletEvent
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// System ledger accounts, they hold the counter entries of the wallet accounts.
+const (
+	LedgerAccountFunding    = "system:funding"    // money entering (debits) or leaving (credits) the system
+	LedgerAccountFees       = "system:fees"       // fees charged on transfers
+	LedgerAccountSettlement = "system:settlement" // pending credits, the amount is held here until the transfer is completed or reverted
+	LedgerAccountFX         = "system:fx"         // currency conversions, the source currency is moved in and the target currency out
+
+	// LedgerAccountWallets is the trial balance line summing up all of the wallet accounts.
+	LedgerAccountWallets = "wallets"
+
+	walletLedgerAccountPrefix = "wallet:"
+)
+
+// WalletLedgerAccount returns the ledger account of the wallet, its balance follows the wallet balance.
+func WalletLedgerAccount(walletID string) string {
+	return walletLedgerAccountPrefix + walletID
+}
+
+// LedgerPosting is a single entry of the journal, the postings of a wallet event move the event amount between two accounts and sum up to 0.
+type LedgerPosting struct {
+	EventID   string          `db:"event_id"`
+	WalletID  string          `db:"wallet_id"` // wallet the event belongs to
+	Account   string          `db:"account"`
+	Currency  Currency        `db:"currency"`
+	Amount    decimal.Decimal `db:"amount"` // positive amounts increase the account balance, negative ones decrease it
+	CreatedAt time.Time       `db:"created_at"`
+}
+
+// NewLedgerPostings moves amount from one account to the other as a balanced pair of postings of the event.
+func NewLedgerPostings(event WalletEvent, currency Currency, from, to string, amount decimal.Decimal) []LedgerPosting {
+	return []LedgerPosting{
+		{EventID: event.ID, WalletID: event.WalletID, Account: from, Currency: currency, Amount: amount.Neg(), CreatedAt: event.CreatedAt},
+		{EventID: event.ID, WalletID: event.WalletID, Account: to, Currency: currency, Amount: amount, CreatedAt: event.CreatedAt},
+	}
+}
+
+// NewSplitLedgerPostings moves amount out of from, the captured part to the to account and the rest to the remainder account,
+// it's the same as NewLedgerPostings if everything was captured.
+func NewSplitLedgerPostings(event WalletEvent, currency Currency, from, to, remainder string, amount, captured decimal.Decimal) []LedgerPosting {
+	result := NewLedgerPostings(event, currency, from, to, captured)
+	if captured.Equal(amount) {
+		return result
+	}
+
+	result[0].Amount = amount.Neg()
+
+	return append(result, LedgerPosting{EventID: event.ID, WalletID: event.WalletID, Account: remainder, Currency: currency, Amount: amount.Sub(captured), CreatedAt: event.CreatedAt})
+}
+
+// LedgerAccountBalance is the sum of the postings of an account in a single currency.
+type LedgerAccountBalance struct {
+	Account  string          `db:"account"`
+	Currency Currency        `db:"currency"`
+	Balance  decimal.Decimal `db:"balance"`
+}
+
+// LedgerCurrencyTotal is the sum of all the postings in a currency, it is 0 if the books balance.
+type LedgerCurrencyTotal struct {
+	Currency Currency
+	Total    decimal.Decimal
+}
+
+type TrialBalance struct {
+	Accounts []LedgerAccountBalance
+	Totals   []LedgerCurrencyTotal
+}
+
+// NewTrialBalance sums up the account balances per currency.
+func NewTrialBalance(accounts []LedgerAccountBalance) TrialBalance {
+	totals := map[Currency]decimal.Decimal{}
+	for _, account := range accounts {
+		totals[account.Currency] = totals[account.Currency].Add(account.Balance)
+	}
+
+	result := TrialBalance{
+		Accounts: accounts,
+		Totals:   make([]LedgerCurrencyTotal, 0, len(totals)),
+	}
+
+	for currency, total := range totals {
+		result.Totals = append(result.Totals, LedgerCurrencyTotal{Currency: currency, Total: total})
+	}
+
+	slices.SortFunc(result.Totals, func(a, b LedgerCurrencyTotal) int {
+		return cmp.Compare(a.Currency, b.Currency)
+	})
+
+	return result
+}
+
+// Balanced reports if the postings of every currency sum up to 0.
+func (t TrialBalance) Balanced() bool {
+	for _, total := range t.Totals {
+		if !total.Total.IsZero() {
+			return false
+		}
+	}
+	return true
+}
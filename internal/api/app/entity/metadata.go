@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Metadata is a set of key value pairs the callers attach to wallets and transfers (e.g. an order id or a sales channel),
+// it isn't interpreted by the service. It's stored as a jsonb object, an empty metadata is nil in memory and {} in the database.
+type Metadata map[string]string
+
+// Value encodes the metadata as a json object, nil is encoded as {} so the column never holds NULL.
+func (m Metadata) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return "{}", nil
+	}
+
+	data, err := json.Marshal(map[string]string(m))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// Scan decodes a json object, an empty one is scanned as nil.
+func (m *Metadata) Scan(src any) error {
+	var data []byte
+
+	switch src := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case string:
+		data = []byte(src)
+	case []byte:
+		data = src
+	default:
+		return fmt.Errorf("unsupported metadata source type %T", src)
+	}
+
+	var result map[string]string
+
+	err := json.Unmarshal(data, &result)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+
+	if len(result) == 0 {
+		result = nil
+	}
+
+	*m = result
+
+	return nil
+}
+
+// ParseMetadataFilter turns key:value pairs into the metadata a row has to contain, the pairs are split on the first colon.
+func ParseMetadataFilter(pairs []string) Metadata {
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	result := make(Metadata, len(pairs))
+	for _, pair := range pairs {
+		key, value, _ := strings.Cut(pair, ":")
+		result[key] = value
+	}
+
+	return result
+}
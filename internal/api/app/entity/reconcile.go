@@ -0,0 +1,57 @@
+package entity
+
+import "time"
+
+// ReconcileOptions selects the wallets a reconciliation run checks and what it does with the mismatches it finds.
+type ReconcileOptions struct {
+	AfterWalletID string // only wallets with a greater id are checked, empty starts from the first wallet
+	Limit         uint64
+	// SettledBefore skips projections that are only behind events created after it, those are most likely still being processed.
+	SettledBefore time.Time
+	Repair        bool // overwrite the mismatched projections with the replayed state
+}
+
+// ProjectionMismatch is a stored wallet projection that doesn't match the replay of the wallet events.
+type ProjectionMismatch struct {
+	WalletID string
+	Fields   []string // the differing projection fields, see DiffProjections
+	Stored   WalletProjection
+	Replayed WalletProjection
+	Repaired bool
+}
+
+// ReconcileResult is the outcome of checking a single page of wallets.
+type ReconcileResult struct {
+	Checked    int
+	Mismatches []ProjectionMismatch
+	// LastWalletID is passed as the AfterWalletID of the next run, empty once there are no more wallets to check.
+	LastWalletID string
+}
+
+// DiffProjections returns the names of the fields in which the stored projection differs from the replayed one.
+// The last event id isn't compared, it follows the last sequence and a wallet without events has its own id there.
+func DiffProjections(stored, replayed WalletProjection) []string {
+	var fields []string
+
+	if !stored.Balance.Equal(replayed.Balance) {
+		fields = append(fields, "balance")
+	}
+
+	if !stored.PendingDebit.Equal(replayed.PendingDebit) {
+		fields = append(fields, "pending_debit")
+	}
+
+	if !stored.PendingCredit.Equal(replayed.PendingCredit) {
+		fields = append(fields, "pending_credit")
+	}
+
+	if !stored.CreditLimit.Equal(replayed.CreditLimit) {
+		fields = append(fields, "credit_limit")
+	}
+
+	if stored.LastSequence != replayed.LastSequence {
+		fields = append(fields, "last_sequence")
+	}
+
+	return fields
+}
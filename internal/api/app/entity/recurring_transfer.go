@@ -0,0 +1,260 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	RecurrenceFrequencyInvalid RecurrenceFrequency = iota
+	RecurrenceFrequencyDaily
+	RecurrenceFrequencyWeekly
+	RecurrenceFrequencyMonthly
+)
+
+const (
+	RecurringStatusInvalid RecurringStatus = iota
+	RecurringStatusActive
+	RecurringStatusPaused
+	RecurringStatusFinished // the end of the recurrence was reached, nothing is executed anymore
+)
+
+// RecurrenceFrequency is the RRULE FREQ of a recurring transfer.
+type RecurrenceFrequency uint
+
+// RecurringStatus is the state of a recurring transfer, only active ones are executed.
+type RecurringStatus uint
+
+// RecurrenceRule is the subset of the iCalendar RRULE supported by recurring transfers, e.g. "FREQ=MONTHLY;BYMONTHDAY=1".
+// MonthDay is only used with a monthly frequency, it's clamped to the last day of shorter months and 0 means the day of the start.
+type RecurrenceRule struct {
+	Frequency RecurrenceFrequency
+	Interval  int
+	MonthDay  int
+}
+
+// ParseRecurrenceRule parses a semicolon separated list of FREQ (DAILY, WEEKLY or MONTHLY, required), INTERVAL (defaults to 1)
+// and BYMONTHDAY (1-31, monthly only) parts, the keys and values are case insensitive.
+func ParseRecurrenceRule(spec string) (RecurrenceRule, error) {
+	rule := RecurrenceRule{Interval: 1}
+
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(spec), "RRULE:"), ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return RecurrenceRule{}, fmt.Errorf("%w: %q isn't a key=value pair", ErrInvalidRecurrenceRule, part)
+		}
+
+		var err error
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Frequency, err = RecurrenceFrequencyString(strings.ToLower(value))
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(value)
+		case "BYMONTHDAY":
+			rule.MonthDay, err = strconv.Atoi(value)
+		default:
+			return RecurrenceRule{}, fmt.Errorf("%w: unsupported part %q", ErrInvalidRecurrenceRule, key)
+		}
+		if err != nil {
+			return RecurrenceRule{}, fmt.Errorf("%w: invalid %s: %w", ErrInvalidRecurrenceRule, key, err)
+		}
+	}
+
+	return rule, rule.Validate()
+}
+
+func (r RecurrenceRule) Validate() error {
+	if !r.Frequency.IsARecurrenceFrequency() || r.Frequency == RecurrenceFrequencyInvalid {
+		return fmt.Errorf("%w: FREQ must be DAILY, WEEKLY or MONTHLY", ErrInvalidRecurrenceRule)
+	}
+
+	if r.Interval < 1 {
+		return fmt.Errorf("%w: INTERVAL must be positive", ErrInvalidRecurrenceRule)
+	}
+
+	if r.MonthDay != 0 && (r.Frequency != RecurrenceFrequencyMonthly || r.MonthDay < 1 || r.MonthDay > 31) {
+		return fmt.Errorf("%w: BYMONTHDAY must be between 1 and 31 and can only be used with FREQ=MONTHLY", ErrInvalidRecurrenceRule)
+	}
+
+	return nil
+}
+
+// String returns the rule in its canonical RRULE form.
+func (r RecurrenceRule) String() string {
+	result := "FREQ=" + strings.ToUpper(r.Frequency.String()) + ";INTERVAL=" + strconv.Itoa(r.Interval)
+	if r.MonthDay != 0 {
+		result += ";BYMONTHDAY=" + strconv.Itoa(r.MonthDay)
+	}
+
+	return result
+}
+
+// Occurrence returns the n-th (zero based) candidate occurrence of the rule counted from start, keeping the time of day of start.
+// For a monthly rule with a month day the first candidate can be before start, see First.
+func (r RecurrenceRule) Occurrence(start time.Time, n int) time.Time {
+	switch r.Frequency {
+	case RecurrenceFrequencyDaily:
+		return start.AddDate(0, 0, n*r.Interval)
+	case RecurrenceFrequencyWeekly:
+		return start.AddDate(0, 0, 7*n*r.Interval)
+	case RecurrenceFrequencyMonthly:
+		day := r.MonthDay
+		if day == 0 {
+			day = start.Day()
+		}
+
+		month := time.Date(start.Year(), start.Month()+time.Month(n*r.Interval), 1, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+		lastDay := month.AddDate(0, 1, -1).Day()
+
+		return month.AddDate(0, 0, min(day, lastDay)-1)
+	default:
+		return start
+	}
+}
+
+// First returns the index of the first occurrence at or after start.
+func (r RecurrenceRule) First(start time.Time) int {
+	if r.Occurrence(start, 0).Before(start) {
+		return 1
+	}
+
+	return 0
+}
+
+// RecurringTransfer is a standing order that debits or credits the wallet on every occurrence of Rule between StartAt and EndAt.
+// Every occurrence is written as a completed transfer with the id returned by RecurringTransferID, so re-running it is a no-op.
+type RecurringTransfer struct {
+	ID                string            `db:"id"`
+	WalletID          string            `db:"wallet_id"`
+	ReferenceID       string            `db:"reference_id"`
+	Direction         TransferDirection `db:"direction"`
+	Amount            decimal.Decimal   `db:"amount"`
+	Currency          string            `db:"currency"`
+	Rule              string            `db:"rule"` // canonical RRULE, see RecurrenceRule
+	StartAt           time.Time         `db:"start_at"`
+	EndAt             *time.Time        `db:"end_at"`
+	Occurrence        int               `db:"occurrence"`  // index of NextRunAt in the rule occurrences counted from StartAt
+	NextRunAt         *time.Time        `db:"next_run_at"` // nil once the recurrence is finished
+	Status            RecurringStatus   `db:"status"`
+	LastTransferID    string            `db:"last_transfer_id"`    // transfer id of the last executed occurrence
+	LastFailureReason string            `db:"last_failure_reason"` // why the last skipped occurrence was rejected, e.g. insufficient balance
+	CreatedAt         time.Time         `db:"created_at"`
+	UpdatedAt         time.Time         `db:"updated_at"`
+}
+
+func NewRecurringTransfer(
+	walletID, referenceID string,
+	direction TransferDirection,
+	amount decimal.Decimal,
+	currency string,
+	rule RecurrenceRule,
+	startAt time.Time,
+	endAt *time.Time,
+) (RecurringTransfer, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return RecurringTransfer{}, fmt.Errorf("failed to generate recurring transfer id: %w", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	result := RecurringTransfer{
+		ID:          id.String(),
+		WalletID:    walletID,
+		ReferenceID: referenceID,
+		Direction:   direction,
+		Amount:      amount,
+		Currency:    currency,
+		Status:      RecurringStatusActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	err = result.Reschedule(rule, startAt, endAt)
+	if err != nil {
+		return RecurringTransfer{}, err
+	}
+
+	return result, nil
+}
+
+// Reschedule restarts the recurrence from startAt, the first occurrence has to be before endAt.
+func (t *RecurringTransfer) Reschedule(rule RecurrenceRule, startAt time.Time, endAt *time.Time) error {
+	startAt = startAt.UTC().Truncate(time.Microsecond)
+
+	t.Rule = rule.String()
+	t.StartAt = startAt
+	t.Occurrence = rule.First(startAt)
+
+	next := rule.Occurrence(startAt, t.Occurrence)
+	t.NextRunAt = &next
+
+	return t.SetEnd(endAt)
+}
+
+// SetEnd changes the end of the recurrence, the next occurrence has to be before it.
+func (t *RecurringTransfer) SetEnd(endAt *time.Time) error {
+	if endAt != nil {
+		end := endAt.UTC().Truncate(time.Microsecond)
+		endAt = &end
+	}
+
+	if endAt != nil && t.NextRunAt != nil && t.NextRunAt.After(*endAt) {
+		return ErrInvalidRecurrenceEnd
+	}
+
+	t.EndAt = endAt
+
+	return nil
+}
+
+// Advance moves NextRunAt to the following occurrence, the recurrence is finished once it's after EndAt.
+func (t *RecurringTransfer) Advance(now time.Time) error {
+	rule, err := ParseRecurrenceRule(t.Rule)
+	if err != nil {
+		return err
+	}
+
+	t.Occurrence++
+	t.UpdatedAt = now.UTC().Truncate(time.Microsecond)
+
+	next := rule.Occurrence(t.StartAt, t.Occurrence)
+	if t.EndAt != nil && next.After(*t.EndAt) {
+		t.NextRunAt = nil
+		t.Status = RecurringStatusFinished
+		return nil
+	}
+
+	t.NextRunAt = &next
+
+	return nil
+}
+
+// SkipMissed advances past the occurrences before now, e.g. the ones missed while the recurring transfer was paused.
+func (t *RecurringTransfer) SkipMissed(now time.Time) error {
+	for t.NextRunAt != nil && t.NextRunAt.Before(now) {
+		err := t.Advance(now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RecurringTransferID returns the deterministic transfer id of the occurrence of the recurring transfer at the given time.
+func RecurringTransferID(recurringID string, at time.Time) string {
+	return recurringID + ":" + at.UTC().Format("20060102T150405Z")
+}
+
+// RecurringTransferFilter narrows down the recurring transfers of a wallet, zero values are ignored.
+type RecurringTransferFilter struct {
+	WalletID string
+	Status   RecurringStatus
+}
@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// refundTransferInfix links the refund transfer to the transfer it refunds.
+const refundTransferInfix = ":refund:"
+
+// RefundTransferID returns the id of the n-th (1 based) refund of the transfer.
+func RefundTransferID(transferID string, n int) string {
+	return transferID + refundTransferInfix + strconv.Itoa(n)
+}
+
+// IsRefundTransferID reports if the transfer refunds another transfer.
+func IsRefundTransferID(transferID string) bool {
+	return strings.Contains(transferID, refundTransferInfix)
+}
+
+// TransferRefund links a refund to the completed transfer it compensates. The refund itself is a completed transfer
+// in the opposite direction written to the same wallet, a credit is refunded with a debit and the other way around.
+type TransferRefund struct {
+	RefundID    string          `db:"refund_id"` // transfer id of the refund, see RefundTransferID
+	WalletID    string          `db:"wallet_id"`
+	TransferID  string          `db:"transfer_id"` // the refunded transfer
+	ReferenceID string          `db:"reference_id"`
+	Amount      decimal.Decimal `db:"amount"`
+	EventID     string          `db:"event_id"`
+	CreatedAt   time.Time       `db:"created_at"`
+}
+
+func NewTransferRefund(event WalletEvent, transferID string) TransferRefund {
+	return TransferRefund{
+		RefundID:    event.TransferID,
+		WalletID:    event.WalletID,
+		TransferID:  transferID,
+		ReferenceID: event.ReferenceID,
+		Amount:      event.Amount,
+		EventID:     event.ID,
+		CreatedAt:   time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+// WalletTransferRefunds is a wallet transfer together with its refunds, oldest first.
+type WalletTransferRefunds struct {
+	WalletTransfer
+	Refunds []TransferRefund
+}
+
+// RefundedAmount returns how much of the transfer was refunded so far.
+func (t WalletTransferRefunds) RefundedAmount() decimal.Decimal {
+	result := decimal.Zero
+	for _, refund := range t.Refunds {
+		result = result.Add(refund.Amount)
+	}
+
+	return result
+}
+
+// RefundableAmount returns how much of the transfer can still be refunded, only completed transfers can be refunded.
+func (t WalletTransferRefunds) RefundableAmount() decimal.Decimal {
+	if t.Status != TransferStatusCompleted {
+		return decimal.Zero
+	}
+
+	return t.CapturedAmount.Sub(t.RefundedAmount())
+}
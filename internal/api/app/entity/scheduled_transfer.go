@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ScheduleStatusInvalid ScheduleStatus = iota
+	ScheduleStatusScheduled
+	ScheduleStatusExecuted
+	ScheduleStatusFailed
+	ScheduleStatusCancelled
+)
+
+// ScheduleStatus is the state of a scheduled transfer, only scheduled ones are executed or can be cancelled.
+type ScheduleStatus uint
+
+// ScheduledTransfer is a debit or credit queued to be executed at ExecuteAt, the wallet balance is only checked when it is executed.
+type ScheduledTransfer struct {
+	ID             string            `db:"id"`
+	WalletID       string            `db:"wallet_id"`
+	TransferID     string            `db:"transfer_id"`
+	ReferenceID    string            `db:"reference_id"`
+	Direction      TransferDirection `db:"direction"`
+	Amount         decimal.Decimal   `db:"amount"`
+	Currency       string            `db:"currency"`
+	TransferStatus TransferStatus    `db:"transfer_status"` // status the transfer is opened with
+	ExpiresAt      *time.Time        `db:"expires_at"`
+	ExecuteAt      time.Time         `db:"execute_at"`
+	Status         ScheduleStatus    `db:"status"`
+	EventID        *string           `db:"event_id"`       // the debit/credit event written on execution
+	FailureReason  string            `db:"failure_reason"` // why the execution failed, e.g. insufficient balance
+	Metadata       Metadata          `db:"metadata"`       // passed on to the transfer when it's executed
+	CreatedAt      time.Time         `db:"created_at"`
+	UpdatedAt      time.Time         `db:"updated_at"`
+}
+
+func NewScheduledTransfer(
+	walletID, transferID, referenceID string,
+	direction TransferDirection,
+	amount decimal.Decimal,
+	currency string,
+	transferStatus TransferStatus,
+	expiresAt *time.Time,
+	executeAt time.Time,
+) (ScheduledTransfer, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return ScheduledTransfer{}, fmt.Errorf("failed to generate scheduled transfer id: %w", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	return ScheduledTransfer{
+		ID:             id.String(),
+		WalletID:       walletID,
+		TransferID:     transferID,
+		ReferenceID:    referenceID,
+		Direction:      direction,
+		Amount:         amount,
+		Currency:       currency,
+		TransferStatus: transferStatus,
+		ExpiresAt:      expiresAt,
+		ExecuteAt:      executeAt.UTC().Truncate(time.Microsecond),
+		Status:         ScheduleStatusScheduled,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// Settle moves the scheduled transfer out of the scheduled status, eventID is set once it was executed and reason once it failed.
+func (t *ScheduledTransfer) Settle(status ScheduleStatus, eventID *string, reason string, now time.Time) {
+	t.Status = status
+	t.EventID = eventID
+	t.FailureReason = reason
+	t.UpdatedAt = now.UTC().Truncate(time.Microsecond)
+}
+
+// ScheduledTransferFilter narrows down the scheduled transfers of a wallet, zero values are ignored.
+type ScheduledTransferFilter struct {
+	WalletID string
+	Status   ScheduleStatus
+}
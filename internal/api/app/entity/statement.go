@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	StatementFormatInvalid StatementFormat = iota
+	StatementFormatJSON
+	StatementFormatCSV
+	StatementFormatCamt053 // ISO 20022 bank to customer statement
+)
+
+// StatementFormat is the encoding a statement is exported in, the zero value falls back to JSON.
+type StatementFormat uint
+
+// Statement lists the transfer events of a wallet created in [From, To) together with the balance before and after them,
+// the balances are replayed from the wallet events so the opening balance plus the balance changes of the lines is the closing balance.
+type Statement struct {
+	WalletID       string
+	Currency       Currency
+	From           time.Time
+	To             time.Time
+	OpeningBalance decimal.Decimal
+	ClosingBalance decimal.Decimal
+	Lines          []StatementLine
+	CreatedAt      time.Time
+}
+
+// StatementLine is a single transfer event of a statement, oldest first.
+type StatementLine struct {
+	EventID       string
+	TransferID    string
+	ReferenceID   string
+	EventType     WalletEventType
+	Direction     TransferDirection // direction of the transfer the event belongs to, status updates inherit it
+	Status        TransferStatus
+	Amount        decimal.Decimal // as written in the event
+	BalanceChange decimal.Decimal // how much the event moved the balance, negative when money left the wallet
+	Balance       decimal.Decimal // running balance after the event
+	Sequence      int64
+	CreatedAt     time.Time
+}
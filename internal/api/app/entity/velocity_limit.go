@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// WalletTierStandard is the tier new wallets start in.
+const WalletTierStandard = "standard"
+
+const (
+	LimitKindInvalid LimitKind = iota
+	LimitKindCreditAmount
+	LimitKindCreditCount
+)
+
+// LimitKind tells what a velocity limit counts, the credited amount or the number of credit transfers.
+type LimitKind uint
+
+// VelocityLimit caps the credits of a wallet over a rolling window, e.g. at most 1000 credited per 24h.
+type VelocityLimit struct {
+	Kind   LimitKind
+	Window time.Duration
+	Max    decimal.Decimal
+}
+
+// VelocityLimits are the limits keyed by wallet id or tier name, a wallet id takes precedence over the wallet tier.
+type VelocityLimits map[string][]VelocityLimit
+
+// For returns the limits of the wallet, the ones set for the wallet id replace the ones of its tier.
+func (l VelocityLimits) For(wallet Wallet) []VelocityLimit {
+	if limits, ok := l[wallet.ID]; ok {
+		return limits
+	}
+
+	return l[wallet.Tier]
+}
+
+func (l VelocityLimit) Validate() error {
+	if !l.Kind.IsALimitKind() || l.Kind == LimitKindInvalid {
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidVelocityLimit, l.Kind)
+	}
+
+	if l.Window <= 0 {
+		return fmt.Errorf("%w: window must be positive", ErrInvalidVelocityLimit)
+	}
+
+	if !l.Max.IsPositive() || (l.Kind == LimitKindCreditCount && !l.Max.IsInteger()) {
+		return fmt.Errorf("%w: max must be positive and a whole number for counts", ErrInvalidVelocityLimit)
+	}
+
+	return nil
+}
+
+// ParseVelocityLimits parses a comma separated list of key:kind:window:max limits where the key is a wallet id or a tier name,
+// e.g. "standard:credit_amount:24h:1000,standard:credit_count:1h:10". An empty spec means no limits.
+func ParseVelocityLimits(spec string) (VelocityLimits, error) {
+	limits := VelocityLimits{}
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, ":")
+		if len(parts) != 4 || parts[0] == "" {
+			return nil, fmt.Errorf("%w: %q must be key:kind:window:max", ErrInvalidVelocityLimit, item)
+		}
+
+		kind, err := LimitKindString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidVelocityLimit, parts[1])
+		}
+
+		window, err := time.ParseDuration(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid window %q", ErrInvalidVelocityLimit, parts[2])
+		}
+
+		maxValue, err := decimal.NewFromString(parts[3])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid max %q", ErrInvalidVelocityLimit, parts[3])
+		}
+
+		limit := VelocityLimit{Kind: kind, Window: window, Max: maxValue}
+
+		err = limit.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		limits[parts[0]] = append(limits[parts[0]], limit)
+	}
+
+	return limits, nil
+}
+
+// LimitExceededError is returned when a transfer would go over one of the wallet velocity limits, it carries the limit that was hit.
+type LimitExceededError struct {
+	Limit VelocityLimit
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s: at most %s %s per %s", ErrLimitExceeded, e.Limit.Max, e.Limit.Kind, e.Limit.Window)
+}
+
+func (e *LimitExceededError) Is(target error) bool {
+	return errors.Is(ErrLimitExceeded, target)
+}
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"fmt"
+	"maps"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -18,6 +19,7 @@ const (
 	EventTypeDebitTransfer
 	EventTypeCreditTransfer
 	EventTypeUpdateTransferStatus
+	EventTypeCreditLimitChanged // not part of a transfer, the amount is the new credit limit of the wallet
 )
 
 const (
@@ -27,26 +29,50 @@ const (
 	TransferStatusFailed
 )
 
+const (
+	TransferDirectionInvalid TransferDirection = iota
+	TransferDirectionDebit
+	TransferDirectionCredit
+)
+
+const (
+	WalletStatusInvalid WalletStatus = iota
+	WalletStatusActive
+	WalletStatusFrozen
+	WalletStatusClosed
+)
+
 const (
 	WalletEventsTopic   = "wallet_events"
 	WalletEventsCreated = "created"
 )
 
-//go:generate enumer -type=WalletEventType,TransferStatus -trimprefix=EventType,TransferStatus -transform=snake -output=wallet_enum.go -json -sql -text
+//go:generate enumer -type=WalletEventType,TransferStatus,TransferDirection,WalletStatus,LimitKind,ScheduleStatus,RecurrenceFrequency,RecurringStatus,FXQuoteStatus,StatementFormat -trimprefix=EventType,TransferStatus,TransferDirection,WalletStatus,LimitKind,ScheduleStatus,RecurrenceFrequency,RecurringStatus,FXQuoteStatus,StatementFormat -transform=snake -output=wallet_enum.go -json -sql -text
 type WalletEventType uint
 
 type TransferStatus uint
 
+// TransferDirection tells if a transfer adds money to the wallet (debit) or removes it (credit).
+type TransferDirection uint
+
+// WalletStatus is the lifecycle state of a wallet, only active wallets accept new transfers.
+type WalletStatus uint
+
 type WalletEvent struct {
-	ID          string          `db:"id" json:"id"`
-	Version     int             `db:"version" json:"version"`
-	TransferID  string          `db:"transfer_id" json:"transfer_id"`
-	ReferenceID string          `db:"reference_id" json:"reference_id"`
-	WalletID    string          `db:"wallet_id" json:"wallet_id"`
-	Amount      decimal.Decimal `db:"amount" json:"amount"`
-	EventType   WalletEventType `db:"event_type" json:"event_type"`
-	Status      TransferStatus  `db:"transfer_status" json:"transfer_status"`
-	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+	ID          string           `db:"id" json:"id"`
+	Version     int              `db:"version" json:"version"`
+	TransferID  string           `db:"transfer_id" json:"transfer_id"`
+	ReferenceID string           `db:"reference_id" json:"reference_id"`
+	WalletID    string           `db:"wallet_id" json:"wallet_id"`
+	Amount      decimal.Decimal  `db:"amount" json:"amount"` // on a status update completing a transfer it's the captured amount, see CapturedAmount
+	EventType   WalletEventType  `db:"event_type" json:"event_type"`
+	Status      TransferStatus   `db:"transfer_status" json:"transfer_status"`
+	Sequence    int64            `db:"sequence" json:"sequence"`               // position of the event in the wallet stream, starts at 1
+	ExpiresAt   *time.Time       `db:"expires_at" json:"expires_at,omitempty"` // only set on pending debit/credit events, after it the transfer is reverted by the expiry job
+	Fee         decimal.Decimal  `db:"fee" json:"fee"`                         // charged on credits, moved to the fee wallet by the linked fee transfer
+	FXRate      *decimal.Decimal `db:"fx_rate" json:"fx_rate,omitempty"`       // only set on the legs of an executed fx quote, the rate the amount was converted at
+	Metadata    Metadata         `db:"metadata" json:"metadata,omitempty"`     // only set on debit/credit events, as given by the caller
+	CreatedAt   time.Time        `db:"created_at" json:"created_at"`
 }
 
 func NewWalletEvent(
@@ -73,14 +99,124 @@ func NewWalletEvent(
 	}, nil
 }
 
+// CapturedAmount returns the part of the pending amount a status update completing the transfer captures,
+// status updates without an amount (written before partial captures were supported) capture all of it.
+func (e WalletEvent) CapturedAmount(pending decimal.Decimal) decimal.Decimal {
+	if e.Amount.IsZero() {
+		return pending
+	}
+
+	return e.Amount
+}
+
+// WalletEventFilter narrows down the events of a wallet, zero values are ignored.
+// Results are ordered from newest to oldest and Cursor is the id of the last event of the previous page.
+type WalletEventFilter struct {
+	WalletID      string
+	Cursor        string
+	Limit         uint64
+	EventType     WalletEventType
+	Status        TransferStatus
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Metadata      Metadata // the event metadata has to contain all of the pairs
+}
+
+// WalletEventPage is a single page of wallet events, NextCursor is empty on the last page.
+type WalletEventPage struct {
+	Events     []WalletEvent
+	NextCursor string
+}
+
+// WalletFilter narrows down the listed wallets, zero values are ignored.
+// Results are ordered from newest to oldest and Cursor is the id of the last wallet of the previous page.
+type WalletFilter struct {
+	Cursor            string
+	Limit             uint64
+	ReferenceID       string
+	ReferenceIDPrefix string
+	CreatedAfter      time.Time
+	CreatedBefore     time.Time
+	MinBalance        *decimal.Decimal // inclusive bounds of the projection balance, nil isn't bounded
+	MaxBalance        *decimal.Decimal
+	Metadata          Metadata // the wallet metadata has to contain all of the pairs
+}
+
+// WalletPage is a single page of wallets with their projections, NextCursor is empty on the last page.
+type WalletPage struct {
+	Wallets    []WalletBalanceProjection
+	NextCursor string
+}
+
+// WalletTransfer is the current state of a single transfer in a wallet, it is a read model built from the wallet events sharing the transfer id.
+type WalletTransfer struct {
+	WalletID       string            `db:"wallet_id"`
+	TransferID     string            `db:"transfer_id"`
+	ReferenceID    string            `db:"reference_id"`
+	Amount         decimal.Decimal   `db:"amount"`
+	CapturedAmount decimal.Decimal   `db:"captured_amount"` // the amount a completed transfer moved, less than Amount after a partial capture
+	Direction      TransferDirection `db:"direction"`
+	Status         TransferStatus    `db:"transfer_status"`
+	LastSequence   int64             `db:"last_sequence"` // sequence of the last event that changed the transfer
+	ExpiresAt      *time.Time        `db:"expires_at"`    // when a still pending transfer gets reverted, nil if it never expires
+	Metadata       Metadata          `db:"metadata"`      // of the event that opened the transfer
+	CreatedAt      time.Time         `db:"created_at"`    // when the transfer was opened
+	UpdatedAt      time.Time         `db:"updated_at"`    // when the transfer status last changed
+}
+
+// IsExpired reports if the transfer has an expiry and it has passed at the given time.
+func (t WalletTransfer) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !t.ExpiresAt.After(now)
+}
+
+// WalletTransferFilter narrows down the transfers of a wallet, zero values are ignored.
+type WalletTransferFilter struct {
+	WalletID string
+	Status   TransferStatus
+	Metadata Metadata // the transfer metadata has to contain all of the pairs
+}
+
+// InternalTransfer links the two legs of a wallet to wallet transfer,
+// a credit transfer on the source wallet and a debit transfer on the destination wallet, both sharing the same transfer id.
+type InternalTransfer struct {
+	TransferID          string          `db:"transfer_id"`
+	ReferenceID         string          `db:"reference_id"`
+	SourceWalletID      string          `db:"source_wallet_id"`
+	DestinationWalletID string          `db:"destination_wallet_id"`
+	Amount              decimal.Decimal `db:"amount"`
+	CreatedAt           time.Time       `db:"created_at"`
+}
+
+func NewInternalTransfer(transferID, referenceID, sourceWalletID, destinationWalletID string, amount decimal.Decimal) InternalTransfer {
+	return InternalTransfer{
+		TransferID:          transferID,
+		ReferenceID:         referenceID,
+		SourceWalletID:      sourceWalletID,
+		DestinationWalletID: destinationWalletID,
+		Amount:              amount,
+		CreatedAt:           time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+// InternalTransferEvents is an internal transfer together with the events written for each of its legs.
+type InternalTransferEvents struct {
+	InternalTransfer
+	CreditEvent WalletEvent
+	DebitEvent  WalletEvent
+}
+
 type Wallet struct {
-	ID          string    `db:"id"`
-	ReferenceID string    `db:"reference_id"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID          string       `db:"id"`
+	ReferenceID string       `db:"reference_id"`
+	Currency    Currency     `db:"currency"`
+	Status      WalletStatus `db:"status"`
+	Tier        string       `db:"tier"` // picks the velocity limits that apply to the wallet
+	Metadata    Metadata     `db:"metadata"`
+	CreatedAt   time.Time    `db:"created_at"`
+	UpdatedAt   time.Time    `db:"updated_at"`
 }
 
-func NewWallet(referenceID string) (Wallet, error) {
+func NewWallet(referenceID string, currency Currency) (Wallet, error) {
 	id, err := uuid.NewV7()
 	if err != nil {
 		return Wallet{}, fmt.Errorf("failed to generate wallet id: %w", err)
@@ -91,6 +227,9 @@ func NewWallet(referenceID string) (Wallet, error) {
 	return Wallet{
 		ID:          id.String(),
 		ReferenceID: referenceID,
+		Currency:    currency,
+		Status:      WalletStatusActive,
+		Tier:        WalletTierStandard,
 		CreatedAt:   tt,
 		UpdatedAt:   tt,
 	}, nil
@@ -101,16 +240,28 @@ type WalletProjection struct {
 	Balance       decimal.Decimal `db:"balance"`
 	PendingDebit  decimal.Decimal `db:"pending_debit"`
 	PendingCredit decimal.Decimal `db:"pending_credit"`
+	CreditLimit   decimal.Decimal `db:"credit_limit"` // how far the balance is allowed to go below zero
 	LastEventID   string          `db:"last_event_id"`
+	LastSequence  int64           `db:"last_sequence"` // stream version the projection was built from
 	CreatedAt     time.Time       `db:"created_at"`
 	UpdatedAt     time.Time       `db:"updated_at"`
 }
 
+// AvailableBalance is the amount that can still be credited from the wallet, the balance plus the credit limit.
+func (p WalletProjection) AvailableBalance() decimal.Decimal {
+	return p.Balance.Add(p.CreditLimit)
+}
+
 type WalletBalanceProjection struct {
 	Wallet
 	WalletProjection
 }
 
+// CreatedAt is the time the wallet was created at, both embedded structs have a created at so it has to be picked explicitly.
+func (p WalletBalanceProjection) CreatedAt() time.Time {
+	return p.Wallet.CreatedAt
+}
+
 func NewWalletProjection(walletID, lastEventID string, balance, pendingDebit, pendingCredit decimal.Decimal) WalletProjection {
 	tt := time.Now().UTC().Truncate(time.Microsecond)
 
@@ -124,3 +275,52 @@ func NewWalletProjection(walletID, lastEventID string, balance, pendingDebit, pe
 		UpdatedAt:     tt,
 	}
 }
+
+// PendingTransfers maps a transfer id to the event that opened it, for transfers that haven't been completed or reverted yet.
+type PendingTransfers map[string]WalletEvent
+
+// WalletSnapshot is a wallet projection folded up to LastSequence together with the transfers that were still pending at that point,
+// applying the events after LastSequence on top of it gives the same state as replaying the whole stream.
+type WalletSnapshot struct {
+	WalletID         string           `db:"wallet_id"`
+	LastEventID      string           `db:"last_event_id"`
+	LastSequence     int64            `db:"last_sequence"`
+	Balance          decimal.Decimal  `db:"balance"`
+	PendingDebit     decimal.Decimal  `db:"pending_debit"`
+	PendingCredit    decimal.Decimal  `db:"pending_credit"`
+	CreditLimit      decimal.Decimal  `db:"credit_limit"`
+	PendingTransfers PendingTransfers `db:"pending_transfers"`
+	CreatedAt        time.Time        `db:"created_at"`
+}
+
+func NewWalletSnapshot(projection WalletProjection, pending PendingTransfers) WalletSnapshot {
+	pendingTransfers := maps.Clone(pending)
+	if pendingTransfers == nil {
+		pendingTransfers = PendingTransfers{}
+	}
+
+	return WalletSnapshot{
+		WalletID:         projection.WalletID,
+		LastEventID:      projection.LastEventID,
+		LastSequence:     projection.LastSequence,
+		Balance:          projection.Balance,
+		PendingDebit:     projection.PendingDebit,
+		PendingCredit:    projection.PendingCredit,
+		CreditLimit:      projection.CreditLimit,
+		PendingTransfers: pendingTransfers,
+		CreatedAt:        time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+// Projection returns the projection the snapshot was taken from.
+func (s WalletSnapshot) Projection() WalletProjection {
+	return WalletProjection{
+		WalletID:      s.WalletID,
+		Balance:       s.Balance,
+		PendingDebit:  s.PendingDebit,
+		PendingCredit: s.PendingCredit,
+		CreditLimit:   s.CreditLimit,
+		LastEventID:   s.LastEventID,
+		LastSequence:  s.LastSequence,
+	}
+}
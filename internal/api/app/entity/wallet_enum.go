@@ -1,4 +1,4 @@
-// Code generated by "enumer -type=WalletEventType,TransferStatus -trimprefix=EventType,TransferStatus -transform=snake -output=wallet_enum.go -json -sql -text"; DO NOT EDIT.
+// Code generated by "enumer -type=WalletEventType,TransferStatus,TransferDirection,WalletStatus,LimitKind,ScheduleStatus,RecurrenceFrequency,RecurringStatus,FXQuoteStatus,StatementFormat -trimprefix=EventType,TransferStatus,TransferDirection,WalletStatus,LimitKind,ScheduleStatus,RecurrenceFrequency,RecurringStatus,FXQuoteStatus,StatementFormat -transform=snake -output=wallet_enum.go -json -sql -text"; DO NOT EDIT.
 
 package entity
 
@@ -9,11 +9,11 @@ import (
 	"strings"
 )
 
-const _WalletEventTypeName = "invaliddebit_transfercredit_transferupdate_transfer_status"
+const _WalletEventTypeName = "invaliddebit_transfercredit_transferupdate_transfer_statuscredit_limit_changed"
 
-var _WalletEventTypeIndex = [...]uint8{0, 7, 21, 36, 58}
+var _WalletEventTypeIndex = [...]uint8{0, 7, 21, 36, 58, 78}
 
-const _WalletEventTypeLowerName = "invaliddebit_transfercredit_transferupdate_transfer_status"
+const _WalletEventTypeLowerName = "invaliddebit_transfercredit_transferupdate_transfer_statuscredit_limit_changed"
 
 func (i WalletEventType) String() string {
 	if i >= WalletEventType(len(_WalletEventTypeIndex)-1) {
@@ -30,9 +30,10 @@ func _WalletEventTypeNoOp() {
 	_ = x[EventTypeDebitTransfer-(1)]
 	_ = x[EventTypeCreditTransfer-(2)]
 	_ = x[EventTypeUpdateTransferStatus-(3)]
+	_ = x[EventTypeCreditLimitChanged-(4)]
 }
 
-var _WalletEventTypeValues = []WalletEventType{EventTypeInvalid, EventTypeDebitTransfer, EventTypeCreditTransfer, EventTypeUpdateTransferStatus}
+var _WalletEventTypeValues = []WalletEventType{EventTypeInvalid, EventTypeDebitTransfer, EventTypeCreditTransfer, EventTypeUpdateTransferStatus, EventTypeCreditLimitChanged}
 
 var _WalletEventTypeNameToValueMap = map[string]WalletEventType{
 	_WalletEventTypeName[0:7]:        EventTypeInvalid,
@@ -43,6 +44,8 @@ var _WalletEventTypeNameToValueMap = map[string]WalletEventType{
 	_WalletEventTypeLowerName[21:36]: EventTypeCreditTransfer,
 	_WalletEventTypeName[36:58]:      EventTypeUpdateTransferStatus,
 	_WalletEventTypeLowerName[36:58]: EventTypeUpdateTransferStatus,
+	_WalletEventTypeName[58:78]:      EventTypeCreditLimitChanged,
+	_WalletEventTypeLowerName[58:78]: EventTypeCreditLimitChanged,
 }
 
 var _WalletEventTypeNames = []string{
@@ -50,6 +53,7 @@ var _WalletEventTypeNames = []string{
 	_WalletEventTypeName[7:21],
 	_WalletEventTypeName[21:36],
 	_WalletEventTypeName[36:58],
+	_WalletEventTypeName[58:78],
 }
 
 // WalletEventTypeString retrieves an enum value from the enum constants string name.
@@ -282,3 +286,1091 @@ func (i *TransferStatus) Scan(value interface{}) error {
 	*i = val
 	return nil
 }
+
+const _TransferDirectionName = "invaliddebitcredit"
+
+var _TransferDirectionIndex = [...]uint8{0, 7, 12, 18}
+
+const _TransferDirectionLowerName = "invaliddebitcredit"
+
+func (i TransferDirection) String() string {
+	if i >= TransferDirection(len(_TransferDirectionIndex)-1) {
+		return fmt.Sprintf("TransferDirection(%d)", i)
+	}
+	return _TransferDirectionName[_TransferDirectionIndex[i]:_TransferDirectionIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _TransferDirectionNoOp() {
+	var x [1]struct{}
+	_ = x[TransferDirectionInvalid-(0)]
+	_ = x[TransferDirectionDebit-(1)]
+	_ = x[TransferDirectionCredit-(2)]
+}
+
+var _TransferDirectionValues = []TransferDirection{TransferDirectionInvalid, TransferDirectionDebit, TransferDirectionCredit}
+
+var _TransferDirectionNameToValueMap = map[string]TransferDirection{
+	_TransferDirectionName[0:7]:        TransferDirectionInvalid,
+	_TransferDirectionLowerName[0:7]:   TransferDirectionInvalid,
+	_TransferDirectionName[7:12]:       TransferDirectionDebit,
+	_TransferDirectionLowerName[7:12]:  TransferDirectionDebit,
+	_TransferDirectionName[12:18]:      TransferDirectionCredit,
+	_TransferDirectionLowerName[12:18]: TransferDirectionCredit,
+}
+
+var _TransferDirectionNames = []string{
+	_TransferDirectionName[0:7],
+	_TransferDirectionName[7:12],
+	_TransferDirectionName[12:18],
+}
+
+// TransferDirectionString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func TransferDirectionString(s string) (TransferDirection, error) {
+	if val, ok := _TransferDirectionNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _TransferDirectionNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to TransferDirection values", s)
+}
+
+// TransferDirectionValues returns all values of the enum
+func TransferDirectionValues() []TransferDirection {
+	return _TransferDirectionValues
+}
+
+// TransferDirectionStrings returns a slice of all String values of the enum
+func TransferDirectionStrings() []string {
+	strs := make([]string, len(_TransferDirectionNames))
+	copy(strs, _TransferDirectionNames)
+	return strs
+}
+
+// IsATransferDirection returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i TransferDirection) IsATransferDirection() bool {
+	for _, v := range _TransferDirectionValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements the json.Marshaler interface for TransferDirection
+func (i TransferDirection) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for TransferDirection
+func (i *TransferDirection) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("TransferDirection should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = TransferDirectionString(s)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for TransferDirection
+func (i TransferDirection) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for TransferDirection
+func (i *TransferDirection) UnmarshalText(text []byte) error {
+	var err error
+	*i, err = TransferDirectionString(string(text))
+	return err
+}
+
+func (i TransferDirection) Value() (driver.Value, error) {
+	return i.String(), nil
+}
+
+func (i *TransferDirection) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	var str string
+	switch v := value.(type) {
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	case fmt.Stringer:
+		str = v.String()
+	default:
+		return fmt.Errorf("invalid value of TransferDirection: %[1]T(%[1]v)", value)
+	}
+
+	val, err := TransferDirectionString(str)
+	if err != nil {
+		return err
+	}
+
+	*i = val
+	return nil
+}
+
+const _WalletStatusName = "invalidactivefrozenclosed"
+
+var _WalletStatusIndex = [...]uint8{0, 7, 13, 19, 25}
+
+const _WalletStatusLowerName = "invalidactivefrozenclosed"
+
+func (i WalletStatus) String() string {
+	if i >= WalletStatus(len(_WalletStatusIndex)-1) {
+		return fmt.Sprintf("WalletStatus(%d)", i)
+	}
+	return _WalletStatusName[_WalletStatusIndex[i]:_WalletStatusIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _WalletStatusNoOp() {
+	var x [1]struct{}
+	_ = x[WalletStatusInvalid-(0)]
+	_ = x[WalletStatusActive-(1)]
+	_ = x[WalletStatusFrozen-(2)]
+	_ = x[WalletStatusClosed-(3)]
+}
+
+var _WalletStatusValues = []WalletStatus{WalletStatusInvalid, WalletStatusActive, WalletStatusFrozen, WalletStatusClosed}
+
+var _WalletStatusNameToValueMap = map[string]WalletStatus{
+	_WalletStatusName[0:7]:        WalletStatusInvalid,
+	_WalletStatusLowerName[0:7]:   WalletStatusInvalid,
+	_WalletStatusName[7:13]:       WalletStatusActive,
+	_WalletStatusLowerName[7:13]:  WalletStatusActive,
+	_WalletStatusName[13:19]:      WalletStatusFrozen,
+	_WalletStatusLowerName[13:19]: WalletStatusFrozen,
+	_WalletStatusName[19:25]:      WalletStatusClosed,
+	_WalletStatusLowerName[19:25]: WalletStatusClosed,
+}
+
+var _WalletStatusNames = []string{
+	_WalletStatusName[0:7],
+	_WalletStatusName[7:13],
+	_WalletStatusName[13:19],
+	_WalletStatusName[19:25],
+}
+
+// WalletStatusString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func WalletStatusString(s string) (WalletStatus, error) {
+	if val, ok := _WalletStatusNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _WalletStatusNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to WalletStatus values", s)
+}
+
+// WalletStatusValues returns all values of the enum
+func WalletStatusValues() []WalletStatus {
+	return _WalletStatusValues
+}
+
+// WalletStatusStrings returns a slice of all String values of the enum
+func WalletStatusStrings() []string {
+	strs := make([]string, len(_WalletStatusNames))
+	copy(strs, _WalletStatusNames)
+	return strs
+}
+
+// IsAWalletStatus returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i WalletStatus) IsAWalletStatus() bool {
+	for _, v := range _WalletStatusValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements the json.Marshaler interface for WalletStatus
+func (i WalletStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for WalletStatus
+func (i *WalletStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("WalletStatus should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = WalletStatusString(s)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for WalletStatus
+func (i WalletStatus) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for WalletStatus
+func (i *WalletStatus) UnmarshalText(text []byte) error {
+	var err error
+	*i, err = WalletStatusString(string(text))
+	return err
+}
+
+func (i WalletStatus) Value() (driver.Value, error) {
+	return i.String(), nil
+}
+
+func (i *WalletStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	var str string
+	switch v := value.(type) {
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	case fmt.Stringer:
+		str = v.String()
+	default:
+		return fmt.Errorf("invalid value of WalletStatus: %[1]T(%[1]v)", value)
+	}
+
+	val, err := WalletStatusString(str)
+	if err != nil {
+		return err
+	}
+
+	*i = val
+	return nil
+}
+
+const _LimitKindName = "invalidcredit_amountcredit_count"
+
+var _LimitKindIndex = [...]uint8{0, 7, 20, 32}
+
+const _LimitKindLowerName = "invalidcredit_amountcredit_count"
+
+func (i LimitKind) String() string {
+	if i >= LimitKind(len(_LimitKindIndex)-1) {
+		return fmt.Sprintf("LimitKind(%d)", i)
+	}
+	return _LimitKindName[_LimitKindIndex[i]:_LimitKindIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _LimitKindNoOp() {
+	var x [1]struct{}
+	_ = x[LimitKindInvalid-(0)]
+	_ = x[LimitKindCreditAmount-(1)]
+	_ = x[LimitKindCreditCount-(2)]
+}
+
+var _LimitKindValues = []LimitKind{LimitKindInvalid, LimitKindCreditAmount, LimitKindCreditCount}
+
+var _LimitKindNameToValueMap = map[string]LimitKind{
+	_LimitKindName[0:7]:        LimitKindInvalid,
+	_LimitKindLowerName[0:7]:   LimitKindInvalid,
+	_LimitKindName[7:20]:       LimitKindCreditAmount,
+	_LimitKindLowerName[7:20]:  LimitKindCreditAmount,
+	_LimitKindName[20:32]:      LimitKindCreditCount,
+	_LimitKindLowerName[20:32]: LimitKindCreditCount,
+}
+
+var _LimitKindNames = []string{
+	_LimitKindName[0:7],
+	_LimitKindName[7:20],
+	_LimitKindName[20:32],
+}
+
+// LimitKindString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func LimitKindString(s string) (LimitKind, error) {
+	if val, ok := _LimitKindNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _LimitKindNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to LimitKind values", s)
+}
+
+// LimitKindValues returns all values of the enum
+func LimitKindValues() []LimitKind {
+	return _LimitKindValues
+}
+
+// LimitKindStrings returns a slice of all String values of the enum
+func LimitKindStrings() []string {
+	strs := make([]string, len(_LimitKindNames))
+	copy(strs, _LimitKindNames)
+	return strs
+}
+
+// IsALimitKind returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i LimitKind) IsALimitKind() bool {
+	for _, v := range _LimitKindValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements the json.Marshaler interface for LimitKind
+func (i LimitKind) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for LimitKind
+func (i *LimitKind) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("LimitKind should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = LimitKindString(s)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for LimitKind
+func (i LimitKind) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for LimitKind
+func (i *LimitKind) UnmarshalText(text []byte) error {
+	var err error
+	*i, err = LimitKindString(string(text))
+	return err
+}
+
+func (i LimitKind) Value() (driver.Value, error) {
+	return i.String(), nil
+}
+
+func (i *LimitKind) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	var str string
+	switch v := value.(type) {
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	case fmt.Stringer:
+		str = v.String()
+	default:
+		return fmt.Errorf("invalid value of LimitKind: %[1]T(%[1]v)", value)
+	}
+
+	val, err := LimitKindString(str)
+	if err != nil {
+		return err
+	}
+
+	*i = val
+	return nil
+}
+
+const _ScheduleStatusName = "invalidscheduledexecutedfailedcancelled"
+
+var _ScheduleStatusIndex = [...]uint8{0, 7, 16, 24, 30, 39}
+
+const _ScheduleStatusLowerName = "invalidscheduledexecutedfailedcancelled"
+
+func (i ScheduleStatus) String() string {
+	if i >= ScheduleStatus(len(_ScheduleStatusIndex)-1) {
+		return fmt.Sprintf("ScheduleStatus(%d)", i)
+	}
+	return _ScheduleStatusName[_ScheduleStatusIndex[i]:_ScheduleStatusIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _ScheduleStatusNoOp() {
+	var x [1]struct{}
+	_ = x[ScheduleStatusInvalid-(0)]
+	_ = x[ScheduleStatusScheduled-(1)]
+	_ = x[ScheduleStatusExecuted-(2)]
+	_ = x[ScheduleStatusFailed-(3)]
+	_ = x[ScheduleStatusCancelled-(4)]
+}
+
+var _ScheduleStatusValues = []ScheduleStatus{ScheduleStatusInvalid, ScheduleStatusScheduled, ScheduleStatusExecuted, ScheduleStatusFailed, ScheduleStatusCancelled}
+
+var _ScheduleStatusNameToValueMap = map[string]ScheduleStatus{
+	_ScheduleStatusName[0:7]:        ScheduleStatusInvalid,
+	_ScheduleStatusLowerName[0:7]:   ScheduleStatusInvalid,
+	_ScheduleStatusName[7:16]:       ScheduleStatusScheduled,
+	_ScheduleStatusLowerName[7:16]:  ScheduleStatusScheduled,
+	_ScheduleStatusName[16:24]:      ScheduleStatusExecuted,
+	_ScheduleStatusLowerName[16:24]: ScheduleStatusExecuted,
+	_ScheduleStatusName[24:30]:      ScheduleStatusFailed,
+	_ScheduleStatusLowerName[24:30]: ScheduleStatusFailed,
+	_ScheduleStatusName[30:39]:      ScheduleStatusCancelled,
+	_ScheduleStatusLowerName[30:39]: ScheduleStatusCancelled,
+}
+
+var _ScheduleStatusNames = []string{
+	_ScheduleStatusName[0:7],
+	_ScheduleStatusName[7:16],
+	_ScheduleStatusName[16:24],
+	_ScheduleStatusName[24:30],
+	_ScheduleStatusName[30:39],
+}
+
+// ScheduleStatusString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func ScheduleStatusString(s string) (ScheduleStatus, error) {
+	if val, ok := _ScheduleStatusNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _ScheduleStatusNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to ScheduleStatus values", s)
+}
+
+// ScheduleStatusValues returns all values of the enum
+func ScheduleStatusValues() []ScheduleStatus {
+	return _ScheduleStatusValues
+}
+
+// ScheduleStatusStrings returns a slice of all String values of the enum
+func ScheduleStatusStrings() []string {
+	strs := make([]string, len(_ScheduleStatusNames))
+	copy(strs, _ScheduleStatusNames)
+	return strs
+}
+
+// IsAScheduleStatus returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i ScheduleStatus) IsAScheduleStatus() bool {
+	for _, v := range _ScheduleStatusValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements the json.Marshaler interface for ScheduleStatus
+func (i ScheduleStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for ScheduleStatus
+func (i *ScheduleStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("ScheduleStatus should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = ScheduleStatusString(s)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for ScheduleStatus
+func (i ScheduleStatus) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for ScheduleStatus
+func (i *ScheduleStatus) UnmarshalText(text []byte) error {
+	var err error
+	*i, err = ScheduleStatusString(string(text))
+	return err
+}
+
+func (i ScheduleStatus) Value() (driver.Value, error) {
+	return i.String(), nil
+}
+
+func (i *ScheduleStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	var str string
+	switch v := value.(type) {
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	case fmt.Stringer:
+		str = v.String()
+	default:
+		return fmt.Errorf("invalid value of ScheduleStatus: %[1]T(%[1]v)", value)
+	}
+
+	val, err := ScheduleStatusString(str)
+	if err != nil {
+		return err
+	}
+
+	*i = val
+	return nil
+}
+
+const _RecurrenceFrequencyName = "invaliddailyweeklymonthly"
+
+var _RecurrenceFrequencyIndex = [...]uint8{0, 7, 12, 18, 25}
+
+const _RecurrenceFrequencyLowerName = "invaliddailyweeklymonthly"
+
+func (i RecurrenceFrequency) String() string {
+	if i >= RecurrenceFrequency(len(_RecurrenceFrequencyIndex)-1) {
+		return fmt.Sprintf("RecurrenceFrequency(%d)", i)
+	}
+	return _RecurrenceFrequencyName[_RecurrenceFrequencyIndex[i]:_RecurrenceFrequencyIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _RecurrenceFrequencyNoOp() {
+	var x [1]struct{}
+	_ = x[RecurrenceFrequencyInvalid-(0)]
+	_ = x[RecurrenceFrequencyDaily-(1)]
+	_ = x[RecurrenceFrequencyWeekly-(2)]
+	_ = x[RecurrenceFrequencyMonthly-(3)]
+}
+
+var _RecurrenceFrequencyValues = []RecurrenceFrequency{RecurrenceFrequencyInvalid, RecurrenceFrequencyDaily, RecurrenceFrequencyWeekly, RecurrenceFrequencyMonthly}
+
+var _RecurrenceFrequencyNameToValueMap = map[string]RecurrenceFrequency{
+	_RecurrenceFrequencyName[0:7]:        RecurrenceFrequencyInvalid,
+	_RecurrenceFrequencyLowerName[0:7]:   RecurrenceFrequencyInvalid,
+	_RecurrenceFrequencyName[7:12]:       RecurrenceFrequencyDaily,
+	_RecurrenceFrequencyLowerName[7:12]:  RecurrenceFrequencyDaily,
+	_RecurrenceFrequencyName[12:18]:      RecurrenceFrequencyWeekly,
+	_RecurrenceFrequencyLowerName[12:18]: RecurrenceFrequencyWeekly,
+	_RecurrenceFrequencyName[18:25]:      RecurrenceFrequencyMonthly,
+	_RecurrenceFrequencyLowerName[18:25]: RecurrenceFrequencyMonthly,
+}
+
+var _RecurrenceFrequencyNames = []string{
+	_RecurrenceFrequencyName[0:7],
+	_RecurrenceFrequencyName[7:12],
+	_RecurrenceFrequencyName[12:18],
+	_RecurrenceFrequencyName[18:25],
+}
+
+// RecurrenceFrequencyString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func RecurrenceFrequencyString(s string) (RecurrenceFrequency, error) {
+	if val, ok := _RecurrenceFrequencyNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _RecurrenceFrequencyNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to RecurrenceFrequency values", s)
+}
+
+// RecurrenceFrequencyValues returns all values of the enum
+func RecurrenceFrequencyValues() []RecurrenceFrequency {
+	return _RecurrenceFrequencyValues
+}
+
+// RecurrenceFrequencyStrings returns a slice of all String values of the enum
+func RecurrenceFrequencyStrings() []string {
+	strs := make([]string, len(_RecurrenceFrequencyNames))
+	copy(strs, _RecurrenceFrequencyNames)
+	return strs
+}
+
+// IsARecurrenceFrequency returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i RecurrenceFrequency) IsARecurrenceFrequency() bool {
+	for _, v := range _RecurrenceFrequencyValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements the json.Marshaler interface for RecurrenceFrequency
+func (i RecurrenceFrequency) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for RecurrenceFrequency
+func (i *RecurrenceFrequency) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("RecurrenceFrequency should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = RecurrenceFrequencyString(s)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for RecurrenceFrequency
+func (i RecurrenceFrequency) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for RecurrenceFrequency
+func (i *RecurrenceFrequency) UnmarshalText(text []byte) error {
+	var err error
+	*i, err = RecurrenceFrequencyString(string(text))
+	return err
+}
+
+func (i RecurrenceFrequency) Value() (driver.Value, error) {
+	return i.String(), nil
+}
+
+func (i *RecurrenceFrequency) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	var str string
+	switch v := value.(type) {
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	case fmt.Stringer:
+		str = v.String()
+	default:
+		return fmt.Errorf("invalid value of RecurrenceFrequency: %[1]T(%[1]v)", value)
+	}
+
+	val, err := RecurrenceFrequencyString(str)
+	if err != nil {
+		return err
+	}
+
+	*i = val
+	return nil
+}
+
+const _RecurringStatusName = "invalidactivepausedfinished"
+
+var _RecurringStatusIndex = [...]uint8{0, 7, 13, 19, 27}
+
+const _RecurringStatusLowerName = "invalidactivepausedfinished"
+
+func (i RecurringStatus) String() string {
+	if i >= RecurringStatus(len(_RecurringStatusIndex)-1) {
+		return fmt.Sprintf("RecurringStatus(%d)", i)
+	}
+	return _RecurringStatusName[_RecurringStatusIndex[i]:_RecurringStatusIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _RecurringStatusNoOp() {
+	var x [1]struct{}
+	_ = x[RecurringStatusInvalid-(0)]
+	_ = x[RecurringStatusActive-(1)]
+	_ = x[RecurringStatusPaused-(2)]
+	_ = x[RecurringStatusFinished-(3)]
+}
+
+var _RecurringStatusValues = []RecurringStatus{RecurringStatusInvalid, RecurringStatusActive, RecurringStatusPaused, RecurringStatusFinished}
+
+var _RecurringStatusNameToValueMap = map[string]RecurringStatus{
+	_RecurringStatusName[0:7]:        RecurringStatusInvalid,
+	_RecurringStatusLowerName[0:7]:   RecurringStatusInvalid,
+	_RecurringStatusName[7:13]:       RecurringStatusActive,
+	_RecurringStatusLowerName[7:13]:  RecurringStatusActive,
+	_RecurringStatusName[13:19]:      RecurringStatusPaused,
+	_RecurringStatusLowerName[13:19]: RecurringStatusPaused,
+	_RecurringStatusName[19:27]:      RecurringStatusFinished,
+	_RecurringStatusLowerName[19:27]: RecurringStatusFinished,
+}
+
+var _RecurringStatusNames = []string{
+	_RecurringStatusName[0:7],
+	_RecurringStatusName[7:13],
+	_RecurringStatusName[13:19],
+	_RecurringStatusName[19:27],
+}
+
+// RecurringStatusString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func RecurringStatusString(s string) (RecurringStatus, error) {
+	if val, ok := _RecurringStatusNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _RecurringStatusNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to RecurringStatus values", s)
+}
+
+// RecurringStatusValues returns all values of the enum
+func RecurringStatusValues() []RecurringStatus {
+	return _RecurringStatusValues
+}
+
+// RecurringStatusStrings returns a slice of all String values of the enum
+func RecurringStatusStrings() []string {
+	strs := make([]string, len(_RecurringStatusNames))
+	copy(strs, _RecurringStatusNames)
+	return strs
+}
+
+// IsARecurringStatus returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i RecurringStatus) IsARecurringStatus() bool {
+	for _, v := range _RecurringStatusValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements the json.Marshaler interface for RecurringStatus
+func (i RecurringStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for RecurringStatus
+func (i *RecurringStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("RecurringStatus should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = RecurringStatusString(s)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for RecurringStatus
+func (i RecurringStatus) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for RecurringStatus
+func (i *RecurringStatus) UnmarshalText(text []byte) error {
+	var err error
+	*i, err = RecurringStatusString(string(text))
+	return err
+}
+
+func (i RecurringStatus) Value() (driver.Value, error) {
+	return i.String(), nil
+}
+
+func (i *RecurringStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	var str string
+	switch v := value.(type) {
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	case fmt.Stringer:
+		str = v.String()
+	default:
+		return fmt.Errorf("invalid value of RecurringStatus: %[1]T(%[1]v)", value)
+	}
+
+	val, err := RecurringStatusString(str)
+	if err != nil {
+		return err
+	}
+
+	*i = val
+	return nil
+}
+
+const _FXQuoteStatusName = "invalidopenexecuted"
+
+var _FXQuoteStatusIndex = [...]uint8{0, 7, 11, 19}
+
+const _FXQuoteStatusLowerName = "invalidopenexecuted"
+
+func (i FXQuoteStatus) String() string {
+	if i >= FXQuoteStatus(len(_FXQuoteStatusIndex)-1) {
+		return fmt.Sprintf("FXQuoteStatus(%d)", i)
+	}
+	return _FXQuoteStatusName[_FXQuoteStatusIndex[i]:_FXQuoteStatusIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _FXQuoteStatusNoOp() {
+	var x [1]struct{}
+	_ = x[FXQuoteStatusInvalid-(0)]
+	_ = x[FXQuoteStatusOpen-(1)]
+	_ = x[FXQuoteStatusExecuted-(2)]
+}
+
+var _FXQuoteStatusValues = []FXQuoteStatus{FXQuoteStatusInvalid, FXQuoteStatusOpen, FXQuoteStatusExecuted}
+
+var _FXQuoteStatusNameToValueMap = map[string]FXQuoteStatus{
+	_FXQuoteStatusName[0:7]:        FXQuoteStatusInvalid,
+	_FXQuoteStatusLowerName[0:7]:   FXQuoteStatusInvalid,
+	_FXQuoteStatusName[7:11]:       FXQuoteStatusOpen,
+	_FXQuoteStatusLowerName[7:11]:  FXQuoteStatusOpen,
+	_FXQuoteStatusName[11:19]:      FXQuoteStatusExecuted,
+	_FXQuoteStatusLowerName[11:19]: FXQuoteStatusExecuted,
+}
+
+var _FXQuoteStatusNames = []string{
+	_FXQuoteStatusName[0:7],
+	_FXQuoteStatusName[7:11],
+	_FXQuoteStatusName[11:19],
+}
+
+// FXQuoteStatusString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func FXQuoteStatusString(s string) (FXQuoteStatus, error) {
+	if val, ok := _FXQuoteStatusNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _FXQuoteStatusNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to FXQuoteStatus values", s)
+}
+
+// FXQuoteStatusValues returns all values of the enum
+func FXQuoteStatusValues() []FXQuoteStatus {
+	return _FXQuoteStatusValues
+}
+
+// FXQuoteStatusStrings returns a slice of all String values of the enum
+func FXQuoteStatusStrings() []string {
+	strs := make([]string, len(_FXQuoteStatusNames))
+	copy(strs, _FXQuoteStatusNames)
+	return strs
+}
+
+// IsAFXQuoteStatus returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i FXQuoteStatus) IsAFXQuoteStatus() bool {
+	for _, v := range _FXQuoteStatusValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements the json.Marshaler interface for FXQuoteStatus
+func (i FXQuoteStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for FXQuoteStatus
+func (i *FXQuoteStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("FXQuoteStatus should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = FXQuoteStatusString(s)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for FXQuoteStatus
+func (i FXQuoteStatus) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for FXQuoteStatus
+func (i *FXQuoteStatus) UnmarshalText(text []byte) error {
+	var err error
+	*i, err = FXQuoteStatusString(string(text))
+	return err
+}
+
+func (i FXQuoteStatus) Value() (driver.Value, error) {
+	return i.String(), nil
+}
+
+func (i *FXQuoteStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	var str string
+	switch v := value.(type) {
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	case fmt.Stringer:
+		str = v.String()
+	default:
+		return fmt.Errorf("invalid value of FXQuoteStatus: %[1]T(%[1]v)", value)
+	}
+
+	val, err := FXQuoteStatusString(str)
+	if err != nil {
+		return err
+	}
+
+	*i = val
+	return nil
+}
+
+const _StatementFormatName = "invalidjsoncsvcamt053"
+
+var _StatementFormatIndex = [...]uint8{0, 7, 11, 14, 21}
+
+const _StatementFormatLowerName = "invalidjsoncsvcamt053"
+
+func (i StatementFormat) String() string {
+	if i >= StatementFormat(len(_StatementFormatIndex)-1) {
+		return fmt.Sprintf("StatementFormat(%d)", i)
+	}
+	return _StatementFormatName[_StatementFormatIndex[i]:_StatementFormatIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _StatementFormatNoOp() {
+	var x [1]struct{}
+	_ = x[StatementFormatInvalid-(0)]
+	_ = x[StatementFormatJSON-(1)]
+	_ = x[StatementFormatCSV-(2)]
+	_ = x[StatementFormatCamt053-(3)]
+}
+
+var _StatementFormatValues = []StatementFormat{StatementFormatInvalid, StatementFormatJSON, StatementFormatCSV, StatementFormatCamt053}
+
+var _StatementFormatNameToValueMap = map[string]StatementFormat{
+	_StatementFormatName[0:7]:        StatementFormatInvalid,
+	_StatementFormatLowerName[0:7]:   StatementFormatInvalid,
+	_StatementFormatName[7:11]:       StatementFormatJSON,
+	_StatementFormatLowerName[7:11]:  StatementFormatJSON,
+	_StatementFormatName[11:14]:      StatementFormatCSV,
+	_StatementFormatLowerName[11:14]: StatementFormatCSV,
+	_StatementFormatName[14:21]:      StatementFormatCamt053,
+	_StatementFormatLowerName[14:21]: StatementFormatCamt053,
+}
+
+var _StatementFormatNames = []string{
+	_StatementFormatName[0:7],
+	_StatementFormatName[7:11],
+	_StatementFormatName[11:14],
+	_StatementFormatName[14:21],
+}
+
+// StatementFormatString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func StatementFormatString(s string) (StatementFormat, error) {
+	if val, ok := _StatementFormatNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _StatementFormatNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to StatementFormat values", s)
+}
+
+// StatementFormatValues returns all values of the enum
+func StatementFormatValues() []StatementFormat {
+	return _StatementFormatValues
+}
+
+// StatementFormatStrings returns a slice of all String values of the enum
+func StatementFormatStrings() []string {
+	strs := make([]string, len(_StatementFormatNames))
+	copy(strs, _StatementFormatNames)
+	return strs
+}
+
+// IsAStatementFormat returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i StatementFormat) IsAStatementFormat() bool {
+	for _, v := range _StatementFormatValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements the json.Marshaler interface for StatementFormat
+func (i StatementFormat) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for StatementFormat
+func (i *StatementFormat) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("StatementFormat should be a string, got %s", data)
+	}
+
+	var err error
+	*i, err = StatementFormatString(s)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for StatementFormat
+func (i StatementFormat) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for StatementFormat
+func (i *StatementFormat) UnmarshalText(text []byte) error {
+	var err error
+	*i, err = StatementFormatString(string(text))
+	return err
+}
+
+func (i StatementFormat) Value() (driver.Value, error) {
+	return i.String(), nil
+}
+
+func (i *StatementFormat) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	var str string
+	switch v := value.(type) {
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	case fmt.Stringer:
+		str = v.String()
+	default:
+		return fmt.Errorf("invalid value of StatementFormat: %[1]T(%[1]v)", value)
+	}
+
+	val, err := StatementFormatString(str)
+	if err != nil {
+		return err
+	}
+
+	*i = val
+	return nil
+}
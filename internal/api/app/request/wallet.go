@@ -1,6 +1,8 @@
 package request
 
 import (
+	"time"
+
 	"github.com/buni/wallet/internal/api/app/entity"
 	"github.com/shopspring/decimal"
 )
@@ -10,7 +12,11 @@ type DebitTransfer struct {
 	ReferenceID string                `json:"reference_id"`
 	TransferID  string                `json:"transfer_id" validate:"required"`
 	Amount      decimal.Decimal       `json:"amount" validate:"required"`
+	Currency    string                `json:"currency" validate:"required"`
 	Status      entity.TransferStatus `json:"status" validate:"required"`
+	ExpiresAt   *time.Time            `json:"expires_at"` // optional, a pending transfer that isn't completed by then is reverted
+	ExecuteAt   *time.Time            `json:"execute_at"` // optional, queues the transfer to be executed at the given time instead of right away
+	Metadata    entity.Metadata       `json:"metadata" validate:"metadata"`
 }
 
 type CreditTransfer struct {
@@ -18,13 +24,18 @@ type CreditTransfer struct {
 	ReferenceID string                `json:"reference_id"`
 	TransferID  string                `json:"transfer_id" validate:"required"`
 	Amount      decimal.Decimal       `json:"amount" validate:"required"`
+	Currency    string                `json:"currency" validate:"required"`
 	Status      entity.TransferStatus `json:"status" validate:"required"`
+	ExpiresAt   *time.Time            `json:"expires_at"` // optional, a pending transfer that isn't completed by then is reverted
+	ExecuteAt   *time.Time            `json:"execute_at"` // optional, queues the transfer to be executed at the given time instead of right away
+	Metadata    entity.Metadata       `json:"metadata" validate:"metadata"`
 }
 
 type CompleteTransfer struct {
-	WalletID    string `json:"-" in:"path=walletID"`
-	TransferID  string `json:"-" in:"path=transferID"`
-	ReferenceID string `json:"reference_id"`
+	WalletID    string           `json:"-" in:"path=walletID"`
+	TransferID  string           `json:"-" in:"path=transferID"`
+	ReferenceID string           `json:"reference_id"`
+	Amount      *decimal.Decimal `json:"amount"` // optional, captures only part of the pending amount and releases the rest
 }
 
 type RevertTransfer struct {
@@ -33,10 +44,194 @@ type RevertTransfer struct {
 	ReferenceID string `json:"reference_id"`
 }
 
+type RefundTransfer struct {
+	WalletID    string           `json:"-" in:"path=walletID"`
+	TransferID  string           `json:"-" in:"path=transferID"`
+	ReferenceID string           `json:"reference_id"`
+	Amount      *decimal.Decimal `json:"amount"` // optional, defaults to the amount left to refund
+}
+
+type ListScheduledTransfers struct {
+	WalletID string                `json:"-" in:"path=walletID"`
+	Status   entity.ScheduleStatus `json:"-" in:"query=status"`
+}
+
+type CancelScheduledTransfer struct {
+	WalletID   string `json:"-" in:"path=walletID"`
+	TransferID string `json:"-" in:"path=transferID"`
+}
+
+type CreateRecurringTransfer struct {
+	WalletID    string                   `json:"-" in:"path=walletID"`
+	ReferenceID string                   `json:"reference_id"`
+	Direction   entity.TransferDirection `json:"direction" validate:"required"`
+	Amount      decimal.Decimal          `json:"amount" validate:"required"`
+	Currency    string                   `json:"currency" validate:"required"`
+	Rule        string                   `json:"rule" validate:"required"` // RRULE, e.g. FREQ=MONTHLY;BYMONTHDAY=1
+	StartAt     *time.Time               `json:"start_at"`                 // optional, defaults to now
+	EndAt       *time.Time               `json:"end_at"`                   // optional, no occurrences after it
+}
+
+type GetRecurringTransfer struct {
+	WalletID    string `json:"-" in:"path=walletID"`
+	RecurringID string `json:"-" in:"path=recurringID"`
+}
+
+type ListRecurringTransfers struct {
+	WalletID string                 `json:"-" in:"path=walletID"`
+	Status   entity.RecurringStatus `json:"-" in:"query=status"`
+}
+
+// UpdateRecurringTransfer replaces the amount, rule, end and status of the recurring transfer,
+// a changed rule restarts the recurrence from now.
+type UpdateRecurringTransfer struct {
+	WalletID    string                 `json:"-" in:"path=walletID"`
+	RecurringID string                 `json:"-" in:"path=recurringID"`
+	Amount      decimal.Decimal        `json:"amount" validate:"required"`
+	Rule        string                 `json:"rule" validate:"required"`
+	EndAt       *time.Time             `json:"end_at"`
+	Status      entity.RecurringStatus `json:"status" validate:"required"` // active or paused
+}
+
+type DeleteRecurringTransfer struct {
+	WalletID    string `json:"-" in:"path=walletID"`
+	RecurringID string `json:"-" in:"path=recurringID"`
+}
+
+type BatchTransfers struct {
+	Atomic bool                `json:"atomic"` // all or nothing in a single transaction, otherwise every item is written on its own
+	Items  []BatchTransferItem `json:"items" validate:"required,dive"`
+}
+
+// BatchTransferItem is one of the debit, credit, complete or revert transfer requests, amount, currency, status and expires_at are only used by debit and credit.
+type BatchTransferItem struct {
+	Operation   string                `json:"operation" validate:"required,oneof=debit credit complete revert"`
+	WalletID    string                `json:"wallet_id" validate:"required"`
+	TransferID  string                `json:"transfer_id" validate:"required"`
+	ReferenceID string                `json:"reference_id"`
+	Amount      decimal.Decimal       `json:"amount"`
+	Currency    string                `json:"currency"`
+	Status      entity.TransferStatus `json:"status"`
+	ExpiresAt   *time.Time            `json:"expires_at"`
+	Metadata    entity.Metadata       `json:"metadata" validate:"metadata"`
+}
+
+type GetTrialBalance struct{}
+
 type GetWallet struct {
 	WalletID string `json:"-" in:"path=walletID"`
 }
 
+type GetWalletByReference struct {
+	ReferenceID string `json:"-" in:"path=referenceID"`
+}
+
+type ListWallets struct {
+	Cursor            string           `json:"-" in:"query=cursor" validate:"omitempty,uuid"`
+	Limit             uint64           `json:"-" in:"query=limit" validate:"max=100"`
+	ReferenceID       string           `json:"-" in:"query=reference_id"`
+	ReferenceIDPrefix string           `json:"-" in:"query=reference_id_prefix"`
+	CreatedAfter      time.Time        `json:"-" in:"query=created_after"`
+	CreatedBefore     time.Time        `json:"-" in:"query=created_before"`
+	MinBalance        *decimal.Decimal `json:"-" in:"query=min_balance"`
+	MaxBalance        *decimal.Decimal `json:"-" in:"query=max_balance"`
+	Metadata          []string         `json:"-" in:"query=metadata" validate:"max=20,dive,metadata_filter"` // key:value pairs the wallet metadata has to contain
+}
+
+type FreezeWallet struct {
+	WalletID string `json:"-" in:"path=walletID"`
+}
+
+type UnfreezeWallet struct {
+	WalletID string `json:"-" in:"path=walletID"`
+}
+
+type CloseWallet struct {
+	WalletID string `json:"-" in:"path=walletID"`
+}
+
+type SetCreditLimit struct {
+	WalletID    string          `json:"-" in:"path=walletID"`
+	ReferenceID string          `json:"reference_id"`
+	CreditLimit decimal.Decimal `json:"credit_limit"` // 0 removes the overdraft
+}
+
+type SetWalletTier struct {
+	WalletID string `json:"-" in:"path=walletID"`
+	Tier     string `json:"tier" validate:"required"`
+}
+
+type GetBalance struct {
+	WalletID string `json:"-" in:"path=walletID"`
+	AsOf     string `json:"-" in:"query=as_of"` // RFC3339 timestamp or event id, empty means the current balance
+}
+
+type ListWalletEvents struct {
+	WalletID      string                 `json:"-" in:"path=walletID"`
+	Cursor        string                 `json:"-" in:"query=cursor" validate:"omitempty,uuid"`
+	Limit         uint64                 `json:"-" in:"query=limit" validate:"max=100"`
+	EventType     entity.WalletEventType `json:"-" in:"query=event_type"`
+	Status        entity.TransferStatus  `json:"-" in:"query=transfer_status"`
+	CreatedAfter  time.Time              `json:"-" in:"query=created_after"`
+	CreatedBefore time.Time              `json:"-" in:"query=created_before"`
+	Metadata      []string               `json:"-" in:"query=metadata" validate:"max=20,dive,metadata_filter"` // key:value pairs the event metadata has to contain
+}
+
+type GetStatement struct {
+	WalletID string                 `json:"-" in:"path=walletID"`
+	From     time.Time              `json:"-" in:"query=from"` // empty starts from the first event of the wallet
+	To       time.Time              `json:"-" in:"query=to"`   // exclusive, empty means now
+	Format   entity.StatementFormat `json:"-" in:"query=format"`
+}
+
+type GetTransfer struct {
+	WalletID   string `json:"-" in:"path=walletID"`
+	TransferID string `json:"-" in:"path=transferID"`
+}
+
+type ListTransfers struct {
+	WalletID string                `json:"-" in:"path=walletID"`
+	Status   entity.TransferStatus `json:"-" in:"query=status"`
+	Metadata []string              `json:"-" in:"query=metadata" validate:"max=20,dive,metadata_filter"` // key:value pairs the transfer metadata has to contain
+}
+
 type CreateWallet struct {
+	ReferenceID string          `json:"reference_id"`
+	Currency    string          `json:"currency" validate:"required"`
+	Metadata    entity.Metadata `json:"metadata" validate:"metadata"`
+}
+
+type InternalTransfer struct {
+	SourceWalletID      string                `json:"source_wallet_id" validate:"required"`
+	DestinationWalletID string                `json:"destination_wallet_id" validate:"required"`
+	ReferenceID         string                `json:"reference_id"`
+	TransferID          string                `json:"transfer_id" validate:"required"`
+	Amount              decimal.Decimal       `json:"amount" validate:"required"`
+	Status              entity.TransferStatus `json:"status" validate:"required"`
+}
+
+type CompleteInternalTransfer struct {
+	TransferID  string `json:"-" in:"path=transferID"`
+	ReferenceID string `json:"reference_id"`
+}
+
+type RevertInternalTransfer struct {
+	TransferID  string `json:"-" in:"path=transferID"`
+	ReferenceID string `json:"reference_id"`
+}
+
+type CreateFXQuote struct {
+	SourceWalletID string          `json:"source_wallet_id" validate:"required"`
+	TargetWalletID string          `json:"target_wallet_id" validate:"required"`
+	Amount         decimal.Decimal `json:"amount" validate:"required"` // in the source wallet currency
+}
+
+type GetFXQuote struct {
+	QuoteID string `json:"-" in:"path=quoteID"`
+}
+
+type ExecuteFXQuote struct {
+	QuoteID     string `json:"-" in:"path=quoteID"`
 	ReferenceID string `json:"reference_id"`
+	TransferID  string `json:"transfer_id" validate:"required"`
 }
@@ -4,15 +4,38 @@ import (
 	"time"
 
 	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/pkg/render"
 	"github.com/shopspring/decimal"
 )
 
 type Wallet struct {
-	ID            string          `json:"id"`
-	ReferenceID   string          `json:"reference_id"`
+	ID               string              `json:"id"`
+	ReferenceID      string              `json:"reference_id"`
+	Currency         entity.Currency     `json:"currency"`
+	Status           entity.WalletStatus `json:"status"`
+	Tier             string              `json:"tier"`
+	Balance          decimal.Decimal     `json:"balance"`
+	PendingDebit     decimal.Decimal     `json:"pending_debit"`
+	PendingCredit    decimal.Decimal     `json:"pending_credit"`
+	CreditLimit      decimal.Decimal     `json:"credit_limit"`
+	AvailableBalance decimal.Decimal     `json:"available_balance"` // balance plus the credit limit
+	Metadata         entity.Metadata     `json:"metadata,omitempty"`
+	CreatedAt        time.Time           `json:"created_at"`
+}
+
+type WalletList struct {
+	Items      []Wallet `json:"items"`
+	NextCursor string   `json:"next_cursor"`
+}
+
+// WalletBalance is the balance of the wallet as of the last event included in it.
+type WalletBalance struct {
+	WalletID      string          `json:"wallet_id"`
 	Balance       decimal.Decimal `json:"balance"`
 	PendingDebit  decimal.Decimal `json:"pending_debit"`
 	PendingCredit decimal.Decimal `json:"pending_credit"`
+	LastEventID   string          `json:"last_event_id"`
+	LastSequence  int64           `json:"last_sequence"`
 }
 
 type WalletEvent struct {
@@ -24,5 +47,206 @@ type WalletEvent struct {
 	Amount      decimal.Decimal        `json:"amount"`
 	EventType   entity.WalletEventType `json:"event_type"`
 	Status      entity.TransferStatus  `json:"status"`
+	Sequence    int64                  `json:"sequence"`
+	ExpiresAt   *time.Time             `json:"expires_at,omitempty"`
+	Fee         decimal.Decimal        `json:"fee"`               // charged on credits on top of the amount
+	FXRate      *decimal.Decimal       `json:"fx_rate,omitempty"` // set on the legs of an executed fx quote
+	Metadata    entity.Metadata        `json:"metadata,omitempty"`
 	CreatedAt   time.Time              `json:"created_at"`
 }
+
+type WalletEventList struct {
+	Items      []WalletEvent `json:"items"`
+	NextCursor string        `json:"next_cursor"`
+}
+
+type Statement struct {
+	WalletID       string          `json:"wallet_id"`
+	Currency       entity.Currency `json:"currency"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	OpeningBalance decimal.Decimal `json:"opening_balance"`
+	ClosingBalance decimal.Decimal `json:"closing_balance"`
+	Lines          []StatementLine `json:"lines"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type StatementLine struct {
+	EventID       string                   `json:"event_id"`
+	TransferID    string                   `json:"transfer_id"`
+	ReferenceID   string                   `json:"reference_id"`
+	EventType     entity.WalletEventType   `json:"event_type"`
+	Direction     entity.TransferDirection `json:"direction"`
+	Status        entity.TransferStatus    `json:"status"`
+	Amount        decimal.Decimal          `json:"amount"`
+	BalanceChange decimal.Decimal          `json:"balance_change"` // negative when money left the wallet
+	Balance       decimal.Decimal          `json:"balance"`        // running balance after the event
+	Sequence      int64                    `json:"sequence"`
+	CreatedAt     time.Time                `json:"created_at"`
+}
+
+type WalletTransfer struct {
+	TransferID     string                   `json:"transfer_id"`
+	WalletID       string                   `json:"wallet_id"`
+	ReferenceID    string                   `json:"reference_id"`
+	Amount         decimal.Decimal          `json:"amount"`
+	CapturedAmount decimal.Decimal          `json:"captured_amount"`
+	Direction      entity.TransferDirection `json:"direction"`
+	Status         entity.TransferStatus    `json:"status"`
+	ExpiresAt      *time.Time               `json:"expires_at,omitempty"`
+	Metadata       entity.Metadata          `json:"metadata,omitempty"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+}
+
+// WalletTransferRefunds is a wallet transfer with its refund history.
+type WalletTransferRefunds struct {
+	WalletTransfer
+	RefundedAmount   decimal.Decimal  `json:"refunded_amount"`
+	RefundableAmount decimal.Decimal  `json:"refundable_amount"`
+	Refunds          []TransferRefund `json:"refunds"`
+}
+
+type TransferRefund struct {
+	RefundID    string          `json:"refund_id"`
+	WalletID    string          `json:"wallet_id"`
+	TransferID  string          `json:"transfer_id"`
+	ReferenceID string          `json:"reference_id"`
+	Amount      decimal.Decimal `json:"amount"`
+	EventID     string          `json:"event_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type WalletTransferList struct {
+	Items []WalletTransfer `json:"items"`
+}
+
+type ScheduledTransfer struct {
+	ID             string                   `json:"id"`
+	WalletID       string                   `json:"wallet_id"`
+	TransferID     string                   `json:"transfer_id"`
+	ReferenceID    string                   `json:"reference_id"`
+	Direction      entity.TransferDirection `json:"direction"`
+	Amount         decimal.Decimal          `json:"amount"`
+	Currency       string                   `json:"currency"`
+	TransferStatus entity.TransferStatus    `json:"transfer_status"`
+	ExpiresAt      *time.Time               `json:"expires_at,omitempty"`
+	ExecuteAt      time.Time                `json:"execute_at"`
+	Status         entity.ScheduleStatus    `json:"status"`
+	EventID        *string                  `json:"event_id,omitempty"`
+	FailureReason  string                   `json:"failure_reason,omitempty"`
+	Metadata       entity.Metadata          `json:"metadata,omitempty"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+}
+
+type ScheduledTransferList struct {
+	Items []ScheduledTransfer `json:"items"`
+}
+
+type RecurringTransfer struct {
+	ID                string                   `json:"id"`
+	WalletID          string                   `json:"wallet_id"`
+	ReferenceID       string                   `json:"reference_id"`
+	Direction         entity.TransferDirection `json:"direction"`
+	Amount            decimal.Decimal          `json:"amount"`
+	Currency          string                   `json:"currency"`
+	Rule              string                   `json:"rule"`
+	StartAt           time.Time                `json:"start_at"`
+	EndAt             *time.Time               `json:"end_at,omitempty"`
+	NextRunAt         *time.Time               `json:"next_run_at,omitempty"`
+	Status            entity.RecurringStatus   `json:"status"`
+	LastTransferID    string                   `json:"last_transfer_id,omitempty"`
+	LastFailureReason string                   `json:"last_failure_reason,omitempty"`
+	CreatedAt         time.Time                `json:"created_at"`
+	UpdatedAt         time.Time                `json:"updated_at"`
+}
+
+type RecurringTransferList struct {
+	Items []RecurringTransfer `json:"items"`
+}
+
+type InternalTransfer struct {
+	TransferID          string          `json:"transfer_id"`
+	ReferenceID         string          `json:"reference_id"`
+	SourceWalletID      string          `json:"source_wallet_id"`
+	DestinationWalletID string          `json:"destination_wallet_id"`
+	Amount              decimal.Decimal `json:"amount"`
+	CreditEvent         WalletEvent     `json:"credit_event"`
+	DebitEvent          WalletEvent     `json:"debit_event"`
+	CreatedAt           time.Time       `json:"created_at"`
+}
+
+type FXQuote struct {
+	ID             string               `json:"id"`
+	SourceWalletID string               `json:"source_wallet_id"`
+	TargetWalletID string               `json:"target_wallet_id"`
+	SourceCurrency entity.Currency      `json:"source_currency"`
+	TargetCurrency entity.Currency      `json:"target_currency"`
+	SourceAmount   decimal.Decimal      `json:"source_amount"`
+	TargetAmount   decimal.Decimal      `json:"target_amount"`
+	Rate           decimal.Decimal      `json:"rate"`
+	Status         entity.FXQuoteStatus `json:"status"`
+	TransferID     string               `json:"transfer_id,omitempty"`
+	ReferenceID    string               `json:"reference_id,omitempty"`
+	ExpiresAt      time.Time            `json:"expires_at"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+// FXQuoteEvents is an executed fx quote with the events written for each of its legs.
+type FXQuoteEvents struct {
+	FXQuote
+	CreditEvent WalletEvent `json:"credit_event"`
+	DebitEvent  WalletEvent `json:"debit_event"`
+}
+
+// ProjectionMismatch is printed by the reconcile command for every stored projection that doesn't match the replay of its events.
+type ProjectionMismatch struct {
+	WalletID string          `json:"wallet_id"`
+	Fields   []string        `json:"fields"`
+	Stored   ProjectionState `json:"stored"`
+	Replayed ProjectionState `json:"replayed"`
+	Repaired bool            `json:"repaired"`
+}
+
+type ProjectionState struct {
+	Balance       decimal.Decimal `json:"balance"`
+	PendingDebit  decimal.Decimal `json:"pending_debit"`
+	PendingCredit decimal.Decimal `json:"pending_credit"`
+	CreditLimit   decimal.Decimal `json:"credit_limit"`
+	LastSequence  int64           `json:"last_sequence"`
+}
+
+type BatchTransferResult struct {
+	StatusCode int           `json:"status_code"` // the status the single transfer route would have responded with
+	Event      *WalletEvent  `json:"event,omitempty"`
+	Error      *render.Error `json:"error,omitempty"`
+}
+
+type BatchTransfers struct {
+	Items []BatchTransferResult `json:"items"`
+}
+
+// BatchTransferError is the response of a failed atomic batch, it's the error of the item at Index.
+type BatchTransferError struct {
+	Index int           `json:"index"`
+	Error *render.Error `json:"error"`
+}
+
+type LedgerAccountBalance struct {
+	Account  string          `json:"account"`
+	Currency entity.Currency `json:"currency"`
+	Balance  decimal.Decimal `json:"balance"`
+}
+
+type LedgerCurrencyTotal struct {
+	Currency entity.Currency `json:"currency"`
+	Total    decimal.Decimal `json:"total"`
+}
+
+type TrialBalance struct {
+	Accounts []LedgerAccountBalance `json:"accounts"`
+	Totals   []LedgerCurrencyTotal  `json:"totals"`
+	Balanced bool                   `json:"balanced"` // the postings of every currency sum up to 0
+}
@@ -49,6 +49,16 @@ func (h *EventCreatedHandler) Handle(ctx context.Context, event *entity.WalletEv
 		if err != nil {
 			return fmt.Errorf("failed to rebuild wallet projection: %w", err)
 		}
+
+		_, err = h.svc.RebuildTransferProjection(ctx, event)
+		if err != nil {
+			return fmt.Errorf("failed to rebuild transfer projection: %w", err)
+		}
+
+		_, err = h.svc.PostLedgerEntries(ctx, event)
+		if err != nil {
+			return fmt.Errorf("failed to post ledger entries: %w", err)
+		}
 		return nil
 	})
 	if err != nil {
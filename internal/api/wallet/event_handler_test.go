@@ -54,6 +54,8 @@ func (s *WalletEventCreatedHandler) TestHandleSuccess() {
 	)
 	s.NoError(err)
 	s.svcMock.EXPECT().RebuildWalletProjection(gomock.Any(), &event).Return(entity.WalletProjection{}, nil)
+	s.svcMock.EXPECT().RebuildTransferProjection(gomock.Any(), &event).Return(entity.WalletTransfer{}, nil)
+	s.svcMock.EXPECT().PostLedgerEntries(gomock.Any(), &event).Return(nil, nil)
 
 	err = s.handler.Handle(context.Background(), &event, nil)
 	s.NoError(err)
@@ -75,6 +77,41 @@ func (s *WalletEventCreatedHandler) TestHandleError() {
 	s.ErrorIs(err, context.DeadlineExceeded)
 }
 
+func (s *WalletEventCreatedHandler) TestHandleTransferProjectionError() {
+	event, err := entity.NewWalletEvent(uuid.Must(uuid.NewV7()).String(),
+		uuid.Must(uuid.NewV7()).String(),
+		uuid.Must(uuid.NewV7()).String(),
+		decimal.NewFromInt(int64(rand.Intn(100000))),
+		entity.WalletEventType(rand.Intn(2)+1),
+		entity.TransferStatus(rand.Intn(2)+1),
+	)
+	s.NoError(err)
+
+	s.svcMock.EXPECT().RebuildWalletProjection(gomock.Any(), &event).Return(entity.WalletProjection{}, nil)
+	s.svcMock.EXPECT().RebuildTransferProjection(gomock.Any(), &event).Return(entity.WalletTransfer{}, context.DeadlineExceeded)
+
+	err = s.handler.Handle(context.Background(), &event, nil)
+	s.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (s *WalletEventCreatedHandler) TestHandlePostLedgerEntriesError() {
+	event, err := entity.NewWalletEvent(uuid.Must(uuid.NewV7()).String(),
+		uuid.Must(uuid.NewV7()).String(),
+		uuid.Must(uuid.NewV7()).String(),
+		decimal.NewFromInt(int64(rand.Intn(100000))),
+		entity.WalletEventType(rand.Intn(2)+1),
+		entity.TransferStatus(rand.Intn(2)+1),
+	)
+	s.NoError(err)
+
+	s.svcMock.EXPECT().RebuildWalletProjection(gomock.Any(), &event).Return(entity.WalletProjection{}, nil)
+	s.svcMock.EXPECT().RebuildTransferProjection(gomock.Any(), &event).Return(entity.WalletTransfer{}, nil)
+	s.svcMock.EXPECT().PostLedgerEntries(gomock.Any(), &event).Return(nil, context.DeadlineExceeded)
+
+	err = s.handler.Handle(context.Background(), &event, nil)
+	s.ErrorIs(err, context.DeadlineExceeded)
+}
+
 func TestWalletEventCreatedHandler(t *testing.T) {
 	suite.Run(t, new(WalletEventCreatedHandler))
 }
@@ -0,0 +1,27 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/contract"
+	"github.com/buni/wallet/internal/pkg/sloglog"
+)
+
+// NewExpiryWorker creates a worker that periodically reverts the pending transfers that weren't completed before their expiry, releasing the held funds.
+func NewExpiryWorker(svc contract.WalletService, opts ...PeriodicWorkerOption) (*PeriodicWorker, error) {
+	return NewPeriodicWorker(func(ctx context.Context, now time.Time, limit uint64) (int, error) {
+		events, err := svc.ExpireTransfers(ctx, now, limit)
+		if len(events) > 0 {
+			sloglog.FromContext(ctx).InfoContext(ctx, "expired pending transfers", slog.Int("count", len(events)))
+		}
+
+		if err != nil {
+			return len(events), fmt.Errorf("failed to expire transfers: %w", err)
+		}
+
+		return len(events), nil
+	}, opts...)
+}
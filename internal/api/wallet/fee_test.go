@@ -0,0 +1,197 @@
+package wallet_test
+
+import (
+	"testing"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeScheduleFee(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule entity.FeeSchedule
+		amount   decimal.Decimal
+		currency entity.Currency
+		expected decimal.Decimal
+	}{
+		{
+			name:     "flat and percentage",
+			schedule: entity.FeeSchedule{Flat: decimal.RequireFromString("0.30"), Percent: decimal.RequireFromString("1.5")},
+			amount:   decimal.NewFromInt(100),
+			currency: "EUR",
+			expected: decimal.RequireFromString("1.80"),
+		},
+		{
+			name:     "raised to min",
+			schedule: entity.FeeSchedule{Percent: decimal.NewFromInt(1), Min: decimal.NewFromInt(2)},
+			amount:   decimal.NewFromInt(50),
+			currency: "EUR",
+			expected: decimal.NewFromInt(2),
+		},
+		{
+			name:     "capped at max",
+			schedule: entity.FeeSchedule{Percent: decimal.NewFromInt(1), Max: decimal.NewFromInt(10)},
+			amount:   decimal.NewFromInt(5000),
+			currency: "EUR",
+			expected: decimal.NewFromInt(10),
+		},
+		{
+			name:     "rounded to the currency minor units",
+			schedule: entity.FeeSchedule{Percent: decimal.RequireFromString("1.5")},
+			amount:   decimal.NewFromInt(333),
+			currency: "JPY",
+			expected: decimal.NewFromInt(5),
+		},
+		{
+			name:     "not rounded without a currency",
+			schedule: entity.FeeSchedule{Percent: decimal.RequireFromString("1.5")},
+			amount:   decimal.RequireFromString("0.333"),
+			currency: entity.CurrencyNone,
+			expected: decimal.RequireFromString("0.004995"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee := tt.schedule.Fee(tt.amount, tt.currency)
+			assert.True(t, tt.expected.Equal(fee), "expected %s, got %s", tt.expected, fee)
+		})
+	}
+}
+
+func TestFeeSchedulesFor(t *testing.T) {
+	schedules := entity.FeeSchedules{
+		entity.WalletTierStandard: {Flat: decimal.NewFromInt(1)},
+		"wallet-id":               {Flat: decimal.NewFromInt(2)},
+	}
+
+	schedule, ok := schedules.For(entity.Wallet{ID: "wallet-id", Tier: entity.WalletTierStandard})
+	assert.True(t, ok)
+	assert.True(t, decimal.NewFromInt(2).Equal(schedule.Flat))
+
+	schedule, ok = schedules.For(entity.Wallet{ID: "other", Tier: entity.WalletTierStandard})
+	assert.True(t, ok)
+	assert.True(t, decimal.NewFromInt(1).Equal(schedule.Flat))
+
+	_, ok = schedules.For(entity.Wallet{ID: "other", Tier: "premium"})
+	assert.False(t, ok)
+}
+
+func TestParseFeeSchedules(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		expected    entity.FeeSchedules
+		expectedErr error
+	}{
+		{
+			name:     "empty spec",
+			spec:     "",
+			expected: entity.FeeSchedules{},
+		},
+		{
+			name: "tier and wallet",
+			spec: "standard:0.30:1.5:0.50:10, 0190e7a4-5a3c-7b1e-9c4d-2f1e8a6b3c5d:0:0.5::",
+			expected: entity.FeeSchedules{
+				"standard": {
+					Flat:    decimal.RequireFromString("0.30"),
+					Percent: decimal.RequireFromString("1.5"),
+					Min:     decimal.RequireFromString("0.50"),
+					Max:     decimal.RequireFromString("10"),
+				},
+				"0190e7a4-5a3c-7b1e-9c4d-2f1e8a6b3c5d": {
+					Percent: decimal.RequireFromString("0.5"),
+					Flat:    decimal.RequireFromString("0"),
+				},
+			},
+		},
+		{
+			name:        "missing part",
+			spec:        "standard:0.30:1.5:0.50",
+			expectedErr: entity.ErrInvalidFeeSchedule,
+		},
+		{
+			name:        "invalid amount",
+			spec:        "standard:abc:1.5:0:0",
+			expectedErr: entity.ErrInvalidFeeSchedule,
+		},
+		{
+			name:        "negative amount",
+			spec:        "standard:0:-1:0:0",
+			expectedErr: entity.ErrInvalidFeeSchedule,
+		},
+		{
+			name:        "max below min",
+			spec:        "standard:0:1:5:2",
+			expectedErr: entity.ErrInvalidFeeSchedule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedules, err := entity.ParseFeeSchedules(tt.spec)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, schedules)
+		})
+	}
+}
+
+func TestParseFeeWallets(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		expected    entity.FeeWallets
+		expectedErr error
+	}{
+		{
+			name:     "empty spec",
+			spec:     "",
+			expected: entity.FeeWallets{},
+		},
+		{
+			name:     "one per currency",
+			spec:     "eur:0190e7a4-5a3c-7b1e-9c4d-2f1e8a6b3c5d, USD:0190e7a5-1b2c-7d3e-8f4a-5b6c7d8e9f0a",
+			expected: entity.FeeWallets{"EUR": "0190e7a4-5a3c-7b1e-9c4d-2f1e8a6b3c5d", "USD": "0190e7a5-1b2c-7d3e-8f4a-5b6c7d8e9f0a"},
+		},
+		{
+			name:        "missing wallet",
+			spec:        "EUR:",
+			expectedErr: entity.ErrInvalidFeeWallet,
+		},
+		{
+			name:        "unsupported currency",
+			spec:        "ABC:0190e7a4-5a3c-7b1e-9c4d-2f1e8a6b3c5d",
+			expectedErr: entity.ErrInvalidFeeWallet,
+		},
+		{
+			name:        "currency with two wallets",
+			spec:        "EUR:0190e7a4-5a3c-7b1e-9c4d-2f1e8a6b3c5d,EUR:0190e7a5-1b2c-7d3e-8f4a-5b6c7d8e9f0a",
+			expectedErr: entity.ErrInvalidFeeWallet,
+		},
+		{
+			name:        "wallet collecting two currencies",
+			spec:        "EUR:0190e7a4-5a3c-7b1e-9c4d-2f1e8a6b3c5d,USD:0190e7a4-5a3c-7b1e-9c4d-2f1e8a6b3c5d",
+			expectedErr: entity.ErrInvalidFeeWallet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallets, err := entity.ParseFeeWallets(tt.spec)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, wallets)
+		})
+	}
+}
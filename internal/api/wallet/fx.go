@@ -0,0 +1,62 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/buni/wallet/internal/api/app/contract"
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	_ contract.FXRateProvider = (*StaticRateProvider)(nil)
+	_ contract.FXRateProvider = (*FileRateProvider)(nil)
+)
+
+// StaticRateProvider serves a fixed set of rates, e.g. the ones set in the configuration.
+type StaticRateProvider struct {
+	rates entity.FXRates
+}
+
+func NewStaticRateProvider(rates entity.FXRates) *StaticRateProvider {
+	return &StaticRateProvider{
+		rates: rates,
+	}
+}
+
+func (p *StaticRateProvider) Rate(_ context.Context, from, to entity.Currency) (decimal.Decimal, error) {
+	rate, ok := p.rates.Rate(from, to)
+	if !ok {
+		return decimal.Decimal{}, fmt.Errorf("%w: %s to %s", entity.ErrFXRateNotFound, from, to)
+	}
+
+	return rate, nil
+}
+
+// FileRateProvider serves the rates stored in a file in the entity.ParseFXRates format, one rate per line.
+// The file is read on every lookup so updating it takes effect without a restart.
+type FileRateProvider struct {
+	path string
+}
+
+func NewFileRateProvider(path string) *FileRateProvider {
+	return &FileRateProvider{
+		path: path,
+	}
+}
+
+func (p *FileRateProvider) Rate(ctx context.Context, from, to entity.Currency) (decimal.Decimal, error) {
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to read fx rates file: %w", err)
+	}
+
+	rates, err := entity.ParseFXRates(string(content))
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse fx rates file: %w", err)
+	}
+
+	return NewStaticRateProvider(rates).Rate(ctx, from, to)
+}
@@ -0,0 +1,96 @@
+package wallet_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFXRates(t *testing.T) {
+	rates, err := entity.ParseFXRates("eur:usd:1.0845, GBP:EUR:1.17\n# comment\n")
+	require.NoError(t, err)
+	assert.Len(t, rates, 2)
+	assert.True(t, decimal.RequireFromString("1.0845").Equal(rates[entity.FXPair{From: "EUR", To: "USD"}]))
+	assert.True(t, decimal.RequireFromString("1.17").Equal(rates[entity.FXPair{From: "GBP", To: "EUR"}]))
+
+	rates, err = entity.ParseFXRates("")
+	require.NoError(t, err)
+	assert.Empty(t, rates)
+
+	for _, spec := range []string{"EUR:USD", "EUR:USD:abc", "EUR:USD:0", "EUR:USD:-1", "EUR:EUR:1", "EUR:XXX:1"} {
+		_, err := entity.ParseFXRates(spec)
+		assert.ErrorIs(t, err, entity.ErrInvalidFXRate, spec)
+	}
+}
+
+func TestFXRatesRate(t *testing.T) {
+	rates := entity.FXRates{{From: "EUR", To: "USD"}: decimal.RequireFromString("1.25")}
+
+	rate, ok := rates.Rate("EUR", "USD")
+	assert.True(t, ok)
+	assert.True(t, decimal.RequireFromString("1.25").Equal(rate), rate.String())
+
+	rate, ok = rates.Rate("USD", "EUR")
+	assert.True(t, ok)
+	assert.True(t, decimal.RequireFromString("0.8").Equal(rate), rate.String())
+
+	_, ok = rates.Rate("EUR", "GBP")
+	assert.False(t, ok)
+}
+
+func TestNewFXQuote(t *testing.T) {
+	source := entity.Wallet{ID: "source", Currency: "EUR"}
+
+	quote, err := entity.NewFXQuote(source, entity.Wallet{ID: "target", Currency: "JPY"}, decimal.RequireFromString("10.55"), decimal.RequireFromString("161.3"), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(1702).Equal(quote.TargetAmount), quote.TargetAmount.String()) // 1701.715 rounded to whole yen
+	assert.Equal(t, entity.FXQuoteStatusOpen, quote.Status)
+	assert.Equal(t, time.Minute, quote.ExpiresAt.Sub(quote.CreatedAt))
+	assert.False(t, quote.IsExpired(quote.CreatedAt))
+	assert.True(t, quote.IsExpired(quote.ExpiresAt))
+
+	_, err = entity.NewFXQuote(entity.Wallet{ID: "source", Currency: "JPY"}, entity.Wallet{ID: "target", Currency: "EUR"}, decimal.NewFromInt(1), decimal.RequireFromString("0.0042"), time.Minute) // 0.0042 EUR rounds to 0
+	assert.ErrorIs(t, err, entity.ErrInvalidFXAmount)
+}
+
+func TestStaticRateProvider(t *testing.T) {
+	provider := wallet.NewStaticRateProvider(entity.FXRates{{From: "EUR", To: "USD"}: decimal.RequireFromString("1.0845")})
+
+	rate, err := provider.Rate(context.Background(), "EUR", "USD")
+	require.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("1.0845").Equal(rate))
+
+	_, err = provider.Rate(context.Background(), "EUR", "GBP")
+	assert.ErrorIs(t, err, entity.ErrFXRateNotFound)
+}
+
+func TestFileRateProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates")
+	provider := wallet.NewFileRateProvider(path)
+
+	_, err := provider.Rate(context.Background(), "EUR", "USD")
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("EUR:USD:1.0845\n"), 0o600))
+
+	rate, err := provider.Rate(context.Background(), "EUR", "USD")
+	require.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("1.0845").Equal(rate))
+
+	require.NoError(t, os.WriteFile(path, []byte("EUR:USD:1.1\nGBP:EUR:1.17\n"), 0o600)) // picked up without recreating the provider
+
+	rate, err = provider.Rate(context.Background(), "EUR", "USD")
+	require.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("1.1").Equal(rate))
+
+	_, err = provider.Rate(context.Background(), "EUR", "CHF")
+	assert.ErrorIs(t, err, entity.ErrFXRateNotFound)
+}
@@ -2,14 +2,20 @@ package wallet
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 
 	"github.com/buni/wallet/internal/api/app/contract"
+	"github.com/buni/wallet/internal/api/app/entity"
 	"github.com/buni/wallet/internal/api/app/request"
 	"github.com/buni/wallet/internal/api/app/response"
 	"github.com/buni/wallet/internal/pkg/handler"
 	"github.com/buni/wallet/internal/pkg/render"
+	"github.com/buni/wallet/internal/pkg/render/errorhandler"
+	"github.com/buni/wallet/internal/pkg/sloglog"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -53,7 +59,134 @@ func (h *Handler) Get(ctx context.Context, req *request.GetWallet) (*response.Wa
 	return walletResp, nil
 }
 
-func (h *Handler) DebitTransfer(ctx context.Context, req *request.DebitTransfer) (*response.WalletEvent, error) {
+func (h *Handler) GetByReference(ctx context.Context, req *request.GetWalletByReference) (*response.Wallet, error) {
+	wallet, err := h.svc.GetByReferenceID(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet by reference id: %w", err)
+	}
+
+	walletResp, err := render.NewResponse[response.Wallet](wallet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render wallet response: %w", err)
+	}
+
+	return walletResp, nil
+}
+
+func (h *Handler) List(ctx context.Context, req *request.ListWallets) (*response.WalletList, error) {
+	page, err := h.svc.List(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+
+	items, err := render.NewResponses[entity.WalletBalanceProjection, response.Wallet](page.Wallets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render wallets response: %w", err)
+	}
+
+	return &response.WalletList{
+		Items:      *items,
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+func (h *Handler) GetBalance(ctx context.Context, req *request.GetBalance) (*response.WalletBalance, error) {
+	balance, err := h.svc.GetBalance(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+
+	balanceResp, err := render.NewResponse[response.WalletBalance](balance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render wallet balance response: %w", err)
+	}
+
+	return balanceResp, nil
+}
+
+func (h *Handler) Freeze(ctx context.Context, req *request.FreezeWallet) (*response.Wallet, error) {
+	wallet, err := h.svc.Freeze(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to freeze wallet: %w", err)
+	}
+
+	walletResp, err := render.NewResponse[response.Wallet](wallet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render wallet response: %w", err)
+	}
+
+	return walletResp, nil
+}
+
+func (h *Handler) Unfreeze(ctx context.Context, req *request.UnfreezeWallet) (*response.Wallet, error) {
+	wallet, err := h.svc.Unfreeze(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unfreeze wallet: %w", err)
+	}
+
+	walletResp, err := render.NewResponse[response.Wallet](wallet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render wallet response: %w", err)
+	}
+
+	return walletResp, nil
+}
+
+func (h *Handler) Close(ctx context.Context, req *request.CloseWallet) (*response.Wallet, error) {
+	wallet, err := h.svc.Close(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close wallet: %w", err)
+	}
+
+	walletResp, err := render.NewResponse[response.Wallet](wallet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render wallet response: %w", err)
+	}
+
+	return walletResp, nil
+}
+
+func (h *Handler) SetCreditLimit(ctx context.Context, req *request.SetCreditLimit) (*response.WalletEvent, error) {
+	event, err := h.svc.SetCreditLimit(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set credit limit: %w", err)
+	}
+
+	eventResp, err := render.NewResponse[response.WalletEvent](event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render wallet event response: %w", err)
+	}
+
+	return eventResp, nil
+}
+
+func (h *Handler) SetTier(ctx context.Context, req *request.SetWalletTier) (*response.Wallet, error) {
+	wallet, err := h.svc.SetTier(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set wallet tier: %w", err)
+	}
+
+	walletResp, err := render.NewResponse[response.Wallet](wallet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render wallet response: %w", err)
+	}
+
+	return walletResp, nil
+}
+
+// DebitTransfer responds with 202 and the scheduled transfer instead of the event if the request has an execution time.
+func (h *Handler) DebitTransfer(w http.ResponseWriter, r *http.Request, req *request.DebitTransfer) (*response.WalletEvent, error) {
+	ctx := r.Context()
+
+	if req.ExecuteAt != nil {
+		transfer, err := h.svc.ScheduleDebitTransfer(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to schedule debit transfer: %w", err)
+		}
+
+		return nil, renderScheduledTransfer(ctx, w, transfer)
+	}
+
 	event, err := h.svc.DebitTransfer(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to debit transfer: %w", err)
@@ -67,7 +200,19 @@ func (h *Handler) DebitTransfer(ctx context.Context, req *request.DebitTransfer)
 	return eventResp, nil
 }
 
-func (h *Handler) CreditTransfer(ctx context.Context, req *request.CreditTransfer) (*response.WalletEvent, error) {
+// CreditTransfer responds with 202 and the scheduled transfer instead of the event if the request has an execution time.
+func (h *Handler) CreditTransfer(w http.ResponseWriter, r *http.Request, req *request.CreditTransfer) (*response.WalletEvent, error) {
+	ctx := r.Context()
+
+	if req.ExecuteAt != nil {
+		transfer, err := h.svc.ScheduleCreditTransfer(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to schedule credit transfer: %w", err)
+		}
+
+		return nil, renderScheduledTransfer(ctx, w, transfer)
+	}
+
 	event, err := h.svc.CreditTransfer(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to credit transfer: %w", err)
@@ -81,6 +226,116 @@ func (h *Handler) CreditTransfer(ctx context.Context, req *request.CreditTransfe
 	return eventResp, nil
 }
 
+func renderScheduledTransfer(ctx context.Context, w http.ResponseWriter, transfer entity.ScheduledTransfer) error {
+	transferResp, err := render.NewResponse[response.ScheduledTransfer](transfer)
+	if err != nil {
+		return fmt.Errorf("failed to render scheduled transfer response: %w", err)
+	}
+
+	render.NewSuccessResponse(ctx, w, http.StatusAccepted, transferResp)
+
+	return nil
+}
+
+func (h *Handler) ListScheduledTransfers(ctx context.Context, req *request.ListScheduledTransfers) (*response.ScheduledTransferList, error) {
+	transfers, err := h.svc.ListScheduledTransfers(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled transfers: %w", err)
+	}
+
+	items, err := render.NewResponses[entity.ScheduledTransfer, response.ScheduledTransfer](transfers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render scheduled transfers response: %w", err)
+	}
+
+	return &response.ScheduledTransferList{
+		Items: *items,
+	}, nil
+}
+
+func (h *Handler) CancelScheduledTransfer(ctx context.Context, req *request.CancelScheduledTransfer) (*response.ScheduledTransfer, error) {
+	transfer, err := h.svc.CancelScheduledTransfer(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel scheduled transfer: %w", err)
+	}
+
+	transferResp, err := render.NewResponse[response.ScheduledTransfer](transfer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render scheduled transfer response: %w", err)
+	}
+
+	return transferResp, nil
+}
+
+func (h *Handler) CreateRecurringTransfer(w http.ResponseWriter, r *http.Request, req *request.CreateRecurringTransfer) (*response.RecurringTransfer, error) {
+	transfer, err := h.svc.CreateRecurringTransfer(r.Context(), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recurring transfer: %w", err)
+	}
+
+	transferResp, err := render.NewResponse[response.RecurringTransfer](transfer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render recurring transfer response: %w", err)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	return transferResp, nil
+}
+
+func (h *Handler) GetRecurringTransfer(ctx context.Context, req *request.GetRecurringTransfer) (*response.RecurringTransfer, error) {
+	transfer, err := h.svc.GetRecurringTransfer(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recurring transfer: %w", err)
+	}
+
+	transferResp, err := render.NewResponse[response.RecurringTransfer](transfer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render recurring transfer response: %w", err)
+	}
+
+	return transferResp, nil
+}
+
+func (h *Handler) ListRecurringTransfers(ctx context.Context, req *request.ListRecurringTransfers) (*response.RecurringTransferList, error) {
+	transfers, err := h.svc.ListRecurringTransfers(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recurring transfers: %w", err)
+	}
+
+	items, err := render.NewResponses[entity.RecurringTransfer, response.RecurringTransfer](transfers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render recurring transfers response: %w", err)
+	}
+
+	return &response.RecurringTransferList{
+		Items: *items,
+	}, nil
+}
+
+func (h *Handler) UpdateRecurringTransfer(ctx context.Context, req *request.UpdateRecurringTransfer) (*response.RecurringTransfer, error) {
+	transfer, err := h.svc.UpdateRecurringTransfer(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update recurring transfer: %w", err)
+	}
+
+	transferResp, err := render.NewResponse[response.RecurringTransfer](transfer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render recurring transfer response: %w", err)
+	}
+
+	return transferResp, nil
+}
+
+func (h *Handler) DeleteRecurringTransfer(ctx context.Context, req *request.DeleteRecurringTransfer) (*response.RecurringTransfer, error) {
+	err := h.svc.DeleteRecurringTransfer(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete recurring transfer: %w", err)
+	}
+
+	return nil, nil //nolint:nilnil // responds with 204
+}
+
 func (h *Handler) CompleteTransfer(ctx context.Context, req *request.CompleteTransfer) (*response.WalletEvent, error) {
 	event, err := h.svc.CompleteTransfer(ctx, req)
 	if err != nil {
@@ -109,19 +364,315 @@ func (h *Handler) RevertTransfer(ctx context.Context, req *request.RevertTransfe
 	return eventResp, nil
 }
 
+func (h *Handler) RefundTransfer(ctx context.Context, req *request.RefundTransfer) (*response.TransferRefund, error) {
+	refund, err := h.svc.RefundTransfer(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refund transfer: %w", err)
+	}
+
+	refundResp, err := render.NewResponse[response.TransferRefund](refund)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render transfer refund response: %w", err)
+	}
+
+	return refundResp, nil
+}
+
+// BatchTransfers responds with a result per item, if an atomic batch fails it responds with the status and error of the failed item instead.
+func (h *Handler) BatchTransfers(w http.ResponseWriter, r *http.Request, req *request.BatchTransfers) (*response.BatchTransfers, error) {
+	ctx := r.Context()
+
+	results, err := h.svc.BatchTransfers(ctx, req)
+	if err != nil {
+		var itemErr *entity.BatchItemError
+		if !errors.As(err, &itemErr) {
+			return nil, fmt.Errorf("failed to run batch transfers: %w", err)
+		}
+
+		logBatchItemError(ctx, itemErr.Index, itemErr.Err)
+
+		code, renderErr := errorhandler.ResolveError(ctx, itemErr.Err)
+		render.NewSuccessResponse(ctx, w, code, response.BatchTransferError{Index: itemErr.Index, Error: renderErr})
+
+		return nil, nil //nolint:nilnil
+	}
+
+	items := make([]response.BatchTransferResult, 0, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			logBatchItemError(ctx, i, result.Err)
+
+			code, renderErr := errorhandler.ResolveError(ctx, result.Err)
+			items = append(items, response.BatchTransferResult{StatusCode: code, Error: renderErr})
+
+			continue
+		}
+
+		eventResp, err := render.NewResponse[response.WalletEvent](result.Event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render wallet event response: %w", err)
+		}
+
+		items = append(items, response.BatchTransferResult{StatusCode: http.StatusOK, Event: eventResp})
+	}
+
+	return &response.BatchTransfers{Items: items}, nil
+}
+
+func logBatchItemError(ctx context.Context, index int, err error) {
+	sloglog.FromContext(ctx).ErrorContext(ctx, "batch item execution error", slog.Int("index", index), sloglog.Error(err))
+}
+
+func (h *Handler) ListEvents(ctx context.Context, req *request.ListWalletEvents) (*response.WalletEventList, error) {
+	page, err := h.svc.ListEvents(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallet events: %w", err)
+	}
+
+	items, err := render.NewResponses[entity.WalletEvent, response.WalletEvent](page.Events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render wallet events response: %w", err)
+	}
+
+	return &response.WalletEventList{
+		Items:      *items,
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+// GetStatement renders JSON statements like any other response, the CSV and camt.053 ones are streamed to w as a download.
+func (h *Handler) GetStatement(w http.ResponseWriter, r *http.Request, req *request.GetStatement) (*response.Statement, error) {
+	ctx := r.Context()
+
+	statement, err := h.svc.GetStatement(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement: %w", err)
+	}
+
+	var write func(w io.Writer, statement entity.Statement) error
+
+	switch req.Format { //nolint:exhaustive
+	case entity.StatementFormatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		write = WriteStatementCSV
+	case entity.StatementFormatCamt053:
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		write = WriteStatementCamt053
+	default:
+		statementResp, err := render.NewResponse[response.Statement](statement)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render statement response: %w", err)
+		}
+
+		return statementResp, nil
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%s-%s.%s"`, statement.WalletID, statement.To.Format("20060102"), statementExtension(req.Format)))
+	w.WriteHeader(http.StatusOK)
+
+	err = write(w, statement)
+	if err != nil { // the status was already sent, so the error can only be logged
+		sloglog.FromContext(ctx).ErrorContext(ctx, "failed to write statement", slog.String("wallet_id", statement.WalletID), sloglog.Error(err))
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+func statementExtension(format entity.StatementFormat) string {
+	if format == entity.StatementFormatCSV {
+		return "csv"
+	}
+
+	return "xml"
+}
+
+func (h *Handler) GetTransfer(ctx context.Context, req *request.GetTransfer) (*response.WalletTransferRefunds, error) {
+	transfer, err := h.svc.GetTransfer(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	transferResp, err := render.NewResponse[response.WalletTransferRefunds](transfer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render wallet transfer response: %w", err)
+	}
+
+	return transferResp, nil
+}
+
+func (h *Handler) ListTransfers(ctx context.Context, req *request.ListTransfers) (*response.WalletTransferList, error) {
+	transfers, err := h.svc.ListTransfers(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfers: %w", err)
+	}
+
+	items, err := render.NewResponses[entity.WalletTransfer, response.WalletTransfer](transfers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render wallet transfers response: %w", err)
+	}
+
+	return &response.WalletTransferList{
+		Items: *items,
+	}, nil
+}
+
+func (h *Handler) InternalTransfer(ctx context.Context, req *request.InternalTransfer) (*response.InternalTransfer, error) {
+	transfer, err := h.svc.InternalTransfer(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to internal transfer: %w", err)
+	}
+
+	transferResp, err := render.NewResponse[response.InternalTransfer](transfer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render internal transfer response: %w", err)
+	}
+
+	return transferResp, nil
+}
+
+func (h *Handler) CompleteInternalTransfer(ctx context.Context, req *request.CompleteInternalTransfer) (*response.InternalTransfer, error) {
+	transfer, err := h.svc.CompleteInternalTransfer(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete internal transfer: %w", err)
+	}
+
+	transferResp, err := render.NewResponse[response.InternalTransfer](transfer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render internal transfer response: %w", err)
+	}
+
+	return transferResp, nil
+}
+
+func (h *Handler) RevertInternalTransfer(ctx context.Context, req *request.RevertInternalTransfer) (*response.InternalTransfer, error) {
+	transfer, err := h.svc.RevertInternalTransfer(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revert internal transfer: %w", err)
+	}
+
+	transferResp, err := render.NewResponse[response.InternalTransfer](transfer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render internal transfer response: %w", err)
+	}
+
+	return transferResp, nil
+}
+
+func (h *Handler) CreateFXQuote(w http.ResponseWriter, r *http.Request, req *request.CreateFXQuote) (*response.FXQuote, error) {
+	quote, err := h.svc.CreateFXQuote(r.Context(), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fx quote: %w", err)
+	}
+
+	quoteResp, err := render.NewResponse[response.FXQuote](quote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render fx quote response: %w", err)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	return quoteResp, nil
+}
+
+func (h *Handler) GetFXQuote(ctx context.Context, req *request.GetFXQuote) (*response.FXQuote, error) {
+	quote, err := h.svc.GetFXQuote(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fx quote: %w", err)
+	}
+
+	quoteResp, err := render.NewResponse[response.FXQuote](quote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render fx quote response: %w", err)
+	}
+
+	return quoteResp, nil
+}
+
+func (h *Handler) ExecuteFXQuote(ctx context.Context, req *request.ExecuteFXQuote) (*response.FXQuoteEvents, error) {
+	quote, err := h.svc.ExecuteFXQuote(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute fx quote: %w", err)
+	}
+
+	quoteResp, err := render.NewResponse[response.FXQuoteEvents](quote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render fx quote response: %w", err)
+	}
+
+	return quoteResp, nil
+}
+
+func (h *Handler) TrialBalance(ctx context.Context, _ *request.GetTrialBalance) (*response.TrialBalance, error) {
+	trialBalance, err := h.svc.TrialBalance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trial balance: %w", err)
+	}
+
+	trialBalanceResp, err := render.NewResponse[response.TrialBalance](trialBalance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render trial balance response: %w", err)
+	}
+
+	return trialBalanceResp, nil
+}
+
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Route("/wallets", func(r chi.Router) {
 		r.Post("/", handler.WrapDefault(h.Create))
+		r.Get("/", handler.WrapDefaultBasic(h.List))
+		r.Get("/by-reference/{referenceID}", handler.WrapDefaultBasic(h.GetByReference))
+		r.Post("/transfers:batch", handler.WrapDefault(h.BatchTransfers))
 		r.Route("/{walletID}", func(r chi.Router) {
 			r.Get("/", handler.WrapDefaultBasic(h.Get))
+			r.Get("/balance", handler.WrapDefaultBasic(h.GetBalance))
+			r.Get("/events", handler.WrapDefaultBasic(h.ListEvents))
+			r.Get("/statements", handler.WrapDefault(h.GetStatement))
+			r.Post("/freeze", handler.WrapDefaultBasic(h.Freeze))
+			r.Post("/unfreeze", handler.WrapDefaultBasic(h.Unfreeze))
+			r.Post("/close", handler.WrapDefaultBasic(h.Close))
 			r.Route("/transfers", func(r chi.Router) {
-				r.Post("/debit", handler.WrapDefaultBasic(h.DebitTransfer))
-				r.Post("/credit", handler.WrapDefaultBasic(h.CreditTransfer))
+				r.Get("/", handler.WrapDefaultBasic(h.ListTransfers))
+				r.Post("/debit", handler.WrapDefault(h.DebitTransfer))
+				r.Post("/credit", handler.WrapDefault(h.CreditTransfer))
 				r.Route("/{transferID}", func(r chi.Router) {
+					r.Get("/", handler.WrapDefaultBasic(h.GetTransfer))
 					r.Post("/complete", handler.WrapDefaultBasic(h.CompleteTransfer))
 					r.Post("/revert", handler.WrapDefaultBasic(h.RevertTransfer))
+					r.Post("/refund", handler.WrapDefaultBasic(h.RefundTransfer))
 				})
 			})
+			r.Route("/scheduled-transfers", func(r chi.Router) {
+				r.Get("/", handler.WrapDefaultBasic(h.ListScheduledTransfers))
+				r.Post("/{transferID}/cancel", handler.WrapDefaultBasic(h.CancelScheduledTransfer))
+			})
+			r.Route("/recurring-transfers", func(r chi.Router) {
+				r.Post("/", handler.WrapDefault(h.CreateRecurringTransfer))
+				r.Get("/", handler.WrapDefaultBasic(h.ListRecurringTransfers))
+				r.Route("/{recurringID}", func(r chi.Router) {
+					r.Get("/", handler.WrapDefaultBasic(h.GetRecurringTransfer))
+					r.Put("/", handler.WrapDefaultBasic(h.UpdateRecurringTransfer))
+					r.Delete("/", handler.WrapDefaultBasic(h.DeleteRecurringTransfer))
+				})
+			})
+		})
+	})
+	r.Route("/admin/wallets/{walletID}", func(r chi.Router) {
+		r.Put("/credit-limit", handler.WrapDefaultBasic(h.SetCreditLimit))
+		r.Put("/tier", handler.WrapDefaultBasic(h.SetTier))
+	})
+	r.Get("/admin/ledger/trial-balance", handler.WrapDefaultBasic(h.TrialBalance))
+	r.Route("/transfers", func(r chi.Router) {
+		r.Post("/", handler.WrapDefaultBasic(h.InternalTransfer))
+		r.Route("/{transferID}", func(r chi.Router) {
+			r.Post("/complete", handler.WrapDefaultBasic(h.CompleteInternalTransfer))
+			r.Post("/revert", handler.WrapDefaultBasic(h.RevertInternalTransfer))
+		})
+	})
+	r.Route("/fx/quotes", func(r chi.Router) {
+		r.Post("/", handler.WrapDefault(h.CreateFXQuote))
+		r.Route("/{quoteID}", func(r chi.Router) {
+			r.Get("/", handler.WrapDefaultBasic(h.GetFXQuote))
+			r.Post("/execute", handler.WrapDefaultBasic(h.ExecuteFXQuote))
 		})
 	})
 }
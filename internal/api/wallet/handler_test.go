@@ -2,9 +2,13 @@ package wallet_test
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	contract_mock "github.com/buni/wallet/internal/api/app/contract/mock"
 	"github.com/buni/wallet/internal/api/app/entity"
@@ -58,6 +62,7 @@ func (s *WalletHandlerTestSuite) buildContext(walletID, transferID string) conte
 func (s *WalletHandlerTestSuite) TestCreateSuccess() {
 	req := &request.CreateWallet{
 		ReferenceID: "ref1",
+		Currency:    "EUR",
 	}
 	expectedBody := response.Wallet{
 		ID:          "id1",
@@ -77,9 +82,86 @@ func (s *WalletHandlerTestSuite) TestCreateSuccess() {
 	s.statusCompare(recorder.Code, http.StatusCreated, recorder.Body.String(), expectedBody)
 }
 
+func (s *WalletHandlerTestSuite) TestCreateWithMetadata() {
+	req := &request.CreateWallet{
+		ReferenceID: "ref1",
+		Currency:    "EUR",
+		Metadata:    entity.Metadata{"customer_id": "42", "channel": "web"},
+	}
+	expectedBody := response.Wallet{
+		ID:          "id1",
+		ReferenceID: "ref1",
+		Metadata:    req.Metadata,
+	}
+
+	s.ctx = s.buildContext("", "")
+
+	s.svcMock.EXPECT().Create(s.ctx, req).Return(entity.Wallet{
+		ID:          expectedBody.ID,
+		ReferenceID: expectedBody.ReferenceID,
+		Metadata:    req.Metadata,
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.Create).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusCreated, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestCreateInvalidMetadata() {
+	tooMany := entity.Metadata{}
+	for i := range 21 {
+		tooMany["key"+strconv.Itoa(i)] = "value"
+	}
+
+	tests := []struct {
+		name     string
+		metadata entity.Metadata
+	}{
+		{name: "too many keys", metadata: tooMany},
+		{name: "invalid key", metadata: entity.Metadata{"order id": "1"}},
+		{name: "long key", metadata: entity.Metadata{strings.Repeat("k", 41): "1"}},
+		{name: "long value", metadata: entity.Metadata{"order_id": strings.Repeat("v", 501)}},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := &request.CreateWallet{
+				ReferenceID: "ref1",
+				Currency:    "EUR",
+				Metadata:    tt.metadata,
+			}
+
+			recorder := httptest.NewRecorder()
+
+			handler.WrapDefault(s.handler.Create).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+			s.Equal(http.StatusBadRequest, recorder.Code)
+			s.Contains(recorder.Body.String(), "metadata can have up to 20 keys")
+		})
+	}
+}
+
+func (s *WalletHandlerTestSuite) TestCreateUnsupportedCurrency() {
+	req := &request.CreateWallet{
+		ReferenceID: "ref1",
+		Currency:    "ABC",
+	}
+
+	s.ctx = s.buildContext("", "")
+
+	s.svcMock.EXPECT().Create(s.ctx, req).Return(entity.Wallet{}, entity.ErrUnsupportedCurrency)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.Create).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "currency must be a supported ISO 4217 code")
+}
+
 func (s *WalletHandlerTestSuite) TestCreateFailure() {
 	req := &request.CreateWallet{
 		ReferenceID: "ref1",
+		Currency:    "EUR",
 	}
 
 	s.ctx = s.buildContext("", "")
@@ -102,9 +184,10 @@ func (s *WalletHandlerTestSuite) TestGetSuccess() {
 		WalletID: "id1",
 	}
 	expectedBody := response.Wallet{
-		ID:          "id1",
-		ReferenceID: "ref1",
-		Balance:     decimal.NewFromInt(100),
+		ID:               "id1",
+		ReferenceID:      "ref1",
+		Balance:          decimal.NewFromInt(100),
+		AvailableBalance: decimal.NewFromInt(100),
 	}
 
 	s.ctx = s.buildContext(req.WalletID, "")
@@ -125,6 +208,150 @@ func (s *WalletHandlerTestSuite) TestGetSuccess() {
 	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
 }
 
+func (s *WalletHandlerTestSuite) TestGetWithCreditLimit() {
+	req := &request.GetWallet{
+		WalletID: "id1",
+	}
+	expectedBody := response.Wallet{
+		ID:               "id1",
+		ReferenceID:      "ref1",
+		Balance:          decimal.NewFromInt(-30),
+		CreditLimit:      decimal.NewFromInt(50),
+		AvailableBalance: decimal.NewFromInt(20),
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().Get(s.ctx, req).Return(entity.WalletBalanceProjection{
+		Wallet: entity.Wallet{
+			ID:          expectedBody.ID,
+			ReferenceID: expectedBody.ReferenceID,
+		},
+		WalletProjection: entity.WalletProjection{
+			Balance:     decimal.NewFromInt(-30),
+			CreditLimit: decimal.NewFromInt(50),
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.Get).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestSetCreditLimitSuccess() {
+	req := &request.SetCreditLimit{
+		WalletID:    "id1",
+		ReferenceID: "ref1",
+		CreditLimit: decimal.NewFromInt(50),
+	}
+	expectedBody := response.WalletEvent{
+		ID:          "event1",
+		TransferID:  "event1",
+		ReferenceID: "ref1",
+		WalletID:    "id1",
+		Amount:      decimal.NewFromInt(50),
+		EventType:   entity.EventTypeCreditLimitChanged,
+		Status:      entity.TransferStatusCompleted,
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().SetCreditLimit(s.ctx, req).Return(entity.WalletEvent{
+		ID:          "event1",
+		TransferID:  "event1",
+		ReferenceID: "ref1",
+		WalletID:    "id1",
+		Amount:      decimal.NewFromInt(50),
+		EventType:   entity.EventTypeCreditLimitChanged,
+		Status:      entity.TransferStatusCompleted,
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.SetCreditLimit).ServeHTTP(recorder, httptest.NewRequest("PUT", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestSetCreditLimitInvalid() {
+	req := &request.SetCreditLimit{
+		WalletID:    "id1",
+		CreditLimit: decimal.NewFromInt(-50),
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().SetCreditLimit(s.ctx, req).Return(entity.WalletEvent{}, entity.ErrInvalidCreditLimit)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.SetCreditLimit).ServeHTTP(recorder, httptest.NewRequest("PUT", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusBadRequest, recorder.Body.String(), nil)
+}
+
+func (s *WalletHandlerTestSuite) TestFreezeSuccess() {
+	req := &request.FreezeWallet{
+		WalletID: "id1",
+	}
+	expectedBody := response.Wallet{
+		ID:               "id1",
+		ReferenceID:      "ref1",
+		Status:           entity.WalletStatusFrozen,
+		Balance:          decimal.NewFromInt(100),
+		AvailableBalance: decimal.NewFromInt(100),
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().Freeze(s.ctx, req).Return(entity.WalletBalanceProjection{
+		Wallet: entity.Wallet{
+			ID:          expectedBody.ID,
+			ReferenceID: expectedBody.ReferenceID,
+			Status:      entity.WalletStatusFrozen,
+		},
+		WalletProjection: entity.WalletProjection{
+			Balance: decimal.NewFromInt(100),
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.Freeze).ServeHTTP(recorder, httptest.NewRequest("POST", "/", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestUnfreezeClosedWallet() {
+	req := &request.UnfreezeWallet{
+		WalletID: "id1",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().Unfreeze(s.ctx, req).Return(entity.WalletBalanceProjection{}, entity.ErrWalletClosed)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.Unfreeze).ServeHTTP(recorder, httptest.NewRequest("POST", "/", nil).WithContext(s.ctx))
+	s.Equal(http.StatusConflict, recorder.Code)
+	s.Contains(recorder.Body.String(), "wallet is closed")
+}
+
+func (s *WalletHandlerTestSuite) TestCloseNotEmpty() {
+	req := &request.CloseWallet{
+		WalletID: "id1",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().Close(s.ctx, req).Return(entity.WalletBalanceProjection{}, entity.ErrWalletNotEmpty)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.Close).ServeHTTP(recorder, httptest.NewRequest("POST", "/", nil).WithContext(s.ctx))
+	s.Equal(http.StatusConflict, recorder.Code)
+	s.Contains(recorder.Body.String(), entity.ErrWalletNotEmpty.Error())
+}
+
 func (s *WalletHandlerTestSuite) TestGetFailure() {
 	req := &request.GetWallet{
 		WalletID: "id1",
@@ -165,6 +392,100 @@ func (s *WalletHandlerTestSuite) TestGetNotFound() {
 	})
 }
 
+func (s *WalletHandlerTestSuite) TestGetByReferenceSuccess() {
+	createdAt := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	expectedBody := response.Wallet{
+		ID:               "id1",
+		ReferenceID:      "ref1",
+		Balance:          decimal.NewFromInt(100),
+		AvailableBalance: decimal.NewFromInt(100),
+		CreatedAt:        createdAt,
+	}
+
+	chiContext := chi.NewRouteContext()
+	chiContext.URLParams.Add("referenceID", "ref1")
+	s.ctx = context.WithValue(s.ctx, chi.RouteCtxKey, chiContext)
+
+	s.svcMock.EXPECT().GetByReferenceID(s.ctx, &request.GetWalletByReference{ReferenceID: "ref1"}).Return(entity.WalletBalanceProjection{
+		Wallet: entity.Wallet{
+			ID:          expectedBody.ID,
+			ReferenceID: expectedBody.ReferenceID,
+			CreatedAt:   createdAt,
+		},
+		WalletProjection: entity.WalletProjection{
+			Balance:   decimal.NewFromInt(100),
+			CreatedAt: createdAt.Add(time.Hour),
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.GetByReference).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestGetByReferenceNotFound() {
+	chiContext := chi.NewRouteContext()
+	chiContext.URLParams.Add("referenceID", "ref1")
+	s.ctx = context.WithValue(s.ctx, chi.RouteCtxKey, chiContext)
+
+	s.svcMock.EXPECT().GetByReferenceID(s.ctx, &request.GetWalletByReference{ReferenceID: "ref1"}).Return(entity.WalletBalanceProjection{}, entity.ErrEntityNotFound)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.GetByReference).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusNotFound, recorder.Body.String(), render.ErrorResponse{
+		Error: &render.Error{
+			Status:  render.NotFoundError,
+			Message: "not found",
+		},
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestListSuccess() {
+	minBalance := decimal.NewFromInt(10)
+	expectedBody := response.WalletList{
+		Items: []response.Wallet{
+			{ID: "id2", ReferenceID: "ref2", Balance: decimal.NewFromInt(20), AvailableBalance: decimal.NewFromInt(20)},
+			{ID: "id1", ReferenceID: "ref1", Balance: decimal.NewFromInt(10), AvailableBalance: decimal.NewFromInt(10)},
+		},
+		NextCursor: "id1",
+	}
+
+	s.svcMock.EXPECT().List(gomock.Any(), &request.ListWallets{
+		Limit:             2,
+		ReferenceIDPrefix: "ref",
+		MinBalance:        &minBalance,
+	}).Return(entity.WalletPage{
+		Wallets: []entity.WalletBalanceProjection{
+			{Wallet: entity.Wallet{ID: "id2", ReferenceID: "ref2"}, WalletProjection: entity.WalletProjection{WalletID: "id2", Balance: decimal.NewFromInt(20)}},
+			{Wallet: entity.Wallet{ID: "id1", ReferenceID: "ref1"}, WalletProjection: entity.WalletProjection{WalletID: "id1", Balance: decimal.NewFromInt(10)}},
+		},
+		NextCursor: "id1",
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.List).ServeHTTP(recorder, httptest.NewRequest("GET", "/?limit=2&reference_id_prefix=ref&min_balance=10", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestListEmpty() {
+	s.svcMock.EXPECT().List(gomock.Any(), &request.ListWallets{}).Return(entity.WalletPage{}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.List).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), response.WalletList{Items: []response.Wallet{}})
+}
+
+func (s *WalletHandlerTestSuite) TestListInvalidCursor() {
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.List).ServeHTTP(recorder, httptest.NewRequest("GET", "/?cursor=not-an-id", nil).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+}
+
 func (s *WalletHandlerTestSuite) TestDebitTransferSuccess() {
 	req := &request.DebitTransfer{
 		WalletID:    "id1",
@@ -172,6 +493,7 @@ func (s *WalletHandlerTestSuite) TestDebitTransferSuccess() {
 		ReferenceID: "ref1",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 	expectedBody := response.WalletEvent{
 		ID:          "event1",
@@ -193,7 +515,7 @@ func (s *WalletHandlerTestSuite) TestDebitTransferSuccess() {
 
 	recorder := httptest.NewRecorder()
 
-	handler.WrapDefaultBasic(s.handler.DebitTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	handler.WrapDefault(s.handler.DebitTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
 	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
 }
 
@@ -204,6 +526,7 @@ func (s *WalletHandlerTestSuite) TestDebitTransferFailure() {
 		ReferenceID: "ref1",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
 	s.ctx = s.buildContext(req.WalletID, "")
@@ -212,7 +535,7 @@ func (s *WalletHandlerTestSuite) TestDebitTransferFailure() {
 
 	recorder := httptest.NewRecorder()
 
-	handler.WrapDefaultBasic(s.handler.DebitTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	handler.WrapDefault(s.handler.DebitTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
 	s.statusCompare(recorder.Code, http.StatusInternalServerError, recorder.Body.String(), render.ErrorResponse{
 		Error: &render.Error{
 			Status:  render.InternalServerError,
@@ -228,6 +551,7 @@ func (s *WalletHandlerTestSuite) TestDebitTransferNotFound() {
 		ReferenceID: "ref1",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
 	s.ctx = s.buildContext(req.WalletID, "")
@@ -236,7 +560,7 @@ func (s *WalletHandlerTestSuite) TestDebitTransferNotFound() {
 
 	recorder := httptest.NewRecorder()
 
-	handler.WrapDefaultBasic(s.handler.DebitTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	handler.WrapDefault(s.handler.DebitTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
 	s.statusCompare(recorder.Code, http.StatusNotFound, recorder.Body.String(), render.ErrorResponse{
 		Error: &render.Error{
 			Status:  render.NotFoundError,
@@ -245,6 +569,62 @@ func (s *WalletHandlerTestSuite) TestDebitTransferNotFound() {
 	})
 }
 
+func (s *WalletHandlerTestSuite) TestDebitTransferCurrencyMismatch() {
+	req := &request.DebitTransfer{
+		WalletID:   "id1",
+		TransferID: "transfer1",
+		Amount:     decimal.NewFromInt(100),
+		Status:     entity.TransferStatusPending,
+		Currency:   "USD",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().DebitTransfer(s.ctx, req).Return(entity.WalletEvent{}, entity.ErrCurrencyMismatch)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.DebitTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "currency must match the wallet currency")
+}
+
+func (s *WalletHandlerTestSuite) TestDebitTransferInvalidExpiry() {
+	req := &request.DebitTransfer{
+		WalletID:   "id1",
+		TransferID: "transfer1",
+		Amount:     decimal.NewFromInt(100),
+		Status:     entity.TransferStatusCompleted,
+		Currency:   "EUR",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().DebitTransfer(s.ctx, req).Return(entity.WalletEvent{}, entity.ErrInvalidTransferExpiry)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.DebitTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "expires_at")
+}
+
+func (s *WalletHandlerTestSuite) TestDebitTransferMissingCurrency() {
+	req := &request.DebitTransfer{
+		WalletID:   "id1",
+		TransferID: "transfer1",
+		Amount:     decimal.NewFromInt(100),
+		Status:     entity.TransferStatusPending,
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.DebitTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+}
+
 func (s *WalletHandlerTestSuite) TestCreditTransferSuccess() {
 	req := &request.CreditTransfer{
 		WalletID:    "id1",
@@ -252,6 +632,7 @@ func (s *WalletHandlerTestSuite) TestCreditTransferSuccess() {
 		ReferenceID: "ref1",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 	expectedBody := response.WalletEvent{
 		ID:          "event1",
@@ -259,6 +640,7 @@ func (s *WalletHandlerTestSuite) TestCreditTransferSuccess() {
 		WalletID:    "id1",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Fee:         decimal.RequireFromString("2.50"),
 	}
 
 	s.ctx = s.buildContext(req.WalletID, "")
@@ -269,14 +651,82 @@ func (s *WalletHandlerTestSuite) TestCreditTransferSuccess() {
 		WalletID:    expectedBody.WalletID,
 		Amount:      expectedBody.Amount,
 		Status:      expectedBody.Status,
+		Fee:         expectedBody.Fee,
 	}, nil)
 
 	recorder := httptest.NewRecorder()
 
-	handler.WrapDefaultBasic(s.handler.CreditTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	handler.WrapDefault(s.handler.CreditTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
 	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
 }
 
+func (s *WalletHandlerTestSuite) TestCreditTransferScheduled() {
+	executeAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	req := &request.CreditTransfer{
+		WalletID:    "id1",
+		TransferID:  "transfer1",
+		ReferenceID: "ref1",
+		Amount:      decimal.NewFromInt(100),
+		Status:      entity.TransferStatusCompleted,
+		Currency:    "EUR",
+		ExecuteAt:   &executeAt,
+	}
+	expectedBody := response.ScheduledTransfer{
+		ID:             "scheduled1",
+		WalletID:       "id1",
+		TransferID:     "transfer1",
+		ReferenceID:    "ref1",
+		Direction:      entity.TransferDirectionCredit,
+		Amount:         decimal.NewFromInt(100),
+		Currency:       "EUR",
+		TransferStatus: entity.TransferStatusCompleted,
+		ExecuteAt:      executeAt,
+		Status:         entity.ScheduleStatusScheduled,
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().ScheduleCreditTransfer(gomock.Any(), testutils.NewMatcher(req)).Return(entity.ScheduledTransfer{
+		ID:             expectedBody.ID,
+		WalletID:       expectedBody.WalletID,
+		TransferID:     expectedBody.TransferID,
+		ReferenceID:    expectedBody.ReferenceID,
+		Direction:      expectedBody.Direction,
+		Amount:         expectedBody.Amount,
+		Currency:       expectedBody.Currency,
+		TransferStatus: expectedBody.TransferStatus,
+		ExecuteAt:      executeAt,
+		Status:         expectedBody.Status,
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.CreditTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusAccepted, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestDebitTransferInvalidExecuteAt() {
+	executeAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	req := &request.DebitTransfer{
+		WalletID:   "id1",
+		TransferID: "transfer1",
+		Amount:     decimal.NewFromInt(100),
+		Status:     entity.TransferStatusCompleted,
+		Currency:   "EUR",
+		ExecuteAt:  &executeAt,
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().ScheduleDebitTransfer(gomock.Any(), testutils.NewMatcher(req)).Return(entity.ScheduledTransfer{}, entity.ErrInvalidExecuteAt)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.DebitTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "execute_at")
+}
+
 func (s *WalletHandlerTestSuite) TestCreditTransferFailure() {
 	req := &request.CreditTransfer{
 		WalletID:    "id1",
@@ -284,6 +734,7 @@ func (s *WalletHandlerTestSuite) TestCreditTransferFailure() {
 		ReferenceID: "ref1",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
 	s.ctx = s.buildContext(req.WalletID, "")
@@ -292,7 +743,7 @@ func (s *WalletHandlerTestSuite) TestCreditTransferFailure() {
 
 	recorder := httptest.NewRecorder()
 
-	handler.WrapDefaultBasic(s.handler.CreditTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	handler.WrapDefault(s.handler.CreditTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
 	s.statusCompare(recorder.Code, http.StatusInternalServerError, recorder.Body.String(), render.ErrorResponse{
 		Error: &render.Error{
 			Status:  render.InternalServerError,
@@ -308,6 +759,7 @@ func (s *WalletHandlerTestSuite) TestCreditTransferNotFound() {
 		ReferenceID: "ref1",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
 	s.ctx = s.buildContext(req.WalletID, "")
@@ -316,7 +768,7 @@ func (s *WalletHandlerTestSuite) TestCreditTransferNotFound() {
 
 	recorder := httptest.NewRecorder()
 
-	handler.WrapDefaultBasic(s.handler.CreditTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	handler.WrapDefault(s.handler.CreditTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
 	s.statusCompare(recorder.Code, http.StatusNotFound, recorder.Body.String(), render.ErrorResponse{
 		Error: &render.Error{
 			Status:  render.NotFoundError,
@@ -375,7 +827,7 @@ func (s *WalletHandlerTestSuite) TestRevertTransferFailure() {
 	})
 }
 
-func (s *WalletHandlerTestSuite) TestRevertTransferNotFound() {
+func (s *WalletHandlerTestSuite) TestRevertTransferUnknownTransfer() {
 	req := &request.RevertTransfer{
 		WalletID:    "id1",
 		TransferID:  "transfer1",
@@ -384,7 +836,7 @@ func (s *WalletHandlerTestSuite) TestRevertTransferNotFound() {
 
 	s.ctx = s.buildContext(req.WalletID, req.TransferID)
 
-	s.svcMock.EXPECT().RevertTransfer(s.ctx, req).Return(entity.WalletEvent{}, entity.ErrEntityNotFound)
+	s.svcMock.EXPECT().RevertTransfer(s.ctx, req).Return(entity.WalletEvent{}, entity.ErrTransferNotFound)
 
 	recorder := httptest.NewRecorder()
 
@@ -397,15 +849,94 @@ func (s *WalletHandlerTestSuite) TestRevertTransferNotFound() {
 	})
 }
 
-func (s *WalletHandlerTestSuite) TestCompleteTransferSuccess() {
-	req := &request.CompleteTransfer{
+func (s *WalletHandlerTestSuite) TestRevertTransferAlreadyCompleted() {
+	req := &request.RevertTransfer{
 		WalletID:    "id1",
 		TransferID:  "transfer1",
 		ReferenceID: "ref1",
 	}
-	expectedBody := response.WalletEvent{
-		ID:          "event1",
-		ReferenceID: "ref1",
+
+	s.ctx = s.buildContext(req.WalletID, req.TransferID)
+
+	s.svcMock.EXPECT().RevertTransfer(s.ctx, req).Return(entity.WalletEvent{}, fmt.Errorf("wrapped: %w", entity.ErrTransferAlreadyCompleted))
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.RevertTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusConflict, recorder.Body.String(), render.ErrorResponse{
+		Error: &render.Error{
+			Status:  render.ConflictError,
+			Message: "transfer already completed",
+		},
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestCompleteTransferAlreadyFailed() {
+	req := &request.CompleteTransfer{
+		WalletID:    "id1",
+		TransferID:  "transfer1",
+		ReferenceID: "ref1",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, req.TransferID)
+
+	s.svcMock.EXPECT().CompleteTransfer(s.ctx, req).Return(entity.WalletEvent{}, entity.ErrTransferAlreadyFailed)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.CompleteTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusConflict, recorder.Code)
+}
+
+func (s *WalletHandlerTestSuite) TestCompleteTransferExpired() {
+	req := &request.CompleteTransfer{
+		WalletID:    "id1",
+		TransferID:  "transfer1",
+		ReferenceID: "ref1",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, req.TransferID)
+
+	s.svcMock.EXPECT().CompleteTransfer(s.ctx, req).Return(entity.WalletEvent{}, entity.ErrTransferExpired)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.CompleteTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusConflict, recorder.Code)
+	s.Contains(recorder.Body.String(), "transfer expired")
+}
+
+func (s *WalletHandlerTestSuite) TestRevertTransferNotFound() {
+	req := &request.RevertTransfer{
+		WalletID:    "id1",
+		TransferID:  "transfer1",
+		ReferenceID: "ref1",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, req.TransferID)
+
+	s.svcMock.EXPECT().RevertTransfer(s.ctx, req).Return(entity.WalletEvent{}, entity.ErrEntityNotFound)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.RevertTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusNotFound, recorder.Body.String(), render.ErrorResponse{
+		Error: &render.Error{
+			Status:  render.NotFoundError,
+			Message: "not found",
+		},
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestCompleteTransferSuccess() {
+	req := &request.CompleteTransfer{
+		WalletID:    "id1",
+		TransferID:  "transfer1",
+		ReferenceID: "ref1",
+	}
+	expectedBody := response.WalletEvent{
+		ID:          "event1",
+		ReferenceID: "ref1",
 		WalletID:    "id1",
 		Status:      entity.TransferStatusCompleted,
 	}
@@ -425,6 +956,99 @@ func (s *WalletHandlerTestSuite) TestCompleteTransferSuccess() {
 	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
 }
 
+func (s *WalletHandlerTestSuite) TestCompleteTransferInvalidCaptureAmount() {
+	amount := decimal.NewFromInt(150)
+	req := &request.CompleteTransfer{
+		WalletID:   "id1",
+		TransferID: "transfer1",
+		Amount:     &amount,
+	}
+
+	s.ctx = s.buildContext(req.WalletID, req.TransferID)
+
+	s.svcMock.EXPECT().CompleteTransfer(gomock.Any(), testutils.NewMatcher(req)).Return(entity.WalletEvent{}, entity.ErrInvalidCaptureAmount)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.CompleteTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "amount")
+}
+
+func (s *WalletHandlerTestSuite) TestRefundTransferSuccess() {
+	amount := decimal.NewFromInt(40)
+	req := &request.RefundTransfer{
+		WalletID:    "id1",
+		TransferID:  "transfer1",
+		ReferenceID: "ref1",
+		Amount:      &amount,
+	}
+	expectedBody := response.TransferRefund{
+		RefundID:    "transfer1:refund:1",
+		WalletID:    "id1",
+		TransferID:  "transfer1",
+		ReferenceID: "ref1",
+		Amount:      amount,
+		EventID:     "event1",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, req.TransferID)
+
+	s.svcMock.EXPECT().RefundTransfer(gomock.Any(), testutils.NewMatcher(req)).Return(entity.TransferRefund{
+		RefundID:    expectedBody.RefundID,
+		WalletID:    expectedBody.WalletID,
+		TransferID:  expectedBody.TransferID,
+		ReferenceID: expectedBody.ReferenceID,
+		Amount:      amount,
+		EventID:     expectedBody.EventID,
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.RefundTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestRefundTransferInvalidAmount() {
+	amount := decimal.NewFromInt(150)
+	req := &request.RefundTransfer{
+		WalletID:   "id1",
+		TransferID: "transfer1",
+		Amount:     &amount,
+	}
+
+	s.ctx = s.buildContext(req.WalletID, req.TransferID)
+
+	s.svcMock.EXPECT().RefundTransfer(gomock.Any(), testutils.NewMatcher(req)).Return(entity.TransferRefund{}, entity.ErrInvalidRefundAmount)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.RefundTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "amount")
+}
+
+func (s *WalletHandlerTestSuite) TestRefundTransferNotRefundable() {
+	req := &request.RefundTransfer{
+		WalletID:   "id1",
+		TransferID: "transfer1",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, req.TransferID)
+
+	s.svcMock.EXPECT().RefundTransfer(gomock.Any(), testutils.NewMatcher(req)).Return(entity.TransferRefund{}, fmt.Errorf("wrapped: %w", entity.ErrTransferNotRefundable))
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.RefundTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusConflict, recorder.Body.String(), render.ErrorResponse{
+		Error: &render.Error{
+			Status:  render.ConflictError,
+			Message: entity.ErrTransferNotRefundable.Error(),
+		},
+	})
+}
+
 func (s *WalletHandlerTestSuite) TestCompleteTransferFailure() {
 	req := &request.CompleteTransfer{
 		WalletID:    "id1",
@@ -469,6 +1093,1137 @@ func (s *WalletHandlerTestSuite) TestCompleteTransferNotFound() {
 	})
 }
 
-func TestWalletHandlerTestSuite(t *testing.T) {
-	suite.Run(t, new(WalletHandlerTestSuite))
+func (s *WalletHandlerTestSuite) TestInternalTransferSuccess() {
+	req := &request.InternalTransfer{
+		SourceWalletID:      "id1",
+		DestinationWalletID: "id2",
+		TransferID:          "transfer1",
+		ReferenceID:         "ref1",
+		Amount:              decimal.NewFromInt(100),
+		Status:              entity.TransferStatusPending,
+	}
+	expectedBody := response.InternalTransfer{
+		TransferID:          "transfer1",
+		ReferenceID:         "ref1",
+		SourceWalletID:      "id1",
+		DestinationWalletID: "id2",
+		Amount:              decimal.NewFromInt(100),
+		CreditEvent: response.WalletEvent{
+			ID:          "event1",
+			TransferID:  "transfer1",
+			ReferenceID: "ref1",
+			WalletID:    "id1",
+			Amount:      decimal.NewFromInt(100),
+			EventType:   entity.EventTypeCreditTransfer,
+			Status:      entity.TransferStatusPending,
+		},
+		DebitEvent: response.WalletEvent{
+			ID:          "event2",
+			TransferID:  "transfer1",
+			ReferenceID: "ref1",
+			WalletID:    "id2",
+			Amount:      decimal.NewFromInt(100),
+			EventType:   entity.EventTypeDebitTransfer,
+			Status:      entity.TransferStatusPending,
+		},
+	}
+
+	s.ctx = s.buildContext("", "")
+
+	s.svcMock.EXPECT().InternalTransfer(s.ctx, req).Return(entity.InternalTransferEvents{
+		InternalTransfer: entity.InternalTransfer{
+			TransferID:          expectedBody.TransferID,
+			ReferenceID:         expectedBody.ReferenceID,
+			SourceWalletID:      expectedBody.SourceWalletID,
+			DestinationWalletID: expectedBody.DestinationWalletID,
+			Amount:              expectedBody.Amount,
+		},
+		CreditEvent: entity.WalletEvent{
+			ID:          expectedBody.CreditEvent.ID,
+			TransferID:  expectedBody.CreditEvent.TransferID,
+			ReferenceID: expectedBody.CreditEvent.ReferenceID,
+			WalletID:    expectedBody.CreditEvent.WalletID,
+			Amount:      expectedBody.CreditEvent.Amount,
+			EventType:   expectedBody.CreditEvent.EventType,
+			Status:      expectedBody.CreditEvent.Status,
+		},
+		DebitEvent: entity.WalletEvent{
+			ID:          expectedBody.DebitEvent.ID,
+			TransferID:  expectedBody.DebitEvent.TransferID,
+			ReferenceID: expectedBody.DebitEvent.ReferenceID,
+			WalletID:    expectedBody.DebitEvent.WalletID,
+			Amount:      expectedBody.DebitEvent.Amount,
+			EventType:   expectedBody.DebitEvent.EventType,
+			Status:      expectedBody.DebitEvent.Status,
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.InternalTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestInternalTransferFailure() {
+	req := &request.InternalTransfer{
+		SourceWalletID:      "id1",
+		DestinationWalletID: "id2",
+		TransferID:          "transfer1",
+		ReferenceID:         "ref1",
+		Amount:              decimal.NewFromInt(100),
+		Status:              entity.TransferStatusPending,
+	}
+
+	s.ctx = s.buildContext("", "")
+
+	s.svcMock.EXPECT().InternalTransfer(s.ctx, req).Return(entity.InternalTransferEvents{}, context.DeadlineExceeded)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.InternalTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusInternalServerError, recorder.Body.String(), render.ErrorResponse{
+		Error: &render.Error{
+			Status:  render.InternalServerError,
+			Message: "internal server error",
+		},
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestInternalTransferSameWallet() {
+	req := &request.InternalTransfer{
+		SourceWalletID:      "id1",
+		DestinationWalletID: "id1",
+		TransferID:          "transfer1",
+		ReferenceID:         "ref1",
+		Amount:              decimal.NewFromInt(100),
+		Status:              entity.TransferStatusPending,
+	}
+
+	s.ctx = s.buildContext("", "")
+
+	s.svcMock.EXPECT().InternalTransfer(s.ctx, req).Return(entity.InternalTransferEvents{}, entity.ErrSameWalletTransfer)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.InternalTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "destination_wallet_id")
+}
+
+func (s *WalletHandlerTestSuite) TestCompleteInternalTransferSuccess() {
+	req := &request.CompleteInternalTransfer{
+		TransferID:  "transfer1",
+		ReferenceID: "ref1",
+	}
+	expectedBody := response.InternalTransfer{
+		TransferID:          "transfer1",
+		SourceWalletID:      "id1",
+		DestinationWalletID: "id2",
+		CreditEvent: response.WalletEvent{
+			ID:          "event1",
+			ReferenceID: "ref1",
+			WalletID:    "id1",
+			Status:      entity.TransferStatusCompleted,
+		},
+		DebitEvent: response.WalletEvent{
+			ID:          "event2",
+			ReferenceID: "ref1",
+			WalletID:    "id2",
+			Status:      entity.TransferStatusCompleted,
+		},
+	}
+
+	s.ctx = s.buildContext("", req.TransferID)
+
+	s.svcMock.EXPECT().CompleteInternalTransfer(s.ctx, req).Return(entity.InternalTransferEvents{
+		InternalTransfer: entity.InternalTransfer{
+			TransferID:          expectedBody.TransferID,
+			SourceWalletID:      expectedBody.SourceWalletID,
+			DestinationWalletID: expectedBody.DestinationWalletID,
+		},
+		CreditEvent: entity.WalletEvent{
+			ID:          expectedBody.CreditEvent.ID,
+			ReferenceID: expectedBody.CreditEvent.ReferenceID,
+			WalletID:    expectedBody.CreditEvent.WalletID,
+			Status:      expectedBody.CreditEvent.Status,
+		},
+		DebitEvent: entity.WalletEvent{
+			ID:          expectedBody.DebitEvent.ID,
+			ReferenceID: expectedBody.DebitEvent.ReferenceID,
+			WalletID:    expectedBody.DebitEvent.WalletID,
+			Status:      expectedBody.DebitEvent.Status,
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.CompleteInternalTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestCompleteInternalTransferNotFound() {
+	req := &request.CompleteInternalTransfer{
+		TransferID:  "transfer1",
+		ReferenceID: "ref1",
+	}
+
+	s.ctx = s.buildContext("", req.TransferID)
+
+	s.svcMock.EXPECT().CompleteInternalTransfer(s.ctx, req).Return(entity.InternalTransferEvents{}, entity.ErrEntityNotFound)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.CompleteInternalTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusNotFound, recorder.Body.String(), render.ErrorResponse{
+		Error: &render.Error{
+			Status:  render.NotFoundError,
+			Message: "not found",
+		},
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestRevertInternalTransferNotFound() {
+	req := &request.RevertInternalTransfer{
+		TransferID:  "transfer1",
+		ReferenceID: "ref1",
+	}
+
+	s.ctx = s.buildContext("", req.TransferID)
+
+	s.svcMock.EXPECT().RevertInternalTransfer(s.ctx, req).Return(entity.InternalTransferEvents{}, entity.ErrEntityNotFound)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.RevertInternalTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusNotFound, recorder.Body.String(), render.ErrorResponse{
+		Error: &render.Error{
+			Status:  render.NotFoundError,
+			Message: "not found",
+		},
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestCreditTransferStreamVersionConflict() {
+	req := &request.CreditTransfer{
+		WalletID:    "id1",
+		TransferID:  "transfer1",
+		ReferenceID: "ref1",
+		Amount:      decimal.NewFromInt(100),
+		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().CreditTransfer(s.ctx, req).Return(entity.WalletEvent{}, entity.ErrStreamVersionConflict)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.CreditTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusConflict, recorder.Body.String(), render.ErrorResponse{
+		Error: &render.Error{
+			Status:  render.ConflictError,
+			Message: "conflict with existing resource",
+		},
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestListEventsSuccess() {
+	expectedBody := response.WalletEventList{
+		Items: []response.WalletEvent{
+			{
+				ID:         "event2",
+				TransferID: "transfer2",
+				WalletID:   "id1",
+				Amount:     decimal.NewFromInt(20),
+				EventType:  entity.EventTypeCreditTransfer,
+				Status:     entity.TransferStatusCompleted,
+				Sequence:   2,
+			},
+			{
+				ID:         "event1",
+				TransferID: "transfer1",
+				WalletID:   "id1",
+				Amount:     decimal.NewFromInt(100),
+				EventType:  entity.EventTypeDebitTransfer,
+				Status:     entity.TransferStatusCompleted,
+				Sequence:   1,
+			},
+		},
+		NextCursor: "event1",
+	}
+
+	s.ctx = s.buildContext("id1", "")
+
+	s.svcMock.EXPECT().ListEvents(gomock.Any(), &request.ListWalletEvents{
+		WalletID:  "id1",
+		Limit:     2,
+		EventType: entity.EventTypeCreditTransfer,
+	}).Return(entity.WalletEventPage{
+		Events: []entity.WalletEvent{
+			{ID: "event2", TransferID: "transfer2", WalletID: "id1", Amount: decimal.NewFromInt(20), EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Sequence: 2},
+			{ID: "event1", TransferID: "transfer1", WalletID: "id1", Amount: decimal.NewFromInt(100), EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Sequence: 1},
+		},
+		NextCursor: "event1",
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.ListEvents).ServeHTTP(recorder, httptest.NewRequest("GET", "/?limit=2&event_type=credit_transfer", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestListEventsEmpty() {
+	s.ctx = s.buildContext("id1", "")
+
+	s.svcMock.EXPECT().ListEvents(gomock.Any(), &request.ListWalletEvents{WalletID: "id1"}).Return(entity.WalletEventPage{}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.ListEvents).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), response.WalletEventList{Items: []response.WalletEvent{}})
+}
+
+func (s *WalletHandlerTestSuite) TestListEventsInvalidLimit() {
+	s.ctx = s.buildContext("id1", "")
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.ListEvents).ServeHTTP(recorder, httptest.NewRequest("GET", "/?limit=1000", nil).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+}
+
+func (s *WalletHandlerTestSuite) TestListEventsNotFound() {
+	s.ctx = s.buildContext("id1", "")
+
+	s.svcMock.EXPECT().ListEvents(gomock.Any(), &request.ListWalletEvents{WalletID: "id1"}).Return(entity.WalletEventPage{}, entity.ErrEntityNotFound)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.ListEvents).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusNotFound, recorder.Body.String(), render.ErrorResponse{
+		Error: &render.Error{
+			Status:  render.NotFoundError,
+			Message: "not found",
+		},
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestGetTransferSuccess() {
+	expectedBody := response.WalletTransferRefunds{
+		WalletTransfer: response.WalletTransfer{
+			TransferID:     "transfer1",
+			WalletID:       "id1",
+			Amount:         decimal.NewFromInt(100),
+			CapturedAmount: decimal.NewFromInt(100),
+			Direction:      entity.TransferDirectionDebit,
+			Status:         entity.TransferStatusCompleted,
+		},
+		RefundedAmount:   decimal.NewFromInt(40),
+		RefundableAmount: decimal.NewFromInt(60),
+		Refunds: []response.TransferRefund{
+			{
+				RefundID:   "transfer1:refund:1",
+				WalletID:   "id1",
+				TransferID: "transfer1",
+				Amount:     decimal.NewFromInt(40),
+				EventID:    "event1",
+			},
+		},
+	}
+
+	s.ctx = s.buildContext("id1", "transfer1")
+
+	s.svcMock.EXPECT().GetTransfer(gomock.Any(), &request.GetTransfer{WalletID: "id1", TransferID: "transfer1"}).Return(entity.WalletTransferRefunds{
+		WalletTransfer: entity.WalletTransfer{
+			TransferID:     "transfer1",
+			WalletID:       "id1",
+			Amount:         decimal.NewFromInt(100),
+			CapturedAmount: decimal.NewFromInt(100),
+			Direction:      entity.TransferDirectionDebit,
+			Status:         entity.TransferStatusCompleted,
+			LastSequence:   1,
+		},
+		Refunds: []entity.TransferRefund{
+			{
+				RefundID:   "transfer1:refund:1",
+				WalletID:   "id1",
+				TransferID: "transfer1",
+				Amount:     decimal.NewFromInt(40),
+				EventID:    "event1",
+			},
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.GetTransfer).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestGetTransferNotFound() {
+	s.ctx = s.buildContext("id1", "transfer1")
+
+	s.svcMock.EXPECT().GetTransfer(gomock.Any(), &request.GetTransfer{WalletID: "id1", TransferID: "transfer1"}).Return(entity.WalletTransferRefunds{}, entity.ErrEntityNotFound)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.GetTransfer).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusNotFound, recorder.Body.String(), render.ErrorResponse{
+		Error: &render.Error{
+			Status:  render.NotFoundError,
+			Message: "not found",
+		},
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestListTransfersSuccess() {
+	expectedBody := response.WalletTransferList{
+		Items: []response.WalletTransfer{
+			{
+				TransferID: "transfer1",
+				WalletID:   "id1",
+				Amount:     decimal.NewFromInt(100),
+				Direction:  entity.TransferDirectionCredit,
+				Status:     entity.TransferStatusPending,
+			},
+		},
+	}
+
+	s.ctx = s.buildContext("id1", "")
+
+	s.svcMock.EXPECT().ListTransfers(gomock.Any(), &request.ListTransfers{WalletID: "id1", Status: entity.TransferStatusPending}).Return([]entity.WalletTransfer{
+		{TransferID: "transfer1", WalletID: "id1", Amount: decimal.NewFromInt(100), Direction: entity.TransferDirectionCredit, Status: entity.TransferStatusPending},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.ListTransfers).ServeHTTP(recorder, httptest.NewRequest("GET", "/?status=pending", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestListTransfersMetadataFilter() {
+	expectedBody := response.WalletTransferList{
+		Items: []response.WalletTransfer{
+			{
+				TransferID: "transfer1",
+				WalletID:   "id1",
+				Amount:     decimal.NewFromInt(100),
+				Direction:  entity.TransferDirectionDebit,
+				Status:     entity.TransferStatusCompleted,
+				Metadata:   entity.Metadata{"order_id": "1", "channel": "web"},
+			},
+		},
+	}
+
+	s.ctx = s.buildContext("id1", "")
+
+	s.svcMock.EXPECT().ListTransfers(gomock.Any(), &request.ListTransfers{WalletID: "id1", Metadata: []string{"order_id:1", "channel:web"}}).Return([]entity.WalletTransfer{
+		{
+			TransferID: "transfer1",
+			WalletID:   "id1",
+			Amount:     decimal.NewFromInt(100),
+			Direction:  entity.TransferDirectionDebit,
+			Status:     entity.TransferStatusCompleted,
+			Metadata:   entity.Metadata{"order_id": "1", "channel": "web"},
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.ListTransfers).ServeHTTP(recorder, httptest.NewRequest("GET", "/?metadata=order_id:1&metadata=channel:web", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestListTransfersInvalidMetadataFilter() {
+	s.ctx = s.buildContext("id1", "")
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.ListTransfers).ServeHTTP(recorder, httptest.NewRequest("GET", "/?metadata=order_id", nil).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "has to be a key:value pair")
+}
+
+func TestWalletHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(WalletHandlerTestSuite))
+}
+
+func (s *WalletHandlerTestSuite) TestCreditTransferLimitExceeded() {
+	req := &request.CreditTransfer{
+		WalletID:   "id1",
+		TransferID: "transfer1",
+		Amount:     decimal.NewFromInt(100),
+		Status:     entity.TransferStatusCompleted,
+		Currency:   "EUR",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().CreditTransfer(s.ctx, req).Return(entity.WalletEvent{}, fmt.Errorf("wrapped: %w", &entity.LimitExceededError{
+		Limit: entity.VelocityLimit{Kind: entity.LimitKindCreditAmount, Window: 24 * time.Hour, Max: decimal.NewFromInt(1000)},
+	}))
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.CreditTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusConflict, recorder.Body.String(), render.ErrorResponse{
+		Error: &render.Error{
+			Status:  render.ConflictError,
+			Message: "limit exceeded",
+			Errors: &render.FieldErrors{
+				{Field: "credit_amount", Message: "at most 1000 per 24h0m0s"},
+			},
+		},
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestSetTierSuccess() {
+	req := &request.SetWalletTier{
+		WalletID: "id1",
+		Tier:     "premium",
+	}
+	expectedBody := response.Wallet{
+		ID:               "id1",
+		ReferenceID:      "ref1",
+		Status:           entity.WalletStatusActive,
+		Tier:             "premium",
+		Balance:          decimal.NewFromInt(100),
+		AvailableBalance: decimal.NewFromInt(100),
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().SetTier(s.ctx, req).Return(entity.WalletBalanceProjection{
+		Wallet: entity.Wallet{
+			ID:          expectedBody.ID,
+			ReferenceID: expectedBody.ReferenceID,
+			Status:      entity.WalletStatusActive,
+			Tier:        "premium",
+		},
+		WalletProjection: entity.WalletProjection{
+			Balance: decimal.NewFromInt(100),
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.SetTier).ServeHTTP(recorder, httptest.NewRequest("PUT", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestSetTierUnknown() {
+	req := &request.SetWalletTier{
+		WalletID: "id1",
+		Tier:     "unknown",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().SetTier(s.ctx, req).Return(entity.WalletBalanceProjection{}, entity.ErrUnknownWalletTier)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.SetTier).ServeHTTP(recorder, httptest.NewRequest("PUT", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "tier must be standard")
+}
+
+func (s *WalletHandlerTestSuite) TestGetBalanceSuccess() {
+	req := &request.GetBalance{
+		WalletID: "id1",
+		AsOf:     "2024-06-30T23:59:59Z",
+	}
+	expectedBody := response.WalletBalance{
+		WalletID:      "id1",
+		Balance:       decimal.NewFromInt(70),
+		PendingCredit: decimal.NewFromInt(30),
+		LastEventID:   "e2",
+		LastSequence:  2,
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().GetBalance(s.ctx, req).Return(entity.WalletProjection{
+		WalletID:      "id1",
+		Balance:       decimal.NewFromInt(70),
+		PendingCredit: decimal.NewFromInt(30),
+		LastEventID:   "e2",
+		LastSequence:  2,
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.GetBalance).ServeHTTP(recorder, httptest.NewRequest("GET", "/?as_of="+req.AsOf, nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestGetBalanceInvalidAsOf() {
+	req := &request.GetBalance{
+		WalletID: "id1",
+		AsOf:     "unknown",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().GetBalance(s.ctx, req).Return(entity.WalletProjection{}, entity.ErrInvalidBalanceAsOf)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.GetBalance).ServeHTTP(recorder, httptest.NewRequest("GET", "/?as_of="+req.AsOf, nil).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "as_of must be an RFC3339 timestamp")
+}
+
+func (s *WalletHandlerTestSuite) statement() entity.Statement {
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	return entity.Statement{
+		WalletID:       "id1",
+		Currency:       "EUR",
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		OpeningBalance: decimal.NewFromInt(100),
+		ClosingBalance: decimal.NewFromInt(80),
+		Lines: []entity.StatementLine{
+			{
+				EventID:       "event1",
+				TransferID:    "transfer1",
+				ReferenceID:   "ref1",
+				EventType:     entity.EventTypeCreditTransfer,
+				Direction:     entity.TransferDirectionCredit,
+				Status:        entity.TransferStatusCompleted,
+				Amount:        decimal.NewFromInt(20),
+				BalanceChange: decimal.NewFromInt(-20),
+				Balance:       decimal.NewFromInt(80),
+				Sequence:      3,
+				CreatedAt:     from.Add(time.Hour),
+			},
+		},
+		CreatedAt: from.AddDate(0, 1, 1),
+	}
+}
+
+func (s *WalletHandlerTestSuite) TestGetStatementJSON() {
+	statement := s.statement()
+	req := &request.GetStatement{WalletID: "id1", From: statement.From, To: statement.To}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().GetStatement(gomock.Any(), req).Return(statement, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.GetStatement).ServeHTTP(recorder, httptest.NewRequest("GET", "/?from=2024-07-01T00:00:00Z&to=2024-08-01T00:00:00Z", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), response.Statement{
+		WalletID:       "id1",
+		Currency:       "EUR",
+		From:           statement.From,
+		To:             statement.To,
+		OpeningBalance: decimal.NewFromInt(100),
+		ClosingBalance: decimal.NewFromInt(80),
+		Lines: []response.StatementLine{
+			{
+				EventID:       "event1",
+				TransferID:    "transfer1",
+				ReferenceID:   "ref1",
+				EventType:     entity.EventTypeCreditTransfer,
+				Direction:     entity.TransferDirectionCredit,
+				Status:        entity.TransferStatusCompleted,
+				Amount:        decimal.NewFromInt(20),
+				BalanceChange: decimal.NewFromInt(-20),
+				Balance:       decimal.NewFromInt(80),
+				Sequence:      3,
+				CreatedAt:     statement.Lines[0].CreatedAt,
+			},
+		},
+		CreatedAt: statement.CreatedAt,
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestGetStatementCSV() {
+	s.ctx = s.buildContext("id1", "")
+
+	s.svcMock.EXPECT().GetStatement(gomock.Any(), &request.GetStatement{WalletID: "id1", Format: entity.StatementFormatCSV}).Return(s.statement(), nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.GetStatement).ServeHTTP(recorder, httptest.NewRequest("GET", "/?format=csv", nil).WithContext(s.ctx))
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Equal("text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+	s.Equal(`attachment; filename="statement-id1-20240801.csv"`, recorder.Header().Get("Content-Disposition"))
+	s.Equal(`record,event_id,transfer_id,reference_id,event_type,direction,status,amount,balance_change,balance,sequence,created_at
+opening_balance,,,,,,,,,100,,2024-07-01T00:00:00Z
+transfer,event1,transfer1,ref1,credit_transfer,credit,completed,20,-20,80,3,2024-07-01T01:00:00Z
+closing_balance,,,,,,,,,80,,2024-08-01T00:00:00Z
+`, recorder.Body.String())
+}
+
+func (s *WalletHandlerTestSuite) TestGetStatementCamt053() {
+	s.ctx = s.buildContext("id1", "")
+
+	s.svcMock.EXPECT().GetStatement(gomock.Any(), &request.GetStatement{WalletID: "id1", Format: entity.StatementFormatCamt053}).Return(s.statement(), nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.GetStatement).ServeHTTP(recorder, httptest.NewRequest("GET", "/?format=camt053", nil).WithContext(s.ctx))
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Equal("application/xml; charset=utf-8", recorder.Header().Get("Content-Type"))
+	s.Contains(recorder.Body.String(), `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">`)
+	s.Contains(recorder.Body.String(), `<Amt Ccy="EUR">20</Amt>`)
+}
+
+func (s *WalletHandlerTestSuite) TestGetStatementInvalidPeriod() {
+	s.ctx = s.buildContext("id1", "")
+
+	s.svcMock.EXPECT().GetStatement(gomock.Any(), gomock.Any()).Return(entity.Statement{}, entity.ErrInvalidStatementPeriod)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.GetStatement).ServeHTTP(recorder, httptest.NewRequest("GET", "/?from=2024-08-01T00:00:00Z&to=2024-07-01T00:00:00Z", nil).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "to must be after from")
+}
+
+func (s *WalletHandlerTestSuite) TestBatchTransfersBestEffort() {
+	req := &request.BatchTransfers{
+		Items: []request.BatchTransferItem{
+			{Operation: entity.BatchOperationDebit, WalletID: "id1", TransferID: "transfer1", Amount: decimal.NewFromInt(100), Currency: "EUR", Status: entity.TransferStatusCompleted},
+			{Operation: entity.BatchOperationComplete, WalletID: "id2", TransferID: "transfer2"},
+		},
+	}
+
+	s.ctx = s.buildContext("", "")
+
+	s.svcMock.EXPECT().BatchTransfers(s.ctx, testutils.NewMatcher(req)).Return([]entity.BatchTransferResult{
+		{Event: entity.WalletEvent{ID: "event1", TransferID: "transfer1", WalletID: "id1", Amount: decimal.NewFromInt(100), Status: entity.TransferStatusCompleted}},
+		{Err: fmt.Errorf("wrapped: %w", entity.ErrTransferNotFound)},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.BatchTransfers).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), response.BatchTransfers{
+		Items: []response.BatchTransferResult{
+			{
+				StatusCode: http.StatusOK,
+				Event:      &response.WalletEvent{ID: "event1", TransferID: "transfer1", WalletID: "id1", Amount: decimal.NewFromInt(100), Status: entity.TransferStatusCompleted},
+			},
+			{
+				StatusCode: http.StatusNotFound,
+				Error:      render.NewError(render.NotFoundError, "not found"),
+			},
+		},
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestBatchTransfersAtomicItemError() {
+	req := &request.BatchTransfers{
+		Atomic: true,
+		Items: []request.BatchTransferItem{
+			{Operation: entity.BatchOperationRevert, WalletID: "id1", TransferID: "transfer1"},
+			{Operation: entity.BatchOperationComplete, WalletID: "id1", TransferID: "transfer2"},
+		},
+	}
+
+	s.ctx = s.buildContext("", "")
+
+	s.svcMock.EXPECT().BatchTransfers(s.ctx, testutils.NewMatcher(req)).Return(nil, fmt.Errorf("wrapped: %w", &entity.BatchItemError{Index: 1, Err: entity.ErrTransferAlreadyCompleted}))
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.BatchTransfers).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusConflict, recorder.Body.String(), response.BatchTransferError{
+		Index: 1,
+		Error: render.NewError(render.ConflictError, entity.ErrTransferAlreadyCompleted.Error()),
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestBatchTransfersInvalidOperation() {
+	req := &request.BatchTransfers{
+		Items: []request.BatchTransferItem{
+			{Operation: "transfer", WalletID: "id1", TransferID: "transfer1"},
+		},
+	}
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.BatchTransfers).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.buildContext("", "")))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "operation")
+}
+
+func (s *WalletHandlerTestSuite) TestTrialBalanceSuccess() {
+	s.ctx = s.buildContext("", "")
+
+	s.svcMock.EXPECT().TrialBalance(s.ctx).Return(entity.NewTrialBalance([]entity.LedgerAccountBalance{
+		{Account: entity.LedgerAccountFunding, Currency: "EUR", Balance: decimal.NewFromInt(-100)},
+		{Account: entity.LedgerAccountWallets, Currency: "EUR", Balance: decimal.NewFromInt(100)},
+	}), nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.TrialBalance).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), response.TrialBalance{
+		Accounts: []response.LedgerAccountBalance{
+			{Account: entity.LedgerAccountFunding, Currency: "EUR", Balance: decimal.NewFromInt(-100)},
+			{Account: entity.LedgerAccountWallets, Currency: "EUR", Balance: decimal.NewFromInt(100)},
+		},
+		Totals:   []response.LedgerCurrencyTotal{{Currency: "EUR", Total: decimal.NewFromInt(0)}},
+		Balanced: true,
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestListScheduledTransfersSuccess() {
+	executeAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	expectedBody := response.ScheduledTransferList{
+		Items: []response.ScheduledTransfer{
+			{
+				ID:             "scheduled1",
+				WalletID:       "id1",
+				TransferID:     "transfer1",
+				Direction:      entity.TransferDirectionDebit,
+				Amount:         decimal.NewFromInt(100),
+				Currency:       "EUR",
+				TransferStatus: entity.TransferStatusCompleted,
+				ExecuteAt:      executeAt,
+				Status:         entity.ScheduleStatusScheduled,
+			},
+		},
+	}
+
+	s.ctx = s.buildContext("id1", "")
+
+	s.svcMock.EXPECT().ListScheduledTransfers(gomock.Any(), &request.ListScheduledTransfers{WalletID: "id1", Status: entity.ScheduleStatusScheduled}).Return([]entity.ScheduledTransfer{
+		{
+			ID:             "scheduled1",
+			WalletID:       "id1",
+			TransferID:     "transfer1",
+			Direction:      entity.TransferDirectionDebit,
+			Amount:         decimal.NewFromInt(100),
+			Currency:       "EUR",
+			TransferStatus: entity.TransferStatusCompleted,
+			ExecuteAt:      executeAt,
+			Status:         entity.ScheduleStatusScheduled,
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.ListScheduledTransfers).ServeHTTP(recorder, httptest.NewRequest("GET", "/?status=scheduled", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestCancelScheduledTransferSuccess() {
+	s.ctx = s.buildContext("id1", "transfer1")
+
+	s.svcMock.EXPECT().CancelScheduledTransfer(gomock.Any(), &request.CancelScheduledTransfer{WalletID: "id1", TransferID: "transfer1"}).Return(entity.ScheduledTransfer{
+		ID:         "scheduled1",
+		WalletID:   "id1",
+		TransferID: "transfer1",
+		Status:     entity.ScheduleStatusCancelled,
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.CancelScheduledTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", nil).WithContext(s.ctx))
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Contains(recorder.Body.String(), `"status":"cancelled"`)
+}
+
+func (s *WalletHandlerTestSuite) TestCancelScheduledTransferNotScheduled() {
+	s.ctx = s.buildContext("id1", "transfer1")
+
+	s.svcMock.EXPECT().CancelScheduledTransfer(gomock.Any(), &request.CancelScheduledTransfer{WalletID: "id1", TransferID: "transfer1"}).Return(entity.ScheduledTransfer{}, entity.ErrTransferNotScheduled)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.CancelScheduledTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", nil).WithContext(s.ctx))
+	s.Equal(http.StatusConflict, recorder.Code)
+}
+
+func (s *WalletHandlerTestSuite) TestCreateRecurringTransferSuccess() {
+	startAt := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	nextRunAt := time.Date(2030, 2, 1, 9, 0, 0, 0, time.UTC)
+	req := &request.CreateRecurringTransfer{
+		WalletID:    "id1",
+		ReferenceID: "rent",
+		Direction:   entity.TransferDirectionCredit,
+		Amount:      decimal.NewFromInt(500),
+		Currency:    "EUR",
+		Rule:        "FREQ=MONTHLY;BYMONTHDAY=1",
+		StartAt:     &startAt,
+	}
+	recurring := entity.RecurringTransfer{
+		ID:          "recurring1",
+		WalletID:    "id1",
+		ReferenceID: "rent",
+		Direction:   entity.TransferDirectionCredit,
+		Amount:      decimal.NewFromInt(500),
+		Currency:    "EUR",
+		Rule:        "FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=1",
+		StartAt:     startAt,
+		Occurrence:  1,
+		NextRunAt:   &nextRunAt,
+		Status:      entity.RecurringStatusActive,
+	}
+	expectedBody := response.RecurringTransfer{
+		ID:          "recurring1",
+		WalletID:    "id1",
+		ReferenceID: "rent",
+		Direction:   entity.TransferDirectionCredit,
+		Amount:      decimal.NewFromInt(500),
+		Currency:    "EUR",
+		Rule:        "FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=1",
+		StartAt:     startAt,
+		NextRunAt:   &nextRunAt,
+		Status:      entity.RecurringStatusActive,
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().CreateRecurringTransfer(gomock.Any(), testutils.NewMatcher(req)).Return(recurring, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.CreateRecurringTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusCreated, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestCreateRecurringTransferInvalidRule() {
+	req := &request.CreateRecurringTransfer{
+		WalletID:  "id1",
+		Direction: entity.TransferDirectionDebit,
+		Amount:    decimal.NewFromInt(500),
+		Currency:  "EUR",
+		Rule:      "FREQ=HOURLY",
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+
+	s.svcMock.EXPECT().CreateRecurringTransfer(gomock.Any(), testutils.NewMatcher(req)).Return(entity.RecurringTransfer{}, entity.ErrInvalidRecurrenceRule)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.CreateRecurringTransfer).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "rule")
+}
+
+func (s *WalletHandlerTestSuite) TestListRecurringTransfersSuccess() {
+	startAt := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	expectedBody := response.RecurringTransferList{
+		Items: []response.RecurringTransfer{
+			{
+				ID:        "recurring1",
+				WalletID:  "id1",
+				Direction: entity.TransferDirectionDebit,
+				Amount:    decimal.NewFromInt(100),
+				Currency:  "EUR",
+				Rule:      "FREQ=DAILY;INTERVAL=1",
+				StartAt:   startAt,
+				NextRunAt: &startAt,
+				Status:    entity.RecurringStatusActive,
+			},
+		},
+	}
+
+	s.ctx = s.buildContext("id1", "")
+
+	s.svcMock.EXPECT().ListRecurringTransfers(gomock.Any(), &request.ListRecurringTransfers{WalletID: "id1", Status: entity.RecurringStatusActive}).Return([]entity.RecurringTransfer{
+		{
+			ID:        "recurring1",
+			WalletID:  "id1",
+			Direction: entity.TransferDirectionDebit,
+			Amount:    decimal.NewFromInt(100),
+			Currency:  "EUR",
+			Rule:      "FREQ=DAILY;INTERVAL=1",
+			StartAt:   startAt,
+			NextRunAt: &startAt,
+			Status:    entity.RecurringStatusActive,
+		},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.ListRecurringTransfers).ServeHTTP(recorder, httptest.NewRequest("GET", "/?status=active", nil).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestUpdateRecurringTransferFinished() {
+	req := &request.UpdateRecurringTransfer{
+		WalletID:    "id1",
+		RecurringID: "recurring1",
+		Amount:      decimal.NewFromInt(100),
+		Rule:        "FREQ=DAILY",
+		Status:      entity.RecurringStatusActive,
+	}
+
+	s.ctx = s.buildContext(req.WalletID, "")
+	chi.RouteContext(s.ctx).URLParams.Add("recurringID", req.RecurringID)
+
+	s.svcMock.EXPECT().UpdateRecurringTransfer(gomock.Any(), testutils.NewMatcher(req)).Return(entity.RecurringTransfer{}, entity.ErrRecurrenceFinished)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.UpdateRecurringTransfer).ServeHTTP(recorder, httptest.NewRequest("PUT", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusConflict, recorder.Code)
+}
+
+func (s *WalletHandlerTestSuite) TestDeleteRecurringTransferSuccess() {
+	s.ctx = s.buildContext("id1", "")
+	chi.RouteContext(s.ctx).URLParams.Add("recurringID", "recurring1")
+
+	s.svcMock.EXPECT().DeleteRecurringTransfer(gomock.Any(), &request.DeleteRecurringTransfer{WalletID: "id1", RecurringID: "recurring1"}).Return(nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.DeleteRecurringTransfer).ServeHTTP(recorder, httptest.NewRequest("DELETE", "/", nil).WithContext(s.ctx))
+	s.Equal(http.StatusNoContent, recorder.Code)
+}
+
+func (s *WalletHandlerTestSuite) TestCreateFXQuoteSuccess() {
+	expiresAt := time.Date(2030, 1, 1, 9, 0, 30, 0, time.UTC)
+	req := &request.CreateFXQuote{
+		SourceWalletID: "id1",
+		TargetWalletID: "id2",
+		Amount:         decimal.NewFromInt(100),
+	}
+	quote := entity.FXQuote{
+		ID:             "quote1",
+		SourceWalletID: "id1",
+		TargetWalletID: "id2",
+		SourceCurrency: "EUR",
+		TargetCurrency: "USD",
+		SourceAmount:   decimal.NewFromInt(100),
+		TargetAmount:   decimal.RequireFromString("108.45"),
+		Rate:           decimal.RequireFromString("1.0845"),
+		Status:         entity.FXQuoteStatusOpen,
+		ExpiresAt:      expiresAt,
+	}
+	expectedBody := response.FXQuote{
+		ID:             "quote1",
+		SourceWalletID: "id1",
+		TargetWalletID: "id2",
+		SourceCurrency: "EUR",
+		TargetCurrency: "USD",
+		SourceAmount:   decimal.NewFromInt(100),
+		TargetAmount:   decimal.RequireFromString("108.45"),
+		Rate:           decimal.RequireFromString("1.0845"),
+		Status:         entity.FXQuoteStatusOpen,
+		ExpiresAt:      expiresAt,
+	}
+
+	s.svcMock.EXPECT().CreateFXQuote(gomock.Any(), testutils.NewMatcher(req)).Return(quote, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.CreateFXQuote).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusCreated, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestCreateFXQuoteRateNotFound() {
+	req := &request.CreateFXQuote{
+		SourceWalletID: "id1",
+		TargetWalletID: "id2",
+		Amount:         decimal.NewFromInt(100),
+	}
+
+	s.svcMock.EXPECT().CreateFXQuote(gomock.Any(), testutils.NewMatcher(req)).Return(entity.FXQuote{}, fmt.Errorf("wrapped: %w", entity.ErrFXRateNotFound))
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefault(s.handler.CreateFXQuote).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.Equal(http.StatusBadRequest, recorder.Code)
+	s.Contains(recorder.Body.String(), "target_wallet_id")
+}
+
+func (s *WalletHandlerTestSuite) TestExecuteFXQuoteSuccess() {
+	rate := decimal.RequireFromString("1.0845")
+	req := &request.ExecuteFXQuote{
+		QuoteID:     "quote1",
+		TransferID:  "transfer1",
+		ReferenceID: "ref1",
+	}
+	quote := entity.FXQuoteEvents{
+		FXQuote: entity.FXQuote{
+			ID:             "quote1",
+			SourceWalletID: "id1",
+			TargetWalletID: "id2",
+			SourceCurrency: "EUR",
+			TargetCurrency: "USD",
+			SourceAmount:   decimal.NewFromInt(100),
+			TargetAmount:   decimal.RequireFromString("108.45"),
+			Rate:           rate,
+			Status:         entity.FXQuoteStatusExecuted,
+			TransferID:     "transfer1",
+			ReferenceID:    "ref1",
+		},
+		CreditEvent: entity.WalletEvent{ID: "event1", TransferID: "transfer1", WalletID: "id1", Amount: decimal.NewFromInt(100), EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, FXRate: &rate},
+		DebitEvent:  entity.WalletEvent{ID: "event2", TransferID: "transfer1", WalletID: "id2", Amount: decimal.RequireFromString("108.45"), EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, FXRate: &rate},
+	}
+	expectedBody := response.FXQuoteEvents{
+		FXQuote: response.FXQuote{
+			ID:             "quote1",
+			SourceWalletID: "id1",
+			TargetWalletID: "id2",
+			SourceCurrency: "EUR",
+			TargetCurrency: "USD",
+			SourceAmount:   decimal.NewFromInt(100),
+			TargetAmount:   decimal.RequireFromString("108.45"),
+			Rate:           rate,
+			Status:         entity.FXQuoteStatusExecuted,
+			TransferID:     "transfer1",
+			ReferenceID:    "ref1",
+		},
+		CreditEvent: response.WalletEvent{ID: "event1", TransferID: "transfer1", WalletID: "id1", Amount: decimal.NewFromInt(100), EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, FXRate: &rate},
+		DebitEvent:  response.WalletEvent{ID: "event2", TransferID: "transfer1", WalletID: "id2", Amount: decimal.RequireFromString("108.45"), EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, FXRate: &rate},
+	}
+
+	chiContext := chi.NewRouteContext()
+	chiContext.URLParams.Add("quoteID", req.QuoteID)
+	s.ctx = context.WithValue(s.ctx, chi.RouteCtxKey, chiContext)
+
+	s.svcMock.EXPECT().ExecuteFXQuote(gomock.Any(), testutils.NewMatcher(req)).Return(quote, nil)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.ExecuteFXQuote).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusOK, recorder.Body.String(), expectedBody)
+}
+
+func (s *WalletHandlerTestSuite) TestExecuteFXQuoteExpired() {
+	req := &request.ExecuteFXQuote{
+		QuoteID:    "quote1",
+		TransferID: "transfer1",
+	}
+
+	chiContext := chi.NewRouteContext()
+	chiContext.URLParams.Add("quoteID", req.QuoteID)
+	s.ctx = context.WithValue(s.ctx, chi.RouteCtxKey, chiContext)
+
+	s.svcMock.EXPECT().ExecuteFXQuote(gomock.Any(), testutils.NewMatcher(req)).Return(entity.FXQuoteEvents{}, fmt.Errorf("wrapped: %w", entity.ErrFXQuoteExpired))
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.ExecuteFXQuote).ServeHTTP(recorder, httptest.NewRequest("POST", "/", testutils.ToJSONReader(s.T(), req)).WithContext(s.ctx))
+	s.statusCompare(recorder.Code, http.StatusConflict, recorder.Body.String(), render.ErrorResponse{
+		Error: &render.Error{
+			Status:  render.ConflictError,
+			Message: entity.ErrFXQuoteExpired.Error(),
+		},
+	})
+}
+
+func (s *WalletHandlerTestSuite) TestGetFXQuoteNotFound() {
+	chiContext := chi.NewRouteContext()
+	chiContext.URLParams.Add("quoteID", "quote1")
+	s.ctx = context.WithValue(s.ctx, chi.RouteCtxKey, chiContext)
+
+	s.svcMock.EXPECT().GetFXQuote(gomock.Any(), testutils.NewMatcher(&request.GetFXQuote{QuoteID: "quote1"})).Return(entity.FXQuote{}, entity.ErrEntityNotFound)
+
+	recorder := httptest.NewRecorder()
+
+	handler.WrapDefaultBasic(s.handler.GetFXQuote).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil).WithContext(s.ctx))
+	s.Equal(http.StatusNotFound, recorder.Code)
 }
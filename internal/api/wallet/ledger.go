@@ -0,0 +1,63 @@
+package wallet
+
+import (
+	"github.com/buni/wallet/internal/api/app/entity"
+)
+
+// LedgerPostings returns the journal entries of the event, transfer is the state of the transfer before the event and nil if the event opens it.
+// The wallet account follows the wallet balance, so the postings mirror ApplyEvents:
+//   - a completed debit moves the amount from the funding account to the wallet
+//   - a pending credit holds the amount in the settlement account, completing it moves it on to the funding account and reverting it back to the wallet
+//   - a partial capture only moves the captured amount, the rest of a pending credit goes back to the wallet
+//   - a completed credit moves the amount from the wallet to the funding account
+//
+// Fee transfers use the fees account in place of the funding account, so it clears once both legs of a fee are settled.
+// The legs of an fx quote use the fx account, it holds the converted positions in each currency.
+// Pending and failed debits, credit limit changes and events ApplyEvents skips (e.g. a second status update) aren't posted.
+func LedgerPostings(event entity.WalletEvent, transfer *entity.WalletTransfer, currency entity.Currency) []entity.LedgerPosting {
+	wallet := entity.WalletLedgerAccount(event.WalletID)
+
+	funding := entity.LedgerAccountFunding
+	switch {
+	case entity.IsFeeTransferID(event.TransferID):
+		funding = entity.LedgerAccountFees
+	case event.FXRate != nil:
+		funding = entity.LedgerAccountFX
+	}
+
+	switch event.EventType {
+	case entity.EventTypeDebitTransfer, entity.EventTypeCreditTransfer:
+		if transfer != nil {
+			return nil // the transfer id was already used, ApplyEvents ignores the event
+		}
+
+		switch {
+		case event.EventType == entity.EventTypeDebitTransfer && event.Status == entity.TransferStatusCompleted:
+			return entity.NewLedgerPostings(event, currency, funding, wallet, event.Amount)
+		case event.EventType == entity.EventTypeCreditTransfer && event.Status == entity.TransferStatusPending:
+			return entity.NewLedgerPostings(event, currency, wallet, entity.LedgerAccountSettlement, event.Amount)
+		case event.EventType == entity.EventTypeCreditTransfer && event.Status == entity.TransferStatusCompleted:
+			return entity.NewLedgerPostings(event, currency, wallet, funding, event.Amount)
+		}
+
+	case entity.EventTypeUpdateTransferStatus:
+		if transfer == nil || transfer.Status != entity.TransferStatusPending || event.Status == entity.TransferStatusPending {
+			return nil
+		}
+
+		captured := event.CapturedAmount(transfer.Amount)
+
+		switch {
+		case transfer.Direction == entity.TransferDirectionDebit && event.Status == entity.TransferStatusCompleted:
+			return entity.NewLedgerPostings(event, currency, funding, wallet, captured)
+		case transfer.Direction == entity.TransferDirectionCredit && event.Status == entity.TransferStatusCompleted:
+			return entity.NewSplitLedgerPostings(event, currency, entity.LedgerAccountSettlement, funding, wallet, transfer.Amount, captured)
+		case transfer.Direction == entity.TransferDirectionCredit && event.Status == entity.TransferStatusFailed:
+			return entity.NewLedgerPostings(event, currency, entity.LedgerAccountSettlement, wallet, transfer.Amount)
+		}
+
+	default: // credit limit changes don't move money
+	}
+
+	return nil
+}
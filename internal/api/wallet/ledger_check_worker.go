@@ -0,0 +1,34 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/contract"
+	"github.com/buni/wallet/internal/pkg/sloglog"
+)
+
+// NewLedgerCheckWorker creates a worker that periodically checks that the ledger postings of every currency sum up to 0
+// and logs an error if they don't. The whole ledger is checked in a single run, so the batch size isn't used.
+func NewLedgerCheckWorker(svc contract.WalletService, opts ...PeriodicWorkerOption) (*PeriodicWorker, error) {
+	opts = append([]PeriodicWorkerOption{WithWorkerInterval(time.Minute)}, opts...)
+
+	return NewPeriodicWorker(func(ctx context.Context, _ time.Time, _ uint64) (int, error) {
+		trialBalance, err := svc.TrialBalance(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get trial balance: %w", err)
+		}
+
+		logger := sloglog.FromContext(ctx)
+
+		for _, total := range trialBalance.Totals {
+			if !total.Total.IsZero() {
+				logger.ErrorContext(ctx, "ledger postings don't balance", slog.String("currency", string(total.Currency)), slog.String("total", total.Total.String()))
+			}
+		}
+
+		return 0, nil
+	}, opts...)
+}
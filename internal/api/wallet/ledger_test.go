@@ -0,0 +1,195 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerPostings(t *testing.T) {
+	walletAccount := entity.WalletLedgerAccount("w1")
+	pendingDebit := &entity.WalletTransfer{Amount: decimal.NewFromInt(30), Direction: entity.TransferDirectionDebit, Status: entity.TransferStatusPending}
+	pendingCredit := &entity.WalletTransfer{Amount: decimal.NewFromInt(20), Direction: entity.TransferDirectionCredit, Status: entity.TransferStatusPending}
+	fxRate := decimal.RequireFromString("1.1")
+
+	tests := []struct {
+		name     string
+		event    entity.WalletEvent
+		transfer *entity.WalletTransfer
+		expected map[string]int64 // account to amount
+	}{
+		{
+			name:     "completed debit is funded",
+			event:    entity.WalletEvent{EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(10)},
+			expected: map[string]int64{entity.LedgerAccountFunding: -10, walletAccount: 10},
+		},
+		{
+			name:  "pending debit isn't posted",
+			event: entity.WalletEvent{EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusPending, Amount: decimal.NewFromInt(10)},
+		},
+		{
+			name:     "pending credit is held in settlement",
+			event:    entity.WalletEvent{EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusPending, Amount: decimal.NewFromInt(10)},
+			expected: map[string]int64{walletAccount: -10, entity.LedgerAccountSettlement: 10},
+		},
+		{
+			name:     "completed credit leaves the system",
+			event:    entity.WalletEvent{EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(10)},
+			expected: map[string]int64{walletAccount: -10, entity.LedgerAccountFunding: 10},
+		},
+		{
+			name:     "reused transfer id isn't posted",
+			event:    entity.WalletEvent{EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(10)},
+			transfer: pendingDebit,
+		},
+		{
+			name:     "completing a pending debit funds it",
+			event:    entity.WalletEvent{EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted},
+			transfer: pendingDebit,
+			expected: map[string]int64{entity.LedgerAccountFunding: -30, walletAccount: 30},
+		},
+		{
+			name:     "reverting a pending debit isn't posted",
+			event:    entity.WalletEvent{EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusFailed},
+			transfer: pendingDebit,
+		},
+		{
+			name:     "completing a pending credit settles it",
+			event:    entity.WalletEvent{EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted},
+			transfer: pendingCredit,
+			expected: map[string]int64{entity.LedgerAccountSettlement: -20, entity.LedgerAccountFunding: 20},
+		},
+		{
+			name:     "partially capturing a pending credit returns the rest",
+			event:    entity.WalletEvent{EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(15)},
+			transfer: pendingCredit,
+			expected: map[string]int64{entity.LedgerAccountSettlement: -20, entity.LedgerAccountFunding: 15, walletAccount: 5},
+		},
+		{
+			name:     "partially capturing a pending debit only funds the captured amount",
+			event:    entity.WalletEvent{EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(25)},
+			transfer: pendingDebit,
+			expected: map[string]int64{entity.LedgerAccountFunding: -25, walletAccount: 25},
+		},
+		{
+			name:     "reverting a pending credit returns the hold",
+			event:    entity.WalletEvent{EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusFailed},
+			transfer: pendingCredit,
+			expected: map[string]int64{entity.LedgerAccountSettlement: -20, walletAccount: 20},
+		},
+		{
+			name:     "settled transfer isn't posted again",
+			event:    entity.WalletEvent{EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusFailed},
+			transfer: &entity.WalletTransfer{Amount: decimal.NewFromInt(20), Direction: entity.TransferDirectionCredit, Status: entity.TransferStatusCompleted},
+		},
+		{
+			name:  "status update of an unknown transfer isn't posted",
+			event: entity.WalletEvent{EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted},
+		},
+		{
+			name:     "completed fee is charged through the fees account",
+			event:    entity.WalletEvent{TransferID: entity.FeeTransferID("t1"), EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(2)},
+			expected: map[string]int64{walletAccount: -2, entity.LedgerAccountFees: 2},
+		},
+		{
+			name:     "completed fee reaches the fee wallet from the fees account",
+			event:    entity.WalletEvent{TransferID: entity.FeeTransferID("t1"), EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(2)},
+			expected: map[string]int64{entity.LedgerAccountFees: -2, walletAccount: 2},
+		},
+		{
+			name:     "completing a pending fee settles it to the fees account",
+			event:    entity.WalletEvent{TransferID: entity.FeeTransferID("t1"), EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted},
+			transfer: pendingCredit,
+			expected: map[string]int64{entity.LedgerAccountSettlement: -20, entity.LedgerAccountFees: 20},
+		},
+		{
+			name:     "fx source leg is converted through the fx account",
+			event:    entity.WalletEvent{EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(10), FXRate: &fxRate},
+			expected: map[string]int64{walletAccount: -10, entity.LedgerAccountFX: 10},
+		},
+		{
+			name:     "fx target leg is paid out of the fx account",
+			event:    entity.WalletEvent{EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(11), FXRate: &fxRate},
+			expected: map[string]int64{entity.LedgerAccountFX: -11, walletAccount: 11},
+		},
+		{
+			name:  "credit limit change isn't posted",
+			event: entity.WalletEvent{EventType: entity.EventTypeCreditLimitChanged, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(100)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.event.ID = "e1"
+			tt.event.WalletID = "w1"
+
+			postings := wallet.LedgerPostings(tt.event, tt.transfer, "EUR")
+
+			got := map[string]int64{}
+			sum := decimal.Decimal{}
+			for _, posting := range postings {
+				assert.Equal(t, "e1", posting.EventID)
+				assert.Equal(t, entity.Currency("EUR"), posting.Currency)
+				got[posting.Account] = posting.Amount.IntPart()
+				sum = sum.Add(posting.Amount)
+			}
+
+			if tt.expected == nil {
+				tt.expected = map[string]int64{}
+			}
+			assert.Equal(t, tt.expected, got)
+			assert.True(t, sum.IsZero(), sum.String())
+		})
+	}
+}
+
+// TestLedgerPostingsFollowWalletBalance posts a whole stream and checks the wallet account ends up with the projected balance.
+func TestLedgerPostingsFollowWalletBalance(t *testing.T) {
+	events := []entity.WalletEvent{
+		{TransferID: "t1", EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(100)},
+		{TransferID: "t2", EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusPending, Amount: decimal.NewFromInt(50)},
+		{TransferID: "t3", EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusPending, Amount: decimal.NewFromInt(30)},
+		{TransferID: "t4", EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusPending, Amount: decimal.NewFromInt(20)},
+		{TransferID: "t2", EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted},
+		{TransferID: "t3", EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusFailed},
+		{TransferID: "t4", EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted},
+		{TransferID: "t4", EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusFailed},
+		{TransferID: "t5", EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(5)},
+	}
+
+	balances := map[string]decimal.Decimal{}
+	for i := range events {
+		events[i].ID = string(rune('a' + i))
+		events[i].WalletID = "w1"
+		events[i].Version = entity.WalletEventVersionOne
+		events[i].Sequence = int64(i + 1)
+
+		var preceding []entity.WalletEvent
+		for _, e := range events[:i] {
+			if e.TransferID == events[i].TransferID {
+				preceding = append(preceding, e)
+			}
+		}
+
+		var transfer *entity.WalletTransfer
+		if built, err := wallet.BuildTransfer(context.Background(), preceding); err == nil {
+			transfer = &built
+		}
+
+		for _, posting := range wallet.LedgerPostings(events[i], transfer, "EUR") {
+			balances[posting.Account] = balances[posting.Account].Add(posting.Amount)
+		}
+	}
+
+	projection := entity.WalletProjection{}
+	require.NoError(t, wallet.ProcessEvents(context.Background(), &projection, events))
+
+	assert.True(t, projection.Balance.Equal(balances[entity.WalletLedgerAccount("w1")]), balances[entity.WalletLedgerAccount("w1")].String())
+	assert.True(t, balances[entity.LedgerAccountSettlement].IsZero(), balances[entity.LedgerAccountSettlement].String())
+	assert.True(t, decimal.NewFromInt(-125).Equal(balances[entity.LedgerAccountFunding]), balances[entity.LedgerAccountFunding].String())
+}
@@ -18,6 +18,24 @@ func TestMain(m *testing.M) {
 	errorhandler.RegisterErrorHandler("unique_constraint_error_handler", errorhandler.ConflictErrorHandler)
 	errorhandler.RegisterErrorHandler("insufficient_balance_error_handler", errorhandler.InsufficientBalanceErrorHandler)
 	errorhandler.RegisterErrorHandler("negative_amount_error_handler", errorhandler.NegativeAmountErrorHandler)
+	errorhandler.RegisterErrorHandler("same_wallet_transfer_error_handler", errorhandler.SameWalletTransferErrorHandler)
+	errorhandler.RegisterErrorHandler("stream_version_conflict_error_handler", errorhandler.StreamVersionConflictErrorHandler)
+	errorhandler.RegisterErrorHandler("transfer_not_found_error_handler", errorhandler.TransferNotFoundErrorHandler)
+	errorhandler.RegisterErrorHandler("transfer_already_settled_error_handler", errorhandler.TransferAlreadySettledErrorHandler)
+	errorhandler.RegisterErrorHandler("currency_error_handler", errorhandler.CurrencyErrorHandler)
+	errorhandler.RegisterErrorHandler("transfer_expiry_error_handler", errorhandler.TransferExpiryErrorHandler)
+	errorhandler.RegisterErrorHandler("capture_amount_error_handler", errorhandler.CaptureAmountErrorHandler)
+	errorhandler.RegisterErrorHandler("refund_error_handler", errorhandler.RefundErrorHandler)
+	errorhandler.RegisterErrorHandler("fx_error_handler", errorhandler.FXErrorHandler)
+	errorhandler.RegisterErrorHandler("statement_error_handler", errorhandler.StatementErrorHandler)
+	errorhandler.RegisterErrorHandler("wallet_status_error_handler", errorhandler.WalletStatusErrorHandler)
+	errorhandler.RegisterErrorHandler("credit_limit_error_handler", errorhandler.CreditLimitErrorHandler)
+	errorhandler.RegisterErrorHandler("limit_exceeded_error_handler", errorhandler.LimitExceededErrorHandler)
+	errorhandler.RegisterErrorHandler("wallet_tier_error_handler", errorhandler.WalletTierErrorHandler)
+	errorhandler.RegisterErrorHandler("balance_as_of_error_handler", errorhandler.BalanceAsOfErrorHandler)
+	errorhandler.RegisterErrorHandler("batch_size_error_handler", errorhandler.BatchSizeErrorHandler)
+	errorhandler.RegisterErrorHandler("scheduled_transfer_error_handler", errorhandler.ScheduledTransferErrorHandler)
+	errorhandler.RegisterErrorHandler("recurring_transfer_error_handler", errorhandler.RecurringTransferErrorHandler)
 
 	code := m.Run()
 	os.Exit(code)
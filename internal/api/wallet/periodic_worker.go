@@ -0,0 +1,115 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/buni/wallet/internal/pkg/sloglog"
+)
+
+var (
+	ErrInvalidWorkerInterval  = errors.New("worker interval can't be negative")
+	ErrInvalidWorkerBatchSize = errors.New("worker batch size must be greater than 0")
+)
+
+// BatchFunc handles up to limit items that are due at now and returns how many it handled.
+type BatchFunc func(ctx context.Context, now time.Time, limit uint64) (int, error)
+
+// PeriodicWorker runs a BatchFunc on every tick, a full batch is followed right away by the next one
+// until a batch isn't full, the rest is picked up on the next tick.
+type PeriodicWorker struct {
+	run       BatchFunc
+	interval  time.Duration
+	batchSize uint64
+	wg        *sync.WaitGroup
+}
+
+type PeriodicWorkerOption func(*PeriodicWorker) error
+
+// WithWorkerInterval sets how often the worker runs, 0 disables the worker.
+func WithWorkerInterval(interval time.Duration) PeriodicWorkerOption {
+	return func(w *PeriodicWorker) error {
+		if interval < 0 {
+			return ErrInvalidWorkerInterval
+		}
+		w.interval = interval
+		return nil
+	}
+}
+
+// WithWorkerBatchSize sets the limit passed to every run.
+func WithWorkerBatchSize(batchSize uint64) PeriodicWorkerOption {
+	return func(w *PeriodicWorker) error {
+		if batchSize == 0 {
+			return ErrInvalidWorkerBatchSize
+		}
+		w.batchSize = batchSize
+		return nil
+	}
+}
+
+func NewPeriodicWorker(run BatchFunc, opts ...PeriodicWorkerOption) (*PeriodicWorker, error) {
+	w := &PeriodicWorker{
+		run:       run,
+		interval:  10 * time.Second,
+		batchSize: 100,
+		wg:        &sync.WaitGroup{},
+	}
+
+	for _, opt := range opts {
+		err := opt(w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply option: %w", err)
+		}
+	}
+
+	return w, nil
+}
+
+// Start runs the worker in the background until ctx is done.
+func (w *PeriodicWorker) Start(ctx context.Context) error {
+	if w.interval == 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(w.interval)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.runBatches(ctx)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (w *PeriodicWorker) runBatches(ctx context.Context) {
+	logger := sloglog.FromContext(ctx)
+
+	for {
+		count, err := w.run(ctx, time.Now().UTC(), w.batchSize)
+		if err != nil {
+			logger.ErrorContext(ctx, "periodic worker run failed", sloglog.Error(err))
+		}
+
+		if err != nil || uint64(count) < w.batchSize || ctx.Err() != nil {
+			return // the rest is picked up on the next tick
+		}
+	}
+}
+
+func (w *PeriodicWorker) Wait() {
+	w.wg.Wait()
+}
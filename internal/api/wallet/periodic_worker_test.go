@@ -0,0 +1,167 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	contract_mock "github.com/buni/wallet/internal/api/app/contract/mock"
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type PeriodicWorkerTestSuite struct {
+	suite.Suite
+	ctrl    *gomock.Controller
+	svcMock *contract_mock.MockWalletService
+}
+
+func (s *PeriodicWorkerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.svcMock = contract_mock.NewMockWalletService(s.ctrl)
+}
+
+func (s *PeriodicWorkerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *PeriodicWorkerTestSuite) TestStartRunsUntilBatchIsNotFull() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var limits []uint64
+
+	counts := []int{2, 2, 1}
+	worker, err := wallet.NewPeriodicWorker(func(_ context.Context, now time.Time, limit uint64) (int, error) {
+		s.WithinDuration(time.Now().UTC(), now, time.Second)
+		limits = append(limits, limit)
+
+		count := counts[len(limits)-1]
+		if len(limits) == len(counts) {
+			cancel()
+		}
+
+		return count, nil
+	}, wallet.WithWorkerInterval(time.Millisecond), wallet.WithWorkerBatchSize(2))
+	s.Require().NoError(err)
+
+	s.NoError(worker.Start(ctx))
+	worker.Wait()
+
+	s.Equal([]uint64{2, 2, 2}, limits)
+}
+
+func (s *PeriodicWorkerTestSuite) TestStartStopsBatchesOnError() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs int
+
+	worker, err := wallet.NewPeriodicWorker(func(context.Context, time.Time, uint64) (int, error) {
+		runs++
+		if runs == 2 {
+			cancel()
+		}
+
+		return 2, context.DeadlineExceeded
+	}, wallet.WithWorkerInterval(time.Millisecond), wallet.WithWorkerBatchSize(2))
+	s.Require().NoError(err)
+
+	s.NoError(worker.Start(ctx))
+	worker.Wait()
+
+	s.Equal(2, runs) // one per tick, a failed full batch isn't retried right away
+}
+
+func (s *PeriodicWorkerTestSuite) TestStartDisabled() {
+	worker, err := wallet.NewPeriodicWorker(func(context.Context, time.Time, uint64) (int, error) {
+		s.Fail("disabled worker ran")
+		return 0, nil
+	}, wallet.WithWorkerInterval(0))
+	s.Require().NoError(err)
+
+	s.NoError(worker.Start(context.Background()))
+	worker.Wait() // returns right away since no worker was started
+}
+
+func (s *PeriodicWorkerTestSuite) TestNewPeriodicWorkerInvalidOptions() {
+	_, err := wallet.NewPeriodicWorker(nil, wallet.WithWorkerInterval(-time.Second))
+	s.ErrorIs(err, wallet.ErrInvalidWorkerInterval)
+
+	_, err = wallet.NewPeriodicWorker(nil, wallet.WithWorkerBatchSize(0))
+	s.ErrorIs(err, wallet.ErrInvalidWorkerBatchSize)
+}
+
+func (s *PeriodicWorkerTestSuite) TestExpiryWorker() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker, err := wallet.NewExpiryWorker(s.svcMock, wallet.WithWorkerInterval(time.Millisecond), wallet.WithWorkerBatchSize(2))
+	s.Require().NoError(err)
+
+	s.svcMock.EXPECT().ExpireTransfers(gomock.Any(), gomock.Any(), uint64(2)).DoAndReturn(func(context.Context, time.Time, uint64) ([]entity.WalletEvent, error) {
+		cancel()
+		return make([]entity.WalletEvent, 1), nil
+	})
+
+	s.NoError(worker.Start(ctx))
+	worker.Wait()
+}
+
+func (s *PeriodicWorkerTestSuite) TestScheduledTransferWorker() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker, err := wallet.NewScheduledTransferWorker(s.svcMock, wallet.WithWorkerInterval(time.Millisecond), wallet.WithWorkerBatchSize(2))
+	s.Require().NoError(err)
+
+	s.svcMock.EXPECT().ExecuteScheduledTransfers(gomock.Any(), gomock.Any(), uint64(2)).DoAndReturn(func(context.Context, time.Time, uint64) ([]entity.ScheduledTransfer, error) {
+		cancel()
+		return make([]entity.ScheduledTransfer, 1), nil
+	})
+
+	s.NoError(worker.Start(ctx))
+	worker.Wait()
+}
+
+func (s *PeriodicWorkerTestSuite) TestRecurringTransferWorker() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker, err := wallet.NewRecurringTransferWorker(s.svcMock, wallet.WithWorkerInterval(time.Millisecond), wallet.WithWorkerBatchSize(2))
+	s.Require().NoError(err)
+
+	s.svcMock.EXPECT().ExecuteRecurringTransfers(gomock.Any(), gomock.Any(), uint64(2)).DoAndReturn(func(context.Context, time.Time, uint64) ([]entity.RecurringTransfer, error) {
+		cancel()
+		return make([]entity.RecurringTransfer, 1), nil
+	})
+
+	s.NoError(worker.Start(ctx))
+	worker.Wait()
+}
+
+func (s *PeriodicWorkerTestSuite) TestLedgerCheckWorker() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker, err := wallet.NewLedgerCheckWorker(s.svcMock, wallet.WithWorkerInterval(time.Millisecond))
+	s.Require().NoError(err)
+
+	gomock.InOrder(
+		s.svcMock.EXPECT().TrialBalance(gomock.Any()).Return(entity.TrialBalance{}, context.DeadlineExceeded),
+		s.svcMock.EXPECT().TrialBalance(gomock.Any()).DoAndReturn(func(context.Context) (entity.TrialBalance, error) {
+			cancel()
+			return entity.TrialBalance{Totals: []entity.LedgerCurrencyTotal{{Currency: "EUR", Total: decimal.NewFromInt(1)}}}, nil
+		}),
+	)
+
+	s.NoError(worker.Start(ctx))
+	worker.Wait()
+}
+
+func TestPeriodicWorkerTestSuite(t *testing.T) {
+	suite.Run(t, new(PeriodicWorkerTestSuite))
+}
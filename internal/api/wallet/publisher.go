@@ -5,7 +5,10 @@ import (
 	"fmt"
 
 	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/pkg/database"
 	"github.com/buni/wallet/internal/pkg/pubsub"
+	"github.com/buni/wallet/internal/pkg/pubsub/jetstream"
+	"github.com/buni/wallet/internal/pkg/pubsub/outbox"
 )
 
 type Publisher struct {
@@ -18,6 +21,12 @@ func NewPublisher(publisher pubsub.Publisher) *Publisher {
 	}
 }
 
+// NewOutboxPublisher stores the events in the outbox in the transaction that writes them, the outbox worker forwards them to jetstream
+// once they're committed. Writes that are rolled back (e.g. a retried append or a failed scheduled transfer) are never published.
+func NewOutboxPublisher(repo outbox.Repository, txm database.TransactionManager) *Publisher {
+	return NewPublisher(outbox.NewPublisher[any](repo, txm, jetstream.JetStreamPublisherType))
+}
+
 func (p *Publisher) PublishCreated(ctx context.Context, event entity.WalletEvent) error {
 	msg, err := pubsub.NewJSONMessage(event, nil)
 	if err != nil {
@@ -0,0 +1,83 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/contract"
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/pkg/sloglog"
+)
+
+var ErrInvalidReconcileGracePeriod = errors.New("reconcile grace period can't be negative")
+
+// ReconcileAll checks the stored projection of every wallet page by page and calls report for each mismatch, it returns how many
+// wallets were checked and how many of them didn't match. The wallets that couldn't be checked don't stop the run, their errors are joined.
+func ReconcileAll(ctx context.Context, svc contract.WalletService, batchSize uint64, gracePeriod time.Duration, repair bool, report func(entity.ProjectionMismatch)) (checked, mismatched int, err error) {
+	opts := entity.ReconcileOptions{
+		Limit:  batchSize,
+		Repair: repair,
+	}
+
+	var errs []error
+
+	for {
+		opts.SettledBefore = time.Now().UTC().Add(-gracePeriod)
+
+		result, err := svc.ReconcileProjections(ctx, opts)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		checked += result.Checked
+		mismatched += len(result.Mismatches)
+
+		for _, mismatch := range result.Mismatches {
+			report(mismatch)
+		}
+
+		if result.LastWalletID == "" || ctx.Err() != nil {
+			return checked, mismatched, errors.Join(errs...)
+		}
+
+		opts.AfterWalletID = result.LastWalletID
+	}
+}
+
+// NewReconcileWorker creates a worker that periodically replays the events of every wallet and logs an error for each stored projection
+// that doesn't match, the mismatched projections are overwritten with the replayed state if repair is set. The batch size is the number
+// of wallets checked per page and every run goes through all of them, the worker is disabled unless an interval is set.
+func NewReconcileWorker(svc contract.WalletService, gracePeriod time.Duration, repair bool, opts ...PeriodicWorkerOption) (*PeriodicWorker, error) {
+	if gracePeriod < 0 {
+		return nil, ErrInvalidReconcileGracePeriod
+	}
+
+	opts = append([]PeriodicWorkerOption{WithWorkerInterval(0)}, opts...)
+
+	return NewPeriodicWorker(func(ctx context.Context, _ time.Time, limit uint64) (int, error) {
+		logger := sloglog.FromContext(ctx)
+
+		checked, mismatched, err := ReconcileAll(ctx, svc, limit, gracePeriod, repair, func(mismatch entity.ProjectionMismatch) {
+			logger.ErrorContext(ctx, "wallet projection doesn't match its events",
+				slog.String("wallet_id", mismatch.WalletID),
+				slog.Any("fields", mismatch.Fields),
+				slog.String("stored_balance", mismatch.Stored.Balance.String()),
+				slog.String("replayed_balance", mismatch.Replayed.Balance.String()),
+				slog.Int64("stored_last_sequence", mismatch.Stored.LastSequence),
+				slog.Int64("replayed_last_sequence", mismatch.Replayed.LastSequence),
+				slog.Bool("repaired", mismatch.Repaired),
+			)
+		})
+
+		logger.InfoContext(ctx, "reconciled wallet projections", slog.Int("checked", checked), slog.Int("mismatched", mismatched))
+
+		if err != nil {
+			return 0, fmt.Errorf("failed to reconcile wallet projections: %w", err)
+		}
+
+		return 0, nil
+	}, opts...)
+}
@@ -0,0 +1,116 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	contract_mock "github.com/buni/wallet/internal/api/app/contract/mock"
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type ReconcileWorkerTestSuite struct {
+	suite.Suite
+	ctrl    *gomock.Controller
+	svcMock *contract_mock.MockWalletService
+}
+
+func (s *ReconcileWorkerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.svcMock = contract_mock.NewMockWalletService(s.ctrl)
+}
+
+func (s *ReconcileWorkerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *ReconcileWorkerTestSuite) TestReconcileAllPages() {
+	mismatch := entity.ProjectionMismatch{WalletID: "wallet-2", Fields: []string{"balance"}}
+
+	gomock.InOrder(
+		s.svcMock.EXPECT().ReconcileProjections(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts entity.ReconcileOptions) (entity.ReconcileResult, error) {
+			s.Empty(opts.AfterWalletID)
+			s.Equal(uint64(2), opts.Limit)
+			s.True(opts.Repair)
+			s.WithinDuration(time.Now().Add(-time.Minute), opts.SettledBefore, time.Second)
+
+			return entity.ReconcileResult{Checked: 2, Mismatches: []entity.ProjectionMismatch{mismatch}, LastWalletID: "wallet-2"}, nil
+		}),
+		s.svcMock.EXPECT().ReconcileProjections(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts entity.ReconcileOptions) (entity.ReconcileResult, error) {
+			s.Equal("wallet-2", opts.AfterWalletID)
+
+			return entity.ReconcileResult{Checked: 0, Mismatches: []entity.ProjectionMismatch{}}, context.DeadlineExceeded
+		}),
+	)
+
+	var reported []entity.ProjectionMismatch
+
+	checked, mismatched, err := wallet.ReconcileAll(context.Background(), s.svcMock, 2, time.Minute, true, func(mismatch entity.ProjectionMismatch) {
+		reported = append(reported, mismatch)
+	})
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Equal(2, checked)
+	s.Equal(1, mismatched)
+	s.Equal([]entity.ProjectionMismatch{mismatch}, reported)
+}
+
+func (s *ReconcileWorkerTestSuite) TestStartReconcilesProjections() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker, err := wallet.NewReconcileWorker(s.svcMock, time.Minute, true, wallet.WithWorkerInterval(time.Millisecond), wallet.WithWorkerBatchSize(10))
+	s.Require().NoError(err)
+
+	gomock.InOrder(
+		s.svcMock.EXPECT().ReconcileProjections(gomock.Any(), gomock.Any()).Return(entity.ReconcileResult{}, context.DeadlineExceeded),
+		s.svcMock.EXPECT().ReconcileProjections(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts entity.ReconcileOptions) (entity.ReconcileResult, error) {
+			s.Equal(uint64(10), opts.Limit)
+			s.True(opts.Repair)
+			cancel()
+
+			return entity.ReconcileResult{Checked: 1, Mismatches: []entity.ProjectionMismatch{{WalletID: "wallet", Fields: []string{"balance"}, Repaired: true}}}, nil
+		}),
+	)
+
+	s.NoError(worker.Start(ctx))
+	worker.Wait()
+}
+
+func (s *ReconcileWorkerTestSuite) TestStartDisabled() {
+	worker, err := wallet.NewReconcileWorker(s.svcMock, time.Minute, false)
+	s.Require().NoError(err)
+
+	s.NoError(worker.Start(context.Background()))
+	worker.Wait()
+}
+
+func (s *ReconcileWorkerTestSuite) TestNewReconcileWorkerInvalidGracePeriod() {
+	_, err := wallet.NewReconcileWorker(s.svcMock, -time.Second, false)
+	s.ErrorIs(err, wallet.ErrInvalidReconcileGracePeriod)
+}
+
+func TestReconcileWorkerTestSuite(t *testing.T) {
+	suite.Run(t, new(ReconcileWorkerTestSuite))
+}
+
+func TestDiffProjections(t *testing.T) {
+	stored := entity.NewWalletProjection("wallet", "wallet", decimal.NewFromInt(10), decimal.Zero, decimal.Zero)
+	stored.LastSequence = 2
+
+	replayed := stored
+	replayed.LastEventID = "other"
+	assert.Empty(t, entity.DiffProjections(stored, replayed))
+
+	replayed.Balance = decimal.RequireFromString("10.00")
+	assert.Empty(t, entity.DiffProjections(stored, replayed))
+
+	replayed.Balance = decimal.NewFromInt(5)
+	replayed.PendingCredit = decimal.NewFromInt(5)
+	replayed.LastSequence = 3
+	assert.Equal(t, []string{"balance", "pending_credit", "last_sequence"}, entity.DiffProjections(stored, replayed))
+}
@@ -0,0 +1,154 @@
+package wallet_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected entity.RecurrenceRule
+		err      bool
+	}{
+		{
+			name:     "monthly on a day",
+			spec:     "FREQ=MONTHLY;BYMONTHDAY=1",
+			expected: entity.RecurrenceRule{Frequency: entity.RecurrenceFrequencyMonthly, Interval: 1, MonthDay: 1},
+		},
+		{
+			name:     "rrule prefix and lower case",
+			spec:     "RRULE:freq=weekly;interval=2",
+			expected: entity.RecurrenceRule{Frequency: entity.RecurrenceFrequencyWeekly, Interval: 2},
+		},
+		{name: "empty", spec: "", err: true},
+		{name: "missing frequency", spec: "INTERVAL=2", err: true},
+		{name: "unknown frequency", spec: "FREQ=HOURLY", err: true},
+		{name: "unsupported part", spec: "FREQ=DAILY;COUNT=3", err: true},
+		{name: "zero interval", spec: "FREQ=DAILY;INTERVAL=0", err: true},
+		{name: "month day on a daily rule", spec: "FREQ=DAILY;BYMONTHDAY=1", err: true},
+		{name: "month day out of range", spec: "FREQ=MONTHLY;BYMONTHDAY=32", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := entity.ParseRecurrenceRule(tt.spec)
+			if tt.err {
+				assert.ErrorIs(t, err, entity.ErrInvalidRecurrenceRule)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rule)
+
+			reparsed, err := entity.ParseRecurrenceRule(rule.String())
+			require.NoError(t, err)
+			assert.Equal(t, rule, reparsed)
+		})
+	}
+}
+
+func TestRecurrenceRuleOccurrence(t *testing.T) {
+	start := time.Date(2024, 1, 31, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rule     entity.RecurrenceRule
+		n        int
+		expected time.Time
+	}{
+		{
+			name:     "daily every other day",
+			rule:     entity.RecurrenceRule{Frequency: entity.RecurrenceFrequencyDaily, Interval: 2},
+			n:        3,
+			expected: time.Date(2024, 2, 6, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekly",
+			rule:     entity.RecurrenceRule{Frequency: entity.RecurrenceFrequencyWeekly, Interval: 1},
+			n:        1,
+			expected: time.Date(2024, 2, 7, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "monthly clamped to the end of a shorter month",
+			rule:     entity.RecurrenceRule{Frequency: entity.RecurrenceFrequencyMonthly, Interval: 1},
+			n:        1,
+			expected: time.Date(2024, 2, 29, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "monthly back on the start day after a shorter month",
+			rule:     entity.RecurrenceRule{Frequency: entity.RecurrenceFrequencyMonthly, Interval: 1},
+			n:        2,
+			expected: time.Date(2024, 3, 31, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "quarterly on the first",
+			rule:     entity.RecurrenceRule{Frequency: entity.RecurrenceFrequencyMonthly, Interval: 3, MonthDay: 1},
+			n:        4,
+			expected: time.Date(2025, 1, 1, 9, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rule.Occurrence(start, tt.n))
+		})
+	}
+}
+
+func TestRecurringTransferFirstOccurrence(t *testing.T) {
+	rule := entity.RecurrenceRule{Frequency: entity.RecurrenceFrequencyMonthly, Interval: 1, MonthDay: 1}
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	transfer, err := entity.NewRecurringTransfer("wallet-id", "ref", entity.TransferDirectionDebit, decimal.NewFromInt(50), "EUR", rule, start, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=1", transfer.Rule)
+	assert.Equal(t, 1, transfer.Occurrence) // the 1st of January is before the start
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), *transfer.NextRunAt)
+	assert.Equal(t, entity.RecurringStatusActive, transfer.Status)
+
+	end := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+	_, err = entity.NewRecurringTransfer("wallet-id", "ref", entity.TransferDirectionDebit, decimal.NewFromInt(50), "EUR", rule, start, &end)
+	assert.ErrorIs(t, err, entity.ErrInvalidRecurrenceEnd)
+}
+
+func TestRecurringTransferAdvance(t *testing.T) {
+	rule := entity.RecurrenceRule{Frequency: entity.RecurrenceFrequencyWeekly, Interval: 1}
+	start := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	transfer, err := entity.NewRecurringTransfer("wallet-id", "ref", entity.TransferDirectionCredit, decimal.NewFromInt(50), "EUR", rule, start, &end)
+	require.NoError(t, err)
+
+	require.NoError(t, transfer.Advance(time.Now()))
+	assert.Equal(t, time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC), *transfer.NextRunAt)
+	assert.Equal(t, entity.RecurringStatusActive, transfer.Status)
+
+	require.NoError(t, transfer.Advance(time.Now()))
+	assert.Nil(t, transfer.NextRunAt)
+	assert.Equal(t, entity.RecurringStatusFinished, transfer.Status)
+}
+
+func TestRecurringTransferSkipMissed(t *testing.T) {
+	rule := entity.RecurrenceRule{Frequency: entity.RecurrenceFrequencyDaily, Interval: 1}
+	start := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	transfer, err := entity.NewRecurringTransfer("wallet-id", "ref", entity.TransferDirectionCredit, decimal.NewFromInt(50), "EUR", rule, start, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, transfer.SkipMissed(time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2024, 1, 6, 8, 0, 0, 0, time.UTC), *transfer.NextRunAt)
+	assert.Equal(t, 5, transfer.Occurrence)
+}
+
+func TestRecurringTransferID(t *testing.T) {
+	at := time.Date(2024, 2, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	assert.Equal(t, "recurring-id:20240201T083000Z", entity.RecurringTransferID("recurring-id", at))
+}
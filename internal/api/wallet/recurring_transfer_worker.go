@@ -0,0 +1,27 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/contract"
+	"github.com/buni/wallet/internal/pkg/sloglog"
+)
+
+// NewRecurringTransferWorker creates a worker that periodically writes the due occurrences of the recurring transfers.
+func NewRecurringTransferWorker(svc contract.WalletService, opts ...PeriodicWorkerOption) (*PeriodicWorker, error) {
+	return NewPeriodicWorker(func(ctx context.Context, now time.Time, limit uint64) (int, error) {
+		transfers, err := svc.ExecuteRecurringTransfers(ctx, now, limit)
+		if len(transfers) > 0 {
+			sloglog.FromContext(ctx).InfoContext(ctx, "executed recurring transfers", slog.Int("count", len(transfers)))
+		}
+
+		if err != nil {
+			return len(transfers), fmt.Errorf("failed to execute recurring transfers: %w", err)
+		}
+
+		return len(transfers), nil
+	}, opts...)
+}
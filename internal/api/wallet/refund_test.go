@@ -0,0 +1,60 @@
+package wallet_test
+
+import (
+	"testing"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefundTransferID(t *testing.T) {
+	id := entity.RefundTransferID("1234", 2)
+
+	assert.Equal(t, "1234:refund:2", id)
+	assert.True(t, entity.IsRefundTransferID(id))
+	assert.False(t, entity.IsRefundTransferID("1234"))
+	assert.False(t, entity.IsRefundTransferID(entity.FeeTransferID("1234")))
+}
+
+func TestWalletTransferRefundableAmount(t *testing.T) {
+	tests := []struct {
+		name       string
+		transfer   entity.WalletTransfer
+		refunds    []entity.TransferRefund
+		refunded   decimal.Decimal
+		refundable decimal.Decimal
+	}{
+		{
+			name:       "not refunded",
+			transfer:   entity.WalletTransfer{Amount: decimal.NewFromInt(100), CapturedAmount: decimal.NewFromInt(100), Status: entity.TransferStatusCompleted},
+			refunded:   decimal.Zero,
+			refundable: decimal.NewFromInt(100),
+		},
+		{
+			name:     "partially captured and refunded",
+			transfer: entity.WalletTransfer{Amount: decimal.NewFromInt(100), CapturedAmount: decimal.NewFromInt(80), Status: entity.TransferStatusCompleted},
+			refunds: []entity.TransferRefund{
+				{Amount: decimal.NewFromInt(30)},
+				{Amount: decimal.RequireFromString("12.5")},
+			},
+			refunded:   decimal.RequireFromString("42.5"),
+			refundable: decimal.RequireFromString("37.5"),
+		},
+		{
+			name:       "pending",
+			transfer:   entity.WalletTransfer{Amount: decimal.NewFromInt(100), Status: entity.TransferStatusPending},
+			refunded:   decimal.Zero,
+			refundable: decimal.Zero,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer := entity.WalletTransferRefunds{WalletTransfer: tt.transfer, Refunds: tt.refunds}
+
+			assert.True(t, tt.refunded.Equal(transfer.RefundedAmount()), transfer.RefundedAmount().String())
+			assert.True(t, tt.refundable.Equal(transfer.RefundableAmount()), transfer.RefundableAmount().String())
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/buni/wallet/internal/api/app/contract"
@@ -12,10 +14,14 @@ import (
 	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/iZettle/structextract"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 const (
 	db = "db"
+
+	uniqueViolationCode       = "23505"
+	walletEventsSequenceIndex = "idx_wallet_events_wallet_id_sequence"
 )
 
 var _ contract.WalletRepository = (*Repository)(nil)
@@ -51,13 +57,51 @@ func (r *Repository) Create(ctx context.Context, wallet entity.Wallet) (entity.W
 	return wallet, nil
 }
 
-func (r *Repository) Get(ctx context.Context, id string) (result entity.Wallet, err error) {
+func (r *Repository) Get(ctx context.Context, id string) (entity.Wallet, error) {
+	return r.get(ctx, id, "")
+}
+
+// GetForShare returns the wallet and takes a share lock on it when called inside of a transaction,
+// that way a lifecycle change waits for the transfers that already read the wallet status.
+func (r *Repository) GetForShare(ctx context.Context, id string) (entity.Wallet, error) {
+	return r.get(ctx, id, "FOR SHARE")
+}
+
+// GetForUpdate returns the wallet and locks it until the transaction ends, it is used before changing the wallet status.
+func (r *Repository) GetForUpdate(ctx context.Context, id string) (entity.Wallet, error) {
+	return r.get(ctx, id, "FOR UPDATE")
+}
+
+func (r *Repository) get(ctx context.Context, id, lock string) (result entity.Wallet, err error) {
+	columns, err := structextract.New(&entity.Wallet{}).NamesFromTag(db)
+	if err != nil {
+		return entity.Wallet{}, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"id": id}).Suffix(lock).ToSql()
+	if err != nil {
+		return entity.Wallet{}, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Wallet{}, entity.ErrEntityNotFound
+		}
+		return entity.Wallet{}, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetByReferenceID returns the wallet of the reference, the reference id is unique so there's at most one.
+func (r *Repository) GetByReferenceID(ctx context.Context, referenceID string) (result entity.Wallet, err error) {
 	columns, err := structextract.New(&entity.Wallet{}).NamesFromTag(db)
 	if err != nil {
 		return entity.Wallet{}, fmt.Errorf("failed to extract columns: %w", err)
 	}
 
-	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"id": id}).ToSql()
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"reference_id": referenceID}).ToSql()
 	if err != nil {
 		return entity.Wallet{}, fmt.Errorf("failed to build select query: %w", err)
 	}
@@ -73,6 +117,147 @@ func (r *Repository) Get(ctx context.Context, id string) (result entity.Wallet,
 	return result, nil
 }
 
+// List returns the wallets matching the filter, newest first. The projections are only joined to filter by balance.
+func (r *Repository) List(ctx context.Context, filter entity.WalletFilter) (result []entity.Wallet, err error) {
+	columns, err := structextract.New(&entity.Wallet{}).NamesFromTag(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	for i, column := range columns {
+		columns[i] = r.table + "." + column
+	}
+
+	builder := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar)
+
+	if filter.Cursor != "" {
+		builder = builder.Where(sq.Lt{r.table + ".id": filter.Cursor}) // UUIDv7's are k-sortable, so everything older than the cursor is the next page
+	}
+
+	if filter.ReferenceID != "" {
+		builder = builder.Where(sq.Eq{r.table + ".reference_id": filter.ReferenceID})
+	}
+
+	if filter.ReferenceIDPrefix != "" {
+		builder = builder.Where(sq.Like{r.table + ".reference_id": escapeLike(filter.ReferenceIDPrefix) + "%"})
+	}
+
+	if len(filter.Metadata) > 0 {
+		builder = builder.Where(r.table+".metadata @> ?::jsonb", filter.Metadata)
+	}
+
+	if !filter.CreatedAfter.IsZero() {
+		builder = builder.Where(sq.GtOrEq{r.table + ".created_at": filter.CreatedAfter})
+	}
+
+	if !filter.CreatedBefore.IsZero() {
+		builder = builder.Where(sq.Lt{r.table + ".created_at": filter.CreatedBefore})
+	}
+
+	if filter.MinBalance != nil || filter.MaxBalance != nil {
+		builder = builder.Join("wallet_projections ON wallet_projections.wallet_id = " + r.table + ".id")
+
+		if filter.MinBalance != nil {
+			builder = builder.Where(sq.GtOrEq{"wallet_projections.balance": *filter.MinBalance})
+		}
+
+		if filter.MaxBalance != nil {
+			builder = builder.Where(sq.LtOrEq{"wallet_projections.balance": *filter.MaxBalance})
+		}
+	}
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(filter.Limit)
+	}
+
+	query, args, err := builder.OrderBy(r.table + ".id DESC").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []entity.Wallet{}
+	}
+
+	return result, nil
+}
+
+// escapeLike escapes the LIKE wildcards, so the value is matched literally.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func (r *Repository) UpdateStatus(ctx context.Context, wallet entity.Wallet) (entity.Wallet, error) {
+	query, args, err := sq.Update(r.table).PlaceholderFormat(sq.Dollar).
+		Set("status", wallet.Status).
+		Set("updated_at", wallet.UpdatedAt).
+		Where(sq.Eq{"id": wallet.ID}).ToSql()
+	if err != nil {
+		return entity.Wallet{}, fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	tag, err := r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return entity.Wallet{}, fmt.Errorf("failed to execute update query: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.Wallet{}, entity.ErrEntityNotFound
+	}
+
+	return wallet, nil
+}
+
+func (r *Repository) UpdateTier(ctx context.Context, wallet entity.Wallet) (entity.Wallet, error) {
+	query, args, err := sq.Update(r.table).PlaceholderFormat(sq.Dollar).
+		Set("tier", wallet.Tier).
+		Set("updated_at", wallet.UpdatedAt).
+		Where(sq.Eq{"id": wallet.ID}).ToSql()
+	if err != nil {
+		return entity.Wallet{}, fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	tag, err := r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return entity.Wallet{}, fmt.Errorf("failed to execute update query: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.Wallet{}, entity.ErrEntityNotFound
+	}
+
+	return wallet, nil
+}
+
+func (r *Repository) ListIDs(ctx context.Context, afterID string, limit uint64) (result []string, err error) {
+	builder := sq.Select("id").From(r.table).PlaceholderFormat(sq.Dollar).OrderBy("id ASC").Limit(limit)
+
+	if afterID != "" {
+		builder = builder.Where(sq.Gt{"id": afterID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []string{}
+	}
+
+	return result, nil
+}
+
 var _ contract.WalletEventRepository = (*EventRepository)(nil)
 
 type EventRepository struct {
@@ -87,7 +272,9 @@ func NewEventRepository(pgxpool *pgxtx.TxWrapper) *EventRepository {
 	}
 }
 
-func (r *EventRepository) Create(ctx context.Context, event entity.WalletEvent) (entity.WalletEvent, error) {
+func (r *EventRepository) Create(ctx context.Context, event entity.WalletEvent, expectedVersion int64) (entity.WalletEvent, error) {
+	event.Sequence = expectedVersion + 1 // the unique (wallet_id, sequence) index rejects the insert if the stream moved past expectedVersion
+
 	fvMap, err := structextract.New(&event).FieldValueFromTagMap(db)
 	if err != nil {
 		return entity.WalletEvent{}, fmt.Errorf("failed to extract field value map: %w", err)
@@ -100,19 +287,151 @@ func (r *EventRepository) Create(ctx context.Context, event entity.WalletEvent)
 
 	err = pgxscan.Get(ctx, r.pgxpool, &event, query, args...)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == walletEventsSequenceIndex {
+			return entity.WalletEvent{}, entity.ErrStreamVersionConflict
+		}
 		return entity.WalletEvent{}, fmt.Errorf("failed to execute query: %w", err)
 	}
 
 	return event, nil
 }
 
+// GetStreamVersion returns the sequence of the last event appended to the wallet stream, 0 if the stream is empty.
+func (r *EventRepository) GetStreamVersion(ctx context.Context, walletID string) (version int64, err error) {
+	query, args, err := sq.Select("COALESCE(MAX(sequence), 0)").From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": walletID}).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.pgxpool, &version, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	return version, nil
+}
+
 func (r *EventRepository) ListByWalletID(ctx context.Context, walletID string) (result []entity.WalletEvent, err error) {
+	return r.ListByWalletIDAfter(ctx, walletID, 0)
+}
+
+func (r *EventRepository) ListByWalletIDAfter(ctx context.Context, walletID string, afterSequence int64) (result []entity.WalletEvent, err error) {
+	columns, err := structextract.New(&entity.WalletEvent{}).NamesFromTag(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"wallet_id": walletID}).
+		Where(sq.Gt{"sequence": afterSequence}).
+		OrderBy("sequence ASC").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []entity.WalletEvent{}
+	}
+
+	return result, nil
+}
+
+func (r *EventRepository) ListByWalletIDSince(ctx context.Context, walletID string, since time.Time) (result []entity.WalletEvent, err error) {
+	columns, err := structextract.New(&entity.WalletEvent{}).NamesFromTag(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"wallet_id": walletID}).
+		Where(sq.GtOrEq{"created_at": since}).
+		OrderBy("sequence ASC").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []entity.WalletEvent{}
+	}
+
+	return result, nil
+}
+
+// List returns the events of a wallet matching the filter, newest first.
+func (r *EventRepository) List(ctx context.Context, filter entity.WalletEventFilter) (result []entity.WalletEvent, err error) {
+	columns, err := structextract.New(&entity.WalletEvent{}).NamesFromTag(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	builder := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": filter.WalletID})
+
+	if filter.Cursor != "" {
+		builder = builder.Where(sq.Lt{"id": filter.Cursor}) // UUIDv7's are k-sortable, so everything older than the cursor is the next page
+	}
+
+	if filter.EventType != entity.EventTypeInvalid {
+		builder = builder.Where(sq.Eq{"event_type": filter.EventType})
+	}
+
+	if filter.Status != entity.TransferStatusInvalid {
+		builder = builder.Where(sq.Eq{"transfer_status": filter.Status})
+	}
+
+	if len(filter.Metadata) > 0 {
+		builder = builder.Where("metadata @> ?::jsonb", filter.Metadata)
+	}
+
+	if !filter.CreatedAfter.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"created_at": filter.CreatedAfter})
+	}
+
+	if !filter.CreatedBefore.IsZero() {
+		builder = builder.Where(sq.Lt{"created_at": filter.CreatedBefore})
+	}
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(filter.Limit)
+	}
+
+	query, args, err := builder.OrderBy("id DESC").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []entity.WalletEvent{}
+	}
+
+	return result, nil
+}
+
+// ListByTransferID returns the events of a single transfer in the order they were appended.
+func (r *EventRepository) ListByTransferID(ctx context.Context, walletID, transferID string) (result []entity.WalletEvent, err error) {
 	columns, err := structextract.New(&entity.WalletEvent{}).NamesFromTag(db)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract columns: %w", err)
 	}
 
-	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": walletID}).OrderBy("id ASC").ToSql()
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"wallet_id": walletID, "transfer_id": transferID}).
+		OrderBy("sequence ASC").ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build select query: %w", err)
 	}
@@ -162,13 +481,28 @@ func (r *ProjectionRepository) Create(ctx context.Context, projection entity.Wal
 	return projection, nil
 }
 
-func (r *ProjectionRepository) Get(ctx context.Context, walletID string) (result entity.WalletProjection, err error) {
+func (r *ProjectionRepository) Get(ctx context.Context, walletID string) (entity.WalletProjection, error) {
+	return r.get(ctx, walletID, false)
+}
+
+// GetForUpdate returns the projection and locks it until the transaction ends, a concurrent rebuild waits for the lock before it updates the projection.
+func (r *ProjectionRepository) GetForUpdate(ctx context.Context, walletID string) (entity.WalletProjection, error) {
+	return r.get(ctx, walletID, true)
+}
+
+func (r *ProjectionRepository) get(ctx context.Context, walletID string, forUpdate bool) (result entity.WalletProjection, err error) {
 	columns, err := structextract.New(&entity.WalletProjection{}).NamesFromTag(db)
 	if err != nil {
 		return entity.WalletProjection{}, fmt.Errorf("failed to extract columns: %w", err)
 	}
 
-	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": walletID}).ToSql()
+	builder := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": walletID})
+
+	if forUpdate {
+		builder = builder.Suffix("FOR UPDATE")
+	}
+
+	query, args, err := builder.ToSql()
 	if err != nil {
 		return entity.WalletProjection{}, fmt.Errorf("failed to build select query: %w", err)
 	}
@@ -184,12 +518,37 @@ func (r *ProjectionRepository) Get(ctx context.Context, walletID string) (result
 	return result, nil
 }
 
+func (r *ProjectionRepository) ListByWalletIDs(ctx context.Context, walletIDs []string) (result []entity.WalletProjection, err error) {
+	columns, err := structextract.New(&entity.WalletProjection{}).NamesFromTag(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": walletIDs}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []entity.WalletProjection{}
+	}
+
+	return result, nil
+}
+
 func (r *ProjectionRepository) Update(ctx context.Context, projection entity.WalletProjection) (entity.WalletProjection, error) {
 	query, args, err := sq.Update(r.table).SetMap(map[string]any{
 		"balance":        projection.Balance,
 		"pending_debit":  projection.PendingDebit,
 		"pending_credit": projection.PendingCredit,
+		"credit_limit":   projection.CreditLimit,
 		"last_event_id":  projection.LastEventID,
+		"last_sequence":  projection.LastSequence,
 		"updated_at":     projection.UpdatedAt,
 	}).Suffix("RETURNING updated_at").PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": projection.WalletID}).ToSql()
 	if err != nil {
@@ -206,3 +565,762 @@ func (r *ProjectionRepository) Update(ctx context.Context, projection entity.Wal
 
 	return projection, nil
 }
+
+var _ contract.WalletSnapshotRepository = (*SnapshotRepository)(nil)
+
+type SnapshotRepository struct {
+	pgxpool *pgxtx.TxWrapper
+	table   string
+}
+
+func NewSnapshotRepository(pgxpool *pgxtx.TxWrapper) *SnapshotRepository {
+	return &SnapshotRepository{
+		pgxpool: pgxpool,
+		table:   "wallet_snapshots",
+	}
+}
+
+// Create stores the snapshot, a snapshot for the same wallet and sequence is identical so taking it twice is a noop.
+func (r *SnapshotRepository) Create(ctx context.Context, snapshot entity.WalletSnapshot) (entity.WalletSnapshot, error) {
+	fvMap, err := structextract.New(&snapshot).FieldValueFromTagMap(db)
+	if err != nil {
+		return entity.WalletSnapshot{}, fmt.Errorf("failed to extract field value map: %w", err)
+	}
+
+	query, args, err := sq.Insert(r.table).SetMap(fvMap).Suffix("ON CONFLICT (wallet_id, last_sequence) DO NOTHING").PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return entity.WalletSnapshot{}, fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	_, err = r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return entity.WalletSnapshot{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+func (r *SnapshotRepository) GetLatest(ctx context.Context, walletID string) (result entity.WalletSnapshot, err error) {
+	columns, err := structextract.New(&entity.WalletSnapshot{}).NamesFromTag(db)
+	if err != nil {
+		return entity.WalletSnapshot{}, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": walletID}).OrderBy("last_sequence DESC").Limit(1).ToSql()
+	if err != nil {
+		return entity.WalletSnapshot{}, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.WalletSnapshot{}, entity.ErrEntityNotFound
+		}
+		return entity.WalletSnapshot{}, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	return result, nil
+}
+
+var _ contract.WalletTransferRepository = (*TransferRepository)(nil)
+
+type TransferRepository struct {
+	pgxpool *pgxtx.TxWrapper
+	table   string
+}
+
+func NewTransferRepository(pgxpool *pgxtx.TxWrapper) *TransferRepository {
+	return &TransferRepository{
+		pgxpool: pgxpool,
+		table:   "wallet_transfers",
+	}
+}
+
+func (r *TransferRepository) Upsert(ctx context.Context, transfer entity.WalletTransfer) error {
+	fvMap, err := structextract.New(&transfer).FieldValueFromTagMap(db)
+	if err != nil {
+		return fmt.Errorf("failed to extract field value map: %w", err)
+	}
+
+	query, args, err := sq.Insert(r.table).SetMap(fvMap).
+		Suffix(`ON CONFLICT (wallet_id, transfer_id) DO UPDATE SET
+			transfer_status = EXCLUDED.transfer_status,
+			captured_amount = EXCLUDED.captured_amount,
+			last_sequence = EXCLUDED.last_sequence,
+			updated_at = EXCLUDED.updated_at
+			WHERE wallet_transfers.last_sequence <= EXCLUDED.last_sequence`). // a redelivered older event can't roll back the status
+		PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	_, err = r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TransferRepository) Get(ctx context.Context, walletID, transferID string) (result entity.WalletTransfer, err error) {
+	columns, err := structextract.New(&entity.WalletTransfer{}).NamesFromTag(db)
+	if err != nil {
+		return entity.WalletTransfer{}, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": walletID, "transfer_id": transferID}).ToSql()
+	if err != nil {
+		return entity.WalletTransfer{}, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.WalletTransfer{}, entity.ErrEntityNotFound
+		}
+		return entity.WalletTransfer{}, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	return result, nil
+}
+
+// List returns the transfers of a wallet matching the filter, newest first.
+func (r *TransferRepository) List(ctx context.Context, filter entity.WalletTransferFilter) (result []entity.WalletTransfer, err error) {
+	columns, err := structextract.New(&entity.WalletTransfer{}).NamesFromTag(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	builder := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": filter.WalletID})
+
+	if filter.Status != entity.TransferStatusInvalid {
+		builder = builder.Where(sq.Eq{"transfer_status": filter.Status})
+	}
+
+	if len(filter.Metadata) > 0 {
+		builder = builder.Where("metadata @> ?::jsonb", filter.Metadata)
+	}
+
+	query, args, err := builder.OrderBy("created_at DESC", "transfer_id DESC").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []entity.WalletTransfer{}
+	}
+
+	return result, nil
+}
+
+func (r *TransferRepository) ListExpired(ctx context.Context, before time.Time, limit uint64) (result []entity.WalletTransfer, err error) {
+	columns, err := structextract.New(&entity.WalletTransfer{}).NamesFromTag(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"transfer_status": entity.TransferStatusPending}).
+		Where(sq.LtOrEq{"expires_at": before}).
+		OrderBy("expires_at", "wallet_id", "transfer_id").
+		Limit(limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []entity.WalletTransfer{}
+	}
+
+	return result, nil
+}
+
+var _ contract.ScheduledTransferRepository = (*ScheduledTransferRepository)(nil)
+
+type ScheduledTransferRepository struct {
+	pgxpool *pgxtx.TxWrapper
+	table   string
+}
+
+func NewScheduledTransferRepository(pgxpool *pgxtx.TxWrapper) *ScheduledTransferRepository {
+	return &ScheduledTransferRepository{
+		pgxpool: pgxpool,
+		table:   "scheduled_transfers",
+	}
+}
+
+func (r *ScheduledTransferRepository) Create(ctx context.Context, transfer entity.ScheduledTransfer) (entity.ScheduledTransfer, error) {
+	fvMap, err := structextract.New(&transfer).FieldValueFromTagMap(db)
+	if err != nil {
+		return entity.ScheduledTransfer{}, fmt.Errorf("failed to extract field value map: %w", err)
+	}
+
+	query, args, err := sq.Insert(r.table).SetMap(fvMap).PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return entity.ScheduledTransfer{}, fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	_, err = r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return entity.ScheduledTransfer{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return transfer, nil
+}
+
+func (r *ScheduledTransferRepository) GetForUpdate(ctx context.Context, walletID, transferID string) (result entity.ScheduledTransfer, err error) {
+	columns, err := structextract.New(&entity.ScheduledTransfer{}).NamesFromTag(db)
+	if err != nil {
+		return entity.ScheduledTransfer{}, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"wallet_id": walletID, "transfer_id": transferID}).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return entity.ScheduledTransfer{}, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ScheduledTransfer{}, entity.ErrEntityNotFound
+		}
+		return entity.ScheduledTransfer{}, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	return result, nil
+}
+
+// List returns the scheduled transfers of a wallet matching the filter, the ones due first come first.
+func (r *ScheduledTransferRepository) List(ctx context.Context, filter entity.ScheduledTransferFilter) (result []entity.ScheduledTransfer, err error) {
+	columns, err := structextract.New(&entity.ScheduledTransfer{}).NamesFromTag(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	builder := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": filter.WalletID})
+
+	if filter.Status != entity.ScheduleStatusInvalid {
+		builder = builder.Where(sq.Eq{"status": filter.Status})
+	}
+
+	query, args, err := builder.OrderBy("execute_at", "transfer_id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []entity.ScheduledTransfer{}
+	}
+
+	return result, nil
+}
+
+func (r *ScheduledTransferRepository) ListDue(ctx context.Context, before time.Time, limit uint64) (result []entity.ScheduledTransfer, err error) {
+	columns, err := structextract.New(&entity.ScheduledTransfer{}).NamesFromTag(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"status": entity.ScheduleStatusScheduled}).
+		Where(sq.LtOrEq{"execute_at": before}).
+		OrderBy("execute_at", "wallet_id", "transfer_id").
+		Limit(limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []entity.ScheduledTransfer{}
+	}
+
+	return result, nil
+}
+
+func (r *ScheduledTransferRepository) Update(ctx context.Context, transfer entity.ScheduledTransfer) error {
+	query, args, err := sq.Update(r.table).PlaceholderFormat(sq.Dollar).
+		Set("status", transfer.Status).
+		Set("event_id", transfer.EventID).
+		Set("failure_reason", transfer.FailureReason).
+		Set("updated_at", transfer.UpdatedAt).
+		Where(sq.Eq{"wallet_id": transfer.WalletID, "transfer_id": transfer.TransferID}).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	tag, err := r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute update query: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+var _ contract.RecurringTransferRepository = (*RecurringTransferRepository)(nil)
+
+type RecurringTransferRepository struct {
+	pgxpool *pgxtx.TxWrapper
+	table   string
+}
+
+func NewRecurringTransferRepository(pgxpool *pgxtx.TxWrapper) *RecurringTransferRepository {
+	return &RecurringTransferRepository{
+		pgxpool: pgxpool,
+		table:   "recurring_transfers",
+	}
+}
+
+func (r *RecurringTransferRepository) Create(ctx context.Context, transfer entity.RecurringTransfer) (entity.RecurringTransfer, error) {
+	fvMap, err := structextract.New(&transfer).FieldValueFromTagMap(db)
+	if err != nil {
+		return entity.RecurringTransfer{}, fmt.Errorf("failed to extract field value map: %w", err)
+	}
+
+	query, args, err := sq.Insert(r.table).SetMap(fvMap).PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return entity.RecurringTransfer{}, fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	_, err = r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return entity.RecurringTransfer{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return transfer, nil
+}
+
+func (r *RecurringTransferRepository) Get(ctx context.Context, walletID, recurringID string) (entity.RecurringTransfer, error) {
+	return r.get(ctx, walletID, recurringID, false)
+}
+
+func (r *RecurringTransferRepository) GetForUpdate(ctx context.Context, walletID, recurringID string) (entity.RecurringTransfer, error) {
+	return r.get(ctx, walletID, recurringID, true)
+}
+
+func (r *RecurringTransferRepository) get(ctx context.Context, walletID, recurringID string, forUpdate bool) (result entity.RecurringTransfer, err error) {
+	columns, err := structextract.New(&entity.RecurringTransfer{}).NamesFromTag(db)
+	if err != nil {
+		return entity.RecurringTransfer{}, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	builder := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": walletID, "id": recurringID})
+
+	if forUpdate {
+		builder = builder.Suffix("FOR UPDATE")
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return entity.RecurringTransfer{}, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.RecurringTransfer{}, entity.ErrEntityNotFound
+		}
+		return entity.RecurringTransfer{}, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	return result, nil
+}
+
+// List returns the recurring transfers of a wallet matching the filter, oldest first.
+func (r *RecurringTransferRepository) List(ctx context.Context, filter entity.RecurringTransferFilter) (result []entity.RecurringTransfer, err error) {
+	columns, err := structextract.New(&entity.RecurringTransfer{}).NamesFromTag(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	builder := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"wallet_id": filter.WalletID})
+
+	if filter.Status != entity.RecurringStatusInvalid {
+		builder = builder.Where(sq.Eq{"status": filter.Status})
+	}
+
+	query, args, err := builder.OrderBy("id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []entity.RecurringTransfer{}
+	}
+
+	return result, nil
+}
+
+func (r *RecurringTransferRepository) ListDue(ctx context.Context, before time.Time, limit uint64) (result []entity.RecurringTransfer, err error) {
+	columns, err := structextract.New(&entity.RecurringTransfer{}).NamesFromTag(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"status": entity.RecurringStatusActive}).
+		Where(sq.LtOrEq{"next_run_at": before}).
+		OrderBy("next_run_at", "id").
+		Limit(limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []entity.RecurringTransfer{}
+	}
+
+	return result, nil
+}
+
+func (r *RecurringTransferRepository) Update(ctx context.Context, transfer entity.RecurringTransfer) error {
+	query, args, err := sq.Update(r.table).PlaceholderFormat(sq.Dollar).
+		Set("amount", transfer.Amount).
+		Set("rule", transfer.Rule).
+		Set("start_at", transfer.StartAt).
+		Set("end_at", transfer.EndAt).
+		Set("occurrence", transfer.Occurrence).
+		Set("next_run_at", transfer.NextRunAt).
+		Set("status", transfer.Status).
+		Set("last_transfer_id", transfer.LastTransferID).
+		Set("last_failure_reason", transfer.LastFailureReason).
+		Set("updated_at", transfer.UpdatedAt).
+		Where(sq.Eq{"wallet_id": transfer.WalletID, "id": transfer.ID}).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	tag, err := r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute update query: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (r *RecurringTransferRepository) Delete(ctx context.Context, walletID, recurringID string) error {
+	query, args, err := sq.Delete(r.table).PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"wallet_id": walletID, "id": recurringID}).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build delete query: %w", err)
+	}
+
+	tag, err := r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute delete query: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+var _ contract.InternalTransferRepository = (*InternalTransferRepository)(nil)
+
+type InternalTransferRepository struct {
+	pgxpool *pgxtx.TxWrapper
+	table   string
+}
+
+func NewInternalTransferRepository(pgxpool *pgxtx.TxWrapper) *InternalTransferRepository {
+	return &InternalTransferRepository{
+		pgxpool: pgxpool,
+		table:   "internal_transfers",
+	}
+}
+
+func (r *InternalTransferRepository) Create(ctx context.Context, transfer entity.InternalTransfer) (entity.InternalTransfer, error) {
+	fvMap, err := structextract.New(&transfer).FieldValueFromTagMap(db)
+	if err != nil {
+		return entity.InternalTransfer{}, fmt.Errorf("failed to extract field value map: %w", err)
+	}
+
+	query, args, err := sq.Insert(r.table).SetMap(fvMap).Suffix("RETURNING created_at").PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return entity.InternalTransfer{}, fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.pgxpool, &transfer, query, args...)
+	if err != nil {
+		return entity.InternalTransfer{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return transfer, nil
+}
+
+func (r *InternalTransferRepository) Get(ctx context.Context, transferID string) (result entity.InternalTransfer, err error) {
+	columns, err := structextract.New(&entity.InternalTransfer{}).NamesFromTag(db)
+	if err != nil {
+		return entity.InternalTransfer{}, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"transfer_id": transferID}).ToSql()
+	if err != nil {
+		return entity.InternalTransfer{}, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.InternalTransfer{}, entity.ErrEntityNotFound
+		}
+		return entity.InternalTransfer{}, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	return result, nil
+}
+
+var _ contract.RefundRepository = (*RefundRepository)(nil)
+
+type RefundRepository struct {
+	pgxpool *pgxtx.TxWrapper
+	table   string
+}
+
+func NewRefundRepository(pgxpool *pgxtx.TxWrapper) *RefundRepository {
+	return &RefundRepository{
+		pgxpool: pgxpool,
+		table:   "transfer_refunds",
+	}
+}
+
+func (r *RefundRepository) Create(ctx context.Context, refund entity.TransferRefund) (entity.TransferRefund, error) {
+	fvMap, err := structextract.New(&refund).FieldValueFromTagMap(db)
+	if err != nil {
+		return entity.TransferRefund{}, fmt.Errorf("failed to extract field value map: %w", err)
+	}
+
+	query, args, err := sq.Insert(r.table).SetMap(fvMap).Suffix("RETURNING created_at").PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return entity.TransferRefund{}, fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.pgxpool, &refund, query, args...)
+	if err != nil {
+		return entity.TransferRefund{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return refund, nil
+}
+
+func (r *RefundRepository) ListByTransferID(ctx context.Context, walletID, transferID string) (result []entity.TransferRefund, err error) {
+	columns, err := structextract.New(&entity.TransferRefund{}).NamesFromTag(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	query, args, err := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"wallet_id": walletID, "transfer_id": transferID}).
+		OrderBy("created_at", "refund_id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	if result == nil {
+		result = []entity.TransferRefund{}
+	}
+
+	return result, nil
+}
+
+var _ contract.FXQuoteRepository = (*FXQuoteRepository)(nil)
+
+type FXQuoteRepository struct {
+	pgxpool *pgxtx.TxWrapper
+	table   string
+}
+
+func NewFXQuoteRepository(pgxpool *pgxtx.TxWrapper) *FXQuoteRepository {
+	return &FXQuoteRepository{
+		pgxpool: pgxpool,
+		table:   "fx_quotes",
+	}
+}
+
+func (r *FXQuoteRepository) Create(ctx context.Context, quote entity.FXQuote) (entity.FXQuote, error) {
+	fvMap, err := structextract.New(&quote).FieldValueFromTagMap(db)
+	if err != nil {
+		return entity.FXQuote{}, fmt.Errorf("failed to extract field value map: %w", err)
+	}
+
+	query, args, err := sq.Insert(r.table).SetMap(fvMap).PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return entity.FXQuote{}, fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	_, err = r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return entity.FXQuote{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return quote, nil
+}
+
+func (r *FXQuoteRepository) Get(ctx context.Context, quoteID string) (entity.FXQuote, error) {
+	return r.get(ctx, quoteID, false)
+}
+
+func (r *FXQuoteRepository) GetForUpdate(ctx context.Context, quoteID string) (entity.FXQuote, error) {
+	return r.get(ctx, quoteID, true)
+}
+
+func (r *FXQuoteRepository) get(ctx context.Context, quoteID string, forUpdate bool) (result entity.FXQuote, err error) {
+	columns, err := structextract.New(&entity.FXQuote{}).NamesFromTag(db)
+	if err != nil {
+		return entity.FXQuote{}, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	builder := sq.Select(columns...).From(r.table).PlaceholderFormat(sq.Dollar).Where(sq.Eq{"id": quoteID})
+
+	if forUpdate {
+		builder = builder.Suffix("FOR UPDATE")
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return entity.FXQuote{}, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.FXQuote{}, entity.ErrEntityNotFound
+		}
+		return entity.FXQuote{}, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *FXQuoteRepository) Update(ctx context.Context, quote entity.FXQuote) error {
+	query, args, err := sq.Update(r.table).PlaceholderFormat(sq.Dollar).
+		Set("status", quote.Status).
+		Set("transfer_id", quote.TransferID).
+		Set("reference_id", quote.ReferenceID).
+		Set("updated_at", quote.UpdatedAt).
+		Where(sq.Eq{"id": quote.ID}).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	tag, err := r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute update query: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+var _ contract.LedgerRepository = (*LedgerRepository)(nil)
+
+type LedgerRepository struct {
+	pgxpool *pgxtx.TxWrapper
+	table   string
+}
+
+func NewLedgerRepository(pgxpool *pgxtx.TxWrapper) *LedgerRepository {
+	return &LedgerRepository{
+		pgxpool: pgxpool,
+		table:   "ledger_postings",
+	}
+}
+
+// CreatePostings stores the postings, the ones that are already stored for the same event and account are skipped.
+func (r *LedgerRepository) CreatePostings(ctx context.Context, postings []entity.LedgerPosting) error {
+	if len(postings) == 0 {
+		return nil
+	}
+
+	columns, err := structextract.New(&entity.LedgerPosting{}).NamesFromTag(db)
+	if err != nil {
+		return fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	builder := sq.Insert(r.table).Columns(columns...)
+	for _, posting := range postings {
+		values, err := structextract.New(&posting).ValuesFromTag(db)
+		if err != nil {
+			return fmt.Errorf("failed to extract values: %w", err)
+		}
+		builder = builder.Values(values...)
+	}
+
+	query, args, err := builder.Suffix("ON CONFLICT (event_id, account) DO NOTHING").PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	_, err = r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+// AccountBalances sums up the postings per account and currency, the wallet accounts are summed up into a single entity.LedgerAccountWallets line.
+func (r *LedgerRepository) AccountBalances(ctx context.Context) (result []entity.LedgerAccountBalance, err error) {
+	account := "CASE WHEN account LIKE 'wallet:%' THEN '" + entity.LedgerAccountWallets + "' ELSE account END"
+
+	query, args, err := sq.Select(account+" AS account", "currency", "SUM(amount) AS balance").From(r.table).
+		GroupBy("1", "2").OrderBy("1", "2").PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	return result, nil
+}
@@ -2,7 +2,10 @@ package wallet
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/buni/wallet/internal/api/app/contract"
@@ -15,36 +18,220 @@ import (
 
 var _ contract.WalletService = (*Service)(nil)
 
+const (
+	defaultEventsPageSize  = 50
+	defaultWalletsPageSize = 50
+	defaultMaxBatchSize    = 100
+	defaultFXQuoteTTL      = 30 * time.Second
+)
+
+var (
+	ErrInvalidAppendAttempts   = errors.New("append attempts must be greater than 0")
+	ErrInvalidSnapshotInterval = errors.New("snapshot interval can't be negative")
+	ErrInvalidMaxBatchSize     = errors.New("max batch size must be greater than 0")
+	ErrMissingFeeWallet        = errors.New("fee schedules require a fee wallet")
+	ErrFeeWalletCurrency       = errors.New("fee wallet doesn't hold the currency it collects")
+	ErrMissingFXRateProvider   = errors.New("fx rate provider can't be nil")
+	ErrInvalidFXQuoteTTL       = errors.New("fx quote ttl must be greater than 0")
+
+	// errOccurrenceHandled is returned when the recurring transfer was executed, paused or deleted after it was listed.
+	errOccurrenceHandled = errors.New("recurring transfer occurrence was already handled")
+)
+
 type Service struct {
-	repo           contract.WalletRepository
-	projectionRepo contract.WalletProjectionRepository
-	eventRepo      contract.WalletEventRepository
-	publisher      contract.WalletEventPublisher
-	txm            database.TransactionManager
+	repo                 contract.WalletRepository
+	projectionRepo       contract.WalletProjectionRepository
+	eventRepo            contract.WalletEventRepository
+	snapshotRepo         contract.WalletSnapshotRepository
+	transferRepo         contract.WalletTransferRepository
+	internalTransferRepo contract.InternalTransferRepository
+	ledgerRepo           contract.LedgerRepository
+	scheduledRepo        contract.ScheduledTransferRepository
+	recurringRepo        contract.RecurringTransferRepository
+	refundRepo           contract.RefundRepository
+	fxQuoteRepo          contract.FXQuoteRepository
+	publisher            contract.WalletEventPublisher
+	txm                  database.TransactionManager
+	maxAppendAttempts    int
+	snapshotInterval     int64
+	velocityLimits       entity.VelocityLimits
+	maxBatchSize         int
+	feeWallets           entity.FeeWallets
+	feeSchedules         entity.FeeSchedules
+	fxRates              contract.FXRateProvider
+	fxQuoteTTL           time.Duration
+}
+
+type ServiceOption func(*Service) error
+
+// WithMaxAppendAttempts sets how many times a write is attempted when it loses the race for the wallet event stream.
+func WithMaxAppendAttempts(attempts int) ServiceOption {
+	return func(s *Service) error {
+		if attempts < 1 {
+			return ErrInvalidAppendAttempts
+		}
+		s.maxAppendAttempts = attempts
+		return nil
+	}
+}
+
+// WithSnapshotInterval sets after how many new events the projection rebuild stores a snapshot, 0 disables taking snapshots.
+func WithSnapshotInterval(interval int64) ServiceOption {
+	return func(s *Service) error {
+		if interval < 0 {
+			return ErrInvalidSnapshotInterval
+		}
+		s.snapshotInterval = interval
+		return nil
+	}
+}
+
+// WithVelocityLimits sets the rolling window credit limits of single wallets and wallet tiers, wallets without limits aren't limited.
+func WithVelocityLimits(limits entity.VelocityLimits) ServiceOption {
+	return func(s *Service) error {
+		for _, keyLimits := range limits {
+			for _, limit := range keyLimits {
+				err := limit.Validate()
+				if err != nil {
+					return err
+				}
+			}
+		}
+		s.velocityLimits = limits
+		return nil
+	}
+}
+
+// WithMaxBatchSize sets how many items a single batch transfer request can contain.
+func WithMaxBatchSize(size int) ServiceOption {
+	return func(s *Service) error {
+		if size < 1 {
+			return ErrInvalidMaxBatchSize
+		}
+		s.maxBatchSize = size
+		return nil
+	}
+}
+
+// WithFees sets the fee schedules charged on credits and the wallets the fees are moved to, one per currency.
+// Wallets in a currency without a fee wallet aren't charged fees.
+func WithFees(feeWallets entity.FeeWallets, schedules entity.FeeSchedules) ServiceOption {
+	return func(s *Service) error {
+		if len(schedules) > 0 && len(feeWallets) == 0 {
+			return ErrMissingFeeWallet
+		}
+
+		err := feeWallets.Validate()
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		for _, schedule := range schedules {
+			err := schedule.Validate()
+			if err != nil {
+				return err
+			}
+		}
+		s.feeWallets = feeWallets
+		s.feeSchedules = schedules
+		return nil
+	}
+}
+
+// WithFX sets where the fx rates are looked up and for how long a created fx quote can be executed.
+func WithFX(provider contract.FXRateProvider, quoteTTL time.Duration) ServiceOption {
+	return func(s *Service) error {
+		if provider == nil {
+			return ErrMissingFXRateProvider
+		}
+
+		if quoteTTL <= 0 {
+			return ErrInvalidFXQuoteTTL
+		}
+		s.fxRates = provider
+		s.fxQuoteTTL = quoteTTL
+		return nil
+	}
+}
+
+// WithScheduledTransfers sets where the debits and credits with an execute_at are stored until they're executed.
+func WithScheduledTransfers(repo contract.ScheduledTransferRepository) ServiceOption {
+	return func(s *Service) error {
+		s.scheduledRepo = repo
+		return nil
+	}
+}
+
+// WithRecurringTransfers sets where the recurring transfers are stored.
+func WithRecurringTransfers(repo contract.RecurringTransferRepository) ServiceOption {
+	return func(s *Service) error {
+		s.recurringRepo = repo
+		return nil
+	}
+}
+
+// WithFXQuotes sets where the fx quotes are stored.
+func WithFXQuotes(repo contract.FXQuoteRepository) ServiceOption {
+	return func(s *Service) error {
+		s.fxQuoteRepo = repo
+		return nil
+	}
 }
 
 func NewService(
 	repo contract.WalletRepository,
 	projectionRepo contract.WalletProjectionRepository,
 	eventRepo contract.WalletEventRepository,
+	snapshotRepo contract.WalletSnapshotRepository,
+	transferRepo contract.WalletTransferRepository,
+	internalTransferRepo contract.InternalTransferRepository,
+	ledgerRepo contract.LedgerRepository,
+	refundRepo contract.RefundRepository,
 	publisher contract.WalletEventPublisher,
 	txm database.TransactionManager,
-) *Service {
-	return &Service{
-		repo:           repo,
-		projectionRepo: projectionRepo,
-		eventRepo:      eventRepo,
-		publisher:      publisher,
-		txm:            txm,
+	opts ...ServiceOption,
+) (*Service, error) {
+	s := &Service{
+		repo:                 repo,
+		projectionRepo:       projectionRepo,
+		eventRepo:            eventRepo,
+		snapshotRepo:         snapshotRepo,
+		transferRepo:         transferRepo,
+		internalTransferRepo: internalTransferRepo,
+		ledgerRepo:           ledgerRepo,
+		refundRepo:           refundRepo,
+		publisher:            publisher,
+		txm:                  txm,
+		maxAppendAttempts:    3,
+		snapshotInterval:     100,
+		maxBatchSize:         defaultMaxBatchSize,
+		fxRates:              NewStaticRateProvider(entity.FXRates{}),
+		fxQuoteTTL:           defaultFXQuoteTTL,
+	}
+
+	for _, opt := range opts {
+		err := opt(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply option: %w", err)
+		}
 	}
+
+	return s, nil
 }
 
 func (s *Service) Create(ctx context.Context, req *request.CreateWallet) (result entity.Wallet, err error) {
-	result, err = entity.NewWallet(req.ReferenceID)
+	currency, err := entity.ParseCurrency(req.Currency)
+	if err != nil {
+		return entity.Wallet{}, err
+	}
+
+	result, err = entity.NewWallet(req.ReferenceID, currency)
 	if err != nil {
 		return entity.Wallet{}, fmt.Errorf("failed to create wallet entity: %w", err)
 	}
 
+	result.Metadata = req.Metadata
+
 	err = s.txm.Run(ctx, func(ctx context.Context) error {
 		result, err = s.repo.Create(ctx, result)
 		if err != nil {
@@ -93,204 +280,2499 @@ func (s *Service) Get(ctx context.Context, req *request.GetWallet) (result entit
 	return result, nil
 }
 
-func (s *Service) DebitTransfer(ctx context.Context, req *request.DebitTransfer) (result entity.WalletEvent, err error) {
-	if req.Amount.IsNegative() {
-		return entity.WalletEvent{}, entity.ErrNegativeAmount
+func (s *Service) GetByReferenceID(ctx context.Context, req *request.GetWalletByReference) (result entity.WalletBalanceProjection, err error) {
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		result.Wallet, err = s.repo.GetByReferenceID(ctx, req.ReferenceID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet by reference id: %w", err)
+		}
+
+		result.WalletProjection, err = s.projectionRepo.Get(ctx, result.Wallet.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet projection: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.WalletBalanceProjection{}, err //nolint:wrapcheck
 	}
 
-	event, err := entity.NewWalletEvent(
-		req.TransferID,
-		req.ReferenceID,
-		req.WalletID,
-		req.Amount,
-		entity.EventTypeDebitTransfer,
-		req.Status,
-	)
+	return result, nil
+}
+
+// List returns a page of wallets together with their projections, the projections of the whole page are read in a single query.
+func (s *Service) List(ctx context.Context, req *request.ListWallets) (result entity.WalletPage, err error) {
+	filter := entity.WalletFilter{
+		Cursor:            req.Cursor,
+		Limit:             req.Limit,
+		ReferenceID:       req.ReferenceID,
+		ReferenceIDPrefix: req.ReferenceIDPrefix,
+		CreatedAfter:      req.CreatedAfter,
+		CreatedBefore:     req.CreatedBefore,
+		MinBalance:        req.MinBalance,
+		MaxBalance:        req.MaxBalance,
+		Metadata:          entity.ParseMetadataFilter(req.Metadata),
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultWalletsPageSize
+	}
+
+	pageSize := filter.Limit
+	filter.Limit++ // fetch one extra wallet to know if there is a next page
+
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		wallets, err := s.repo.List(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to list wallets: %w", err)
+		}
+
+		if uint64(len(wallets)) > pageSize {
+			wallets = wallets[:pageSize]
+			result.NextCursor = wallets[pageSize-1].ID
+		}
+
+		walletIDs := make([]string, 0, len(wallets))
+		for _, wallet := range wallets {
+			walletIDs = append(walletIDs, wallet.ID)
+		}
+
+		projections, err := s.projectionRepo.ListByWalletIDs(ctx, walletIDs)
+		if err != nil {
+			return fmt.Errorf("failed to list wallet projections: %w", err)
+		}
+
+		byWalletID := make(map[string]entity.WalletProjection, len(projections))
+		for _, projection := range projections {
+			byWalletID[projection.WalletID] = projection
+		}
+
+		result.Wallets = make([]entity.WalletBalanceProjection, 0, len(wallets))
+		for _, wallet := range wallets {
+			projection, ok := byWalletID[wallet.ID]
+			if !ok {
+				return fmt.Errorf("failed to get projection of wallet %s: %w", wallet.ID, entity.ErrEntityNotFound) // created in the same transaction as the wallet
+			}
+
+			result.Wallets = append(result.Wallets, entity.WalletBalanceProjection{Wallet: wallet, WalletProjection: projection})
+		}
+
+		return nil
+	})
 	if err != nil {
-		return result, fmt.Errorf("failed to create wallet event: %w", err)
+		return entity.WalletPage{}, err //nolint:wrapcheck
 	}
 
+	return result, nil
+}
+
+func (s *Service) Freeze(ctx context.Context, req *request.FreezeWallet) (entity.WalletBalanceProjection, error) {
+	return s.changeStatus(ctx, req.WalletID, entity.WalletStatusFrozen)
+}
+
+func (s *Service) Unfreeze(ctx context.Context, req *request.UnfreezeWallet) (entity.WalletBalanceProjection, error) {
+	return s.changeStatus(ctx, req.WalletID, entity.WalletStatusActive)
+}
+
+func (s *Service) Close(ctx context.Context, req *request.CloseWallet) (entity.WalletBalanceProjection, error) {
+	return s.changeStatus(ctx, req.WalletID, entity.WalletStatusClosed)
+}
+
+// changeStatus moves the wallet to the given status, setting the status the wallet already has is a no-op.
+// A closed wallet can't be changed anymore and a frozen wallet has to be unfrozen before it can be closed.
+func (s *Service) changeStatus(ctx context.Context, walletID string, status entity.WalletStatus) (result entity.WalletBalanceProjection, err error) {
 	err = s.txm.Run(ctx, func(ctx context.Context) error {
-		_, err = s.repo.Get(ctx, req.WalletID) // make sure the wallet exists
+		wallet, err := s.repo.GetForUpdate(ctx, walletID) // transfers take a share lock on the wallet, so none of them is in flight while the status changes
 		if err != nil {
 			return fmt.Errorf("failed to get wallet: %w", err)
 		}
 
-		result, err = s.eventRepo.Create(ctx, event) // when doing a debit transfer we don't need to rebuild the state as we are only adding to the balance
-		if err != nil {
-			return fmt.Errorf("failed to create wallet event: %w", err)
+		if wallet.Status != status {
+			err = s.checkStatusChange(ctx, wallet, status)
+			if err != nil {
+				return err
+			}
+
+			wallet.Status = status
+			wallet.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+			wallet, err = s.repo.UpdateStatus(ctx, wallet)
+			if err != nil {
+				return fmt.Errorf("failed to update wallet status: %w", err)
+			}
 		}
 
-		err = s.publisher.PublishCreated(ctx, result)
+		projection, err := s.projectionRepo.Get(ctx, walletID)
 		if err != nil {
-			return fmt.Errorf("failed to publish wallet event: %w", err)
+			return fmt.Errorf("failed to get wallet projection: %w", err)
+		}
+
+		result = entity.WalletBalanceProjection{
+			Wallet:           wallet,
+			WalletProjection: projection,
 		}
 
 		return nil
 	})
 	if err != nil {
-		return entity.WalletEvent{}, err //nolint:wrapcheck
+		return entity.WalletBalanceProjection{}, err //nolint:wrapcheck
 	}
 
 	return result, nil
 }
 
-func (s *Service) CreditTransfer(ctx context.Context, req *request.CreditTransfer) (result entity.WalletEvent, err error) {
-	if req.Amount.IsNegative() {
-		return entity.WalletEvent{}, entity.ErrNegativeAmount
+// checkStatusChange makes sure the wallet can move to the given status, it should be called inside of a transaction after locking the wallet.
+func (s *Service) checkStatusChange(ctx context.Context, wallet entity.Wallet, status entity.WalletStatus) error {
+	if wallet.Status == entity.WalletStatusClosed {
+		return entity.ErrWalletClosed
 	}
 
-	event, err := entity.NewWalletEvent(
-		req.TransferID,
-		req.ReferenceID,
-		req.WalletID,
-		req.Amount,
-		entity.EventTypeCreditTransfer,
-		req.Status,
-	)
+	if status != entity.WalletStatusClosed {
+		return nil
+	}
+
+	if wallet.Status == entity.WalletStatusFrozen {
+		return entity.ErrWalletFrozen
+	}
+
+	projection, pending, _, err := s.walletState(ctx, wallet.ID) // the stored projection can lag behind the stream, so the state is rebuilt
 	if err != nil {
-		return result, fmt.Errorf("failed to create wallet event: %w", err)
+		return err
+	}
+
+	if !projection.Balance.IsZero() || !projection.PendingDebit.IsZero() || !projection.PendingCredit.IsZero() || len(pending) > 0 {
+		return entity.ErrWalletNotEmpty
 	}
 
+	return nil
+}
+
+// GetBalance replays the wallet events up to a point in time, either the events created at or before a timestamp
+// or the events up to and including the given event. An empty as of replays the whole stream.
+func (s *Service) GetBalance(ctx context.Context, req *request.GetBalance) (result entity.WalletProjection, err error) {
 	err = s.txm.Run(ctx, func(ctx context.Context) error {
-		_, err = s.repo.Get(ctx, req.WalletID) // make sure the wallet exists
+		_, err := s.repo.Get(ctx, req.WalletID)
 		if err != nil {
 			return fmt.Errorf("failed to get wallet: %w", err)
 		}
 
-		events, err := s.eventRepo.ListByWalletID(ctx, req.WalletID) // get all events for the wallet and rebuild the state
+		events, err := s.eventRepo.ListByWalletID(ctx, req.WalletID)
 		if err != nil {
 			return fmt.Errorf("failed to list wallet events: %w", err)
 		}
 
-		projection := &entity.WalletProjection{}
-
-		err = ProcessEvents(ctx, projection, events)
+		events, err = eventsAsOf(events, req.AsOf)
 		if err != nil {
-			return fmt.Errorf("failed to process wallet events: %w", err)
+			return err
 		}
 
-		if projection.Balance.LessThan(req.Amount) {
-			return entity.ErrInsufficientBalance
-		}
-
-		result, err = s.eventRepo.Create(ctx, event) // when doing a debit transfer we don't need to rebuild the state as we are only adding to the balance
-		if err != nil {
-			return fmt.Errorf("failed to create wallet event: %w", err)
-		}
+		result = entity.WalletProjection{WalletID: req.WalletID}
 
-		err = s.publisher.PublishCreated(ctx, result)
+		err = ProcessEvents(ctx, &result, events)
 		if err != nil {
-			return fmt.Errorf("failed to publish wallet event: %w", err)
+			return fmt.Errorf("failed to process events: %w", err)
 		}
 
 		return nil
 	})
 	if err != nil {
-		return entity.WalletEvent{}, err //nolint:wrapcheck
+		return entity.WalletProjection{}, err //nolint:wrapcheck
 	}
 
 	return result, nil
 }
 
-func (s *Service) CompleteTransfer(ctx context.Context, req *request.CompleteTransfer) (result entity.WalletEvent, err error) { //nolint:dupl
-	event, err := entity.NewWalletEvent(req.TransferID, req.ReferenceID, req.WalletID, decimal.NewFromInt(0), entity.EventTypeUpdateTransferStatus, entity.TransferStatusCompleted)
-	if err != nil {
-		return result, fmt.Errorf("failed to create wallet event: %w", err)
+// eventsAsOf cuts the wallet stream at asOf, a timestamp keeps the events created at or before it, anything else is treated as an event id.
+func eventsAsOf(events []entity.WalletEvent, asOf string) ([]entity.WalletEvent, error) {
+	if asOf == "" {
+		return events, nil
+	}
+
+	if at, err := time.Parse(time.RFC3339Nano, asOf); err == nil {
+		result := make([]entity.WalletEvent, 0, len(events))
+		for _, event := range events {
+			if !event.CreatedAt.After(at) {
+				result = append(result, event)
+			}
+		}
+		return result, nil
+	}
+
+	for i, event := range events {
+		if event.ID == asOf {
+			return events[:i+1], nil
+		}
+	}
+
+	return nil, entity.ErrInvalidBalanceAsOf
+}
+
+// SetTier moves the wallet to another tier, the tier has to be the standard one or have velocity limits configured.
+func (s *Service) SetTier(ctx context.Context, req *request.SetWalletTier) (result entity.WalletBalanceProjection, err error) {
+	if _, ok := s.velocityLimits[req.Tier]; !ok && req.Tier != entity.WalletTierStandard {
+		return entity.WalletBalanceProjection{}, entity.ErrUnknownWalletTier
 	}
 
 	err = s.txm.Run(ctx, func(ctx context.Context) error {
-		_, err = s.repo.Get(ctx, req.WalletID) // make sure the wallet exists
+		wallet, err := s.repo.GetForUpdate(ctx, req.WalletID)
 		if err != nil {
 			return fmt.Errorf("failed to get wallet: %w", err)
 		}
 
-		// since we don't really care if the transfer_id exists, we can just create the event and worst case it will just get ignored during state rebuild
-		result, err = s.eventRepo.Create(ctx, event)
+		err = checkWalletStatus(wallet, true)
 		if err != nil {
-			return fmt.Errorf("failed to create wallet event: %w", err)
+			return err
+		}
+
+		if wallet.Tier != req.Tier {
+			wallet.Tier = req.Tier
+			wallet.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+			wallet, err = s.repo.UpdateTier(ctx, wallet)
+			if err != nil {
+				return fmt.Errorf("failed to update wallet tier: %w", err)
+			}
 		}
 
-		err = s.publisher.PublishCreated(ctx, result)
+		projection, err := s.projectionRepo.Get(ctx, req.WalletID)
 		if err != nil {
-			return fmt.Errorf("failed to publish wallet event: %w", err)
+			return fmt.Errorf("failed to get wallet projection: %w", err)
+		}
+
+		result = entity.WalletBalanceProjection{
+			Wallet:           wallet,
+			WalletProjection: projection,
 		}
 
 		return nil
 	})
 	if err != nil {
-		return entity.WalletEvent{}, err //nolint:wrapcheck
+		return entity.WalletBalanceProjection{}, err //nolint:wrapcheck
 	}
 
 	return result, nil
 }
 
-func (s *Service) RevertTransfer(ctx context.Context, req *request.RevertTransfer) (result entity.WalletEvent, err error) { //nolint:dupl
-	event, err := entity.NewWalletEvent(req.TransferID, req.ReferenceID, req.WalletID, decimal.NewFromInt(0), entity.EventTypeUpdateTransferStatus, entity.TransferStatusFailed)
-	if err != nil {
-		return result, fmt.Errorf("failed to create wallet event: %w", err)
+func (s *Service) ListEvents(ctx context.Context, req *request.ListWalletEvents) (result entity.WalletEventPage, err error) {
+	filter := entity.WalletEventFilter{
+		WalletID:      req.WalletID,
+		Cursor:        req.Cursor,
+		Limit:         req.Limit,
+		EventType:     req.EventType,
+		Status:        req.Status,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Metadata:      entity.ParseMetadataFilter(req.Metadata),
 	}
 
+	if filter.Limit == 0 {
+		filter.Limit = defaultEventsPageSize
+	}
+
+	pageSize := filter.Limit
+	filter.Limit++ // fetch one extra event to know if there is a next page
+
 	err = s.txm.Run(ctx, func(ctx context.Context) error {
 		_, err = s.repo.Get(ctx, req.WalletID) // make sure the wallet exists
 		if err != nil {
 			return fmt.Errorf("failed to get wallet: %w", err)
 		}
 
-		// since we don't really care if the transfer_id exists, we can just create the event and worst case it will just get ignored during state rebuild
-		result, err = s.eventRepo.Create(ctx, event)
-		if err != nil {
-			return fmt.Errorf("failed to create wallet event: %w", err)
-		}
-
-		err = s.publisher.PublishCreated(ctx, result)
+		result.Events, err = s.eventRepo.List(ctx, filter)
 		if err != nil {
-			return fmt.Errorf("failed to publish wallet event: %w", err)
+			return fmt.Errorf("failed to list wallet events: %w", err)
 		}
 
 		return nil
 	})
 	if err != nil {
-		return entity.WalletEvent{}, err //nolint:wrapcheck
+		return entity.WalletEventPage{}, err //nolint:wrapcheck
+	}
+
+	if uint64(len(result.Events)) > pageSize {
+		result.Events = result.Events[:pageSize]
+		result.NextCursor = result.Events[pageSize-1].ID
 	}
 
 	return result, nil
 }
 
-func (s *Service) RebuildWalletProjection(ctx context.Context, event *entity.WalletEvent) (result entity.WalletProjection, err error) {
-	logger := sloglog.FromContext(ctx)
+// GetStatement replays the whole wallet stream, see FoldStatement. The period starts at the wallet creation and ends now if not set.
+// The statement is built in memory before anything is written, its size is bounded by the number of events of the wallet
+// (every event is loaded and one line is kept per transfer event in the period), not by the period alone.
+func (s *Service) GetStatement(ctx context.Context, req *request.GetStatement) (result entity.Statement, err error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	result = entity.Statement{
+		WalletID:  req.WalletID,
+		From:      req.From.UTC(),
+		To:        req.To.UTC(),
+		CreatedAt: now,
+	}
+
+	if result.To.IsZero() {
+		result.To = now
+	}
+
 	err = s.txm.Run(ctx, func(ctx context.Context) error {
-		projection, err := s.projectionRepo.Get(ctx, event.WalletID)
+		wallet, err := s.repo.Get(ctx, req.WalletID)
 		if err != nil {
-			return fmt.Errorf("failed to get wallet projection: %w", err)
+			return fmt.Errorf("failed to get wallet: %w", err)
 		}
 
-		if projection.LastEventID >= event.ID { // UUIDv7's are k-sortable and lexicographic, so we can just compare the last event id using comparison operators, since strings in go are compared lexicographically
-			logger.InfoContext(ctx, "no new events to process skipping rebuild") // if we need to do a full rebuild for some reason and there are no new events a different function should be used
-			return nil
+		result.Currency = wallet.Currency
+		if result.From.IsZero() {
+			result.From = wallet.CreatedAt.UTC()
 		}
 
-		events, err := s.eventRepo.ListByWalletID(ctx, event.WalletID)
+		if !result.From.Before(result.To) {
+			return entity.ErrInvalidStatementPeriod
+		}
+
+		events, err := s.eventRepo.ListByWalletID(ctx, req.WalletID)
 		if err != nil {
 			return fmt.Errorf("failed to list wallet events: %w", err)
 		}
 
-		err = ProcessEvents(ctx, &result, events)
+		err = FoldStatement(ctx, &result, events)
 		if err != nil {
-			return fmt.Errorf("failed to process wallet events: %w", err)
+			return fmt.Errorf("failed to fold statement: %w", err)
 		}
 
-		result.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+		return nil
+	})
+	if err != nil {
+		return entity.Statement{}, err //nolint:wrapcheck
+	}
 
-		_, err = s.projectionRepo.Update(ctx, result)
+	return result, nil
+}
+
+func (s *Service) GetTransfer(ctx context.Context, req *request.GetTransfer) (result entity.WalletTransferRefunds, err error) {
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		result.WalletTransfer, err = s.transferRepo.Get(ctx, req.WalletID, req.TransferID)
 		if err != nil {
-			return fmt.Errorf("failed to update wallet projection: %w", err)
+			return fmt.Errorf("failed to get wallet transfer: %w", err)
+		}
+
+		result.Refunds, err = s.refundRepo.ListByTransferID(ctx, req.WalletID, req.TransferID)
+		if err != nil {
+			return fmt.Errorf("failed to list transfer refunds: %w", err)
 		}
 
 		return nil
 	})
 	if err != nil {
-		return entity.WalletProjection{}, err //nolint:wrapcheck
+		return entity.WalletTransferRefunds{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+func (s *Service) ListTransfers(ctx context.Context, req *request.ListTransfers) (result []entity.WalletTransfer, err error) {
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		_, err = s.repo.Get(ctx, req.WalletID) // make sure the wallet exists
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		result, err = s.transferRepo.List(ctx, entity.WalletTransferFilter{
+			WalletID: req.WalletID,
+			Status:   req.Status,
+			Metadata: entity.ParseMetadataFilter(req.Metadata),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list wallet transfers: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+func (s *Service) DebitTransfer(ctx context.Context, req *request.DebitTransfer) (result entity.WalletEvent, err error) {
+	if req.Amount.IsNegative() {
+		return entity.WalletEvent{}, entity.ErrNegativeAmount
+	}
+
+	event, err := entity.NewWalletEvent(
+		req.TransferID,
+		req.ReferenceID,
+		req.WalletID,
+		req.Amount,
+		entity.EventTypeDebitTransfer,
+		req.Status,
+	)
+	if err != nil {
+		return result, fmt.Errorf("failed to create wallet event: %w", err)
+	}
+
+	event.ExpiresAt, err = transferExpiry(req.ExpiresAt, req.Status)
+	if err != nil {
+		return entity.WalletEvent{}, err
+	}
+
+	event.Metadata = req.Metadata
+
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		wallet, err := s.repo.GetForShare(ctx, req.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		err = checkWalletStatus(wallet, false)
+		if err != nil {
+			return err
+		}
+
+		err = validateWalletAmount(wallet, req.Currency, req.Amount)
+		if err != nil {
+			return err
+		}
+
+		version, err := s.eventRepo.GetStreamVersion(ctx, req.WalletID) // when doing a debit transfer we don't need to rebuild the state as we are only adding to the balance
+		if err != nil {
+			return fmt.Errorf("failed to get wallet stream version: %w", err)
+		}
+
+		result, err = s.appendEvent(ctx, event, version)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.WalletEvent{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+func (s *Service) CreditTransfer(ctx context.Context, req *request.CreditTransfer) (result entity.WalletEvent, err error) {
+	if req.Amount.IsNegative() {
+		return entity.WalletEvent{}, entity.ErrNegativeAmount
+	}
+
+	event, err := entity.NewWalletEvent(
+		req.TransferID,
+		req.ReferenceID,
+		req.WalletID,
+		req.Amount,
+		entity.EventTypeCreditTransfer,
+		req.Status,
+	)
+	if err != nil {
+		return result, fmt.Errorf("failed to create wallet event: %w", err)
+	}
+
+	event.ExpiresAt, err = transferExpiry(req.ExpiresAt, req.Status)
+	if err != nil {
+		return entity.WalletEvent{}, err
+	}
+
+	event.Metadata = req.Metadata
+
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		wallet, err := s.repo.GetForShare(ctx, req.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		err = checkWalletStatus(wallet, false)
+		if err != nil {
+			return err
+		}
+
+		err = validateWalletAmount(wallet, req.Currency, req.Amount)
+		if err != nil {
+			return err
+		}
+
+		projection, err := s.currentProjection(ctx, req.WalletID)
+		if err != nil {
+			return err
+		}
+
+		event.Fee = s.transferFee(wallet, req.Amount)
+
+		if projection.AvailableBalance().LessThan(req.Amount.Add(event.Fee)) {
+			return entity.ErrInsufficientBalance
+		}
+
+		err = s.checkVelocityLimits(ctx, wallet, req.Amount)
+		if err != nil {
+			return err
+		}
+
+		result, err = s.appendEvent(ctx, event, projection.LastSequence) // fails if another event was appended after the balance check
+		if err != nil {
+			return err
+		}
+
+		if result.Fee.IsPositive() {
+			return s.appendFee(ctx, wallet, result)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.WalletEvent{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// SetCreditLimit records the new credit limit of the wallet as an event, lowering it below the current overdraft is allowed,
+// the wallet then only rejects further credits until the balance is back within the limit.
+func (s *Service) SetCreditLimit(ctx context.Context, req *request.SetCreditLimit) (result entity.WalletEvent, err error) {
+	if req.CreditLimit.IsNegative() {
+		return entity.WalletEvent{}, entity.ErrInvalidCreditLimit
+	}
+
+	event, err := entity.NewWalletEvent("", req.ReferenceID, req.WalletID, req.CreditLimit, entity.EventTypeCreditLimitChanged, entity.TransferStatusCompleted)
+	if err != nil {
+		return result, fmt.Errorf("failed to create wallet event: %w", err)
+	}
+
+	event.TransferID = event.ID // not a transfer, but the transfer id is required and unique per wallet and event type
+
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		wallet, err := s.repo.Get(ctx, req.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		err = checkWalletStatus(wallet, true)
+		if err != nil {
+			return err
+		}
+
+		if wallet.Currency.ValidateAmount(req.CreditLimit) != nil {
+			return entity.ErrInvalidCreditLimit
+		}
+
+		version, err := s.eventRepo.GetStreamVersion(ctx, req.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet stream version: %w", err)
+		}
+
+		result, err = s.appendEvent(ctx, event, version)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.WalletEvent{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+func (s *Service) CompleteTransfer(ctx context.Context, req *request.CompleteTransfer) (result entity.WalletEvent, err error) { //nolint:dupl
+	event, err := entity.NewWalletEvent(req.TransferID, req.ReferenceID, req.WalletID, decimal.NewFromInt(0), entity.EventTypeUpdateTransferStatus, entity.TransferStatusCompleted)
+	if err != nil {
+		return result, fmt.Errorf("failed to create wallet event: %w", err)
+	}
+
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		wallet, err := s.repo.GetForShare(ctx, req.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		err = checkWalletStatus(wallet, false)
+		if err != nil {
+			return err
+		}
+
+		version, err := s.eventRepo.GetStreamVersion(ctx, req.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet stream version: %w", err)
+		}
+
+		transfer, err := s.checkTransferPending(ctx, req.WalletID, req.TransferID)
+		if err != nil {
+			return err
+		}
+
+		if transfer.IsExpired(time.Now().UTC()) {
+			return entity.ErrTransferExpired // the expiry job is about to revert it, the hold can't be captured anymore
+		}
+
+		event.Amount, err = captureAmount(wallet, transfer, req.Amount)
+		if err != nil {
+			return err
+		}
+
+		result, err = s.appendEvent(ctx, event, version) // fails if the transfer was settled after the check
+		if err != nil {
+			return err
+		}
+
+		return s.settleFee(ctx, wallet, transfer, result)
+	})
+	if err != nil {
+		return entity.WalletEvent{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+func (s *Service) RevertTransfer(ctx context.Context, req *request.RevertTransfer) (result entity.WalletEvent, err error) { //nolint:dupl
+	event, err := entity.NewWalletEvent(req.TransferID, req.ReferenceID, req.WalletID, decimal.NewFromInt(0), entity.EventTypeUpdateTransferStatus, entity.TransferStatusFailed)
+	if err != nil {
+		return result, fmt.Errorf("failed to create wallet event: %w", err)
+	}
+
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		wallet, err := s.repo.GetForShare(ctx, req.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		err = checkWalletStatus(wallet, true) // reverting releases the held funds, so it is allowed while the wallet is frozen
+		if err != nil {
+			return err
+		}
+
+		version, err := s.eventRepo.GetStreamVersion(ctx, req.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet stream version: %w", err)
+		}
+
+		transfer, err := s.checkTransferPending(ctx, req.WalletID, req.TransferID)
+		if err != nil {
+			return err
+		}
+
+		result, err = s.appendEvent(ctx, event, version) // fails if the transfer was settled after the check
+		if err != nil {
+			return err
+		}
+
+		return s.settleFee(ctx, wallet, transfer, result)
+	})
+	if err != nil {
+		return entity.WalletEvent{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// RefundTransfer compensates a completed transfer with a completed transfer in the opposite direction, a refunded credit comes back
+// to the wallet as a debit and a refunded debit leaves it again as a credit. A transfer can be refunded in parts until its captured
+// amount is used up. Refunds aren't charged fees and don't return the fee of a refunded credit.
+func (s *Service) RefundTransfer(ctx context.Context, req *request.RefundTransfer) (result entity.TransferRefund, err error) {
+	if entity.IsFeeTransferID(req.TransferID) || entity.IsRefundTransferID(req.TransferID) {
+		return entity.TransferRefund{}, entity.ErrTransferNotRefundable
+	}
+
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		wallet, err := s.repo.GetForShare(ctx, req.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		err = checkWalletStatus(wallet, false)
+		if err != nil {
+			return err
+		}
+
+		transfer, err := s.refundableTransfer(ctx, req.WalletID, req.TransferID)
+		if err != nil {
+			return err
+		}
+
+		amount, err := refundAmount(wallet, transfer, req.Amount)
+		if err != nil {
+			return err
+		}
+
+		projection, err := s.currentProjection(ctx, req.WalletID)
+		if err != nil {
+			return err
+		}
+
+		eventType := entity.EventTypeDebitTransfer
+		if transfer.Direction == entity.TransferDirectionDebit {
+			eventType = entity.EventTypeCreditTransfer
+
+			if projection.AvailableBalance().LessThan(amount) {
+				return entity.ErrInsufficientBalance
+			}
+		}
+
+		refundID := entity.RefundTransferID(req.TransferID, len(transfer.Refunds)+1)
+
+		event, err := entity.NewWalletEvent(refundID, req.ReferenceID, req.WalletID, amount, eventType, entity.TransferStatusCompleted)
+		if err != nil {
+			return fmt.Errorf("failed to create wallet event: %w", err)
+		}
+
+		event, err = s.appendEvent(ctx, event, projection.LastSequence) // fails if another refund was written after the checks
+		if err != nil {
+			return err
+		}
+
+		result, err = s.refundRepo.Create(ctx, entity.NewTransferRefund(event, req.TransferID))
+		if err != nil {
+			return fmt.Errorf("failed to create transfer refund: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.TransferRefund{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// BatchTransfers runs the debit, credit, complete and revert items in order. An atomic batch runs all of them in a single transaction
+// and is retried as a whole on a stream version conflict, otherwise every item is written on its own and a failed item doesn't stop the rest.
+func (s *Service) BatchTransfers(ctx context.Context, req *request.BatchTransfers) (result []entity.BatchTransferResult, err error) {
+	if len(req.Items) == 0 || len(req.Items) > s.maxBatchSize {
+		return nil, entity.ErrInvalidBatchSize
+	}
+
+	if !req.Atomic {
+		result = make([]entity.BatchTransferResult, len(req.Items))
+		for i, item := range req.Items {
+			result[i].Event, result[i].Err = s.batchTransfer(ctx, item)
+		}
+
+		return result, nil
+	}
+
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		ctx = context.WithValue(ctx, nestedAppendKey{}, true)
+
+		result = make([]entity.BatchTransferResult, len(req.Items))
+		for i, item := range req.Items {
+			event, err := s.batchTransfer(ctx, item)
+			if err != nil {
+				return &entity.BatchItemError{Index: i, Err: err}
+			}
+
+			result[i].Event = event
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+func (s *Service) batchTransfer(ctx context.Context, item request.BatchTransferItem) (entity.WalletEvent, error) {
+	switch item.Operation {
+	case entity.BatchOperationDebit:
+		return s.DebitTransfer(ctx, &request.DebitTransfer{
+			WalletID:    item.WalletID,
+			ReferenceID: item.ReferenceID,
+			TransferID:  item.TransferID,
+			Amount:      item.Amount,
+			Currency:    item.Currency,
+			Status:      item.Status,
+			ExpiresAt:   item.ExpiresAt,
+			Metadata:    item.Metadata,
+		})
+	case entity.BatchOperationCredit:
+		return s.CreditTransfer(ctx, &request.CreditTransfer{
+			WalletID:    item.WalletID,
+			ReferenceID: item.ReferenceID,
+			TransferID:  item.TransferID,
+			Amount:      item.Amount,
+			Currency:    item.Currency,
+			Status:      item.Status,
+			ExpiresAt:   item.ExpiresAt,
+			Metadata:    item.Metadata,
+		})
+	case entity.BatchOperationComplete:
+		req := &request.CompleteTransfer{WalletID: item.WalletID, TransferID: item.TransferID, ReferenceID: item.ReferenceID}
+		if !item.Amount.IsZero() {
+			req.Amount = &item.Amount // partial capture
+		}
+
+		return s.CompleteTransfer(ctx, req)
+	case entity.BatchOperationRevert:
+		return s.RevertTransfer(ctx, &request.RevertTransfer{WalletID: item.WalletID, TransferID: item.TransferID, ReferenceID: item.ReferenceID})
+	default:
+		return entity.WalletEvent{}, entity.ErrInvalidBatchOperation
+	}
+}
+
+// ScheduleDebitTransfer queues the debit to be executed at req.ExecuteAt.
+func (s *Service) ScheduleDebitTransfer(ctx context.Context, req *request.DebitTransfer) (entity.ScheduledTransfer, error) {
+	return s.scheduleTransfer(ctx, req.WalletID, req.TransferID, req.ReferenceID, entity.TransferDirectionDebit, req.Amount, req.Currency, req.Status, req.ExpiresAt, req.ExecuteAt, req.Metadata)
+}
+
+// ScheduleCreditTransfer queues the credit to be executed at req.ExecuteAt, the available balance is only checked once it's executed.
+func (s *Service) ScheduleCreditTransfer(ctx context.Context, req *request.CreditTransfer) (entity.ScheduledTransfer, error) {
+	return s.scheduleTransfer(ctx, req.WalletID, req.TransferID, req.ReferenceID, entity.TransferDirectionCredit, req.Amount, req.Currency, req.Status, req.ExpiresAt, req.ExecuteAt, req.Metadata)
+}
+
+func (s *Service) scheduleTransfer(
+	ctx context.Context,
+	walletID, transferID, referenceID string,
+	direction entity.TransferDirection,
+	amount decimal.Decimal,
+	currency string,
+	status entity.TransferStatus,
+	expiresAt, executeAt *time.Time,
+	metadata entity.Metadata,
+) (result entity.ScheduledTransfer, err error) {
+	if amount.IsNegative() {
+		return entity.ScheduledTransfer{}, entity.ErrNegativeAmount
+	}
+
+	if executeAt == nil || !executeAt.After(time.Now().UTC()) {
+		return entity.ScheduledTransfer{}, entity.ErrInvalidExecuteAt
+	}
+
+	expiry, err := transferExpiry(expiresAt, status)
+	if err != nil {
+		return entity.ScheduledTransfer{}, err
+	}
+
+	if expiry != nil && !expiry.After(*executeAt) {
+		return entity.ScheduledTransfer{}, entity.ErrInvalidExecuteAt
+	}
+
+	transfer, err := entity.NewScheduledTransfer(walletID, transferID, referenceID, direction, amount, currency, status, expiry, *executeAt)
+	if err != nil {
+		return entity.ScheduledTransfer{}, fmt.Errorf("failed to create scheduled transfer: %w", err)
+	}
+
+	transfer.Metadata = metadata
+
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		wallet, err := s.repo.GetForShare(ctx, walletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		err = checkWalletStatus(wallet, false)
+		if err != nil {
+			return err
+		}
+
+		err = validateWalletAmount(wallet, currency, amount)
+		if err != nil {
+			return err
+		}
+
+		err = s.checkTransferUnused(ctx, walletID, transferID)
+		if err != nil {
+			return err
+		}
+
+		result, err = s.scheduledRepo.Create(ctx, transfer)
+		if err != nil {
+			return fmt.Errorf("failed to create scheduled transfer: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ScheduledTransfer{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+func (s *Service) ListScheduledTransfers(ctx context.Context, req *request.ListScheduledTransfers) (result []entity.ScheduledTransfer, err error) {
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		_, err = s.repo.Get(ctx, req.WalletID) // make sure the wallet exists
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		result, err = s.scheduledRepo.List(ctx, entity.ScheduledTransferFilter{
+			WalletID: req.WalletID,
+			Status:   req.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list scheduled transfers: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// CancelScheduledTransfer cancels a transfer that wasn't executed yet, the row lock makes it wait for an execution that is in progress.
+func (s *Service) CancelScheduledTransfer(ctx context.Context, req *request.CancelScheduledTransfer) (result entity.ScheduledTransfer, err error) {
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		result, err = s.scheduledRepo.GetForUpdate(ctx, req.WalletID, req.TransferID)
+		if err != nil {
+			return fmt.Errorf("failed to get scheduled transfer: %w", err)
+		}
+
+		if result.Status != entity.ScheduleStatusScheduled {
+			return entity.ErrTransferNotScheduled
+		}
+
+		result.Settle(entity.ScheduleStatusCancelled, nil, "", time.Now())
+
+		err = s.scheduledRepo.Update(ctx, result)
+		if err != nil {
+			return fmt.Errorf("failed to update scheduled transfer: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ScheduledTransfer{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// checkTransferUnused makes sure the wallet has no events for the transfer id yet.
+func (s *Service) checkTransferUnused(ctx context.Context, walletID, transferID string) error {
+	events, err := s.eventRepo.ListByTransferID(ctx, walletID, transferID)
+	if err != nil {
+		return fmt.Errorf("failed to list transfer events: %w", err)
+	}
+
+	if len(events) > 0 {
+		return entity.ErrTransferAlreadyExists
+	}
+
+	return nil
+}
+
+func (s *Service) CreateRecurringTransfer(ctx context.Context, req *request.CreateRecurringTransfer) (result entity.RecurringTransfer, err error) {
+	if req.Amount.IsNegative() {
+		return entity.RecurringTransfer{}, entity.ErrNegativeAmount
+	}
+
+	rule, err := entity.ParseRecurrenceRule(req.Rule)
+	if err != nil {
+		return entity.RecurringTransfer{}, err
+	}
+
+	startAt := time.Now().UTC()
+	if req.StartAt != nil {
+		if req.StartAt.Before(startAt) {
+			return entity.RecurringTransfer{}, entity.ErrInvalidRecurrenceStart
+		}
+		startAt = *req.StartAt
+	}
+
+	transfer, err := entity.NewRecurringTransfer(req.WalletID, req.ReferenceID, req.Direction, req.Amount, req.Currency, rule, startAt, req.EndAt)
+	if err != nil {
+		return entity.RecurringTransfer{}, fmt.Errorf("failed to create recurring transfer: %w", err)
+	}
+
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		wallet, err := s.repo.Get(ctx, req.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		err = checkWalletStatus(wallet, false)
+		if err != nil {
+			return err
+		}
+
+		err = validateWalletAmount(wallet, req.Currency, req.Amount)
+		if err != nil {
+			return err
+		}
+
+		result, err = s.recurringRepo.Create(ctx, transfer)
+		if err != nil {
+			return fmt.Errorf("failed to create recurring transfer: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.RecurringTransfer{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+func (s *Service) GetRecurringTransfer(ctx context.Context, req *request.GetRecurringTransfer) (result entity.RecurringTransfer, err error) {
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		result, err = s.recurringRepo.Get(ctx, req.WalletID, req.RecurringID)
+		if err != nil {
+			return fmt.Errorf("failed to get recurring transfer: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.RecurringTransfer{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+func (s *Service) ListRecurringTransfers(ctx context.Context, req *request.ListRecurringTransfers) (result []entity.RecurringTransfer, err error) {
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		_, err = s.repo.Get(ctx, req.WalletID) // make sure the wallet exists
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		result, err = s.recurringRepo.List(ctx, entity.RecurringTransferFilter{
+			WalletID: req.WalletID,
+			Status:   req.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list recurring transfers: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// UpdateRecurringTransfer replaces the amount, rule, end and status of a recurring transfer that isn't finished yet.
+// A changed rule restarts the recurrence from now and resuming a paused one skips the occurrences missed while it was paused.
+func (s *Service) UpdateRecurringTransfer(ctx context.Context, req *request.UpdateRecurringTransfer) (result entity.RecurringTransfer, err error) {
+	if req.Amount.IsNegative() {
+		return entity.RecurringTransfer{}, entity.ErrNegativeAmount
+	}
+
+	if req.Status != entity.RecurringStatusActive && req.Status != entity.RecurringStatusPaused {
+		return entity.RecurringTransfer{}, entity.ErrInvalidRecurringStatus
+	}
+
+	rule, err := entity.ParseRecurrenceRule(req.Rule)
+	if err != nil {
+		return entity.RecurringTransfer{}, err
+	}
+
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		result, err = s.recurringRepo.GetForUpdate(ctx, req.WalletID, req.RecurringID)
+		if err != nil {
+			return fmt.Errorf("failed to get recurring transfer: %w", err)
+		}
+
+		if result.Status == entity.RecurringStatusFinished {
+			return entity.ErrRecurrenceFinished
+		}
+
+		wallet, err := s.repo.Get(ctx, req.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		err = validateWalletAmount(wallet, result.Currency, req.Amount)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+
+		if rule.String() != result.Rule {
+			err = result.Reschedule(rule, maxTime(now, result.StartAt), req.EndAt)
+		} else {
+			err = result.SetEnd(req.EndAt)
+		}
+		if err != nil {
+			return err
+		}
+
+		if result.Status == entity.RecurringStatusPaused && req.Status == entity.RecurringStatusActive {
+			err = result.SkipMissed(now)
+			if err != nil {
+				return err
+			}
+		}
+
+		if result.Status != entity.RecurringStatusFinished {
+			result.Status = req.Status
+		}
+
+		result.Amount = req.Amount
+		result.UpdatedAt = now.Truncate(time.Microsecond)
+
+		err = s.recurringRepo.Update(ctx, result)
+		if err != nil {
+			return fmt.Errorf("failed to update recurring transfer: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.RecurringTransfer{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// DeleteRecurringTransfer stops the recurring transfer, the transfers of the occurrences that were already executed are kept.
+func (s *Service) DeleteRecurringTransfer(ctx context.Context, req *request.DeleteRecurringTransfer) error {
+	return s.txm.Run(ctx, func(ctx context.Context) error { //nolint:wrapcheck
+		err := s.recurringRepo.Delete(ctx, req.WalletID, req.RecurringID)
+		if err != nil {
+			return fmt.Errorf("failed to delete recurring transfer: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
+func (s *Service) InternalTransfer(ctx context.Context, req *request.InternalTransfer) (result entity.InternalTransferEvents, err error) {
+	if req.Amount.IsNegative() {
+		return entity.InternalTransferEvents{}, entity.ErrNegativeAmount
+	}
+
+	if req.SourceWalletID == req.DestinationWalletID {
+		return entity.InternalTransferEvents{}, entity.ErrSameWalletTransfer
+	}
+
+	transfer := entity.NewInternalTransfer(req.TransferID, req.ReferenceID, req.SourceWalletID, req.DestinationWalletID, req.Amount)
+
+	creditEvent, err := entity.NewWalletEvent(req.TransferID, req.ReferenceID, req.SourceWalletID, req.Amount, entity.EventTypeCreditTransfer, req.Status)
+	if err != nil {
+		return result, fmt.Errorf("failed to create wallet event: %w", err)
+	}
+
+	debitEvent, err := entity.NewWalletEvent(req.TransferID, req.ReferenceID, req.DestinationWalletID, req.Amount, entity.EventTypeDebitTransfer, req.Status)
+	if err != nil {
+		return result, fmt.Errorf("failed to create wallet event: %w", err)
+	}
+
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		sourceWallet, err := s.repo.GetForShare(ctx, req.SourceWalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get source wallet: %w", err)
+		}
+
+		destinationWallet, err := s.repo.GetForShare(ctx, req.DestinationWalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get destination wallet: %w", err)
+		}
+
+		err = errors.Join(checkWalletStatus(sourceWallet, false), checkWalletStatus(destinationWallet, false))
+		if err != nil {
+			return err
+		}
+
+		err = validateWalletAmount(destinationWallet, string(sourceWallet.Currency), req.Amount) // both wallets have to hold the same currency
+		if err != nil {
+			return err
+		}
+
+		projection, err := s.currentProjection(ctx, req.SourceWalletID)
+		if err != nil {
+			return err
+		}
+
+		if projection.AvailableBalance().LessThan(req.Amount) {
+			return entity.ErrInsufficientBalance
+		}
+
+		err = s.checkVelocityLimits(ctx, sourceWallet, req.Amount) // the source leg is a credit like any other
+		if err != nil {
+			return err
+		}
+
+		destinationVersion, err := s.eventRepo.GetStreamVersion(ctx, req.DestinationWalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet stream version: %w", err)
+		}
+
+		result.InternalTransfer, err = s.internalTransferRepo.Create(ctx, transfer)
+		if err != nil {
+			return fmt.Errorf("failed to create internal transfer: %w", err)
+		}
+
+		result.CreditEvent, err = s.appendEvent(ctx, creditEvent, projection.LastSequence)
+		if err != nil {
+			return err
+		}
+
+		result.DebitEvent, err = s.appendEvent(ctx, debitEvent, destinationVersion)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.InternalTransferEvents{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+func (s *Service) CompleteInternalTransfer(ctx context.Context, req *request.CompleteInternalTransfer) (entity.InternalTransferEvents, error) {
+	return s.updateInternalTransferStatus(ctx, req.TransferID, req.ReferenceID, entity.TransferStatusCompleted)
+}
+
+func (s *Service) RevertInternalTransfer(ctx context.Context, req *request.RevertInternalTransfer) (entity.InternalTransferEvents, error) {
+	return s.updateInternalTransferStatus(ctx, req.TransferID, req.ReferenceID, entity.TransferStatusFailed)
+}
+
+// updateInternalTransferStatus writes the status update for both legs of an internal transfer in a single transaction.
+func (s *Service) updateInternalTransferStatus(ctx context.Context, transferID, referenceID string, status entity.TransferStatus) (result entity.InternalTransferEvents, err error) {
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		result.InternalTransfer, err = s.internalTransferRepo.Get(ctx, transferID)
+		if err != nil {
+			return fmt.Errorf("failed to get internal transfer: %w", err)
+		}
+
+		for _, walletID := range []string{result.SourceWalletID, result.DestinationWalletID} {
+			wallet, err := s.repo.GetForShare(ctx, walletID)
+			if err != nil {
+				return fmt.Errorf("failed to get wallet: %w", err)
+			}
+
+			err = checkWalletStatus(wallet, status == entity.TransferStatusFailed)
+			if err != nil {
+				return err
+			}
+		}
+
+		creditEvent, err := entity.NewWalletEvent(transferID, referenceID, result.SourceWalletID, decimal.NewFromInt(0), entity.EventTypeUpdateTransferStatus, status)
+		if err != nil {
+			return fmt.Errorf("failed to create wallet event: %w", err)
+		}
+
+		debitEvent, err := entity.NewWalletEvent(transferID, referenceID, result.DestinationWalletID, decimal.NewFromInt(0), entity.EventTypeUpdateTransferStatus, status)
+		if err != nil {
+			return fmt.Errorf("failed to create wallet event: %w", err)
+		}
+
+		sourceVersion, err := s.eventRepo.GetStreamVersion(ctx, result.SourceWalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet stream version: %w", err)
+		}
+
+		destinationVersion, err := s.eventRepo.GetStreamVersion(ctx, result.DestinationWalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet stream version: %w", err)
+		}
+
+		_, err = s.checkTransferPending(ctx, result.SourceWalletID, transferID) // both legs are always updated together, so checking one of them is enough
+		if err != nil {
+			return err
+		}
+
+		result.CreditEvent, err = s.appendEvent(ctx, creditEvent, sourceVersion)
+		if err != nil {
+			return err
+		}
+
+		result.DebitEvent, err = s.appendEvent(ctx, debitEvent, destinationVersion)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.InternalTransferEvents{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+func (s *Service) CreateFXQuote(ctx context.Context, req *request.CreateFXQuote) (result entity.FXQuote, err error) {
+	if req.Amount.IsNegative() {
+		return entity.FXQuote{}, entity.ErrNegativeAmount
+	}
+
+	if req.SourceWalletID == req.TargetWalletID {
+		return entity.FXQuote{}, entity.ErrSameWalletTransfer
+	}
+
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		sourceWallet, err := s.repo.Get(ctx, req.SourceWalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get source wallet: %w", err)
+		}
+
+		targetWallet, err := s.repo.Get(ctx, req.TargetWalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get target wallet: %w", err)
+		}
+
+		err = errors.Join(checkWalletStatus(sourceWallet, false), checkWalletStatus(targetWallet, false))
+		if err != nil {
+			return err
+		}
+
+		if sourceWallet.Currency == targetWallet.Currency {
+			return entity.ErrFXSameCurrency
+		}
+
+		err = sourceWallet.Currency.ValidateAmount(req.Amount)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		rate, err := s.fxRates.Rate(ctx, sourceWallet.Currency, targetWallet.Currency)
+		if err != nil {
+			return fmt.Errorf("failed to get fx rate: %w", err)
+		}
+
+		quote, err := entity.NewFXQuote(sourceWallet, targetWallet, req.Amount, rate, s.fxQuoteTTL)
+		if err != nil {
+			return fmt.Errorf("failed to create fx quote: %w", err)
+		}
+
+		result, err = s.fxQuoteRepo.Create(ctx, quote)
+		if err != nil {
+			return fmt.Errorf("failed to create fx quote: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.FXQuote{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+func (s *Service) GetFXQuote(ctx context.Context, req *request.GetFXQuote) (result entity.FXQuote, err error) {
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		result, err = s.fxQuoteRepo.Get(ctx, req.QuoteID)
+		if err != nil {
+			return fmt.Errorf("failed to get fx quote: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.FXQuote{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// ExecuteFXQuote converts the quoted amount at the quoted rate, the source wallet is credited the source amount and the
+// target wallet is debited the target amount, both legs are completed right away and share req.TransferID.
+func (s *Service) ExecuteFXQuote(ctx context.Context, req *request.ExecuteFXQuote) (result entity.FXQuoteEvents, err error) {
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		result.FXQuote, err = s.fxQuoteRepo.GetForUpdate(ctx, req.QuoteID) // executing the same quote concurrently waits here
+		if err != nil {
+			return fmt.Errorf("failed to get fx quote: %w", err)
+		}
+
+		if result.Status == entity.FXQuoteStatusExecuted {
+			return entity.ErrFXQuoteExecuted
+		}
+
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		if result.IsExpired(now) {
+			return entity.ErrFXQuoteExpired
+		}
+
+		sourceWallet, err := s.repo.GetForShare(ctx, result.SourceWalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get source wallet: %w", err)
+		}
+
+		targetWallet, err := s.repo.GetForShare(ctx, result.TargetWalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get target wallet: %w", err)
+		}
+
+		err = errors.Join(checkWalletStatus(sourceWallet, false), checkWalletStatus(targetWallet, false))
+		if err != nil {
+			return err
+		}
+
+		projection, err := s.currentProjection(ctx, result.SourceWalletID)
+		if err != nil {
+			return err
+		}
+
+		if projection.AvailableBalance().LessThan(result.SourceAmount) {
+			return entity.ErrInsufficientBalance
+		}
+
+		err = s.checkVelocityLimits(ctx, sourceWallet, result.SourceAmount)
+		if err != nil {
+			return err
+		}
+
+		targetVersion, err := s.eventRepo.GetStreamVersion(ctx, result.TargetWalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet stream version: %w", err)
+		}
+
+		rate := result.Rate
+
+		creditEvent, err := entity.NewWalletEvent(req.TransferID, req.ReferenceID, result.SourceWalletID, result.SourceAmount, entity.EventTypeCreditTransfer, entity.TransferStatusCompleted)
+		if err != nil {
+			return fmt.Errorf("failed to create wallet event: %w", err)
+		}
+		creditEvent.FXRate = &rate
+
+		debitEvent, err := entity.NewWalletEvent(req.TransferID, req.ReferenceID, result.TargetWalletID, result.TargetAmount, entity.EventTypeDebitTransfer, entity.TransferStatusCompleted)
+		if err != nil {
+			return fmt.Errorf("failed to create wallet event: %w", err)
+		}
+		debitEvent.FXRate = &rate
+
+		result.CreditEvent, err = s.appendEvent(ctx, creditEvent, projection.LastSequence)
+		if err != nil {
+			return err
+		}
+
+		result.DebitEvent, err = s.appendEvent(ctx, debitEvent, targetVersion)
+		if err != nil {
+			return err
+		}
+
+		result.Status = entity.FXQuoteStatusExecuted
+		result.TransferID = req.TransferID
+		result.ReferenceID = req.ReferenceID
+		result.UpdatedAt = now
+
+		err = s.fxQuoteRepo.Update(ctx, result.FXQuote)
+		if err != nil {
+			return fmt.Errorf("failed to update fx quote: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.FXQuoteEvents{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// checkWalletStatus makes sure the wallet accepts transfers, allowFrozen is set for the operations that release held funds.
+func checkWalletStatus(wallet entity.Wallet, allowFrozen bool) error {
+	switch wallet.Status {
+	case entity.WalletStatusFrozen:
+		if allowFrozen {
+			return nil
+		}
+		return entity.ErrWalletFrozen
+	case entity.WalletStatusClosed:
+		return entity.ErrWalletClosed
+	default:
+		return nil
+	}
+}
+
+// checkVelocityLimits makes sure crediting amount keeps the wallet within its own limits or the ones of its tier,
+// the credits in each window are counted from the wallet events so the check is as fresh as the balance check.
+func (s *Service) checkVelocityLimits(ctx context.Context, wallet entity.Wallet, amount decimal.Decimal) error {
+	limits := s.velocityLimits.For(wallet)
+	if len(limits) == 0 {
+		return nil
+	}
+
+	var longestWindow time.Duration
+	for _, limit := range limits {
+		longestWindow = max(longestWindow, limit.Window)
+	}
+
+	now := time.Now().UTC()
+
+	events, err := s.eventRepo.ListByWalletIDSince(ctx, wallet.ID, now.Add(-longestWindow))
+	if err != nil {
+		return fmt.Errorf("failed to list wallet events: %w", err)
+	}
+
+	for _, limit := range limits {
+		usedAmount, usedCount := CreditUsage(events, now.Add(-limit.Window))
+
+		switch limit.Kind {
+		case entity.LimitKindCreditAmount:
+			if usedAmount.Add(amount).GreaterThan(limit.Max) {
+				return &entity.LimitExceededError{Limit: limit}
+			}
+		case entity.LimitKindCreditCount:
+			if decimal.NewFromInt(usedCount + 1).GreaterThan(limit.Max) {
+				return &entity.LimitExceededError{Limit: limit}
+			}
+		}
+	}
+
+	return nil
+}
+
+// captureAmount returns the amount completing the transfer captures, all of the pending amount unless a smaller one is requested.
+func captureAmount(wallet entity.Wallet, transfer entity.WalletTransfer, amount *decimal.Decimal) (decimal.Decimal, error) {
+	if amount == nil {
+		return transfer.Amount, nil
+	}
+
+	if amount.IsNegative() {
+		return decimal.Zero, entity.ErrNegativeAmount
+	}
+
+	if amount.IsZero() || amount.GreaterThan(transfer.Amount) {
+		return decimal.Zero, entity.ErrInvalidCaptureAmount
+	}
+
+	return *amount, wallet.Currency.ValidateAmount(*amount) //nolint:wrapcheck
+}
+
+// refundAmount returns the amount to refund, nil refunds whatever is left.
+func refundAmount(wallet entity.Wallet, transfer entity.WalletTransferRefunds, amount *decimal.Decimal) (decimal.Decimal, error) {
+	refundable := transfer.RefundableAmount()
+
+	if amount == nil {
+		if !refundable.IsPositive() {
+			return decimal.Zero, entity.ErrInvalidRefundAmount // already fully refunded
+		}
+		return refundable, nil
+	}
+
+	if amount.IsNegative() {
+		return decimal.Zero, entity.ErrNegativeAmount
+	}
+
+	if amount.IsZero() || amount.GreaterThan(refundable) {
+		return decimal.Zero, entity.ErrInvalidRefundAmount
+	}
+
+	return *amount, wallet.Currency.ValidateAmount(*amount) //nolint:wrapcheck
+}
+
+// validateWalletAmount makes sure the transfer currency is the wallet currency and the amount fits the currency minor units.
+func validateWalletAmount(wallet entity.Wallet, currency string, amount decimal.Decimal) error {
+	if !wallet.Currency.Matches(currency) {
+		return entity.ErrCurrencyMismatch
+	}
+
+	return wallet.Currency.ValidateAmount(amount) //nolint:wrapcheck
+}
+
+// transferFee returns the fee of crediting amount from the wallet, 0 if its schedule doesn't charge one, its currency has no fee wallet
+// or the wallet is the fee wallet.
+func (s *Service) transferFee(wallet entity.Wallet, amount decimal.Decimal) decimal.Decimal {
+	schedule, ok := s.feeSchedules.For(wallet)
+	if !ok {
+		return decimal.Zero
+	}
+
+	feeWalletID, ok := s.feeWallets.For(wallet.Currency)
+	if !ok || wallet.ID == feeWalletID {
+		return decimal.Zero
+	}
+
+	return schedule.Fee(amount, wallet.Currency)
+}
+
+// appendFee moves the fee of the credit from the wallet to the fee wallet with a linked transfer in the same status as the credit,
+// it should be called inside of a transaction right after the credit was appended.
+func (s *Service) appendFee(ctx context.Context, wallet entity.Wallet, credit entity.WalletEvent) error {
+	feeWalletID, _ := s.feeWallets.For(wallet.Currency) // the credit is only charged a fee if the currency has a fee wallet
+
+	feeWallet, err := s.repo.GetForShare(ctx, feeWalletID)
+	if err != nil {
+		return fmt.Errorf("failed to get fee wallet: %w", err)
+	}
+
+	err = checkWalletStatus(feeWallet, false)
+	if err != nil {
+		return err
+	}
+
+	if !feeWallet.Currency.Matches(string(wallet.Currency)) {
+		return fmt.Errorf("%w: %s", ErrFeeWalletCurrency, wallet.Currency)
+	}
+
+	transferID := entity.FeeTransferID(credit.TransferID)
+
+	creditEvent, err := entity.NewWalletEvent(transferID, credit.ReferenceID, credit.WalletID, credit.Fee, entity.EventTypeCreditTransfer, credit.Status)
+	if err != nil {
+		return fmt.Errorf("failed to create wallet event: %w", err)
+	}
+
+	debitEvent, err := entity.NewWalletEvent(transferID, credit.ReferenceID, feeWallet.ID, credit.Fee, entity.EventTypeDebitTransfer, credit.Status)
+	if err != nil {
+		return fmt.Errorf("failed to create wallet event: %w", err)
+	}
+
+	_, err = s.appendEvent(ctx, creditEvent, credit.Sequence)
+	if err != nil {
+		return err
+	}
+
+	feeWalletVersion, err := s.eventRepo.GetStreamVersion(ctx, feeWallet.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get wallet stream version: %w", err)
+	}
+
+	_, err = s.appendEvent(ctx, debitEvent, feeWalletVersion)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// settlesFee reports if the transfer can have a fee transfer linked to it that settleFee has to update.
+func (s *Service) settlesFee(transfer entity.WalletTransfer) bool {
+	return transfer.Direction == entity.TransferDirectionCredit && len(s.feeWallets) > 0 && !entity.IsFeeTransferID(transfer.TransferID)
+}
+
+// settleFee moves both legs of the fee transfer linked to a pending credit of the wallet to the status the credit was just settled with,
+// it should be called inside of a transaction right after the status update was appended. Only the legs still pending are updated.
+func (s *Service) settleFee(ctx context.Context, wallet entity.Wallet, transfer entity.WalletTransfer, update entity.WalletEvent) error {
+	feeWalletID, ok := s.feeWallets.For(wallet.Currency)
+	if !s.settlesFee(transfer) || !ok {
+		return nil
+	}
+
+	transferID := entity.FeeTransferID(transfer.TransferID)
+
+	for _, walletID := range []string{update.WalletID, feeWalletID} {
+		version := update.Sequence
+		if walletID != update.WalletID {
+			var err error
+
+			version, err = s.eventRepo.GetStreamVersion(ctx, walletID)
+			if err != nil {
+				return fmt.Errorf("failed to get wallet stream version: %w", err)
+			}
+		}
+
+		_, err := s.checkTransferPending(ctx, walletID, transferID)
+		if errors.Is(err, entity.ErrTransferNotFound) || errors.Is(err, entity.ErrTransferAlreadyCompleted) || errors.Is(err, entity.ErrTransferAlreadyFailed) {
+			continue // the credit wasn't charged a fee or the fee wallet changed since
+		}
+
+		if err != nil {
+			return err
+		}
+
+		event, err := entity.NewWalletEvent(transferID, update.ReferenceID, walletID, decimal.NewFromInt(0), entity.EventTypeUpdateTransferStatus, update.Status)
+		if err != nil {
+			return fmt.Errorf("failed to create wallet event: %w", err)
+		}
+
+		_, err = s.appendEvent(ctx, event, version)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkTransferPending makes sure the transfer exists in the wallet and can still be completed or reverted, it should be called inside of a transaction
+// after reading the stream version, that way a concurrent status update makes the following append fail with a version conflict.
+func (s *Service) checkTransferPending(ctx context.Context, walletID, transferID string) (entity.WalletTransfer, error) {
+	events, err := s.eventRepo.ListByTransferID(ctx, walletID, transferID)
+	if err != nil {
+		return entity.WalletTransfer{}, fmt.Errorf("failed to list transfer events: %w", err)
+	}
+
+	transfer, err := BuildTransfer(ctx, events)
+	if err != nil {
+		if errors.Is(err, entity.ErrEntityNotFound) {
+			return entity.WalletTransfer{}, entity.ErrTransferNotFound
+		}
+		return entity.WalletTransfer{}, fmt.Errorf("failed to build wallet transfer: %w", err)
+	}
+
+	switch transfer.Status {
+	case entity.TransferStatusCompleted:
+		return entity.WalletTransfer{}, entity.ErrTransferAlreadyCompleted
+	case entity.TransferStatusFailed:
+		return entity.WalletTransfer{}, entity.ErrTransferAlreadyFailed
+	default:
+		return transfer, nil
+	}
+}
+
+// refundableTransfer returns the completed transfer together with its refunds. A leg of an internal transfer or of an executed
+// fx quote can't be refunded on its own, the other wallet would keep its leg.
+func (s *Service) refundableTransfer(ctx context.Context, walletID, transferID string) (entity.WalletTransferRefunds, error) {
+	events, err := s.eventRepo.ListByTransferID(ctx, walletID, transferID)
+	if err != nil {
+		return entity.WalletTransferRefunds{}, fmt.Errorf("failed to list transfer events: %w", err)
+	}
+
+	transfer, err := BuildTransfer(ctx, events)
+	if err != nil {
+		if errors.Is(err, entity.ErrEntityNotFound) {
+			return entity.WalletTransferRefunds{}, entity.ErrTransferNotFound
+		}
+		return entity.WalletTransferRefunds{}, fmt.Errorf("failed to build wallet transfer: %w", err)
+	}
+
+	if transfer.Status != entity.TransferStatusCompleted || slices.ContainsFunc(events, isFXEvent) {
+		return entity.WalletTransferRefunds{}, entity.ErrTransferNotRefundable
+	}
+
+	internalTransfer, err := s.internalTransferRepo.Get(ctx, transferID)
+	switch {
+	case err == nil && (internalTransfer.SourceWalletID == walletID || internalTransfer.DestinationWalletID == walletID):
+		return entity.WalletTransferRefunds{}, entity.ErrTransferNotRefundable
+	case err != nil && !errors.Is(err, entity.ErrEntityNotFound):
+		return entity.WalletTransferRefunds{}, fmt.Errorf("failed to get internal transfer: %w", err)
+	}
+
+	refunds, err := s.refundRepo.ListByTransferID(ctx, walletID, transferID)
+	if err != nil {
+		return entity.WalletTransferRefunds{}, fmt.Errorf("failed to list transfer refunds: %w", err)
+	}
+
+	return entity.WalletTransferRefunds{WalletTransfer: transfer, Refunds: refunds}, nil
+}
+
+// isFXEvent reports if the event is a leg of an executed fx quote, those are linked across wallets like internal transfers.
+func isFXEvent(event entity.WalletEvent) bool {
+	return event.FXRate != nil
+}
+
+// transferExpiry validates the requested expiry of a transfer, only pending transfers can expire and the expiry has to be in the future.
+func transferExpiry(expiresAt *time.Time, status entity.TransferStatus) (*time.Time, error) {
+	if expiresAt == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	result := expiresAt.UTC().Truncate(time.Microsecond)
+	if status != entity.TransferStatusPending || !result.After(time.Now().UTC()) {
+		return nil, entity.ErrInvalidTransferExpiry
+	}
+
+	return &result, nil
+}
+
+// currentProjection rebuilds the wallet state from its event stream, it should be called inside of a transaction.
+func (s *Service) currentProjection(ctx context.Context, walletID string) (entity.WalletProjection, error) {
+	projection, _, _, err := s.walletState(ctx, walletID)
+	return projection, err
+}
+
+// walletState folds the events appended after the latest snapshot on top of it, if there is no snapshot the whole stream is replayed.
+// It returns the projection, the transfers that are still pending and the sequence of the snapshot the replay started from.
+func (s *Service) walletState(ctx context.Context, walletID string) (projection entity.WalletProjection, pending entity.PendingTransfers, snapshotSequence int64, err error) {
+	pending = entity.PendingTransfers{}
+
+	snapshot, err := s.snapshotRepo.GetLatest(ctx, walletID)
+	if err != nil && !errors.Is(err, entity.ErrEntityNotFound) {
+		return entity.WalletProjection{}, nil, 0, fmt.Errorf("failed to get wallet snapshot: %w", err)
+	}
+
+	if err == nil {
+		projection = snapshot.Projection()
+		pending = snapshot.PendingTransfers
+		snapshotSequence = snapshot.LastSequence
+	}
+
+	events, err := s.eventRepo.ListByWalletIDAfter(ctx, walletID, snapshotSequence)
+	if err != nil {
+		return entity.WalletProjection{}, nil, 0, fmt.Errorf("failed to list wallet events: %w", err)
+	}
+
+	err = ApplyEvents(ctx, &projection, pending, events)
+	if err != nil {
+		return entity.WalletProjection{}, nil, 0, fmt.Errorf("failed to process wallet events: %w", err)
+	}
+
+	return projection, pending, snapshotSequence, nil
+}
+
+// nestedAppendKey marks a context whose appends run inside of an outer runAppend, e.g. an atomic batch.
+type nestedAppendKey struct{}
+
+// runAppend runs fn in a transaction, retrying it from scratch when an append lost the race for the wallet event stream.
+// Nested calls join the outer transaction which a conflict aborts, so only the outermost call retries.
+func (s *Service) runAppend(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	attempts := s.maxAppendAttempts
+	if nested, _ := ctx.Value(nestedAppendKey{}).(bool); nested {
+		attempts = 1
+	}
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = s.txm.Run(ctx, fn)
+		if !errors.Is(err, entity.ErrStreamVersionConflict) {
+			return err //nolint:wrapcheck
+		}
+
+		sloglog.FromContext(ctx).WarnContext(ctx, "wallet event stream version conflict", slog.Int("attempt", attempt))
+	}
+
+	return err
+}
+
+// appendEvent stores the event at expectedVersion+1 and publishes it, it should be called inside of a transaction.
+func (s *Service) appendEvent(ctx context.Context, event entity.WalletEvent, expectedVersion int64) (entity.WalletEvent, error) {
+	result, err := s.eventRepo.Create(ctx, event, expectedVersion)
+	if err != nil {
+		return entity.WalletEvent{}, fmt.Errorf("failed to create wallet event: %w", err)
+	}
+
+	err = s.publisher.PublishCreated(ctx, result)
+	if err != nil {
+		return entity.WalletEvent{}, fmt.Errorf("failed to publish wallet event: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *Service) RebuildWalletProjection(ctx context.Context, event *entity.WalletEvent) (result entity.WalletProjection, err error) {
+	logger := sloglog.FromContext(ctx)
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		projection, err := s.projectionRepo.Get(ctx, event.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet projection: %w", err)
+		}
+
+		// the stream sequence is assigned at append time, so unlike the event id it can't go out of order when an append is retried,
+		// events published before the sequence existed have none and always trigger a rebuild
+		if event.Sequence > 0 && projection.LastSequence >= event.Sequence {
+			logger.InfoContext(ctx, "no new events to process skipping rebuild") // if we need to do a full rebuild for some reason and there are no new events a different function should be used
+			return nil
+		}
+
+		var (
+			pending          entity.PendingTransfers
+			snapshotSequence int64
+		)
+
+		result, pending, snapshotSequence, err = s.walletState(ctx, event.WalletID)
+		if err != nil {
+			return err
+		}
+
+		result.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+		_, err = s.projectionRepo.Update(ctx, result)
+		if err != nil {
+			return fmt.Errorf("failed to update wallet projection: %w", err)
+		}
+
+		if s.snapshotInterval > 0 && result.LastSequence-snapshotSequence >= s.snapshotInterval {
+			_, err = s.snapshotRepo.Create(ctx, entity.NewWalletSnapshot(result, pending))
+			if err != nil {
+				return fmt.Errorf("failed to create wallet snapshot: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.WalletProjection{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// ReconcileProjections replays the whole event stream of every wallet in the page, the snapshots are skipped so a broken snapshot is caught too,
+// and compares the result with the stored projection. A projection that is only behind events created after opts.SettledBefore is left to the
+// worker still processing them. A failure to check one wallet doesn't stop the others, the errors are joined and returned after the whole page was checked.
+func (s *Service) ReconcileProjections(ctx context.Context, opts entity.ReconcileOptions) (result entity.ReconcileResult, err error) {
+	var walletIDs []string
+
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		walletIDs, err = s.repo.ListIDs(ctx, opts.AfterWalletID, opts.Limit)
+		if err != nil {
+			return fmt.Errorf("failed to list wallet ids: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ReconcileResult{}, err //nolint:wrapcheck
+	}
+
+	result.Mismatches = []entity.ProjectionMismatch{}
+
+	var errs []error
+
+	for _, walletID := range walletIDs {
+		mismatch, err := s.reconcileProjection(ctx, walletID, opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to reconcile wallet %s: %w", walletID, err))
+			continue
+		}
+
+		result.Checked++
+
+		if len(mismatch.Fields) > 0 {
+			result.Mismatches = append(result.Mismatches, mismatch)
+		}
+	}
+
+	if len(walletIDs) > 0 && uint64(len(walletIDs)) == opts.Limit {
+		result.LastWalletID = walletIDs[len(walletIDs)-1]
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// reconcileProjection checks a single wallet, the returned mismatch has no fields if the stored projection is up to date.
+// When repairing the projection is locked first, so a concurrent rebuild can't be overwritten with an older state.
+func (s *Service) reconcileProjection(ctx context.Context, walletID string, opts entity.ReconcileOptions) (result entity.ProjectionMismatch, err error) {
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		getProjection := s.projectionRepo.Get
+		if opts.Repair {
+			getProjection = s.projectionRepo.GetForUpdate
+		}
+
+		stored, err := getProjection(ctx, walletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet projection: %w", err)
+		}
+
+		events, err := s.eventRepo.ListByWalletID(ctx, walletID)
+		if err != nil {
+			return fmt.Errorf("failed to list wallet events: %w", err)
+		}
+
+		replayed := entity.NewWalletProjection(walletID, walletID, decimal.NewFromInt(0), decimal.NewFromInt(0), decimal.NewFromInt(0)) // the state of a new wallet
+
+		err = ProcessEvents(ctx, &replayed, events)
+		if err != nil {
+			return fmt.Errorf("failed to process wallet events: %w", err)
+		}
+
+		result = entity.ProjectionMismatch{
+			WalletID: walletID,
+			Fields:   entity.DiffProjections(stored, replayed),
+			Stored:   stored,
+			Replayed: replayed,
+		}
+
+		if len(result.Fields) == 0 || isProcessingBehind(stored, events, opts.SettledBefore) {
+			result.Fields = nil
+			return nil
+		}
+
+		if !opts.Repair {
+			return nil
+		}
+
+		replayed.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+		result.Replayed, err = s.projectionRepo.Update(ctx, replayed)
+		if err != nil {
+			return fmt.Errorf("failed to update wallet projection: %w", err)
+		}
+
+		result.Repaired = true
+
+		return nil
+	})
+	if err != nil {
+		return entity.ProjectionMismatch{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// isProcessingBehind reports if the stored projection is only missing events created after settledBefore,
+// the projection is rebuilt asynchronously so those are most likely still on their way to the worker.
+func isProcessingBehind(stored entity.WalletProjection, events []entity.WalletEvent, settledBefore time.Time) bool {
+	for _, event := range events {
+		if event.Sequence <= stored.LastSequence {
+			continue
+		}
+
+		return event.CreatedAt.After(settledBefore) // the oldest missing event decides
+	}
+
+	return false
+}
+
+// ExpireTransfers reverts the pending transfers that expired at or before now, the candidates come from the transfer read model,
+// but each of them is checked against its events before the revert is written, so a transfer settled in the meantime is skipped.
+// A failure to expire one transfer doesn't stop the others, the errors are joined and returned after the whole batch was processed.
+func (s *Service) ExpireTransfers(ctx context.Context, now time.Time, limit uint64) (result []entity.WalletEvent, err error) {
+	logger := sloglog.FromContext(ctx)
+
+	var transfers []entity.WalletTransfer
+
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		transfers, err = s.transferRepo.ListExpired(ctx, now, limit)
+		if err != nil {
+			return fmt.Errorf("failed to list expired transfers: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	result = make([]entity.WalletEvent, 0, len(transfers))
+
+	var errs []error
+
+	for _, transfer := range transfers {
+		event, err := s.expireTransfer(ctx, transfer)
+		if err != nil {
+			if errors.Is(err, entity.ErrTransferAlreadyCompleted) || errors.Is(err, entity.ErrTransferAlreadyFailed) {
+				logger.InfoContext(ctx, "transfer settled before it expired", slog.String("wallet_id", transfer.WalletID), slog.String("transfer_id", transfer.TransferID))
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("failed to expire transfer %s: %w", transfer.TransferID, err))
+			continue
+		}
+
+		result = append(result, event)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// expireTransfer writes the failed status for a single expired transfer, the fee charged on an expired credit is reverted with it.
+func (s *Service) expireTransfer(ctx context.Context, transfer entity.WalletTransfer) (result entity.WalletEvent, err error) {
+	event, err := entity.NewWalletEvent(transfer.TransferID, transfer.ReferenceID, transfer.WalletID, decimal.NewFromInt(0), entity.EventTypeUpdateTransferStatus, entity.TransferStatusFailed)
+	if err != nil {
+		return result, fmt.Errorf("failed to create wallet event: %w", err)
+	}
+
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		version, err := s.eventRepo.GetStreamVersion(ctx, transfer.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet stream version: %w", err)
+		}
+
+		pending, err := s.checkTransferPending(ctx, transfer.WalletID, transfer.TransferID)
+		if err != nil {
+			return err
+		}
+
+		result, err = s.appendEvent(ctx, event, version)
+		if err != nil {
+			return err
+		}
+
+		if !s.settlesFee(pending) {
+			return nil
+		}
+
+		wallet, err := s.repo.Get(ctx, transfer.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		return s.settleFee(ctx, wallet, pending, result)
+	})
+	if err != nil {
+		return entity.WalletEvent{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// ExecuteScheduledTransfers executes the scheduled transfers due at or before now. A transfer the wallet rejects, e.g. because of an
+// insufficient balance, is marked as failed with the reason, other errors leave it scheduled so the next run retries it.
+// A failure to execute one transfer doesn't stop the others, the errors are joined and returned after the whole batch was processed.
+func (s *Service) ExecuteScheduledTransfers(ctx context.Context, now time.Time, limit uint64) (result []entity.ScheduledTransfer, err error) {
+	logger := sloglog.FromContext(ctx)
+
+	var transfers []entity.ScheduledTransfer
+
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		transfers, err = s.scheduledRepo.ListDue(ctx, now, limit)
+		if err != nil {
+			return fmt.Errorf("failed to list due scheduled transfers: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	result = make([]entity.ScheduledTransfer, 0, len(transfers))
+
+	var errs []error
+
+	for _, transfer := range transfers {
+		executed, err := s.executeScheduledTransfer(ctx, transfer)
+		if err != nil && isTransferRejection(err) {
+			executed, err = s.failScheduledTransfer(ctx, transfer, err)
+		}
+
+		if err != nil {
+			if errors.Is(err, entity.ErrTransferNotScheduled) {
+				logger.InfoContext(ctx, "scheduled transfer settled before it was executed", slog.String("wallet_id", transfer.WalletID), slog.String("transfer_id", transfer.TransferID))
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("failed to execute scheduled transfer %s: %w", transfer.TransferID, err))
+			continue
+		}
+
+		result = append(result, executed)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// executeScheduledTransfer writes the debit or credit event of the scheduled transfer and marks it executed in the same transaction.
+// It returns entity.ErrTransferNotScheduled if the transfer was cancelled or executed in the meantime.
+func (s *Service) executeScheduledTransfer(ctx context.Context, transfer entity.ScheduledTransfer) (result entity.ScheduledTransfer, err error) {
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		result, err = s.scheduledRepo.GetForUpdate(ctx, transfer.WalletID, transfer.TransferID)
+		if err != nil {
+			return fmt.Errorf("failed to get scheduled transfer: %w", err)
+		}
+
+		if result.Status != entity.ScheduleStatusScheduled {
+			return entity.ErrTransferNotScheduled
+		}
+
+		err = s.checkTransferUnused(ctx, result.WalletID, result.TransferID)
+		if err != nil {
+			return err
+		}
+
+		event, err := s.nestedTransfer(ctx, result.Direction, request.DebitTransfer{
+			WalletID:    result.WalletID,
+			ReferenceID: result.ReferenceID,
+			TransferID:  result.TransferID,
+			Amount:      result.Amount,
+			Currency:    result.Currency,
+			Status:      result.TransferStatus,
+			ExpiresAt:   result.ExpiresAt,
+			Metadata:    result.Metadata,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Settle(entity.ScheduleStatusExecuted, &event.ID, "", time.Now())
+
+		err = s.scheduledRepo.Update(ctx, result)
+		if err != nil {
+			return fmt.Errorf("failed to update scheduled transfer: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ScheduledTransfer{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// failScheduledTransfer marks the scheduled transfer as failed with the reason the wallet rejected it.
+func (s *Service) failScheduledTransfer(ctx context.Context, transfer entity.ScheduledTransfer, reason error) (result entity.ScheduledTransfer, err error) {
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		result, err = s.scheduledRepo.GetForUpdate(ctx, transfer.WalletID, transfer.TransferID)
+		if err != nil {
+			return fmt.Errorf("failed to get scheduled transfer: %w", err)
+		}
+
+		if result.Status != entity.ScheduleStatusScheduled {
+			return entity.ErrTransferNotScheduled
+		}
+
+		result.Settle(entity.ScheduleStatusFailed, nil, reason.Error(), time.Now())
+
+		err = s.scheduledRepo.Update(ctx, result)
+		if err != nil {
+			return fmt.Errorf("failed to update scheduled transfer: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ScheduledTransfer{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// ExecuteRecurringTransfers writes the due occurrence of the active recurring transfers and moves them on to their next occurrence.
+// An occurrence the wallet rejects, e.g. because of an insufficient balance, is skipped and the reason is kept on the recurring transfer,
+// other errors leave it due so the next run retries it. Occurrences missed while the worker wasn't running are caught up one at a time.
+func (s *Service) ExecuteRecurringTransfers(ctx context.Context, now time.Time, limit uint64) (result []entity.RecurringTransfer, err error) {
+	var transfers []entity.RecurringTransfer
+
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		transfers, err = s.recurringRepo.ListDue(ctx, now, limit)
+		if err != nil {
+			return fmt.Errorf("failed to list due recurring transfers: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	result = make([]entity.RecurringTransfer, 0, len(transfers))
+
+	var errs []error
+
+	for _, transfer := range transfers {
+		advanced, err := s.executeRecurringTransfer(ctx, transfer, now)
+		if err != nil && isTransferRejection(err) {
+			advanced, err = s.skipRecurringOccurrence(ctx, transfer, now, err)
+		}
+
+		if err != nil {
+			if errors.Is(err, errOccurrenceHandled) {
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("failed to execute recurring transfer %s: %w", transfer.ID, err))
+			continue
+		}
+
+		result = append(result, advanced)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// executeRecurringTransfer writes the occurrence of the recurring transfer and advances it in the same transaction.
+// An occurrence whose transfer id was already used isn't written again.
+func (s *Service) executeRecurringTransfer(ctx context.Context, transfer entity.RecurringTransfer, now time.Time) (result entity.RecurringTransfer, err error) {
+	err = s.runAppend(ctx, func(ctx context.Context) error {
+		result, err = s.lockDueOccurrence(ctx, transfer)
+		if err != nil {
+			return err
+		}
+
+		transferID := entity.RecurringTransferID(result.ID, *result.NextRunAt)
+
+		err = s.checkTransferUnused(ctx, result.WalletID, transferID)
+		switch {
+		case err == nil:
+			_, err = s.nestedTransfer(ctx, result.Direction, request.DebitTransfer{
+				WalletID:    result.WalletID,
+				ReferenceID: result.ReferenceID,
+				TransferID:  transferID,
+				Amount:      result.Amount,
+				Currency:    result.Currency,
+				Status:      entity.TransferStatusCompleted,
+			})
+			if err != nil {
+				return err
+			}
+		case errors.Is(err, entity.ErrTransferAlreadyExists):
+			sloglog.FromContext(ctx).InfoContext(ctx, "recurring transfer occurrence already written", slog.String("wallet_id", result.WalletID), slog.String("transfer_id", transferID))
+		default:
+			return err
+		}
+
+		result.LastTransferID = transferID
+		result.LastFailureReason = ""
+
+		return s.advanceRecurringTransfer(ctx, &result, now)
+	})
+	if err != nil {
+		return entity.RecurringTransfer{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// skipRecurringOccurrence moves the recurring transfer past the occurrence the wallet rejected, keeping the reason.
+func (s *Service) skipRecurringOccurrence(ctx context.Context, transfer entity.RecurringTransfer, now time.Time, reason error) (result entity.RecurringTransfer, err error) {
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		result, err = s.lockDueOccurrence(ctx, transfer)
+		if err != nil {
+			return err
+		}
+
+		result.LastFailureReason = reason.Error()
+
+		return s.advanceRecurringTransfer(ctx, &result, now)
+	})
+	if err != nil {
+		return entity.RecurringTransfer{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// lockDueOccurrence locks the recurring transfer and makes sure it's still active and due at the occurrence it was listed with.
+func (s *Service) lockDueOccurrence(ctx context.Context, transfer entity.RecurringTransfer) (entity.RecurringTransfer, error) {
+	result, err := s.recurringRepo.GetForUpdate(ctx, transfer.WalletID, transfer.ID)
+	if errors.Is(err, entity.ErrEntityNotFound) {
+		return entity.RecurringTransfer{}, errOccurrenceHandled // deleted in the meantime
+	}
+	if err != nil {
+		return entity.RecurringTransfer{}, fmt.Errorf("failed to get recurring transfer: %w", err)
+	}
+
+	if result.Status != entity.RecurringStatusActive || result.NextRunAt == nil || transfer.NextRunAt == nil || !result.NextRunAt.Equal(*transfer.NextRunAt) {
+		return entity.RecurringTransfer{}, errOccurrenceHandled
+	}
+
+	return result, nil
+}
+
+func (s *Service) advanceRecurringTransfer(ctx context.Context, transfer *entity.RecurringTransfer, now time.Time) error {
+	err := transfer.Advance(now)
+	if err != nil {
+		return fmt.Errorf("failed to advance recurring transfer: %w", err)
+	}
+
+	err = s.recurringRepo.Update(ctx, *transfer)
+	if err != nil {
+		return fmt.Errorf("failed to update recurring transfer: %w", err)
+	}
+
+	return nil
+}
+
+// nestedTransfer writes req as a debit or credit depending on the direction, joining the runAppend of the caller.
+func (s *Service) nestedTransfer(ctx context.Context, direction entity.TransferDirection, req request.DebitTransfer) (entity.WalletEvent, error) {
+	ctx = context.WithValue(ctx, nestedAppendKey{}, true)
+
+	switch direction {
+	case entity.TransferDirectionDebit:
+		return s.DebitTransfer(ctx, &req)
+	case entity.TransferDirectionCredit:
+		credit := request.CreditTransfer(req)
+		return s.CreditTransfer(ctx, &credit)
+	default:
+		return entity.WalletEvent{}, fmt.Errorf("unknown transfer direction %s", direction)
+	}
+}
+
+// isTransferRejection reports whether writing a scheduled or recurring transfer failed for a reason retrying won't fix.
+func isTransferRejection(err error) bool {
+	for _, target := range []error{
+		entity.ErrEntityNotFound,
+		entity.ErrInsufficientBalance,
+		entity.ErrNegativeAmount,
+		entity.ErrWalletFrozen,
+		entity.ErrWalletClosed,
+		entity.ErrCurrencyMismatch,
+		entity.ErrInvalidAmountScale,
+		entity.ErrLimitExceeded,
+		entity.ErrInvalidTransferExpiry,
+		entity.ErrTransferAlreadyExists,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RebuildTransferProjection rebuilds the state of the transfer the event belongs to from the transfer events.
+func (s *Service) RebuildTransferProjection(ctx context.Context, event *entity.WalletEvent) (result entity.WalletTransfer, err error) {
+	if event.EventType == entity.EventTypeCreditLimitChanged {
+		return entity.WalletTransfer{}, nil // not part of a transfer
+	}
+
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		events, err := s.eventRepo.ListByTransferID(ctx, event.WalletID, event.TransferID)
+		if err != nil {
+			return fmt.Errorf("failed to list transfer events: %w", err)
+		}
+
+		result, err = BuildTransfer(ctx, events)
+		if err != nil {
+			return fmt.Errorf("failed to build wallet transfer: %w", err)
+		}
+
+		err = s.transferRepo.Upsert(ctx, result)
+		if err != nil {
+			return fmt.Errorf("failed to upsert wallet transfer: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.WalletTransfer{}, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// PostLedgerEntries stores the journal entries of the event, the postings depend on the state of the transfer before the event
+// which is rebuilt from the transfer events preceding it, so posting an event again gives the same postings.
+func (s *Service) PostLedgerEntries(ctx context.Context, event *entity.WalletEvent) (result []entity.LedgerPosting, err error) {
+	if event.EventType == entity.EventTypeCreditLimitChanged {
+		return nil, nil // doesn't move money
+	}
+
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		wallet, err := s.repo.Get(ctx, event.WalletID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		events, err := s.eventRepo.ListByTransferID(ctx, event.WalletID, event.TransferID)
+		if err != nil {
+			return fmt.Errorf("failed to list transfer events: %w", err)
+		}
+
+		var transfer *entity.WalletTransfer
+
+		preceding := slices.DeleteFunc(events, func(e entity.WalletEvent) bool { return e.Sequence >= event.Sequence })
+		built, err := BuildTransfer(ctx, preceding)
+		switch {
+		case err == nil:
+			transfer = &built
+		case !errors.Is(err, entity.ErrEntityNotFound):
+			return fmt.Errorf("failed to build wallet transfer: %w", err)
+		}
+
+		result = LedgerPostings(*event, transfer, wallet.Currency)
+
+		err = s.ledgerRepo.CreatePostings(ctx, result)
+		if err != nil {
+			return fmt.Errorf("failed to create ledger postings: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return result, nil
+}
+
+// TrialBalance sums up the ledger postings per account, the books balance if the postings of every currency sum up to 0.
+func (s *Service) TrialBalance(ctx context.Context) (result entity.TrialBalance, err error) {
+	err = s.txm.Run(ctx, func(ctx context.Context) error {
+		accounts, err := s.ledgerRepo.AccountBalances(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get ledger account balances: %w", err)
+		}
+
+		result = entity.NewTrialBalance(accounts)
+
+		return nil
+	})
+	if err != nil {
+		return entity.TrialBalance{}, err //nolint:wrapcheck
 	}
 
 	return result, nil
@@ -2,7 +2,9 @@ package wallet_test
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	contract_mock "github.com/buni/wallet/internal/api/app/contract/mock"
 	"github.com/buni/wallet/internal/api/app/entity"
@@ -17,12 +19,20 @@ import (
 
 type WalletServiceTestSuite struct {
 	suite.Suite
-	ctrl               *gomock.Controller
-	repoMock           *contract_mock.MockWalletRepository
-	eventRepoMock      *contract_mock.MockWalletEventRepository
-	projectionRepoMock *contract_mock.MockWalletProjectionRepository
-	publisherMock      *contract_mock.MockWalletEventPublisher
-	svc                *wallet.Service
+	ctrl                     *gomock.Controller
+	repoMock                 *contract_mock.MockWalletRepository
+	eventRepoMock            *contract_mock.MockWalletEventRepository
+	projectionRepoMock       *contract_mock.MockWalletProjectionRepository
+	snapshotRepoMock         *contract_mock.MockWalletSnapshotRepository
+	transferRepoMock         *contract_mock.MockWalletTransferRepository
+	internalTransferRepoMock *contract_mock.MockInternalTransferRepository
+	ledgerRepoMock           *contract_mock.MockLedgerRepository
+	scheduledRepoMock        *contract_mock.MockScheduledTransferRepository
+	recurringRepoMock        *contract_mock.MockRecurringTransferRepository
+	refundRepoMock           *contract_mock.MockRefundRepository
+	fxQuoteRepoMock          *contract_mock.MockFXQuoteRepository
+	publisherMock            *contract_mock.MockWalletEventPublisher
+	svc                      *wallet.Service
 }
 
 func (s *WalletServiceTestSuite) SetupTest() {
@@ -30,21 +40,53 @@ func (s *WalletServiceTestSuite) SetupTest() {
 	s.repoMock = contract_mock.NewMockWalletRepository(s.ctrl)
 	s.eventRepoMock = contract_mock.NewMockWalletEventRepository(s.ctrl)
 	s.projectionRepoMock = contract_mock.NewMockWalletProjectionRepository(s.ctrl)
+	s.snapshotRepoMock = contract_mock.NewMockWalletSnapshotRepository(s.ctrl)
+	s.transferRepoMock = contract_mock.NewMockWalletTransferRepository(s.ctrl)
+	s.internalTransferRepoMock = contract_mock.NewMockInternalTransferRepository(s.ctrl)
+	s.ledgerRepoMock = contract_mock.NewMockLedgerRepository(s.ctrl)
+	s.scheduledRepoMock = contract_mock.NewMockScheduledTransferRepository(s.ctrl)
+	s.recurringRepoMock = contract_mock.NewMockRecurringTransferRepository(s.ctrl)
+	s.refundRepoMock = contract_mock.NewMockRefundRepository(s.ctrl)
+	s.fxQuoteRepoMock = contract_mock.NewMockFXQuoteRepository(s.ctrl)
 	s.publisherMock = contract_mock.NewMockWalletEventPublisher(s.ctrl)
-	s.svc = wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.publisherMock, testutils.NoopTransactionManager{})
+	svc, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{},
+		wallet.WithScheduledTransfers(s.scheduledRepoMock),
+		wallet.WithRecurringTransfers(s.recurringRepoMock),
+		wallet.WithFXQuotes(s.fxQuoteRepoMock),
+	)
+	s.Require().NoError(err)
+	s.svc = svc
 }
 
 func (s *WalletServiceTestSuite) TearDownTest() {
 	s.ctrl.Finish()
 }
 
+func (s *WalletServiceTestSuite) pendingTransferEvents(walletID, transferID string) []entity.WalletEvent {
+	return []entity.WalletEvent{
+		{
+			Version:    entity.WalletEventVersionOne,
+			TransferID: transferID,
+			WalletID:   walletID,
+			Amount:     decimal.NewFromInt(100),
+			EventType:  entity.EventTypeDebitTransfer,
+			Status:     entity.TransferStatusPending,
+			Sequence:   1,
+		},
+	}
+}
+
 func (s *WalletServiceTestSuite) TestCreateSuccess() {
 	req := &request.CreateWallet{
 		ReferenceID: "ref-id",
+		Currency:    "EUR",
 	}
 
 	wallet := entity.Wallet{
 		ReferenceID: req.ReferenceID,
+		Currency:    "EUR",
+		Status:      entity.WalletStatusActive,
+		Tier:        entity.WalletTierStandard,
 	}
 
 	s.repoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(wallet, cmpopts.IgnoreFields(entity.Wallet{}, "ID", "CreatedAt", "UpdatedAt"))).Return(entity.Wallet{
@@ -57,13 +99,40 @@ func (s *WalletServiceTestSuite) TestCreateSuccess() {
 	s.NotEmpty(wallet)
 }
 
+func (s *WalletServiceTestSuite) TestCreateWithMetadata() {
+	req := &request.CreateWallet{
+		ReferenceID: "ref-id",
+		Currency:    "EUR",
+		Metadata:    entity.Metadata{"customer_id": "42"},
+	}
+
+	wallet := entity.Wallet{
+		ReferenceID: req.ReferenceID,
+		Currency:    "EUR",
+		Status:      entity.WalletStatusActive,
+		Tier:        entity.WalletTierStandard,
+		Metadata:    req.Metadata,
+	}
+
+	s.repoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(wallet, cmpopts.IgnoreFields(entity.Wallet{}, "ID", "CreatedAt", "UpdatedAt"))).Return(wallet, nil)
+	s.projectionRepoMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.WalletProjection{}, nil)
+
+	result, err := s.svc.Create(context.Background(), req)
+	s.NoError(err)
+	s.Equal(req.Metadata, result.Metadata)
+}
+
 func (s *WalletServiceTestSuite) TestCreateCreateWalletError() {
 	req := &request.CreateWallet{
 		ReferenceID: "ref-id",
+		Currency:    "EUR",
 	}
 
 	wallet := entity.Wallet{
 		ReferenceID: req.ReferenceID,
+		Currency:    "EUR",
+		Status:      entity.WalletStatusActive,
+		Tier:        entity.WalletTierStandard,
 	}
 
 	s.repoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(wallet, cmpopts.IgnoreFields(entity.Wallet{}, "ID", "CreatedAt", "UpdatedAt"))).Return(entity.Wallet{}, context.DeadlineExceeded)
@@ -76,10 +145,14 @@ func (s *WalletServiceTestSuite) TestCreateCreateWalletError() {
 func (s *WalletServiceTestSuite) TestCreateProjectionError() {
 	req := &request.CreateWallet{
 		ReferenceID: "ref-id",
+		Currency:    "EUR",
 	}
 
 	wallet := entity.Wallet{
 		ReferenceID: req.ReferenceID,
+		Currency:    "EUR",
+		Status:      entity.WalletStatusActive,
+		Tier:        entity.WalletTierStandard,
 	}
 
 	s.repoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(wallet, cmpopts.IgnoreFields(entity.Wallet{}, "ID", "CreatedAt", "UpdatedAt"))).Return(entity.Wallet{
@@ -91,6 +164,33 @@ func (s *WalletServiceTestSuite) TestCreateProjectionError() {
 	s.ErrorIs(err, context.DeadlineExceeded)
 }
 
+func (s *WalletServiceTestSuite) TestCreateUnsupportedCurrency() {
+	for _, currency := range []string{"ABC", "XXX", "EURO"} {
+		wallet, err := s.svc.Create(context.Background(), &request.CreateWallet{
+			ReferenceID: "ref-id",
+			Currency:    currency,
+		})
+		s.ErrorIs(err, entity.ErrUnsupportedCurrency, currency)
+		s.Empty(wallet)
+	}
+}
+
+func (s *WalletServiceTestSuite) TestCreateNormalizesCurrency() {
+	wallet := entity.Wallet{
+		ReferenceID: "ref-id",
+		Currency:    "BHD",
+		Status:      entity.WalletStatusActive,
+		Tier:        entity.WalletTierStandard,
+	}
+
+	s.repoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(wallet, cmpopts.IgnoreFields(entity.Wallet{}, "ID", "CreatedAt", "UpdatedAt"))).Return(wallet, nil)
+	s.projectionRepoMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.WalletProjection{}, nil)
+
+	result, err := s.svc.Create(context.Background(), &request.CreateWallet{ReferenceID: "ref-id", Currency: "bhd"})
+	s.NoError(err)
+	s.Equal(wallet, result)
+}
+
 func (s *WalletServiceTestSuite) TestGetSuccess() {
 	req := &request.GetWallet{
 		WalletID: "wallet-id",
@@ -146,6 +246,124 @@ func (s *WalletServiceTestSuite) TestGetProjectionError() {
 	s.Empty(result)
 }
 
+func (s *WalletServiceTestSuite) TestGetByReferenceIDSuccess() {
+	req := &request.GetWalletByReference{
+		ReferenceID: "ref-id",
+	}
+
+	wallet := entity.Wallet{
+		ID:          "wallet-id",
+		ReferenceID: req.ReferenceID,
+	}
+
+	projection := entity.WalletProjection{
+		WalletID: wallet.ID,
+	}
+
+	s.repoMock.EXPECT().GetByReferenceID(gomock.Any(), req.ReferenceID).Return(wallet, nil)
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), wallet.ID).Return(projection, nil)
+
+	result, err := s.svc.GetByReferenceID(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.WalletBalanceProjection{
+		Wallet:           wallet,
+		WalletProjection: projection,
+	}, result)
+}
+
+func (s *WalletServiceTestSuite) TestGetByReferenceIDNotFound() {
+	req := &request.GetWalletByReference{
+		ReferenceID: "ref-id",
+	}
+
+	s.repoMock.EXPECT().GetByReferenceID(gomock.Any(), req.ReferenceID).Return(entity.Wallet{}, entity.ErrEntityNotFound)
+
+	result, err := s.svc.GetByReferenceID(context.Background(), req)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestListNextPage() {
+	minBalance := decimal.NewFromInt(10)
+	req := &request.ListWallets{
+		Cursor:            "cursor-id",
+		Limit:             2,
+		ReferenceIDPrefix: "ref",
+		MinBalance:        &minBalance,
+	}
+
+	wallets := []entity.Wallet{
+		{ID: "3", ReferenceID: "ref3"},
+		{ID: "2", ReferenceID: "ref2"},
+		{ID: "1", ReferenceID: "ref1"},
+	}
+
+	projections := []entity.WalletProjection{ // not in the order of the wallets
+		{WalletID: "2", Balance: decimal.NewFromInt(20)},
+		{WalletID: "3", Balance: decimal.NewFromInt(30)},
+	}
+
+	s.repoMock.EXPECT().List(gomock.Any(), entity.WalletFilter{
+		Cursor:            req.Cursor,
+		Limit:             3,
+		ReferenceIDPrefix: req.ReferenceIDPrefix,
+		MinBalance:        req.MinBalance,
+	}).Return(wallets, nil)
+	s.projectionRepoMock.EXPECT().ListByWalletIDs(gomock.Any(), []string{"3", "2"}).Return(projections, nil)
+
+	result, err := s.svc.List(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.WalletPage{
+		Wallets: []entity.WalletBalanceProjection{
+			{Wallet: wallets[0], WalletProjection: projections[1]},
+			{Wallet: wallets[1], WalletProjection: projections[0]},
+		},
+		NextCursor: "2",
+	}, result)
+}
+
+func (s *WalletServiceTestSuite) TestListLastPage() {
+	req := &request.ListWallets{
+		ReferenceID: "ref1",
+	}
+
+	wallets := []entity.Wallet{{ID: "1", ReferenceID: "ref1"}}
+	projections := []entity.WalletProjection{{WalletID: "1"}}
+
+	s.repoMock.EXPECT().List(gomock.Any(), entity.WalletFilter{
+		Limit:       51, // default page size + 1
+		ReferenceID: req.ReferenceID,
+	}).Return(wallets, nil)
+	s.projectionRepoMock.EXPECT().ListByWalletIDs(gomock.Any(), []string{"1"}).Return(projections, nil)
+
+	result, err := s.svc.List(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.WalletPage{
+		Wallets: []entity.WalletBalanceProjection{{Wallet: wallets[0], WalletProjection: projections[0]}},
+	}, result)
+}
+
+func (s *WalletServiceTestSuite) TestListMissingProjection() {
+	req := &request.ListWallets{}
+
+	s.repoMock.EXPECT().List(gomock.Any(), gomock.Any()).Return([]entity.Wallet{{ID: "1"}}, nil)
+	s.projectionRepoMock.EXPECT().ListByWalletIDs(gomock.Any(), []string{"1"}).Return([]entity.WalletProjection{}, nil)
+
+	result, err := s.svc.List(context.Background(), req)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestListError() {
+	req := &request.ListWallets{}
+
+	s.repoMock.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, context.DeadlineExceeded)
+
+	result, err := s.svc.List(context.Background(), req)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Empty(result)
+}
+
 func (s *WalletServiceTestSuite) TestDebitTransferSuccess() {
 	req := &request.DebitTransfer{
 		WalletID:    "wallet-id",
@@ -153,6 +371,7 @@ func (s *WalletServiceTestSuite) TestDebitTransferSuccess() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
 	event := entity.WalletEvent{
@@ -165,10 +384,12 @@ func (s *WalletServiceTestSuite) TestDebitTransferSuccess() {
 		Status:      req.Status,
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
 	}, nil)
-	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(event, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(4)).Return(event, nil)
 	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(nil)
 
 	result, err := s.svc.DebitTransfer(context.Background(), req)
@@ -176,6 +397,98 @@ func (s *WalletServiceTestSuite) TestDebitTransferSuccess() {
 	s.Equal(event, result)
 }
 
+func (s *WalletServiceTestSuite) TestDebitTransferWithMetadata() {
+	req := &request.DebitTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     decimal.NewFromInt(100),
+		Status:     entity.TransferStatusCompleted,
+		Currency:   "EUR",
+		Metadata:   entity.Metadata{"order_id": "1"},
+	}
+
+	event := entity.WalletEvent{
+		Version:    entity.WalletEventVersionOne,
+		TransferID: req.TransferID,
+		WalletID:   req.WalletID,
+		Amount:     req.Amount,
+		EventType:  entity.EventTypeDebitTransfer,
+		Status:     req.Status,
+		Metadata:   req.Metadata,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(4)).Return(event, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), event).Return(nil) // the metadata is part of the published payload
+
+	result, err := s.svc.DebitTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(event, result)
+}
+
+func (s *WalletServiceTestSuite) TestDebitTransferWithExpiry() {
+	expiresAt := time.Now().Add(time.Hour).In(time.FixedZone("CET", 3600))
+	wantExpiresAt := expiresAt.UTC().Truncate(time.Microsecond)
+
+	req := &request.DebitTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     decimal.NewFromInt(100),
+		Status:     entity.TransferStatusPending,
+		Currency:   "EUR",
+		ExpiresAt:  &expiresAt,
+	}
+
+	event := entity.WalletEvent{
+		Version:    entity.WalletEventVersionOne,
+		TransferID: req.TransferID,
+		WalletID:   req.WalletID,
+		Amount:     req.Amount,
+		EventType:  entity.EventTypeDebitTransfer,
+		Status:     req.Status,
+		ExpiresAt:  &wantExpiresAt,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(4)).Return(event, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), gomock.Any()).Return(nil)
+
+	result, err := s.svc.DebitTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(event, result)
+}
+
+func (s *WalletServiceTestSuite) TestDebitTransferInvalidExpiry() {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	for _, req := range []*request.DebitTransfer{
+		{WalletID: "wallet-id", TransferID: "1234", Amount: decimal.NewFromInt(100), Currency: "EUR", Status: entity.TransferStatusPending, ExpiresAt: &past},
+		{WalletID: "wallet-id", TransferID: "1234", Amount: decimal.NewFromInt(100), Currency: "EUR", Status: entity.TransferStatusCompleted, ExpiresAt: &future},
+	} {
+		result, err := s.svc.DebitTransfer(context.Background(), req)
+		s.ErrorIs(err, entity.ErrInvalidTransferExpiry)
+		s.Empty(result)
+	}
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferInvalidExpiry() {
+	past := time.Now().Add(-time.Minute)
+
+	result, err := s.svc.CreditTransfer(context.Background(), &request.CreditTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     decimal.NewFromInt(100),
+		Currency:   "EUR",
+		Status:     entity.TransferStatusPending,
+		ExpiresAt:  &past,
+	})
+	s.ErrorIs(err, entity.ErrInvalidTransferExpiry)
+	s.Empty(result)
+}
+
 func (s *WalletServiceTestSuite) TestDebitTransferGetError() {
 	req := &request.DebitTransfer{
 		WalletID:    "wallet-id",
@@ -183,9 +496,10 @@ func (s *WalletServiceTestSuite) TestDebitTransferGetError() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{}, context.DeadlineExceeded)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{}, context.DeadlineExceeded)
 
 	result, err := s.svc.DebitTransfer(context.Background(), req)
 	s.ErrorIs(err, context.DeadlineExceeded)
@@ -199,6 +513,7 @@ func (s *WalletServiceTestSuite) TestDebitTransferCreateError() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
 	event := entity.WalletEvent{
@@ -211,10 +526,12 @@ func (s *WalletServiceTestSuite) TestDebitTransferCreateError() {
 		Status:      req.Status,
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
 	}, nil)
-	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(entity.WalletEvent{}, context.DeadlineExceeded)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(4)).Return(entity.WalletEvent{}, context.DeadlineExceeded)
 
 	result, err := s.svc.DebitTransfer(context.Background(), req)
 	s.ErrorIs(err, context.DeadlineExceeded)
@@ -228,6 +545,7 @@ func (s *WalletServiceTestSuite) TestDebitTransferPublishCreatedError() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
 	event := entity.WalletEvent{
@@ -240,10 +558,12 @@ func (s *WalletServiceTestSuite) TestDebitTransferPublishCreatedError() {
 		Status:      req.Status,
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
 	}, nil)
-	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(event, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(4)).Return(event, nil)
 	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(context.DeadlineExceeded)
 
 	_, err := s.svc.DebitTransfer(context.Background(), req)
@@ -257,6 +577,7 @@ func (s *WalletServiceTestSuite) TestCreditTransferSuccess() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
 	event := entity.WalletEvent{
@@ -269,10 +590,12 @@ func (s *WalletServiceTestSuite) TestCreditTransferSuccess() {
 		Status:      req.Status,
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
 	}, nil)
-	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), req.WalletID).Return([]entity.WalletEvent{
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return([]entity.WalletEvent{
 		{
 			Version:     entity.WalletEventVersionOne,
 			TransferID:  req.TransferID,
@@ -281,10 +604,11 @@ func (s *WalletServiceTestSuite) TestCreditTransferSuccess() {
 			Amount:      decimal.NewFromInt(100),
 			EventType:   entity.EventTypeDebitTransfer,
 			Status:      entity.TransferStatusCompleted,
+			Sequence:    1,
 		},
 	}, nil)
 
-	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(event, nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(1)).Return(event, nil)
 	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(nil)
 
 	result, err := s.svc.CreditTransfer(context.Background(), req)
@@ -299,9 +623,10 @@ func (s *WalletServiceTestSuite) TestCreditTransferGetError() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{}, context.DeadlineExceeded)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{}, context.DeadlineExceeded)
 
 	result, err := s.svc.CreditTransfer(context.Background(), req)
 	s.ErrorIs(err, context.DeadlineExceeded)
@@ -315,12 +640,15 @@ func (s *WalletServiceTestSuite) TestCreditTransferListByWalletIDError() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
 	}, nil)
-	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), req.WalletID).Return(nil, context.DeadlineExceeded)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return(nil, context.DeadlineExceeded)
 
 	result, err := s.svc.CreditTransfer(context.Background(), req)
 	s.ErrorIs(err, context.DeadlineExceeded)
@@ -334,6 +662,7 @@ func (s *WalletServiceTestSuite) TestCreditTransferCreateError() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
 	event := entity.WalletEvent{
@@ -346,10 +675,12 @@ func (s *WalletServiceTestSuite) TestCreditTransferCreateError() {
 		Status:      req.Status,
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
 	}, nil)
-	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), req.WalletID).Return([]entity.WalletEvent{
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return([]entity.WalletEvent{
 		{
 			Version:     entity.WalletEventVersionOne,
 			TransferID:  req.TransferID,
@@ -358,9 +689,10 @@ func (s *WalletServiceTestSuite) TestCreditTransferCreateError() {
 			Amount:      decimal.NewFromInt(100),
 			EventType:   entity.EventTypeDebitTransfer,
 			Status:      entity.TransferStatusCompleted,
+			Sequence:    1,
 		},
 	}, nil)
-	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(entity.WalletEvent{}, context.DeadlineExceeded)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(1)).Return(entity.WalletEvent{}, context.DeadlineExceeded)
 
 	result, err := s.svc.CreditTransfer(context.Background(), req)
 	s.ErrorIs(err, context.DeadlineExceeded)
@@ -374,6 +706,7 @@ func (s *WalletServiceTestSuite) TestCreditTransferPublishCreatedError() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(100),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
 	event := entity.WalletEvent{
@@ -386,10 +719,12 @@ func (s *WalletServiceTestSuite) TestCreditTransferPublishCreatedError() {
 		Status:      req.Status,
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
 	}, nil)
-	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), req.WalletID).Return([]entity.WalletEvent{
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return([]entity.WalletEvent{
 		{
 			Version:     entity.WalletEventVersionOne,
 			TransferID:  req.TransferID,
@@ -398,9 +733,10 @@ func (s *WalletServiceTestSuite) TestCreditTransferPublishCreatedError() {
 			Amount:      decimal.NewFromInt(100),
 			EventType:   entity.EventTypeDebitTransfer,
 			Status:      entity.TransferStatusCompleted,
+			Sequence:    1,
 		},
 	}, nil)
-	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(event, nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(1)).Return(event, nil)
 	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(context.DeadlineExceeded)
 
 	_, err := s.svc.CreditTransfer(context.Background(), req)
@@ -414,12 +750,15 @@ func (s *WalletServiceTestSuite) TestCreditTransferInsufficientBalance() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(50),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
 	}, nil)
-	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), req.WalletID).Return([]entity.WalletEvent{
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return([]entity.WalletEvent{
 		{
 			Version:     entity.WalletEventVersionOne,
 			TransferID:  req.TransferID,
@@ -442,12 +781,15 @@ func (s *WalletServiceTestSuite) TestCreditTransferProcessEventsError() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(50),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
 	}, nil)
-	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), req.WalletID).Return([]entity.WalletEvent{
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return([]entity.WalletEvent{
 		{
 			Version:     entity.WalletEventVersionOne,
 			TransferID:  req.TransferID,
@@ -470,6 +812,7 @@ func (s *WalletServiceTestSuite) TestCreditTransferNegativeAmountError() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(-50),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
 	_, err := s.svc.CreditTransfer(context.Background(), req)
@@ -483,12 +826,84 @@ func (s *WalletServiceTestSuite) TestDebitTransferNegativeAmountError() {
 		TransferID:  "1234",
 		Amount:      decimal.NewFromInt(-50),
 		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
 	}
 
 	_, err := s.svc.DebitTransfer(context.Background(), req)
 	s.ErrorIs(err, entity.ErrNegativeAmount)
 }
 
+func (s *WalletServiceTestSuite) TestDebitTransferCurrencyMismatch() {
+	req := &request.DebitTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     decimal.NewFromInt(100),
+		Currency:   "USD",
+		Status:     entity.TransferStatusPending,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+
+	result, err := s.svc.DebitTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrCurrencyMismatch)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestDebitTransferInvalidAmountScale() {
+	tests := []struct {
+		currency    entity.Currency
+		amount      string
+		expectedErr error
+	}{
+		{currency: "JPY", amount: "100.5", expectedErr: entity.ErrInvalidAmountScale},
+		{currency: "EUR", amount: "1.001", expectedErr: entity.ErrInvalidAmountScale},
+		{currency: "KWD", amount: "1.0001", expectedErr: entity.ErrInvalidAmountScale},
+		{currency: "EUR", amount: "1.100"}, // trailing zeros don't add precision
+		{currency: "KWD", amount: "1.001"},
+		{currency: entity.CurrencyNone, amount: "1.00001"}, // legacy wallets have no minor unit rule
+	}
+
+	for _, tt := range tests {
+		req := &request.DebitTransfer{
+			WalletID:   "wallet-id",
+			TransferID: "1234",
+			Amount:     decimal.RequireFromString(tt.amount),
+			Currency:   string(tt.currency),
+			Status:     entity.TransferStatusPending,
+		}
+
+		s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: tt.currency}, nil)
+		if tt.expectedErr == nil {
+			s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(0), nil)
+			s.eventRepoMock.EXPECT().Create(gomock.Any(), gomock.Any(), int64(0)).Return(entity.WalletEvent{}, nil)
+			s.publisherMock.EXPECT().PublishCreated(gomock.Any(), gomock.Any()).Return(nil)
+		}
+
+		_, err := s.svc.DebitTransfer(context.Background(), req)
+		if tt.expectedErr != nil {
+			s.ErrorIs(err, tt.expectedErr, tt.amount)
+			continue
+		}
+		s.NoError(err, tt.amount)
+	}
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferCurrencyMismatch() {
+	req := &request.CreditTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     decimal.NewFromInt(100),
+		Currency:   "usd",
+		Status:     entity.TransferStatusPending,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "GBP"}, nil)
+
+	result, err := s.svc.CreditTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrCurrencyMismatch)
+	s.Empty(result)
+}
+
 func (s *WalletServiceTestSuite) TestCompleteTransferSuccess() {
 	req := &request.CompleteTransfer{
 		WalletID:    "wallet-id",
@@ -501,14 +916,18 @@ func (s *WalletServiceTestSuite) TestCompleteTransferSuccess() {
 		TransferID:  req.TransferID,
 		ReferenceID: req.ReferenceID,
 		WalletID:    req.WalletID,
+		Amount:      decimal.NewFromInt(100), // the whole pending amount is captured
 		EventType:   entity.EventTypeUpdateTransferStatus,
 		Status:      entity.TransferStatusCompleted,
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
 	}, nil)
-	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(event, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(s.pendingTransferEvents(req.WalletID, req.TransferID), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(4)).Return(event, nil)
 	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(nil)
 
 	result, err := s.svc.CompleteTransfer(context.Background(), req)
@@ -523,7 +942,7 @@ func (s *WalletServiceTestSuite) TestCompleteTransferGetError() {
 		ReferenceID: "123",
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{}, context.DeadlineExceeded)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{}, context.DeadlineExceeded)
 
 	result, err := s.svc.CompleteTransfer(context.Background(), req)
 	s.ErrorIs(err, context.DeadlineExceeded)
@@ -542,14 +961,18 @@ func (s *WalletServiceTestSuite) TestCompleteTransferCreateError() {
 		TransferID:  req.TransferID,
 		ReferenceID: req.ReferenceID,
 		WalletID:    req.WalletID,
+		Amount:      decimal.NewFromInt(100), // the whole pending amount is captured
 		EventType:   entity.EventTypeUpdateTransferStatus,
 		Status:      entity.TransferStatusCompleted,
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
 	}, nil)
-	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(entity.WalletEvent{}, context.DeadlineExceeded)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(s.pendingTransferEvents(req.WalletID, req.TransferID), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(4)).Return(entity.WalletEvent{}, context.DeadlineExceeded)
 
 	result, err := s.svc.CompleteTransfer(context.Background(), req)
 	s.ErrorIs(err, context.DeadlineExceeded)
@@ -568,146 +991,534 @@ func (s *WalletServiceTestSuite) TestCompleteTransferPublishCreatedError() {
 		TransferID:  req.TransferID,
 		ReferenceID: req.ReferenceID,
 		WalletID:    req.WalletID,
+		Amount:      decimal.NewFromInt(100), // the whole pending amount is captured
 		EventType:   entity.EventTypeUpdateTransferStatus,
 		Status:      entity.TransferStatusCompleted,
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
 	}, nil)
-	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(event, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(s.pendingTransferEvents(req.WalletID, req.TransferID), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(4)).Return(event, nil)
 	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(context.DeadlineExceeded)
 
 	_, err := s.svc.CompleteTransfer(context.Background(), req)
 	s.ErrorIs(err, context.DeadlineExceeded)
 }
 
-func (s *WalletServiceTestSuite) TestRevertTransferSuccess() {
-	req := &request.RevertTransfer{
-		WalletID:    "wallet-id",
-		TransferID:  "1234",
-		ReferenceID: "123",
+func (s *WalletServiceTestSuite) TestCompleteTransferPartialCapture() {
+	captured := decimal.NewFromInt(80)
+	req := &request.CompleteTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     &captured,
 	}
 
 	event := entity.WalletEvent{
-		Version:     entity.WalletEventVersionOne,
-		TransferID:  req.TransferID,
-		ReferenceID: req.ReferenceID,
-		WalletID:    req.WalletID,
-		EventType:   entity.EventTypeUpdateTransferStatus,
-		Status:      entity.TransferStatusFailed,
+		Version:    entity.WalletEventVersionOne,
+		TransferID: req.TransferID,
+		WalletID:   req.WalletID,
+		Amount:     captured,
+		EventType:  entity.EventTypeUpdateTransferStatus,
+		Status:     entity.TransferStatusCompleted,
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
-	}, nil)
-	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(event, nil)
-	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(s.pendingTransferEvents(req.WalletID, req.TransferID), nil)
+	s.expectAppend(event, 4)
 
-	result, err := s.svc.RevertTransfer(context.Background(), req)
+	result, err := s.svc.CompleteTransfer(context.Background(), req)
 	s.NoError(err)
-	s.Equal(event, result)
+	s.True(captured.Equal(result.Amount))
 }
 
-func (s *WalletServiceTestSuite) TestRevertTransferGetError() {
-	req := &request.RevertTransfer{
-		WalletID:    "wallet-id",
-		TransferID:  "1234",
-		ReferenceID: "123",
+func (s *WalletServiceTestSuite) TestCompleteTransferInvalidCaptureAmount() {
+	tests := []struct {
+		name   string
+		amount decimal.Decimal
+		err    error
+	}{
+		{name: "zero", amount: decimal.Zero, err: entity.ErrInvalidCaptureAmount},
+		{name: "more than the pending amount", amount: decimal.NewFromInt(101), err: entity.ErrInvalidCaptureAmount},
+		{name: "negative", amount: decimal.NewFromInt(-1), err: entity.ErrNegativeAmount},
+		{name: "more decimal places than the currency", amount: decimal.RequireFromString("10.001"), err: entity.ErrInvalidAmountScale},
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{}, context.DeadlineExceeded)
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := &request.CompleteTransfer{WalletID: "wallet-id", TransferID: "1234", Amount: &tt.amount}
 
-	result, err := s.svc.RevertTransfer(context.Background(), req)
-	s.ErrorIs(err, context.DeadlineExceeded)
+			s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+			s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+			s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(s.pendingTransferEvents(req.WalletID, req.TransferID), nil)
+
+			_, err := s.svc.CompleteTransfer(context.Background(), req)
+			s.ErrorIs(err, tt.err)
+		})
+	}
+}
+
+func (s *WalletServiceTestSuite) TestCompleteTransferNotFound() {
+	req := &request.CompleteTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return([]entity.WalletEvent{}, nil)
+
+	result, err := s.svc.CompleteTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrTransferNotFound)
 	s.Empty(result)
 }
 
-func (s *WalletServiceTestSuite) TestRevertTransferCreateError() {
-	req := &request.RevertTransfer{
-		WalletID:    "wallet-id",
-		TransferID:  "1234",
-		ReferenceID: "123",
+func (s *WalletServiceTestSuite) TestCompleteTransferAlreadyCompleted() {
+	req := &request.CompleteTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
 	}
 
-	event := entity.WalletEvent{
-		Version:     entity.WalletEventVersionOne,
-		TransferID:  req.TransferID,
-		ReferenceID: req.ReferenceID,
-		WalletID:    req.WalletID,
-		EventType:   entity.EventTypeUpdateTransferStatus,
-		Status:      entity.TransferStatusFailed,
+	events := s.pendingTransferEvents(req.WalletID, req.TransferID)
+	events = append(events, entity.WalletEvent{
+		Version:    entity.WalletEventVersionOne,
+		TransferID: req.TransferID,
+		WalletID:   req.WalletID,
+		EventType:  entity.EventTypeUpdateTransferStatus,
+		Status:     entity.TransferStatusCompleted,
+		Sequence:   2,
+	})
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(events, nil)
+
+	result, err := s.svc.CompleteTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrTransferAlreadyCompleted)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestCompleteTransferExpired() {
+	req := &request.CompleteTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
-	}, nil)
-	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(entity.WalletEvent{}, context.DeadlineExceeded)
+	expiresAt := time.Now().UTC().Add(-time.Second)
+	events := s.pendingTransferEvents(req.WalletID, req.TransferID)
+	events[0].ExpiresAt = &expiresAt
 
-	result, err := s.svc.RevertTransfer(context.Background(), req)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(events, nil)
+
+	result, err := s.svc.CompleteTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrTransferExpired)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestCompleteTransferListByTransferIDError() {
+	req := &request.CompleteTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(nil, context.DeadlineExceeded)
+
+	result, err := s.svc.CompleteTransfer(context.Background(), req)
 	s.ErrorIs(err, context.DeadlineExceeded)
 	s.Empty(result)
 }
 
-func (s *WalletServiceTestSuite) TestRevertTransferPublishCreatedError() {
-	req := &request.RevertTransfer{
+func (s *WalletServiceTestSuite) completedTransferEvents(walletID, transferID string, eventType entity.WalletEventType) []entity.WalletEvent {
+	return []entity.WalletEvent{
+		{
+			Version:    entity.WalletEventVersionOne,
+			TransferID: transferID,
+			WalletID:   walletID,
+			Amount:     decimal.NewFromInt(100),
+			EventType:  eventType,
+			Status:     entity.TransferStatusCompleted,
+			Sequence:   2,
+		},
+	}
+}
+
+func (s *WalletServiceTestSuite) TestRefundTransferPartialCredit() {
+	amount := decimal.NewFromInt(50)
+	req := &request.RefundTransfer{
 		WalletID:    "wallet-id",
 		TransferID:  "1234",
-		ReferenceID: "123",
+		ReferenceID: "ref-id",
+		Amount:      &amount,
 	}
 
 	event := entity.WalletEvent{
 		Version:     entity.WalletEventVersionOne,
-		TransferID:  req.TransferID,
+		TransferID:  "1234:refund:2",
 		ReferenceID: req.ReferenceID,
 		WalletID:    req.WalletID,
-		EventType:   entity.EventTypeUpdateTransferStatus,
-		Status:      entity.TransferStatusFailed,
+		Amount:      amount,
+		EventType:   entity.EventTypeDebitTransfer, // the credited amount comes back to the wallet
+		Status:      entity.TransferStatusCompleted,
 	}
 
-	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{
-		ID: req.WalletID,
+	s.expectFundedWallet(req.WalletID, entity.WalletTierStandard)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).
+		Return(s.completedTransferEvents(req.WalletID, req.TransferID, entity.EventTypeCreditTransfer), nil)
+	s.internalTransferRepoMock.EXPECT().Get(gomock.Any(), req.TransferID).Return(entity.InternalTransfer{}, entity.ErrEntityNotFound)
+	s.refundRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return([]entity.TransferRefund{
+		{RefundID: "1234:refund:1", WalletID: req.WalletID, TransferID: req.TransferID, Amount: decimal.NewFromInt(30)},
 	}, nil)
-	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(event, nil)
-	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(context.DeadlineExceeded)
+	s.expectAppend(event, 1)
+	s.refundRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(entity.TransferRefund{
+		RefundID:    event.TransferID,
+		WalletID:    req.WalletID,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		Amount:      amount,
+	}, cmpopts.IgnoreFields(entity.TransferRefund{}, "EventID", "CreatedAt"))).DoAndReturn(func(_ context.Context, refund entity.TransferRefund) (entity.TransferRefund, error) {
+		return refund, nil
+	})
 
-	_, err := s.svc.RevertTransfer(context.Background(), req)
-	s.ErrorIs(err, context.DeadlineExceeded)
+	result, err := s.svc.RefundTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal("1234:refund:2", result.RefundID)
+	s.Equal(req.TransferID, result.TransferID)
+	s.True(amount.Equal(result.Amount))
 }
 
-func (s *WalletServiceTestSuite) TestRebuildWalletProjectionSuccess() {
-	event := &entity.WalletEvent{
-		ID:          "123",
-		Version:     entity.WalletEventVersionOne,
-		TransferID:  "1234",
-		ReferenceID: "123",
-		WalletID:    "wallet-id",
-		Amount:      decimal.NewFromInt(100),
-		EventType:   entity.EventTypeDebitTransfer,
-		Status:      entity.TransferStatusCompleted,
+func (s *WalletServiceTestSuite) TestRefundTransferDebitRemainder() {
+	req := &request.RefundTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
 	}
-	projection := entity.WalletProjection{
-		WalletID:      "wallet-id",
-		Balance:       decimal.NewFromInt(100),
-		PendingDebit:  decimal.Decimal{},
-		PendingCredit: decimal.Decimal{},
-		LastEventID:   event.ID,
+
+	event := entity.WalletEvent{
+		Version:    entity.WalletEventVersionOne,
+		TransferID: "1234:refund:1",
+		WalletID:   req.WalletID,
+		Amount:     decimal.NewFromInt(100),
+		EventType:  entity.EventTypeCreditTransfer, // the debited amount leaves the wallet again
+		Status:     entity.TransferStatusCompleted,
 	}
-	s.projectionRepoMock.EXPECT().Get(gomock.Any(), event.WalletID).Return(entity.WalletProjection{
-		WalletID: "12",
-	}, nil)
 
-	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), event.WalletID).Return([]entity.WalletEvent{*event}, nil)
-	s.projectionRepoMock.EXPECT().Update(gomock.Any(), testutils.NewMatcher(projection, cmpopts.IgnoreFields(entity.WalletProjection{}, "UpdatedAt", "CreatedAt"))).Return(entity.WalletProjection{}, nil)
-	got, err := s.svc.RebuildWalletProjection(context.Background(), event)
+	s.expectFundedWallet(req.WalletID, entity.WalletTierStandard)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).
+		Return(s.completedTransferEvents(req.WalletID, req.TransferID, entity.EventTypeDebitTransfer), nil)
+	s.internalTransferRepoMock.EXPECT().Get(gomock.Any(), req.TransferID).Return(entity.InternalTransfer{}, entity.ErrEntityNotFound)
+	s.refundRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return([]entity.TransferRefund{}, nil)
+	s.expectAppend(event, 1)
+	s.refundRepoMock.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, refund entity.TransferRefund) (entity.TransferRefund, error) {
+		return refund, nil
+	})
 
+	result, err := s.svc.RefundTransfer(context.Background(), req)
 	s.NoError(err)
-	s.NotEmpty(got.UpdatedAt)
+	s.Equal("1234:refund:1", result.RefundID)
+	s.True(decimal.NewFromInt(100).Equal(result.Amount))
+}
+
+func (s *WalletServiceTestSuite) TestRefundTransferInsufficientBalance() {
+	req := &request.RefundTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+	}
+
+	events := s.completedTransferEvents(req.WalletID, req.TransferID, entity.EventTypeDebitTransfer)
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(events, nil)
+	s.internalTransferRepoMock.EXPECT().Get(gomock.Any(), req.TransferID).Return(entity.InternalTransfer{}, entity.ErrEntityNotFound)
+	s.refundRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return([]entity.TransferRefund{}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return(append(events, entity.WalletEvent{
+		Version:    entity.WalletEventVersionOne,
+		TransferID: "spent",
+		WalletID:   req.WalletID,
+		Amount:     decimal.NewFromInt(90),
+		EventType:  entity.EventTypeCreditTransfer,
+		Status:     entity.TransferStatusCompleted,
+		Sequence:   3,
+	}), nil)
+
+	result, err := s.svc.RefundTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrInsufficientBalance)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestRefundTransferNotRefundable() {
+	s.Run("pending", func() {
+		req := &request.RefundTransfer{WalletID: "wallet-id", TransferID: "1234"}
+
+		s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+		s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(s.pendingTransferEvents(req.WalletID, req.TransferID), nil)
+
+		_, err := s.svc.RefundTransfer(context.Background(), req)
+		s.ErrorIs(err, entity.ErrTransferNotRefundable)
+	})
+
+	s.Run("unknown transfer", func() {
+		req := &request.RefundTransfer{WalletID: "wallet-id", TransferID: "1234"}
+
+		s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+		s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return([]entity.WalletEvent{}, nil)
+
+		_, err := s.svc.RefundTransfer(context.Background(), req)
+		s.ErrorIs(err, entity.ErrTransferNotFound)
+	})
+
+	s.Run("internal transfer leg", func() {
+		req := &request.RefundTransfer{WalletID: "wallet-id", TransferID: "1234"}
+
+		s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+		s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).
+			Return(s.completedTransferEvents(req.WalletID, req.TransferID, entity.EventTypeCreditTransfer), nil)
+		s.internalTransferRepoMock.EXPECT().Get(gomock.Any(), req.TransferID).
+			Return(entity.InternalTransfer{TransferID: req.TransferID, SourceWalletID: req.WalletID, DestinationWalletID: "other"}, nil)
+
+		_, err := s.svc.RefundTransfer(context.Background(), req)
+		s.ErrorIs(err, entity.ErrTransferNotRefundable)
+	})
+
+	s.Run("fx quote leg", func() {
+		req := &request.RefundTransfer{WalletID: "wallet-id", TransferID: "1234"}
+		rate := decimal.RequireFromString("1.1")
+		events := s.completedTransferEvents(req.WalletID, req.TransferID, entity.EventTypeCreditTransfer)
+		events[0].FXRate = &rate
+
+		s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+		s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(events, nil)
+
+		_, err := s.svc.RefundTransfer(context.Background(), req)
+		s.ErrorIs(err, entity.ErrTransferNotRefundable)
+	})
+
+	for _, transferID := range []string{entity.FeeTransferID("1234"), entity.RefundTransferID("1234", 1)} {
+		s.Run(transferID, func() {
+			_, err := s.svc.RefundTransfer(context.Background(), &request.RefundTransfer{WalletID: "wallet-id", TransferID: transferID})
+			s.ErrorIs(err, entity.ErrTransferNotRefundable)
+		})
+	}
+}
+
+func (s *WalletServiceTestSuite) TestRefundTransferInvalidAmount() {
+	refunded := []entity.TransferRefund{{RefundID: "1234:refund:1", Amount: decimal.NewFromInt(60)}}
+	fullyRefunded := []entity.TransferRefund{{RefundID: "1234:refund:1", Amount: decimal.NewFromInt(100)}}
+	tooMuch := decimal.NewFromInt(41)
+	zero := decimal.Zero
+	negative := decimal.NewFromInt(-1)
+	scale := decimal.RequireFromString("10.001")
+
+	tests := []struct {
+		name    string
+		amount  *decimal.Decimal
+		refunds []entity.TransferRefund
+		err     error
+	}{
+		{name: "fully refunded", refunds: fullyRefunded, err: entity.ErrInvalidRefundAmount},
+		{name: "more than left to refund", amount: &tooMuch, refunds: refunded, err: entity.ErrInvalidRefundAmount},
+		{name: "zero", amount: &zero, err: entity.ErrInvalidRefundAmount},
+		{name: "negative", amount: &negative, err: entity.ErrNegativeAmount},
+		{name: "more decimal places than the currency", amount: &scale, err: entity.ErrInvalidAmountScale},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := &request.RefundTransfer{WalletID: "wallet-id", TransferID: "1234", Amount: tt.amount}
+
+			s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+			s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).
+				Return(s.completedTransferEvents(req.WalletID, req.TransferID, entity.EventTypeCreditTransfer), nil)
+			s.internalTransferRepoMock.EXPECT().Get(gomock.Any(), req.TransferID).Return(entity.InternalTransfer{}, entity.ErrEntityNotFound)
+			s.refundRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(append([]entity.TransferRefund{}, tt.refunds...), nil)
+
+			_, err := s.svc.RefundTransfer(context.Background(), req)
+			s.ErrorIs(err, tt.err)
+		})
+	}
+}
+
+func (s *WalletServiceTestSuite) TestRevertTransferSuccess() {
+	req := &request.RevertTransfer{
+		WalletID:    "wallet-id",
+		TransferID:  "1234",
+		ReferenceID: "123",
+	}
+
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.WalletID,
+		EventType:   entity.EventTypeUpdateTransferStatus,
+		Status:      entity.TransferStatusFailed,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
+	}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(s.pendingTransferEvents(req.WalletID, req.TransferID), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(4)).Return(event, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(nil)
+
+	result, err := s.svc.RevertTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(event, result)
+}
+
+func (s *WalletServiceTestSuite) TestRevertTransferGetError() {
+	req := &request.RevertTransfer{
+		WalletID:    "wallet-id",
+		TransferID:  "1234",
+		ReferenceID: "123",
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{}, context.DeadlineExceeded)
+
+	result, err := s.svc.RevertTransfer(context.Background(), req)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestRevertTransferCreateError() {
+	req := &request.RevertTransfer{
+		WalletID:    "wallet-id",
+		TransferID:  "1234",
+		ReferenceID: "123",
+	}
+
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.WalletID,
+		EventType:   entity.EventTypeUpdateTransferStatus,
+		Status:      entity.TransferStatusFailed,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
+	}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(s.pendingTransferEvents(req.WalletID, req.TransferID), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(4)).Return(entity.WalletEvent{}, context.DeadlineExceeded)
+
+	result, err := s.svc.RevertTransfer(context.Background(), req)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestRevertTransferPublishCreatedError() {
+	req := &request.RevertTransfer{
+		WalletID:    "wallet-id",
+		TransferID:  "1234",
+		ReferenceID: "123",
+	}
+
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.WalletID,
+		EventType:   entity.EventTypeUpdateTransferStatus,
+		Status:      entity.TransferStatusFailed,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{
+		ID:       req.WalletID,
+		Currency: "EUR",
+	}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(s.pendingTransferEvents(req.WalletID, req.TransferID), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(4)).Return(event, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt"))).Return(context.DeadlineExceeded)
+
+	_, err := s.svc.RevertTransfer(context.Background(), req)
+	s.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (s *WalletServiceTestSuite) TestRevertTransferAlreadyFailed() {
+	req := &request.RevertTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+	}
+
+	events := s.pendingTransferEvents(req.WalletID, req.TransferID)
+	events[0].Status = entity.TransferStatusFailed
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(events, nil)
+
+	result, err := s.svc.RevertTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrTransferAlreadyFailed)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestRevertTransferAlreadyCompleted() {
+	req := &request.RevertTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+	}
+
+	events := s.pendingTransferEvents(req.WalletID, req.TransferID)
+	events[0].Status = entity.TransferStatusCompleted // transfers created as completed can't be reverted either
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(events, nil)
+
+	result, err := s.svc.RevertTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrTransferAlreadyCompleted)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestRebuildWalletProjectionSuccess() {
+	event := &entity.WalletEvent{
+		ID:          "123",
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  "1234",
+		ReferenceID: "123",
+		WalletID:    "wallet-id",
+		Amount:      decimal.NewFromInt(100),
+		EventType:   entity.EventTypeDebitTransfer,
+		Status:      entity.TransferStatusCompleted,
+		Sequence:    1,
+	}
+	projection := entity.WalletProjection{
+		WalletID:      "wallet-id",
+		Balance:       decimal.NewFromInt(100),
+		PendingDebit:  decimal.Decimal{},
+		PendingCredit: decimal.Decimal{},
+		LastEventID:   event.ID,
+		LastSequence:  event.Sequence,
+	}
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), event.WalletID).Return(entity.WalletProjection{
+		WalletID: "12",
+	}, nil)
+
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), event.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), event.WalletID, int64(0)).Return([]entity.WalletEvent{*event}, nil)
+	s.projectionRepoMock.EXPECT().Update(gomock.Any(), testutils.NewMatcher(projection, cmpopts.IgnoreFields(entity.WalletProjection{}, "UpdatedAt", "CreatedAt"))).Return(entity.WalletProjection{}, nil)
+	got, err := s.svc.RebuildWalletProjection(context.Background(), event)
+
+	s.NoError(err)
+	s.NotEmpty(got.UpdatedAt)
 	got.UpdatedAt = projection.UpdatedAt
 	s.Equal(projection, got)
 }
 
-func (s *WalletServiceTestSuite) TestRebuildWalletProjectionNoopOnSameLastEvent() {
+func (s *WalletServiceTestSuite) TestRebuildWalletProjectionNoopOnSameLastSequence() {
 	event := &entity.WalletEvent{
 		ID:          "123",
 		Version:     entity.WalletEventVersionOne,
@@ -717,11 +1528,13 @@ func (s *WalletServiceTestSuite) TestRebuildWalletProjectionNoopOnSameLastEvent(
 		Amount:      decimal.NewFromInt(100),
 		EventType:   entity.EventTypeDebitTransfer,
 		Status:      entity.TransferStatusCompleted,
+		Sequence:    2,
 	}
 
 	s.projectionRepoMock.EXPECT().Get(gomock.Any(), event.WalletID).Return(entity.WalletProjection{
-		WalletID:    "123",
-		LastEventID: event.ID,
+		WalletID:     "123",
+		LastEventID:  event.ID,
+		LastSequence: event.Sequence,
 	}, nil)
 
 	got, err := s.svc.RebuildWalletProjection(context.Background(), event)
@@ -765,7 +1578,8 @@ func (s *WalletServiceTestSuite) TestRebuildWalletProjectionListByWalletIDError(
 		WalletID: "12",
 	}, nil)
 
-	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), event.WalletID).Return(nil, context.DeadlineExceeded)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), event.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), event.WalletID, int64(0)).Return(nil, context.DeadlineExceeded)
 
 	got, err := s.svc.RebuildWalletProjection(context.Background(), event)
 
@@ -795,7 +1609,8 @@ func (s *WalletServiceTestSuite) TestRebuildWalletProjectionUpdateError() {
 		WalletID: "12",
 	}, nil)
 
-	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), event.WalletID).Return([]entity.WalletEvent{*event}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), event.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), event.WalletID, int64(0)).Return([]entity.WalletEvent{*event}, nil)
 	s.projectionRepoMock.EXPECT().Update(gomock.Any(), testutils.NewMatcher(projection, cmpopts.IgnoreFields(entity.WalletProjection{}, "UpdatedAt", "CreatedAt"))).Return(entity.WalletProjection{}, context.DeadlineExceeded)
 	_, err := s.svc.RebuildWalletProjection(context.Background(), event)
 
@@ -818,12 +1633,2846 @@ func (s *WalletServiceTestSuite) TestRebuildWalletProjectionProcessEventsError()
 		WalletID: "12",
 	}, nil)
 
-	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), event.WalletID).Return([]entity.WalletEvent{*event}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), event.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), event.WalletID, int64(0)).Return([]entity.WalletEvent{*event}, nil)
 	_, err := s.svc.RebuildWalletProjection(context.Background(), event)
 
 	s.ErrorIs(err, entity.ErrUnsupportedEventType)
 }
 
-func TestWalletServiceTestSuite(t *testing.T) {
-	suite.Run(t, new(WalletServiceTestSuite))
+func (s *WalletServiceTestSuite) TestInternalTransferSuccess() {
+	req := &request.InternalTransfer{
+		SourceWalletID:      "source-wallet-id",
+		DestinationWalletID: "destination-wallet-id",
+		ReferenceID:         "123",
+		TransferID:          "1234",
+		Amount:              decimal.NewFromInt(100),
+		Status:              entity.TransferStatusPending,
+	}
+
+	transfer := entity.InternalTransfer{
+		TransferID:          req.TransferID,
+		ReferenceID:         req.ReferenceID,
+		SourceWalletID:      req.SourceWalletID,
+		DestinationWalletID: req.DestinationWalletID,
+		Amount:              req.Amount,
+	}
+
+	creditEvent := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.SourceWalletID,
+		Amount:      req.Amount,
+		EventType:   entity.EventTypeCreditTransfer,
+		Status:      req.Status,
+	}
+
+	debitEvent := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.DestinationWalletID,
+		Amount:      req.Amount,
+		EventType:   entity.EventTypeDebitTransfer,
+		Status:      req.Status,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.SourceWalletID).Return(entity.Wallet{ID: req.SourceWalletID, Currency: "EUR"}, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.DestinationWalletID).Return(entity.Wallet{ID: req.DestinationWalletID, Currency: "EUR"}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.SourceWalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.SourceWalletID, int64(0)).Return([]entity.WalletEvent{
+		{
+			Version:   entity.WalletEventVersionOne,
+			WalletID:  req.SourceWalletID,
+			Amount:    decimal.NewFromInt(100),
+			EventType: entity.EventTypeDebitTransfer,
+			Status:    entity.TransferStatusCompleted,
+			Sequence:  3,
+		},
+	}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.DestinationWalletID).Return(int64(7), nil)
+	s.internalTransferRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(transfer, cmpopts.IgnoreFields(entity.InternalTransfer{}, "CreatedAt"))).Return(transfer, nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(creditEvent, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(3)).Return(creditEvent, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), creditEvent).Return(nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(debitEvent, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(7)).Return(debitEvent, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), debitEvent).Return(nil)
+
+	result, err := s.svc.InternalTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.InternalTransferEvents{
+		InternalTransfer: transfer,
+		CreditEvent:      creditEvent,
+		DebitEvent:       debitEvent,
+	}, result)
+}
+
+func (s *WalletServiceTestSuite) TestInternalTransferInsufficientBalance() {
+	req := &request.InternalTransfer{
+		SourceWalletID:      "source-wallet-id",
+		DestinationWalletID: "destination-wallet-id",
+		ReferenceID:         "123",
+		TransferID:          "1234",
+		Amount:              decimal.NewFromInt(100),
+		Status:              entity.TransferStatusPending,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.SourceWalletID).Return(entity.Wallet{ID: req.SourceWalletID, Currency: "EUR"}, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.DestinationWalletID).Return(entity.Wallet{ID: req.DestinationWalletID, Currency: "EUR"}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.SourceWalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.SourceWalletID, int64(0)).Return([]entity.WalletEvent{
+		{
+			Version:   entity.WalletEventVersionOne,
+			WalletID:  req.SourceWalletID,
+			Amount:    decimal.NewFromInt(20),
+			EventType: entity.EventTypeDebitTransfer,
+			Status:    entity.TransferStatusCompleted,
+		},
+	}, nil)
+
+	result, err := s.svc.InternalTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrInsufficientBalance)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestInternalTransferDestinationWalletNotFound() {
+	req := &request.InternalTransfer{
+		SourceWalletID:      "source-wallet-id",
+		DestinationWalletID: "destination-wallet-id",
+		TransferID:          "1234",
+		Amount:              decimal.NewFromInt(100),
+		Status:              entity.TransferStatusPending,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.SourceWalletID).Return(entity.Wallet{ID: req.SourceWalletID, Currency: "EUR"}, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.DestinationWalletID).Return(entity.Wallet{}, entity.ErrEntityNotFound)
+
+	result, err := s.svc.InternalTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestInternalTransferCreateError() {
+	req := &request.InternalTransfer{
+		SourceWalletID:      "source-wallet-id",
+		DestinationWalletID: "destination-wallet-id",
+		TransferID:          "1234",
+		Amount:              decimal.NewFromInt(10),
+		Status:              entity.TransferStatusPending,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.SourceWalletID).Return(entity.Wallet{ID: req.SourceWalletID, Currency: "EUR"}, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.DestinationWalletID).Return(entity.Wallet{ID: req.DestinationWalletID, Currency: "EUR"}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.SourceWalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.SourceWalletID, int64(0)).Return([]entity.WalletEvent{
+		{
+			Version:   entity.WalletEventVersionOne,
+			WalletID:  req.SourceWalletID,
+			Amount:    decimal.NewFromInt(20),
+			EventType: entity.EventTypeDebitTransfer,
+			Status:    entity.TransferStatusCompleted,
+		},
+	}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.DestinationWalletID).Return(int64(0), nil)
+	s.internalTransferRepoMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.InternalTransfer{}, context.DeadlineExceeded)
+
+	result, err := s.svc.InternalTransfer(context.Background(), req)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestInternalTransferCurrencyMismatch() {
+	req := &request.InternalTransfer{
+		SourceWalletID:      "source-wallet-id",
+		DestinationWalletID: "destination-wallet-id",
+		TransferID:          "1234",
+		Amount:              decimal.NewFromInt(100),
+		Status:              entity.TransferStatusPending,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.SourceWalletID).Return(entity.Wallet{ID: req.SourceWalletID, Currency: "EUR"}, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.DestinationWalletID).Return(entity.Wallet{ID: req.DestinationWalletID, Currency: "USD"}, nil)
+
+	result, err := s.svc.InternalTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrCurrencyMismatch)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestInternalTransferSameWalletError() {
+	req := &request.InternalTransfer{
+		SourceWalletID:      "wallet-id",
+		DestinationWalletID: "wallet-id",
+		TransferID:          "1234",
+		Amount:              decimal.NewFromInt(100),
+		Status:              entity.TransferStatusPending,
+	}
+
+	_, err := s.svc.InternalTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrSameWalletTransfer)
+}
+
+func (s *WalletServiceTestSuite) TestInternalTransferNegativeAmountError() {
+	req := &request.InternalTransfer{
+		SourceWalletID:      "source-wallet-id",
+		DestinationWalletID: "destination-wallet-id",
+		TransferID:          "1234",
+		Amount:              decimal.NewFromInt(-100),
+		Status:              entity.TransferStatusPending,
+	}
+
+	_, err := s.svc.InternalTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrNegativeAmount)
+}
+
+func (s *WalletServiceTestSuite) TestCompleteInternalTransferSuccess() {
+	req := &request.CompleteInternalTransfer{
+		TransferID:  "1234",
+		ReferenceID: "123",
+	}
+
+	transfer := entity.InternalTransfer{
+		TransferID:          req.TransferID,
+		SourceWalletID:      "source-wallet-id",
+		DestinationWalletID: "destination-wallet-id",
+		Amount:              decimal.NewFromInt(100),
+	}
+
+	creditEvent := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    transfer.SourceWalletID,
+		EventType:   entity.EventTypeUpdateTransferStatus,
+		Status:      entity.TransferStatusCompleted,
+	}
+
+	debitEvent := creditEvent
+	debitEvent.WalletID = transfer.DestinationWalletID
+
+	s.internalTransferRepoMock.EXPECT().Get(gomock.Any(), req.TransferID).Return(transfer, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), transfer.SourceWalletID).Return(entity.Wallet{ID: transfer.SourceWalletID, Status: entity.WalletStatusActive}, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), transfer.DestinationWalletID).Return(entity.Wallet{ID: transfer.DestinationWalletID, Status: entity.WalletStatusActive}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), transfer.SourceWalletID).Return(int64(3), nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), transfer.DestinationWalletID).Return(int64(5), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), transfer.SourceWalletID, req.TransferID).Return(s.pendingTransferEvents(transfer.SourceWalletID, req.TransferID), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(creditEvent, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(3)).Return(creditEvent, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), creditEvent).Return(nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(debitEvent, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(5)).Return(debitEvent, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), debitEvent).Return(nil)
+
+	result, err := s.svc.CompleteInternalTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.InternalTransferEvents{
+		InternalTransfer: transfer,
+		CreditEvent:      creditEvent,
+		DebitEvent:       debitEvent,
+	}, result)
+}
+
+func (s *WalletServiceTestSuite) TestCompleteInternalTransferNotFound() {
+	req := &request.CompleteInternalTransfer{
+		TransferID: "1234",
+	}
+
+	s.internalTransferRepoMock.EXPECT().Get(gomock.Any(), req.TransferID).Return(entity.InternalTransfer{}, entity.ErrEntityNotFound)
+
+	result, err := s.svc.CompleteInternalTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestCompleteInternalTransferAlreadyFailed() {
+	req := &request.CompleteInternalTransfer{
+		TransferID: "1234",
+	}
+
+	transfer := entity.InternalTransfer{
+		TransferID:          req.TransferID,
+		SourceWalletID:      "source-wallet-id",
+		DestinationWalletID: "destination-wallet-id",
+		Amount:              decimal.NewFromInt(100),
+	}
+
+	events := s.pendingTransferEvents(transfer.SourceWalletID, req.TransferID)
+	events = append(events, entity.WalletEvent{
+		Version:    entity.WalletEventVersionOne,
+		TransferID: req.TransferID,
+		WalletID:   transfer.SourceWalletID,
+		EventType:  entity.EventTypeUpdateTransferStatus,
+		Status:     entity.TransferStatusFailed,
+		Sequence:   2,
+	})
+
+	s.internalTransferRepoMock.EXPECT().Get(gomock.Any(), req.TransferID).Return(transfer, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), transfer.SourceWalletID).Return(entity.Wallet{ID: transfer.SourceWalletID, Status: entity.WalletStatusActive}, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), transfer.DestinationWalletID).Return(entity.Wallet{ID: transfer.DestinationWalletID, Status: entity.WalletStatusActive}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), transfer.SourceWalletID).Return(int64(3), nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), transfer.DestinationWalletID).Return(int64(5), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), transfer.SourceWalletID, req.TransferID).Return(events, nil)
+
+	result, err := s.svc.CompleteInternalTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrTransferAlreadyFailed)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestRevertInternalTransferSuccess() {
+	req := &request.RevertInternalTransfer{
+		TransferID:  "1234",
+		ReferenceID: "123",
+	}
+
+	transfer := entity.InternalTransfer{
+		TransferID:          req.TransferID,
+		SourceWalletID:      "source-wallet-id",
+		DestinationWalletID: "destination-wallet-id",
+		Amount:              decimal.NewFromInt(100),
+	}
+
+	creditEvent := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    transfer.SourceWalletID,
+		EventType:   entity.EventTypeUpdateTransferStatus,
+		Status:      entity.TransferStatusFailed,
+	}
+
+	debitEvent := creditEvent
+	debitEvent.WalletID = transfer.DestinationWalletID
+
+	s.internalTransferRepoMock.EXPECT().Get(gomock.Any(), req.TransferID).Return(transfer, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), transfer.SourceWalletID).Return(entity.Wallet{ID: transfer.SourceWalletID, Status: entity.WalletStatusActive}, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), transfer.DestinationWalletID).Return(entity.Wallet{ID: transfer.DestinationWalletID, Status: entity.WalletStatusActive}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), transfer.SourceWalletID).Return(int64(3), nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), transfer.DestinationWalletID).Return(int64(5), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), transfer.SourceWalletID, req.TransferID).Return(s.pendingTransferEvents(transfer.SourceWalletID, req.TransferID), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(creditEvent, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(3)).Return(creditEvent, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), creditEvent).Return(nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(debitEvent, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(5)).Return(entity.WalletEvent{}, context.DeadlineExceeded)
+
+	result, err := s.svc.RevertInternalTransfer(context.Background(), req)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferRetryOnStreamVersionConflict() {
+	req := &request.CreditTransfer{
+		WalletID:    "wallet-id",
+		ReferenceID: "123",
+		TransferID:  "1234",
+		Amount:      decimal.NewFromInt(100),
+		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
+	}
+
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.WalletID,
+		Amount:      req.Amount,
+		EventType:   entity.EventTypeCreditTransfer,
+		Status:      req.Status,
+	}
+
+	staleEvents := []entity.WalletEvent{
+		{
+			Version:   entity.WalletEventVersionOne,
+			WalletID:  req.WalletID,
+			Amount:    decimal.NewFromInt(150),
+			EventType: entity.EventTypeDebitTransfer,
+			Status:    entity.TransferStatusCompleted,
+			Sequence:  1,
+		},
+	}
+	currentEvents := []entity.WalletEvent{
+		staleEvents[0],
+		{
+			Version:   entity.WalletEventVersionOne,
+			WalletID:  req.WalletID,
+			Amount:    decimal.NewFromInt(20),
+			EventType: entity.EventTypeCreditTransfer,
+			Status:    entity.TransferStatusCompleted,
+			Sequence:  2,
+		},
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil).Times(2)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound).Times(2)
+	gomock.InOrder(
+		s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return(staleEvents, nil),
+		s.eventRepoMock.EXPECT().Create(gomock.Any(), gomock.Any(), int64(1)).Return(entity.WalletEvent{}, entity.ErrStreamVersionConflict),
+		s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return(currentEvents, nil),
+		s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(2)).Return(event, nil),
+	)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), event).Return(nil)
+
+	result, err := s.svc.CreditTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(event, result)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferStreamVersionConflictAttemptsExhausted() {
+	req := &request.CreditTransfer{
+		WalletID:    "wallet-id",
+		ReferenceID: "123",
+		TransferID:  "1234",
+		Amount:      decimal.NewFromInt(100),
+		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil).Times(3)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound).Times(3)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return([]entity.WalletEvent{
+		{
+			Version:   entity.WalletEventVersionOne,
+			WalletID:  req.WalletID,
+			Amount:    decimal.NewFromInt(150),
+			EventType: entity.EventTypeDebitTransfer,
+			Status:    entity.TransferStatusCompleted,
+			Sequence:  1,
+		},
+	}, nil).Times(3)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), gomock.Any(), int64(1)).Return(entity.WalletEvent{}, entity.ErrStreamVersionConflict).Times(3)
+
+	result, err := s.svc.CreditTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrStreamVersionConflict)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestNewServiceInvalidMaxAppendAttempts() {
+	svc, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{}, wallet.WithMaxAppendAttempts(0))
+	s.ErrorIs(err, wallet.ErrInvalidAppendAttempts)
+	s.Nil(svc)
+}
+
+func (s *WalletServiceTestSuite) TestRebuildWalletProjectionLegacyEventWithoutSequence() {
+	event := &entity.WalletEvent{
+		ID:          "123",
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  "1234",
+		ReferenceID: "123",
+		WalletID:    "wallet-id",
+		Amount:      decimal.NewFromInt(100),
+		EventType:   entity.EventTypeDebitTransfer,
+		Status:      entity.TransferStatusCompleted,
+	}
+
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), event.WalletID).Return(entity.WalletProjection{
+		WalletID:     event.WalletID,
+		LastEventID:  event.ID,
+		LastSequence: 1,
+	}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), event.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), event.WalletID, int64(0)).Return([]entity.WalletEvent{*event}, nil)
+	s.projectionRepoMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(entity.WalletProjection{}, nil)
+
+	got, err := s.svc.RebuildWalletProjection(context.Background(), event)
+	s.NoError(err)
+	s.True(got.Balance.Equal(decimal.NewFromInt(100)))
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferFromSnapshot() {
+	req := &request.CreditTransfer{
+		WalletID:    "wallet-id",
+		ReferenceID: "123",
+		TransferID:  "1234",
+		Amount:      decimal.NewFromInt(100),
+		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
+	}
+
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.WalletID,
+		Amount:      req.Amount,
+		EventType:   entity.EventTypeCreditTransfer,
+		Status:      req.Status,
+	}
+
+	snapshot := entity.WalletSnapshot{
+		WalletID:     req.WalletID,
+		LastSequence: 10,
+		Balance:      decimal.NewFromInt(60),
+		PendingDebit: decimal.NewFromInt(40),
+		PendingTransfers: entity.PendingTransfers{
+			"pending-debit": {
+				TransferID: "pending-debit",
+				WalletID:   req.WalletID,
+				Amount:     decimal.NewFromInt(40),
+				EventType:  entity.EventTypeDebitTransfer,
+				Status:     entity.TransferStatusPending,
+				Sequence:   9,
+			},
+		},
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(snapshot, nil)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(10)).Return([]entity.WalletEvent{
+		{
+			Version:    entity.WalletEventVersionOne,
+			TransferID: "pending-debit",
+			WalletID:   req.WalletID,
+			EventType:  entity.EventTypeUpdateTransferStatus,
+			Status:     entity.TransferStatusCompleted,
+			Sequence:   11,
+		},
+	}, nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(11)).Return(event, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), event).Return(nil)
+
+	result, err := s.svc.CreditTransfer(context.Background(), req) // only the snapshot and the tail together cover the amount
+	s.NoError(err)
+	s.Equal(event, result)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferSnapshotError() {
+	req := &request.CreditTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     decimal.NewFromInt(100),
+		Status:     entity.TransferStatusPending,
+		Currency:   "EUR",
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, context.DeadlineExceeded)
+
+	result, err := s.svc.CreditTransfer(context.Background(), req)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestRebuildWalletProjectionCreatesSnapshot() {
+	svc, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{}, wallet.WithSnapshotInterval(2))
+	s.Require().NoError(err)
+
+	events := []entity.WalletEvent{
+		{ID: "1", Version: entity.WalletEventVersionOne, TransferID: "1", WalletID: "wallet-id", Amount: decimal.NewFromInt(100), EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Sequence: 1},
+		{ID: "2", Version: entity.WalletEventVersionOne, TransferID: "2", WalletID: "wallet-id", Amount: decimal.NewFromInt(20), EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusPending, Sequence: 2},
+	}
+
+	snapshot := entity.WalletSnapshot{
+		WalletID:      "wallet-id",
+		LastEventID:   "2",
+		LastSequence:  2,
+		Balance:       decimal.NewFromInt(80),
+		PendingCredit: decimal.NewFromInt(20),
+		PendingTransfers: entity.PendingTransfers{
+			"2": events[1],
+		},
+	}
+
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), "wallet-id").Return(entity.WalletProjection{WalletID: "wallet-id"}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), "wallet-id").Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), "wallet-id", int64(0)).Return(events, nil)
+	s.projectionRepoMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(entity.WalletProjection{}, nil)
+	s.snapshotRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(snapshot, cmpopts.IgnoreFields(entity.WalletSnapshot{}, "CreatedAt"))).Return(snapshot, nil)
+
+	_, err = svc.RebuildWalletProjection(context.Background(), &events[1])
+	s.NoError(err)
+}
+
+func (s *WalletServiceTestSuite) TestRebuildWalletProjectionSkipsSnapshotBelowInterval() {
+	svc, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{}, wallet.WithSnapshotInterval(5))
+	s.Require().NoError(err)
+
+	event := entity.WalletEvent{ID: "7", Version: entity.WalletEventVersionOne, TransferID: "7", WalletID: "wallet-id", Amount: decimal.NewFromInt(10), EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Sequence: 7}
+
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), "wallet-id").Return(entity.WalletProjection{WalletID: "wallet-id", LastSequence: 6}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), "wallet-id").Return(entity.WalletSnapshot{
+		WalletID:         "wallet-id",
+		LastEventID:      "6",
+		LastSequence:     6,
+		Balance:          decimal.NewFromInt(90),
+		PendingTransfers: entity.PendingTransfers{},
+	}, nil)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), "wallet-id", int64(6)).Return([]entity.WalletEvent{event}, nil)
+	s.projectionRepoMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(entity.WalletProjection{}, nil)
+
+	got, err := svc.RebuildWalletProjection(context.Background(), &event)
+	s.NoError(err)
+	s.True(got.Balance.Equal(decimal.NewFromInt(100)))
+	s.Equal(int64(7), got.LastSequence)
+}
+
+func (s *WalletServiceTestSuite) TestNewServiceInvalidSnapshotInterval() {
+	svc, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{}, wallet.WithSnapshotInterval(-1))
+	s.ErrorIs(err, wallet.ErrInvalidSnapshotInterval)
+	s.Nil(svc)
+}
+
+func (s *WalletServiceTestSuite) TestListEventsNextPage() {
+	req := &request.ListWalletEvents{
+		WalletID:  "wallet-id",
+		Cursor:    "cursor-id",
+		Limit:     2,
+		EventType: entity.EventTypeCreditTransfer,
+	}
+
+	events := []entity.WalletEvent{
+		{ID: "3", WalletID: req.WalletID, EventType: entity.EventTypeCreditTransfer},
+		{ID: "2", WalletID: req.WalletID, EventType: entity.EventTypeCreditTransfer},
+		{ID: "1", WalletID: req.WalletID, EventType: entity.EventTypeCreditTransfer},
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().List(gomock.Any(), entity.WalletEventFilter{
+		WalletID:  req.WalletID,
+		Cursor:    req.Cursor,
+		Limit:     3,
+		EventType: req.EventType,
+	}).Return(events, nil)
+
+	result, err := s.svc.ListEvents(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.WalletEventPage{
+		Events:     events[:2],
+		NextCursor: "2",
+	}, result)
+}
+
+func (s *WalletServiceTestSuite) TestListEventsLastPage() {
+	req := &request.ListWalletEvents{
+		WalletID: "wallet-id",
+		Status:   entity.TransferStatusPending,
+	}
+
+	events := []entity.WalletEvent{
+		{ID: "2", WalletID: req.WalletID, Status: entity.TransferStatusPending},
+		{ID: "1", WalletID: req.WalletID, Status: entity.TransferStatusPending},
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().List(gomock.Any(), entity.WalletEventFilter{
+		WalletID: req.WalletID,
+		Limit:    51, // default page size + 1
+		Status:   req.Status,
+	}).Return(events, nil)
+
+	result, err := s.svc.ListEvents(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.WalletEventPage{Events: events}, result)
+}
+
+func (s *WalletServiceTestSuite) TestListEventsWalletNotFound() {
+	req := &request.ListWalletEvents{
+		WalletID: "wallet-id",
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{}, entity.ErrEntityNotFound)
+
+	result, err := s.svc.ListEvents(context.Background(), req)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestListEventsListError() {
+	req := &request.ListWalletEvents{
+		WalletID: "wallet-id",
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, context.DeadlineExceeded)
+
+	result, err := s.svc.ListEvents(context.Background(), req)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestGetTransferSuccess() {
+	req := &request.GetTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "transfer-id",
+	}
+
+	transfer := entity.WalletTransfer{
+		WalletID:   req.WalletID,
+		TransferID: req.TransferID,
+		Amount:     decimal.NewFromInt(10),
+		Direction:  entity.TransferDirectionDebit,
+		Status:     entity.TransferStatusPending,
+	}
+
+	refunds := []entity.TransferRefund{
+		{RefundID: "transfer-id:refund:1", WalletID: req.WalletID, TransferID: req.TransferID, Amount: decimal.NewFromInt(4)},
+	}
+
+	s.transferRepoMock.EXPECT().Get(gomock.Any(), req.WalletID, req.TransferID).Return(transfer, nil)
+	s.refundRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(refunds, nil)
+
+	result, err := s.svc.GetTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.WalletTransferRefunds{WalletTransfer: transfer, Refunds: refunds}, result)
+}
+
+func (s *WalletServiceTestSuite) TestGetTransferNotFound() {
+	req := &request.GetTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "transfer-id",
+	}
+
+	s.transferRepoMock.EXPECT().Get(gomock.Any(), req.WalletID, req.TransferID).Return(entity.WalletTransfer{}, entity.ErrEntityNotFound)
+
+	result, err := s.svc.GetTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestListTransfersSuccess() {
+	req := &request.ListTransfers{
+		WalletID: "wallet-id",
+		Status:   entity.TransferStatusPending,
+	}
+
+	transfers := []entity.WalletTransfer{
+		{WalletID: req.WalletID, TransferID: "2", Status: entity.TransferStatusPending},
+		{WalletID: req.WalletID, TransferID: "1", Status: entity.TransferStatusPending},
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.transferRepoMock.EXPECT().List(gomock.Any(), entity.WalletTransferFilter{
+		WalletID: req.WalletID,
+		Status:   req.Status,
+	}).Return(transfers, nil)
+
+	result, err := s.svc.ListTransfers(context.Background(), req)
+	s.NoError(err)
+	s.Equal(transfers, result)
+}
+
+func (s *WalletServiceTestSuite) TestListTransfersMetadataFilter() {
+	req := &request.ListTransfers{
+		WalletID: "wallet-id",
+		Metadata: []string{"order_id:1", "note:a:b"},
+	}
+
+	transfers := []entity.WalletTransfer{
+		{WalletID: req.WalletID, TransferID: "1", Metadata: entity.Metadata{"order_id": "1", "note": "a:b"}},
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.transferRepoMock.EXPECT().List(gomock.Any(), entity.WalletTransferFilter{
+		WalletID: req.WalletID,
+		Metadata: entity.Metadata{"order_id": "1", "note": "a:b"}, // split on the first colon
+	}).Return(transfers, nil)
+
+	result, err := s.svc.ListTransfers(context.Background(), req)
+	s.NoError(err)
+	s.Equal(transfers, result)
+}
+
+func (s *WalletServiceTestSuite) TestListTransfersWalletNotFound() {
+	req := &request.ListTransfers{
+		WalletID: "wallet-id",
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{}, entity.ErrEntityNotFound)
+
+	result, err := s.svc.ListTransfers(context.Background(), req)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestRebuildTransferProjectionSuccess() {
+	event := &entity.WalletEvent{
+		WalletID:   "wallet-id",
+		TransferID: "transfer-id",
+		EventType:  entity.EventTypeUpdateTransferStatus,
+		Status:     entity.TransferStatusFailed,
+		Version:    entity.WalletEventVersionOne,
+		Sequence:   2,
+	}
+
+	events := []entity.WalletEvent{
+		{
+			WalletID:   event.WalletID,
+			TransferID: event.TransferID,
+			Amount:     decimal.NewFromInt(10),
+			EventType:  entity.EventTypeCreditTransfer,
+			Status:     entity.TransferStatusPending,
+			Version:    entity.WalletEventVersionOne,
+			Sequence:   1,
+		},
+		*event,
+	}
+
+	expected := entity.WalletTransfer{
+		WalletID:     event.WalletID,
+		TransferID:   event.TransferID,
+		Amount:       decimal.NewFromInt(10),
+		Direction:    entity.TransferDirectionCredit,
+		Status:       entity.TransferStatusFailed,
+		LastSequence: 2,
+	}
+
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), event.WalletID, event.TransferID).Return(events, nil)
+	s.transferRepoMock.EXPECT().Upsert(gomock.Any(), expected).Return(nil)
+
+	result, err := s.svc.RebuildTransferProjection(context.Background(), event)
+	s.NoError(err)
+	s.Equal(expected, result)
+}
+
+func (s *WalletServiceTestSuite) TestRebuildTransferProjectionUpsertError() {
+	event := &entity.WalletEvent{
+		WalletID:   "wallet-id",
+		TransferID: "transfer-id",
+		EventType:  entity.EventTypeDebitTransfer,
+		Status:     entity.TransferStatusPending,
+		Version:    entity.WalletEventVersionOne,
+		Sequence:   1,
+	}
+
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), event.WalletID, event.TransferID).Return([]entity.WalletEvent{*event}, nil)
+	s.transferRepoMock.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(context.DeadlineExceeded)
+
+	result, err := s.svc.RebuildTransferProjection(context.Background(), event)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestExpireTransfersSuccess() {
+	now := time.Now().UTC()
+	expiresAt := now.Add(-time.Minute)
+
+	expired := entity.WalletTransfer{WalletID: "wallet-id", TransferID: "1234", ReferenceID: "ref", Status: entity.TransferStatusPending, ExpiresAt: &expiresAt}
+	settled := entity.WalletTransfer{WalletID: "wallet-id", TransferID: "5678", Status: entity.TransferStatusPending, ExpiresAt: &expiresAt} // the read model hasn't caught up with the completion yet
+
+	settledEvents := s.pendingTransferEvents(settled.WalletID, settled.TransferID)
+	settledEvents = append(settledEvents, entity.WalletEvent{
+		Version:    entity.WalletEventVersionOne,
+		TransferID: settled.TransferID,
+		WalletID:   settled.WalletID,
+		EventType:  entity.EventTypeUpdateTransferStatus,
+		Status:     entity.TransferStatusCompleted,
+		Sequence:   2,
+	})
+
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  expired.TransferID,
+		ReferenceID: expired.ReferenceID,
+		WalletID:    expired.WalletID,
+		EventType:   entity.EventTypeUpdateTransferStatus,
+		Status:      entity.TransferStatusFailed,
+	}
+
+	s.transferRepoMock.EXPECT().ListExpired(gomock.Any(), now, uint64(10)).Return([]entity.WalletTransfer{expired, settled}, nil)
+
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), expired.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), expired.WalletID, expired.TransferID).Return(s.pendingTransferEvents(expired.WalletID, expired.TransferID), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(4)).Return(event, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), gomock.Any()).Return(nil)
+
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), settled.WalletID).Return(int64(5), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), settled.WalletID, settled.TransferID).Return(settledEvents, nil)
+
+	result, err := s.svc.ExpireTransfers(context.Background(), now, 10)
+	s.NoError(err)
+	s.Equal([]entity.WalletEvent{event}, result)
+}
+
+func (s *WalletServiceTestSuite) TestExpireTransfersContinuesAfterError() {
+	now := time.Now().UTC()
+	first := entity.WalletTransfer{WalletID: "wallet-1", TransferID: "1234", Status: entity.TransferStatusPending}
+	second := entity.WalletTransfer{WalletID: "wallet-2", TransferID: "5678", Status: entity.TransferStatusPending}
+
+	s.transferRepoMock.EXPECT().ListExpired(gomock.Any(), now, uint64(10)).Return([]entity.WalletTransfer{first, second}, nil)
+
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), first.WalletID).Return(int64(0), context.DeadlineExceeded)
+
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), second.WalletID).Return(int64(1), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), second.WalletID, second.TransferID).Return(s.pendingTransferEvents(second.WalletID, second.TransferID), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), gomock.Any(), int64(1)).Return(entity.WalletEvent{TransferID: second.TransferID}, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), gomock.Any()).Return(nil)
+
+	result, err := s.svc.ExpireTransfers(context.Background(), now, 10)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Len(result, 1)
+}
+
+func (s *WalletServiceTestSuite) TestExpireTransfersListExpiredError() {
+	s.transferRepoMock.EXPECT().ListExpired(gomock.Any(), gomock.Any(), uint64(10)).Return(nil, errors.New("boom"))
+
+	result, err := s.svc.ExpireTransfers(context.Background(), time.Now(), 10)
+	s.Error(err)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestFreezeSuccess() {
+	req := &request.FreezeWallet{WalletID: "wallet-id"}
+	wallet := entity.Wallet{ID: req.WalletID, Currency: "EUR", Status: entity.WalletStatusActive}
+	frozen := entity.Wallet{ID: req.WalletID, Currency: "EUR", Status: entity.WalletStatusFrozen}
+	projection := entity.WalletProjection{WalletID: req.WalletID, Balance: decimal.NewFromInt(10)}
+
+	s.repoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID).Return(wallet, nil)
+	s.repoMock.EXPECT().UpdateStatus(gomock.Any(), testutils.NewMatcher(frozen, cmpopts.IgnoreFields(entity.Wallet{}, "UpdatedAt"))).Return(frozen, nil)
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(projection, nil)
+
+	result, err := s.svc.Freeze(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.WalletBalanceProjection{Wallet: frozen, WalletProjection: projection}, result)
+}
+
+func (s *WalletServiceTestSuite) TestFreezeAlreadyFrozen() {
+	req := &request.FreezeWallet{WalletID: "wallet-id"}
+	wallet := entity.Wallet{ID: req.WalletID, Status: entity.WalletStatusFrozen}
+
+	s.repoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID).Return(wallet, nil)
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.WalletProjection{WalletID: req.WalletID}, nil)
+
+	result, err := s.svc.Freeze(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.WalletStatusFrozen, result.Status)
+}
+
+func (s *WalletServiceTestSuite) TestFreezeClosedWallet() {
+	req := &request.FreezeWallet{WalletID: "wallet-id"}
+
+	s.repoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Status: entity.WalletStatusClosed}, nil)
+
+	result, err := s.svc.Freeze(context.Background(), req)
+	s.ErrorIs(err, entity.ErrWalletClosed)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestFreezeWalletNotFound() {
+	req := &request.FreezeWallet{WalletID: "wallet-id"}
+
+	s.repoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID).Return(entity.Wallet{}, entity.ErrEntityNotFound)
+
+	result, err := s.svc.Freeze(context.Background(), req)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestUnfreezeSuccess() {
+	req := &request.UnfreezeWallet{WalletID: "wallet-id"}
+	active := entity.Wallet{ID: req.WalletID, Status: entity.WalletStatusActive}
+
+	s.repoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Status: entity.WalletStatusFrozen}, nil)
+	s.repoMock.EXPECT().UpdateStatus(gomock.Any(), testutils.NewMatcher(active, cmpopts.IgnoreFields(entity.Wallet{}, "UpdatedAt"))).Return(active, nil)
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.WalletProjection{WalletID: req.WalletID}, nil)
+
+	result, err := s.svc.Unfreeze(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.WalletStatusActive, result.Status)
+}
+
+func (s *WalletServiceTestSuite) TestCloseSuccess() {
+	req := &request.CloseWallet{WalletID: "wallet-id"}
+	closed := entity.Wallet{ID: req.WalletID, Status: entity.WalletStatusClosed}
+
+	events := []entity.WalletEvent{ // money in and out again, nothing left in the wallet
+		{Version: entity.WalletEventVersionOne, WalletID: req.WalletID, TransferID: "1", Amount: decimal.NewFromInt(10), EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Sequence: 1},
+		{Version: entity.WalletEventVersionOne, WalletID: req.WalletID, TransferID: "2", Amount: decimal.NewFromInt(10), EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Sequence: 2},
+	}
+
+	s.repoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Status: entity.WalletStatusActive}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return(events, nil)
+	s.repoMock.EXPECT().UpdateStatus(gomock.Any(), testutils.NewMatcher(closed, cmpopts.IgnoreFields(entity.Wallet{}, "UpdatedAt"))).Return(closed, nil)
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.WalletProjection{WalletID: req.WalletID}, nil)
+
+	result, err := s.svc.Close(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.WalletStatusClosed, result.Status)
+}
+
+func (s *WalletServiceTestSuite) TestCloseNotEmpty() {
+	tests := map[string][]entity.WalletEvent{
+		"balance": {
+			{Version: entity.WalletEventVersionOne, TransferID: "1", Amount: decimal.NewFromInt(10), EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Sequence: 1},
+		},
+		"pending transfer": s.pendingTransferEvents("wallet-id", "1"),
+		"pending zero amount transfer": {
+			{Version: entity.WalletEventVersionOne, TransferID: "1", Amount: decimal.NewFromInt(0), EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusPending, Sequence: 1},
+		},
+	}
+
+	for name, events := range tests {
+		req := &request.CloseWallet{WalletID: "wallet-id"}
+
+		s.repoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Status: entity.WalletStatusActive}, nil)
+		s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+		s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return(events, nil)
+
+		result, err := s.svc.Close(context.Background(), req)
+		s.ErrorIs(err, entity.ErrWalletNotEmpty, name)
+		s.Empty(result, name)
+	}
+}
+
+func (s *WalletServiceTestSuite) TestCloseFrozenWallet() {
+	req := &request.CloseWallet{WalletID: "wallet-id"}
+
+	s.repoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Status: entity.WalletStatusFrozen}, nil)
+
+	result, err := s.svc.Close(context.Background(), req)
+	s.ErrorIs(err, entity.ErrWalletFrozen)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestTransfersRejectedByWalletStatus() {
+	tests := []struct {
+		status      entity.WalletStatus
+		expectedErr error
+	}{
+		{status: entity.WalletStatusFrozen, expectedErr: entity.ErrWalletFrozen},
+		{status: entity.WalletStatusClosed, expectedErr: entity.ErrWalletClosed},
+	}
+
+	for _, tt := range tests {
+		wallet := entity.Wallet{ID: "wallet-id", Currency: "EUR", Status: tt.status}
+
+		s.repoMock.EXPECT().GetForShare(gomock.Any(), wallet.ID).Return(wallet, nil).Times(3)
+
+		_, err := s.svc.DebitTransfer(context.Background(), &request.DebitTransfer{
+			WalletID: wallet.ID, TransferID: "1234", Amount: decimal.NewFromInt(1), Currency: "EUR", Status: entity.TransferStatusPending,
+		})
+		s.ErrorIs(err, tt.expectedErr, tt.status.String())
+
+		_, err = s.svc.CreditTransfer(context.Background(), &request.CreditTransfer{
+			WalletID: wallet.ID, TransferID: "1234", Amount: decimal.NewFromInt(1), Currency: "EUR", Status: entity.TransferStatusPending,
+		})
+		s.ErrorIs(err, tt.expectedErr, tt.status.String())
+
+		_, err = s.svc.CompleteTransfer(context.Background(), &request.CompleteTransfer{WalletID: wallet.ID, TransferID: "1234"})
+		s.ErrorIs(err, tt.expectedErr, tt.status.String())
+	}
+}
+
+func (s *WalletServiceTestSuite) TestRevertTransferFrozenWallet() {
+	req := &request.RevertTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Status: entity.WalletStatusFrozen}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(1), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(s.pendingTransferEvents(req.WalletID, req.TransferID), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), gomock.Any(), int64(1)).Return(entity.WalletEvent{TransferID: req.TransferID}, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), gomock.Any()).Return(nil)
+
+	result, err := s.svc.RevertTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(req.TransferID, result.TransferID)
+}
+
+func (s *WalletServiceTestSuite) TestRevertTransferClosedWallet() {
+	req := &request.RevertTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Status: entity.WalletStatusClosed}, nil)
+
+	result, err := s.svc.RevertTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrWalletClosed)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestInternalTransferFrozenDestinationWallet() {
+	req := &request.InternalTransfer{
+		SourceWalletID:      "source-wallet-id",
+		DestinationWalletID: "destination-wallet-id",
+		TransferID:          "1234",
+		Amount:              decimal.NewFromInt(100),
+		Status:              entity.TransferStatusPending,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.SourceWalletID).Return(entity.Wallet{ID: req.SourceWalletID, Currency: "EUR", Status: entity.WalletStatusActive}, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.DestinationWalletID).Return(entity.Wallet{ID: req.DestinationWalletID, Currency: "EUR", Status: entity.WalletStatusFrozen}, nil)
+
+	result, err := s.svc.InternalTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrWalletFrozen)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestCompleteInternalTransferFrozenWallet() {
+	req := &request.CompleteInternalTransfer{TransferID: "1234"}
+	transfer := entity.InternalTransfer{TransferID: req.TransferID, SourceWalletID: "source-wallet-id", DestinationWalletID: "destination-wallet-id"}
+
+	s.internalTransferRepoMock.EXPECT().Get(gomock.Any(), req.TransferID).Return(transfer, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), transfer.SourceWalletID).Return(entity.Wallet{ID: transfer.SourceWalletID, Status: entity.WalletStatusFrozen}, nil)
+
+	result, err := s.svc.CompleteInternalTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrWalletFrozen)
+	s.Empty(result)
+}
+
+func TestWalletServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WalletServiceTestSuite))
+}
+
+func (s *WalletServiceTestSuite) TestSetCreditLimitSuccess() {
+	req := &request.SetCreditLimit{
+		WalletID:    "wallet-id",
+		ReferenceID: "123",
+		CreditLimit: decimal.NewFromInt(50),
+	}
+
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.WalletID,
+		Amount:      req.CreditLimit,
+		EventType:   entity.EventTypeCreditLimitChanged,
+		Status:      entity.TransferStatusCompleted,
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR", Status: entity.WalletStatusFrozen}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(3), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "TransferID", "CreatedAt")), int64(3)).DoAndReturn(
+		func(_ context.Context, created entity.WalletEvent, _ int64) (entity.WalletEvent, error) {
+			s.NotEmpty(created.TransferID)
+			s.Equal(created.ID, created.TransferID)
+			return created, nil
+		})
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), gomock.Any()).Return(nil)
+
+	result, err := s.svc.SetCreditLimit(context.Background(), req)
+	s.NoError(err)
+	s.True(req.CreditLimit.Equal(result.Amount))
+	s.Equal(entity.EventTypeCreditLimitChanged, result.EventType)
+}
+
+func (s *WalletServiceTestSuite) TestSetCreditLimitNegative() {
+	req := &request.SetCreditLimit{
+		WalletID:    "wallet-id",
+		CreditLimit: decimal.NewFromInt(-1),
+	}
+
+	_, err := s.svc.SetCreditLimit(context.Background(), req)
+	s.ErrorIs(err, entity.ErrInvalidCreditLimit)
+}
+
+func (s *WalletServiceTestSuite) TestSetCreditLimitInvalidScale() {
+	req := &request.SetCreditLimit{
+		WalletID:    "wallet-id",
+		CreditLimit: decimal.RequireFromString("10.001"),
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR", Status: entity.WalletStatusActive}, nil)
+
+	_, err := s.svc.SetCreditLimit(context.Background(), req)
+	s.ErrorIs(err, entity.ErrInvalidCreditLimit)
+}
+
+func (s *WalletServiceTestSuite) TestSetCreditLimitClosedWallet() {
+	req := &request.SetCreditLimit{
+		WalletID:    "wallet-id",
+		CreditLimit: decimal.NewFromInt(50),
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR", Status: entity.WalletStatusClosed}, nil)
+
+	_, err := s.svc.SetCreditLimit(context.Background(), req)
+	s.ErrorIs(err, entity.ErrWalletClosed)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferWithinCreditLimit() {
+	req := &request.CreditTransfer{
+		WalletID:    "wallet-id",
+		ReferenceID: "123",
+		TransferID:  "1234",
+		Amount:      decimal.NewFromInt(60),
+		Status:      entity.TransferStatusCompleted,
+		Currency:    "EUR",
+	}
+
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.WalletID,
+		Amount:      req.Amount,
+		EventType:   entity.EventTypeCreditTransfer,
+		Status:      req.Status,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return([]entity.WalletEvent{
+		{
+			Version:   entity.WalletEventVersionOne,
+			WalletID:  req.WalletID,
+			Amount:    decimal.NewFromInt(20),
+			EventType: entity.EventTypeDebitTransfer,
+			Status:    entity.TransferStatusCompleted,
+			Sequence:  1,
+		},
+		{
+			Version:    entity.WalletEventVersionOne,
+			TransferID: "limit-event",
+			WalletID:   req.WalletID,
+			Amount:     decimal.NewFromInt(50),
+			EventType:  entity.EventTypeCreditLimitChanged,
+			Status:     entity.TransferStatusCompleted,
+			Sequence:   2,
+		},
+	}, nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(2)).Return(event, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), event).Return(nil)
+
+	result, err := s.svc.CreditTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(event, result)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferBeyondCreditLimit() {
+	req := &request.CreditTransfer{
+		WalletID:    "wallet-id",
+		ReferenceID: "123",
+		TransferID:  "1234",
+		Amount:      decimal.NewFromInt(71),
+		Status:      entity.TransferStatusCompleted,
+		Currency:    "EUR",
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return([]entity.WalletEvent{
+		{
+			Version:   entity.WalletEventVersionOne,
+			WalletID:  req.WalletID,
+			Amount:    decimal.NewFromInt(20),
+			EventType: entity.EventTypeDebitTransfer,
+			Status:    entity.TransferStatusCompleted,
+			Sequence:  1,
+		},
+		{
+			Version:    entity.WalletEventVersionOne,
+			TransferID: "limit-event",
+			WalletID:   req.WalletID,
+			Amount:     decimal.NewFromInt(50),
+			EventType:  entity.EventTypeCreditLimitChanged,
+			Status:     entity.TransferStatusCompleted,
+			Sequence:   2,
+		},
+	}, nil)
+
+	_, err := s.svc.CreditTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrInsufficientBalance)
+}
+
+func (s *WalletServiceTestSuite) TestRebuildTransferProjectionSkipsCreditLimitEvent() {
+	event := &entity.WalletEvent{
+		WalletID:   "wallet-id",
+		TransferID: "event-id",
+		EventType:  entity.EventTypeCreditLimitChanged,
+		Status:     entity.TransferStatusCompleted,
+		Version:    entity.WalletEventVersionOne,
+		Sequence:   2,
+	}
+
+	result, err := s.svc.RebuildTransferProjection(context.Background(), event)
+	s.NoError(err)
+	s.Equal(entity.WalletTransfer{}, result)
+}
+
+func (s *WalletServiceTestSuite) limitedService(limits entity.VelocityLimits) *wallet.Service {
+	svc, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{}, wallet.WithVelocityLimits(limits))
+	s.Require().NoError(err)
+	return svc
+}
+
+func (s *WalletServiceTestSuite) expectFundedWallet(walletID, tier string) {
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), walletID).Return(entity.Wallet{ID: walletID, Currency: "EUR", Tier: tier}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), walletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), walletID, int64(0)).Return([]entity.WalletEvent{
+		{
+			Version:    entity.WalletEventVersionOne,
+			TransferID: "funding",
+			WalletID:   walletID,
+			Amount:     decimal.NewFromInt(1000),
+			EventType:  entity.EventTypeDebitTransfer,
+			Status:     entity.TransferStatusCompleted,
+			Sequence:   1,
+		},
+	}, nil)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferVelocityAmountExceeded() {
+	limit := entity.VelocityLimit{Kind: entity.LimitKindCreditAmount, Window: 24 * time.Hour, Max: decimal.NewFromInt(100)}
+	svc := s.limitedService(entity.VelocityLimits{entity.WalletTierStandard: {limit}})
+	req := &request.CreditTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     decimal.NewFromInt(30),
+		Status:     entity.TransferStatusCompleted,
+		Currency:   "EUR",
+	}
+
+	s.expectFundedWallet(req.WalletID, entity.WalletTierStandard)
+	s.eventRepoMock.EXPECT().ListByWalletIDSince(gomock.Any(), req.WalletID, gomock.Any()).Return([]entity.WalletEvent{
+		{
+			TransferID: "earlier",
+			WalletID:   req.WalletID,
+			Amount:     decimal.NewFromInt(80),
+			EventType:  entity.EventTypeCreditTransfer,
+			Status:     entity.TransferStatusCompleted,
+			CreatedAt:  time.Now().UTC().Add(-time.Hour),
+		},
+	}, nil)
+
+	_, err := svc.CreditTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrLimitExceeded)
+
+	var limitErr *entity.LimitExceededError
+	s.Require().ErrorAs(err, &limitErr)
+	s.Equal(limit, limitErr.Limit)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferVelocityCountExceeded() {
+	limit := entity.VelocityLimit{Kind: entity.LimitKindCreditCount, Window: time.Hour, Max: decimal.NewFromInt(1)}
+	svc := s.limitedService(entity.VelocityLimits{entity.WalletTierStandard: {
+		{Kind: entity.LimitKindCreditAmount, Window: 24 * time.Hour, Max: decimal.NewFromInt(1000)},
+		limit,
+	}})
+	req := &request.CreditTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     decimal.NewFromInt(1),
+		Status:     entity.TransferStatusCompleted,
+		Currency:   "EUR",
+	}
+
+	s.expectFundedWallet(req.WalletID, entity.WalletTierStandard)
+	s.eventRepoMock.EXPECT().ListByWalletIDSince(gomock.Any(), req.WalletID, gomock.Any()).Return([]entity.WalletEvent{
+		{
+			TransferID: "earlier",
+			WalletID:   req.WalletID,
+			Amount:     decimal.NewFromInt(1),
+			EventType:  entity.EventTypeCreditTransfer,
+			Status:     entity.TransferStatusPending,
+			CreatedAt:  time.Now().UTC().Add(-10 * time.Minute),
+		},
+	}, nil)
+
+	_, err := svc.CreditTransfer(context.Background(), req)
+
+	var limitErr *entity.LimitExceededError
+	s.Require().ErrorAs(err, &limitErr)
+	s.Equal(limit, limitErr.Limit)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferWithinVelocityLimits() {
+	svc := s.limitedService(entity.VelocityLimits{entity.WalletTierStandard: {
+		{Kind: entity.LimitKindCreditAmount, Window: 24 * time.Hour, Max: decimal.NewFromInt(100)},
+	}})
+	req := &request.CreditTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     decimal.NewFromInt(30),
+		Status:     entity.TransferStatusCompleted,
+		Currency:   "EUR",
+	}
+
+	event := entity.WalletEvent{
+		Version:    entity.WalletEventVersionOne,
+		TransferID: req.TransferID,
+		WalletID:   req.WalletID,
+		Amount:     req.Amount,
+		EventType:  entity.EventTypeCreditTransfer,
+		Status:     req.Status,
+	}
+
+	s.expectFundedWallet(req.WalletID, entity.WalletTierStandard)
+	s.eventRepoMock.EXPECT().ListByWalletIDSince(gomock.Any(), req.WalletID, gomock.Any()).Return([]entity.WalletEvent{
+		{
+			TransferID: "earlier",
+			WalletID:   req.WalletID,
+			Amount:     decimal.NewFromInt(80),
+			EventType:  entity.EventTypeCreditTransfer,
+			Status:     entity.TransferStatusPending,
+			CreatedAt:  time.Now().UTC().Add(-time.Hour),
+		},
+		{
+			TransferID: "earlier",
+			WalletID:   req.WalletID,
+			EventType:  entity.EventTypeUpdateTransferStatus,
+			Status:     entity.TransferStatusFailed,
+			CreatedAt:  time.Now().UTC().Add(-time.Minute),
+		},
+	}, nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(1)).Return(event, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), event).Return(nil)
+
+	result, err := svc.CreditTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(event, result)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferVelocityWalletOverride() {
+	limit := entity.VelocityLimit{Kind: entity.LimitKindCreditAmount, Window: 24 * time.Hour, Max: decimal.NewFromInt(10)}
+	svc := s.limitedService(entity.VelocityLimits{
+		entity.WalletTierStandard: {{Kind: entity.LimitKindCreditAmount, Window: 24 * time.Hour, Max: decimal.NewFromInt(100)}},
+		"wallet-id":               {limit},
+	})
+	req := &request.CreditTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     decimal.NewFromInt(30),
+		Status:     entity.TransferStatusCompleted,
+		Currency:   "EUR",
+	}
+
+	s.expectFundedWallet(req.WalletID, entity.WalletTierStandard)
+	s.eventRepoMock.EXPECT().ListByWalletIDSince(gomock.Any(), req.WalletID, gomock.Any()).Return([]entity.WalletEvent{}, nil)
+
+	_, err := svc.CreditTransfer(context.Background(), req)
+
+	var limitErr *entity.LimitExceededError
+	s.Require().ErrorAs(err, &limitErr)
+	s.Equal(limit, limitErr.Limit)
+}
+
+func (s *WalletServiceTestSuite) TestInternalTransferVelocityLimitExceeded() {
+	svc := s.limitedService(entity.VelocityLimits{"restricted": {
+		{Kind: entity.LimitKindCreditAmount, Window: time.Hour, Max: decimal.NewFromInt(10)},
+	}})
+	req := &request.InternalTransfer{
+		SourceWalletID:      "source-wallet-id",
+		DestinationWalletID: "destination-wallet-id",
+		TransferID:          "1234",
+		Amount:              decimal.NewFromInt(20),
+		Status:              entity.TransferStatusCompleted,
+	}
+
+	s.expectFundedWallet(req.SourceWalletID, "restricted")
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.DestinationWalletID).Return(entity.Wallet{ID: req.DestinationWalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().ListByWalletIDSince(gomock.Any(), req.SourceWalletID, gomock.Any()).Return([]entity.WalletEvent{}, nil)
+
+	_, err := svc.InternalTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrLimitExceeded)
+}
+
+func (s *WalletServiceTestSuite) TestNewServiceInvalidVelocityLimit() {
+	svc, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{}, wallet.WithVelocityLimits(entity.VelocityLimits{
+		entity.WalletTierStandard: {{Kind: entity.LimitKindCreditAmount, Max: decimal.NewFromInt(10)}},
+	}))
+	s.ErrorIs(err, entity.ErrInvalidVelocityLimit)
+	s.Nil(svc)
+}
+
+func (s *WalletServiceTestSuite) TestSetTierSuccess() {
+	svc := s.limitedService(entity.VelocityLimits{"premium": {
+		{Kind: entity.LimitKindCreditAmount, Window: 24 * time.Hour, Max: decimal.NewFromInt(5000)},
+	}})
+	req := &request.SetWalletTier{WalletID: "wallet-id", Tier: "premium"}
+	current := entity.Wallet{ID: req.WalletID, Currency: "EUR", Status: entity.WalletStatusActive, Tier: entity.WalletTierStandard}
+	updated := entity.Wallet{ID: req.WalletID, Currency: "EUR", Status: entity.WalletStatusActive, Tier: "premium"}
+	projection := entity.WalletProjection{WalletID: req.WalletID, Balance: decimal.NewFromInt(10)}
+
+	s.repoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID).Return(current, nil)
+	s.repoMock.EXPECT().UpdateTier(gomock.Any(), testutils.NewMatcher(updated, cmpopts.IgnoreFields(entity.Wallet{}, "UpdatedAt"))).Return(updated, nil)
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(projection, nil)
+
+	result, err := svc.SetTier(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.WalletBalanceProjection{Wallet: updated, WalletProjection: projection}, result)
+}
+
+func (s *WalletServiceTestSuite) TestSetTierUnknown() {
+	req := &request.SetWalletTier{WalletID: "wallet-id", Tier: "premium"}
+
+	_, err := s.svc.SetTier(context.Background(), req)
+	s.ErrorIs(err, entity.ErrUnknownWalletTier)
+}
+
+func (s *WalletServiceTestSuite) TestSetTierClosedWallet() {
+	req := &request.SetWalletTier{WalletID: "wallet-id", Tier: entity.WalletTierStandard}
+
+	s.repoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Status: entity.WalletStatusClosed}, nil)
+
+	_, err := s.svc.SetTier(context.Background(), req)
+	s.ErrorIs(err, entity.ErrWalletClosed)
+}
+
+func (s *WalletServiceTestSuite) balanceHistory(walletID string) []entity.WalletEvent {
+	start := time.Date(2024, 6, 30, 10, 0, 0, 0, time.UTC)
+
+	return []entity.WalletEvent{
+		{ID: "e1", Version: entity.WalletEventVersionOne, TransferID: "t1", WalletID: walletID, Amount: decimal.NewFromInt(100), EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Sequence: 1, CreatedAt: start},
+		{ID: "e2", Version: entity.WalletEventVersionOne, TransferID: "t2", WalletID: walletID, Amount: decimal.NewFromInt(30), EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusPending, Sequence: 2, CreatedAt: start.Add(time.Hour)},
+		{ID: "e3", Version: entity.WalletEventVersionOne, TransferID: "t2", WalletID: walletID, EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusFailed, Sequence: 3, CreatedAt: start.Add(48 * time.Hour)},
+	}
+}
+
+func (s *WalletServiceTestSuite) TestGetBalanceAsOfTimestamp() {
+	req := &request.GetBalance{WalletID: "wallet-id", AsOf: "2024-06-30T23:59:59Z"}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID}, nil)
+	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), req.WalletID).Return(s.balanceHistory(req.WalletID), nil)
+
+	result, err := s.svc.GetBalance(context.Background(), req)
+	s.NoError(err)
+	s.Equal(req.WalletID, result.WalletID)
+	s.True(decimal.NewFromInt(70).Equal(result.Balance), result.Balance.String())
+	s.True(decimal.NewFromInt(30).Equal(result.PendingCredit), result.PendingCredit.String())
+	s.Equal("e2", result.LastEventID)
+	s.Equal(int64(2), result.LastSequence)
+}
+
+func (s *WalletServiceTestSuite) TestGetBalanceAsOfEventID() {
+	req := &request.GetBalance{WalletID: "wallet-id", AsOf: "e1"}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID}, nil)
+	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), req.WalletID).Return(s.balanceHistory(req.WalletID), nil)
+
+	result, err := s.svc.GetBalance(context.Background(), req)
+	s.NoError(err)
+	s.True(decimal.NewFromInt(100).Equal(result.Balance), result.Balance.String())
+	s.True(result.PendingCredit.IsZero())
+	s.Equal(int64(1), result.LastSequence)
+}
+
+func (s *WalletServiceTestSuite) TestGetBalanceCurrent() {
+	req := &request.GetBalance{WalletID: "wallet-id"}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID}, nil)
+	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), req.WalletID).Return(s.balanceHistory(req.WalletID), nil)
+
+	result, err := s.svc.GetBalance(context.Background(), req)
+	s.NoError(err)
+	s.True(decimal.NewFromInt(100).Equal(result.Balance), result.Balance.String())
+	s.True(result.PendingCredit.IsZero())
+	s.Equal(int64(3), result.LastSequence)
+}
+
+func (s *WalletServiceTestSuite) TestGetBalanceBeforeFirstEvent() {
+	req := &request.GetBalance{WalletID: "wallet-id", AsOf: "2024-01-01T00:00:00Z"}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID}, nil)
+	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), req.WalletID).Return(s.balanceHistory(req.WalletID), nil)
+
+	result, err := s.svc.GetBalance(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.WalletProjection{WalletID: req.WalletID}, result)
+}
+
+func (s *WalletServiceTestSuite) TestGetBalanceUnknownEventID() {
+	req := &request.GetBalance{WalletID: "wallet-id", AsOf: "unknown"}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID}, nil)
+	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), req.WalletID).Return(s.balanceHistory(req.WalletID), nil)
+
+	_, err := s.svc.GetBalance(context.Background(), req)
+	s.ErrorIs(err, entity.ErrInvalidBalanceAsOf)
+}
+
+func (s *WalletServiceTestSuite) TestGetBalanceWalletNotFound() {
+	req := &request.GetBalance{WalletID: "wallet-id"}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{}, entity.ErrEntityNotFound)
+
+	_, err := s.svc.GetBalance(context.Background(), req)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func (s *WalletServiceTestSuite) expectDebit(walletID string, version int64) entity.WalletEvent {
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  "t-" + walletID,
+		ReferenceID: "ref",
+		WalletID:    walletID,
+		Amount:      decimal.NewFromInt(100),
+		EventType:   entity.EventTypeDebitTransfer,
+		Status:      entity.TransferStatusCompleted,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), walletID).Return(entity.Wallet{ID: walletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), walletID).Return(version, nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), version).Return(event, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), gomock.Any()).Return(nil)
+
+	return event
+}
+
+func batchDebit(walletID string) request.BatchTransferItem {
+	return request.BatchTransferItem{
+		Operation:   entity.BatchOperationDebit,
+		WalletID:    walletID,
+		TransferID:  "t-" + walletID,
+		ReferenceID: "ref",
+		Amount:      decimal.NewFromInt(100),
+		Currency:    "EUR",
+		Status:      entity.TransferStatusCompleted,
+	}
+}
+
+func (s *WalletServiceTestSuite) TestBatchTransfersBestEffort() {
+	req := &request.BatchTransfers{
+		Items: []request.BatchTransferItem{
+			batchDebit("wallet-1"),
+			{Operation: entity.BatchOperationComplete, WalletID: "wallet-2", TransferID: "missing"},
+			batchDebit("wallet-3"),
+		},
+	}
+
+	first := s.expectDebit("wallet-1", 1)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), "wallet-2").Return(entity.Wallet{ID: "wallet-2", Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), "wallet-2").Return(int64(1), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), "wallet-2", "missing").Return([]entity.WalletEvent{}, nil)
+	third := s.expectDebit("wallet-3", 2)
+
+	result, err := s.svc.BatchTransfers(context.Background(), req)
+	s.NoError(err)
+	s.Require().Len(result, 3)
+	s.Equal(entity.BatchTransferResult{Event: first}, result[0])
+	s.ErrorIs(result[1].Err, entity.ErrTransferNotFound)
+	s.Equal(entity.BatchTransferResult{Event: third}, result[2])
+}
+
+func (s *WalletServiceTestSuite) TestBatchTransfersAtomicItemError() {
+	req := &request.BatchTransfers{
+		Atomic: true,
+		Items:  []request.BatchTransferItem{batchDebit("wallet-1"), batchDebit("wallet-2"), batchDebit("wallet-3")},
+	}
+
+	s.expectDebit("wallet-1", 1)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), "wallet-2").Return(entity.Wallet{}, entity.ErrEntityNotFound)
+
+	result, err := s.svc.BatchTransfers(context.Background(), req)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+	s.Nil(result)
+
+	var itemErr *entity.BatchItemError
+	s.Require().ErrorAs(err, &itemErr)
+	s.Equal(1, itemErr.Index)
+}
+
+func (s *WalletServiceTestSuite) TestBatchTransfersAtomicRetriesWholeBatch() {
+	req := &request.BatchTransfers{
+		Atomic: true,
+		Items:  []request.BatchTransferItem{batchDebit("wallet-1"), batchDebit("wallet-2")},
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), "wallet-1").Return(entity.Wallet{ID: "wallet-1", Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), "wallet-1").Return(int64(1), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), gomock.Any(), int64(1)).Return(entity.WalletEvent{}, entity.ErrStreamVersionConflict)
+
+	first := s.expectDebit("wallet-1", 2) // the nested append doesn't retry on its own, the whole batch is run again
+	second := s.expectDebit("wallet-2", 1)
+
+	result, err := s.svc.BatchTransfers(context.Background(), req)
+	s.NoError(err)
+	s.Equal([]entity.BatchTransferResult{{Event: first}, {Event: second}}, result)
+}
+
+func (s *WalletServiceTestSuite) TestBatchTransfersInvalidSize() {
+	svc, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{}, wallet.WithMaxBatchSize(1))
+	s.Require().NoError(err)
+
+	_, err = svc.BatchTransfers(context.Background(), &request.BatchTransfers{})
+	s.ErrorIs(err, entity.ErrInvalidBatchSize)
+
+	_, err = svc.BatchTransfers(context.Background(), &request.BatchTransfers{Items: []request.BatchTransferItem{batchDebit("wallet-1"), batchDebit("wallet-2")}})
+	s.ErrorIs(err, entity.ErrInvalidBatchSize)
+}
+
+func (s *WalletServiceTestSuite) TestNewServiceInvalidMaxBatchSize() {
+	svc, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{}, wallet.WithMaxBatchSize(0))
+	s.ErrorIs(err, wallet.ErrInvalidMaxBatchSize)
+	s.Nil(svc)
+}
+
+func (s *WalletServiceTestSuite) TestPostLedgerEntriesStatusUpdate() {
+	event := entity.WalletEvent{
+		ID:         "event-2",
+		Version:    entity.WalletEventVersionOne,
+		TransferID: "1234",
+		WalletID:   "wallet-id",
+		EventType:  entity.EventTypeUpdateTransferStatus,
+		Status:     entity.TransferStatusCompleted,
+		Sequence:   2,
+	}
+	events := append(s.pendingTransferEvents(event.WalletID, event.TransferID), event)
+
+	s.repoMock.EXPECT().Get(gomock.Any(), event.WalletID).Return(entity.Wallet{ID: event.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), event.WalletID, event.TransferID).Return(events, nil)
+
+	expected := entity.NewLedgerPostings(event, "EUR", entity.LedgerAccountFunding, entity.WalletLedgerAccount(event.WalletID), decimal.NewFromInt(100))
+	s.ledgerRepoMock.EXPECT().CreatePostings(gomock.Any(), expected).Return(nil)
+
+	result, err := s.svc.PostLedgerEntries(context.Background(), &event)
+	s.NoError(err)
+	s.Equal(expected, result)
+}
+
+func (s *WalletServiceTestSuite) TestPostLedgerEntriesAlreadySettled() {
+	events := s.pendingTransferEvents("wallet-id", "1234")
+	events = append(events,
+		entity.WalletEvent{ID: "event-2", Version: entity.WalletEventVersionOne, TransferID: "1234", WalletID: "wallet-id", EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted, Sequence: 2},
+		entity.WalletEvent{ID: "event-3", Version: entity.WalletEventVersionOne, TransferID: "1234", WalletID: "wallet-id", EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusFailed, Sequence: 3},
+	)
+
+	s.repoMock.EXPECT().Get(gomock.Any(), "wallet-id").Return(entity.Wallet{ID: "wallet-id", Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), "wallet-id", "1234").Return(events, nil)
+	s.ledgerRepoMock.EXPECT().CreatePostings(gomock.Any(), gomock.Nil()).Return(nil)
+
+	result, err := s.svc.PostLedgerEntries(context.Background(), &events[2])
+	s.NoError(err)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestPostLedgerEntriesCreditLimitChanged() {
+	result, err := s.svc.PostLedgerEntries(context.Background(), &entity.WalletEvent{EventType: entity.EventTypeCreditLimitChanged})
+	s.NoError(err)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestPostLedgerEntriesCreateError() {
+	event := s.pendingTransferEvents("wallet-id", "1234")[0]
+	event.EventType = entity.EventTypeCreditTransfer
+
+	s.repoMock.EXPECT().Get(gomock.Any(), event.WalletID).Return(entity.Wallet{ID: event.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), event.WalletID, event.TransferID).Return([]entity.WalletEvent{event}, nil)
+	s.ledgerRepoMock.EXPECT().CreatePostings(gomock.Any(), gomock.Len(2)).Return(context.DeadlineExceeded)
+
+	_, err := s.svc.PostLedgerEntries(context.Background(), &event)
+	s.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (s *WalletServiceTestSuite) TestTrialBalance() {
+	s.ledgerRepoMock.EXPECT().AccountBalances(gomock.Any()).Return([]entity.LedgerAccountBalance{
+		{Account: entity.LedgerAccountFunding, Currency: "EUR", Balance: decimal.NewFromInt(-100)},
+		{Account: entity.LedgerAccountWallets, Currency: "EUR", Balance: decimal.NewFromInt(100)},
+		{Account: entity.LedgerAccountFunding, Currency: "USD", Balance: decimal.NewFromInt(-5)},
+		{Account: entity.LedgerAccountWallets, Currency: "USD", Balance: decimal.NewFromInt(4)},
+	}, nil)
+
+	result, err := s.svc.TrialBalance(context.Background())
+	s.NoError(err)
+	s.Len(result.Accounts, 4)
+	s.Require().Len(result.Totals, 2)
+	s.Equal(entity.Currency("EUR"), result.Totals[0].Currency)
+	s.True(result.Totals[0].Total.IsZero())
+	s.Equal(entity.Currency("USD"), result.Totals[1].Currency)
+	s.True(decimal.NewFromInt(-1).Equal(result.Totals[1].Total))
+	s.False(result.Balanced())
+}
+
+func (s *WalletServiceTestSuite) feeService() *wallet.Service {
+	svc, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{},
+		wallet.WithFees(entity.FeeWallets{"EUR": "fee-wallet-id"}, entity.FeeSchedules{entity.WalletTierStandard: {Flat: decimal.NewFromInt(1), Percent: decimal.NewFromInt(2)}}))
+	s.Require().NoError(err)
+	return svc
+}
+
+// expectAppend expects the event to be stored at expectedVersion and published, the stored event is returned as is, so its Sequence is the one the repository assigned.
+func (s *WalletServiceTestSuite) expectAppend(event entity.WalletEvent, expectedVersion int64) {
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt", "Sequence")), expectedVersion).Return(event, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), event).Return(nil)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferWithFee() {
+	svc := s.feeService()
+	req := &request.CreditTransfer{
+		WalletID:    "wallet-id",
+		ReferenceID: "123",
+		TransferID:  "1234",
+		Amount:      decimal.NewFromInt(30),
+		Status:      entity.TransferStatusPending,
+		Currency:    "EUR",
+	}
+
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.WalletID,
+		Amount:      req.Amount,
+		EventType:   entity.EventTypeCreditTransfer,
+		Status:      req.Status,
+		Fee:         decimal.RequireFromString("1.6"),
+	}
+	storedEvent := event
+	storedEvent.Sequence = 2
+
+	feeCredit := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  entity.FeeTransferID(req.TransferID),
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.WalletID,
+		Amount:      event.Fee,
+		EventType:   entity.EventTypeCreditTransfer,
+		Status:      req.Status,
+	}
+	feeDebit := feeCredit
+	feeDebit.WalletID = "fee-wallet-id"
+	feeDebit.EventType = entity.EventTypeDebitTransfer
+
+	s.expectFundedWallet(req.WalletID, entity.WalletTierStandard)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(event, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(1)).Return(storedEvent, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), storedEvent).Return(nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), "fee-wallet-id").Return(entity.Wallet{ID: "fee-wallet-id", Currency: "EUR", Status: entity.WalletStatusActive}, nil)
+	s.expectAppend(feeCredit, 2)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), "fee-wallet-id").Return(int64(7), nil)
+	s.expectAppend(feeDebit, 7)
+
+	result, err := svc.CreditTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(storedEvent, result)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferFeeInsufficientBalance() {
+	svc := s.feeService()
+	req := &request.CreditTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     decimal.NewFromInt(990),
+		Status:     entity.TransferStatusCompleted,
+		Currency:   "EUR",
+	}
+
+	s.expectFundedWallet(req.WalletID, entity.WalletTierStandard) // 1000 covers the amount but not the 20.80 fee on top of it
+
+	_, err := svc.CreditTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrInsufficientBalance)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferFeeWalletCurrencyMismatch() {
+	svc := s.feeService()
+	req := &request.CreditTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+		Amount:     decimal.NewFromInt(30),
+		Status:     entity.TransferStatusCompleted,
+		Currency:   "EUR",
+	}
+
+	s.expectFundedWallet(req.WalletID, entity.WalletTierStandard)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), gomock.Any(), int64(1)).Return(entity.WalletEvent{WalletID: req.WalletID, Fee: decimal.RequireFromString("1.6"), Sequence: 2}, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), gomock.Any()).Return(nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), "fee-wallet-id").Return(entity.Wallet{ID: "fee-wallet-id", Currency: "USD", Status: entity.WalletStatusActive}, nil)
+
+	_, err := svc.CreditTransfer(context.Background(), req)
+	s.ErrorIs(err, wallet.ErrFeeWalletCurrency)
+}
+
+func (s *WalletServiceTestSuite) TestRevertTransferRevertsFee() {
+	svc := s.feeService()
+	req := &request.RevertTransfer{
+		WalletID:    "wallet-id",
+		TransferID:  "1234",
+		ReferenceID: "123",
+	}
+	feeTransferID := entity.FeeTransferID(req.TransferID)
+
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.WalletID,
+		EventType:   entity.EventTypeUpdateTransferStatus,
+		Status:      entity.TransferStatusFailed,
+		Sequence:    5,
+	}
+	feeEvent := event
+	feeEvent.TransferID = feeTransferID
+	feeEvent.Sequence = 0
+	feeWalletEvent := feeEvent
+	feeWalletEvent.WalletID = "fee-wallet-id"
+
+	pendingCredit := func(walletID, transferID string, eventType entity.WalletEventType) []entity.WalletEvent {
+		return []entity.WalletEvent{{Version: entity.WalletEventVersionOne, TransferID: transferID, WalletID: walletID, Amount: decimal.NewFromInt(10), EventType: eventType, Status: entity.TransferStatusPending, Sequence: 1}}
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(pendingCredit(req.WalletID, req.TransferID, entity.EventTypeCreditTransfer), nil)
+	s.expectAppend(event, 4)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, feeTransferID).Return(pendingCredit(req.WalletID, feeTransferID, entity.EventTypeCreditTransfer), nil)
+	s.expectAppend(feeEvent, 5)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), "fee-wallet-id").Return(int64(9), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), "fee-wallet-id", feeTransferID).Return(pendingCredit("fee-wallet-id", feeTransferID, entity.EventTypeDebitTransfer), nil)
+	s.expectAppend(feeWalletEvent, 9)
+
+	result, err := svc.RevertTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(event, result)
+}
+
+func (s *WalletServiceTestSuite) TestExpireTransfersRevertsFee() {
+	svc := s.feeService()
+	now := time.Now().UTC()
+	expiresAt := now.Add(-time.Minute)
+	expired := entity.WalletTransfer{WalletID: "wallet-id", TransferID: "1234", ReferenceID: "ref", Status: entity.TransferStatusPending, ExpiresAt: &expiresAt}
+	feeTransferID := entity.FeeTransferID(expired.TransferID)
+
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  expired.TransferID,
+		ReferenceID: expired.ReferenceID,
+		WalletID:    expired.WalletID,
+		EventType:   entity.EventTypeUpdateTransferStatus,
+		Status:      entity.TransferStatusFailed,
+		Sequence:    5,
+	}
+	feeEvent := event
+	feeEvent.TransferID = feeTransferID
+	feeEvent.Sequence = 0
+	feeWalletEvent := feeEvent
+	feeWalletEvent.WalletID = "fee-wallet-id"
+
+	pendingCredit := func(walletID, transferID string, eventType entity.WalletEventType) []entity.WalletEvent {
+		return []entity.WalletEvent{{Version: entity.WalletEventVersionOne, TransferID: transferID, WalletID: walletID, Amount: decimal.NewFromInt(10), EventType: eventType, Status: entity.TransferStatusPending, Sequence: 1}}
+	}
+
+	s.transferRepoMock.EXPECT().ListExpired(gomock.Any(), now, uint64(10)).Return([]entity.WalletTransfer{expired}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), expired.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), expired.WalletID, expired.TransferID).Return(pendingCredit(expired.WalletID, expired.TransferID, entity.EventTypeCreditTransfer), nil)
+	s.expectAppend(event, 4)
+	s.repoMock.EXPECT().Get(gomock.Any(), expired.WalletID).Return(entity.Wallet{ID: expired.WalletID, Currency: "EUR"}, nil) // the fee wallet is picked by the wallet currency
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), expired.WalletID, feeTransferID).Return(pendingCredit(expired.WalletID, feeTransferID, entity.EventTypeCreditTransfer), nil)
+	s.expectAppend(feeEvent, 5)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), "fee-wallet-id").Return(int64(9), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), "fee-wallet-id", feeTransferID).Return(pendingCredit("fee-wallet-id", feeTransferID, entity.EventTypeDebitTransfer), nil)
+	s.expectAppend(feeWalletEvent, 9)
+
+	result, err := svc.ExpireTransfers(context.Background(), now, 10)
+	s.NoError(err)
+	s.Equal([]entity.WalletEvent{event}, result)
+}
+
+func (s *WalletServiceTestSuite) TestCompleteTransferWithoutFee() {
+	svc := s.feeService()
+	req := &request.CompleteTransfer{
+		WalletID:   "wallet-id",
+		TransferID: "1234",
+	}
+
+	event := entity.WalletEvent{
+		Version:    entity.WalletEventVersionOne,
+		TransferID: req.TransferID,
+		WalletID:   req.WalletID,
+		Amount:     decimal.NewFromInt(10),
+		EventType:  entity.EventTypeUpdateTransferStatus,
+		Status:     entity.TransferStatusCompleted,
+		Sequence:   5,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), req.WalletID).Return(int64(4), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return([]entity.WalletEvent{
+		{Version: entity.WalletEventVersionOne, TransferID: req.TransferID, WalletID: req.WalletID, Amount: decimal.NewFromInt(10), EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusPending, Sequence: 1},
+	}, nil)
+	s.expectAppend(event, 4)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, entity.FeeTransferID(req.TransferID)).Return(nil, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), "fee-wallet-id").Return(int64(9), nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), "fee-wallet-id", entity.FeeTransferID(req.TransferID)).Return(nil, nil)
+
+	result, err := svc.CompleteTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(event, result)
+}
+
+func (s *WalletServiceTestSuite) TestWithFeesMissingFeeWallet() {
+	_, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{},
+		wallet.WithFees(nil, entity.FeeSchedules{entity.WalletTierStandard: {Flat: decimal.NewFromInt(1)}}))
+	s.ErrorIs(err, wallet.ErrMissingFeeWallet)
+}
+
+func (s *WalletServiceTestSuite) TestWithFeesInvalidFeeWallets() {
+	_, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{},
+		wallet.WithFees(entity.FeeWallets{"EUR": "fee-wallet-id", "USD": "fee-wallet-id"}, entity.FeeSchedules{entity.WalletTierStandard: {Flat: decimal.NewFromInt(1)}}))
+	s.ErrorIs(err, entity.ErrInvalidFeeWallet)
+}
+
+func (s *WalletServiceTestSuite) TestCreditTransferWithoutFeeWalletForCurrency() {
+	svc := s.feeService()
+	req := &request.CreditTransfer{
+		WalletID:    "wallet-id",
+		ReferenceID: "123",
+		TransferID:  "1234",
+		Amount:      decimal.NewFromInt(30),
+		Status:      entity.TransferStatusCompleted,
+		Currency:    "USD",
+	}
+
+	event := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    req.WalletID,
+		Amount:      req.Amount,
+		EventType:   entity.EventTypeCreditTransfer,
+		Status:      req.Status,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "USD", Tier: entity.WalletTierStandard}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), req.WalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), req.WalletID, int64(0)).Return([]entity.WalletEvent{
+		{Version: entity.WalletEventVersionOne, TransferID: "funding", WalletID: req.WalletID, Amount: decimal.NewFromInt(1000), EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Sequence: 1},
+	}, nil)
+	s.expectAppend(event, 1) // the USD credit isn't charged a fee since only EUR fees are collected
+
+	result, err := svc.CreditTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(event, result)
+}
+
+func (s *WalletServiceTestSuite) TestScheduleCreditTransferSuccess() {
+	executeAt := time.Now().UTC().Add(time.Hour)
+	req := &request.CreditTransfer{
+		WalletID:    "wallet-id",
+		ReferenceID: "123",
+		TransferID:  "1234",
+		Amount:      decimal.NewFromInt(5000), // more than the balance, it's only checked once the transfer is executed
+		Currency:    "EUR",
+		Status:      entity.TransferStatusCompleted,
+		ExecuteAt:   &executeAt,
+	}
+
+	expected := entity.ScheduledTransfer{
+		WalletID:       req.WalletID,
+		TransferID:     req.TransferID,
+		ReferenceID:    req.ReferenceID,
+		Direction:      entity.TransferDirectionCredit,
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+		TransferStatus: req.Status,
+		ExecuteAt:      executeAt.Truncate(time.Microsecond),
+		Status:         entity.ScheduleStatusScheduled,
+	}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(nil, nil)
+	s.scheduledRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(expected, cmpopts.IgnoreFields(entity.ScheduledTransfer{}, "ID", "CreatedAt", "UpdatedAt"))).
+		DoAndReturn(func(_ context.Context, transfer entity.ScheduledTransfer) (entity.ScheduledTransfer, error) {
+			return transfer, nil
+		})
+
+	result, err := s.svc.ScheduleCreditTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.ScheduleStatusScheduled, result.Status)
+	s.NotEmpty(result.ID)
+}
+
+func (s *WalletServiceTestSuite) TestScheduleDebitTransferInvalidExecuteAt() {
+	past := time.Now().UTC().Add(-time.Minute)
+	executeAt := time.Now().UTC().Add(time.Hour)
+	expiresAt := executeAt.Add(-time.Minute)
+
+	tests := []struct {
+		name string
+		req  *request.DebitTransfer
+	}{
+		{
+			name: "in the past",
+			req:  &request.DebitTransfer{WalletID: "wallet-id", TransferID: "1234", Amount: decimal.NewFromInt(10), Currency: "EUR", Status: entity.TransferStatusCompleted, ExecuteAt: &past},
+		},
+		{
+			name: "after the expiry",
+			req:  &request.DebitTransfer{WalletID: "wallet-id", TransferID: "1234", Amount: decimal.NewFromInt(10), Currency: "EUR", Status: entity.TransferStatusPending, ExpiresAt: &expiresAt, ExecuteAt: &executeAt},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			_, err := s.svc.ScheduleDebitTransfer(context.Background(), tt.req)
+			s.ErrorIs(err, entity.ErrInvalidExecuteAt)
+		})
+	}
+}
+
+func (s *WalletServiceTestSuite) TestScheduleDebitTransferAlreadyExists() {
+	executeAt := time.Now().UTC().Add(time.Hour)
+	req := &request.DebitTransfer{WalletID: "wallet-id", TransferID: "1234", Amount: decimal.NewFromInt(10), Currency: "EUR", Status: entity.TransferStatusCompleted, ExecuteAt: &executeAt}
+
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), req.WalletID, req.TransferID).Return(s.pendingTransferEvents(req.WalletID, req.TransferID), nil)
+
+	_, err := s.svc.ScheduleDebitTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrTransferAlreadyExists)
+}
+
+func (s *WalletServiceTestSuite) scheduledTransfer(direction entity.TransferDirection, amount int64) entity.ScheduledTransfer {
+	return entity.ScheduledTransfer{
+		ID:             "scheduled-id",
+		WalletID:       "wallet-id",
+		TransferID:     "1234",
+		ReferenceID:    "ref",
+		Direction:      direction,
+		Amount:         decimal.NewFromInt(amount),
+		Currency:       "EUR",
+		TransferStatus: entity.TransferStatusCompleted,
+		ExecuteAt:      time.Now().UTC().Add(-time.Minute),
+		Status:         entity.ScheduleStatusScheduled,
+	}
+}
+
+func (s *WalletServiceTestSuite) TestExecuteScheduledTransfersSuccess() {
+	now := time.Now().UTC()
+	scheduled := s.scheduledTransfer(entity.TransferDirectionDebit, 100)
+
+	event := entity.WalletEvent{
+		ID:          "event-id",
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  scheduled.TransferID,
+		ReferenceID: scheduled.ReferenceID,
+		WalletID:    scheduled.WalletID,
+		Amount:      scheduled.Amount,
+		EventType:   entity.EventTypeDebitTransfer,
+		Status:      entity.TransferStatusCompleted,
+	}
+
+	executed := scheduled
+	executed.Status = entity.ScheduleStatusExecuted
+	executed.EventID = &event.ID
+
+	s.scheduledRepoMock.EXPECT().ListDue(gomock.Any(), now, uint64(10)).Return([]entity.ScheduledTransfer{scheduled}, nil)
+	s.scheduledRepoMock.EXPECT().GetForUpdate(gomock.Any(), scheduled.WalletID, scheduled.TransferID).Return(scheduled, nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), scheduled.WalletID, scheduled.TransferID).Return(nil, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), scheduled.WalletID).Return(entity.Wallet{ID: scheduled.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), scheduled.WalletID).Return(int64(3), nil)
+	s.expectAppend(event, 3)
+	s.scheduledRepoMock.EXPECT().Update(gomock.Any(), testutils.NewMatcher(executed, cmpopts.IgnoreFields(entity.ScheduledTransfer{}, "UpdatedAt"))).Return(nil)
+
+	result, err := s.svc.ExecuteScheduledTransfers(context.Background(), now, 10)
+	s.NoError(err)
+	s.Require().Len(result, 1)
+	s.Equal(entity.ScheduleStatusExecuted, result[0].Status)
+	s.Equal(&event.ID, result[0].EventID)
+}
+
+func (s *WalletServiceTestSuite) TestExecuteScheduledTransfersMetadata() {
+	now := time.Now().UTC()
+	scheduled := s.scheduledTransfer(entity.TransferDirectionDebit, 100)
+	scheduled.Metadata = entity.Metadata{"order_id": "1"}
+
+	event := entity.WalletEvent{
+		ID:          "event-id",
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  scheduled.TransferID,
+		ReferenceID: scheduled.ReferenceID,
+		WalletID:    scheduled.WalletID,
+		Amount:      scheduled.Amount,
+		EventType:   entity.EventTypeDebitTransfer,
+		Status:      entity.TransferStatusCompleted,
+		Metadata:    scheduled.Metadata,
+	}
+
+	s.scheduledRepoMock.EXPECT().ListDue(gomock.Any(), now, uint64(10)).Return([]entity.ScheduledTransfer{scheduled}, nil)
+	s.scheduledRepoMock.EXPECT().GetForUpdate(gomock.Any(), scheduled.WalletID, scheduled.TransferID).Return(scheduled, nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), scheduled.WalletID, scheduled.TransferID).Return(nil, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), scheduled.WalletID).Return(entity.Wallet{ID: scheduled.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), scheduled.WalletID).Return(int64(3), nil)
+	s.expectAppend(event, 3)
+	s.scheduledRepoMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	result, err := s.svc.ExecuteScheduledTransfers(context.Background(), now, 10)
+	s.NoError(err)
+	s.Require().Len(result, 1)
+	s.Equal(entity.ScheduleStatusExecuted, result[0].Status)
+}
+
+func (s *WalletServiceTestSuite) TestExecuteScheduledTransfersInsufficientBalance() {
+	now := time.Now().UTC()
+	scheduled := s.scheduledTransfer(entity.TransferDirectionCredit, 2000)
+
+	failed := scheduled
+	failed.Status = entity.ScheduleStatusFailed
+	failed.FailureReason = entity.ErrInsufficientBalance.Error()
+
+	s.scheduledRepoMock.EXPECT().ListDue(gomock.Any(), now, uint64(10)).Return([]entity.ScheduledTransfer{scheduled}, nil)
+	s.scheduledRepoMock.EXPECT().GetForUpdate(gomock.Any(), scheduled.WalletID, scheduled.TransferID).Return(scheduled, nil).Times(2)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), scheduled.WalletID, scheduled.TransferID).Return(nil, nil)
+	s.expectFundedWallet(scheduled.WalletID, entity.WalletTierStandard)
+	s.scheduledRepoMock.EXPECT().Update(gomock.Any(), testutils.NewMatcher(failed, cmpopts.IgnoreFields(entity.ScheduledTransfer{}, "UpdatedAt"))).Return(nil)
+
+	result, err := s.svc.ExecuteScheduledTransfers(context.Background(), now, 10)
+	s.NoError(err)
+	s.Require().Len(result, 1)
+	s.Equal(entity.ScheduleStatusFailed, result[0].Status)
+}
+
+func (s *WalletServiceTestSuite) TestExecuteScheduledTransfersSkipsCancelled() {
+	now := time.Now().UTC()
+	scheduled := s.scheduledTransfer(entity.TransferDirectionDebit, 100)
+	cancelled := scheduled
+	cancelled.Status = entity.ScheduleStatusCancelled // cancelled after it was listed
+
+	s.scheduledRepoMock.EXPECT().ListDue(gomock.Any(), now, uint64(10)).Return([]entity.ScheduledTransfer{scheduled}, nil)
+	s.scheduledRepoMock.EXPECT().GetForUpdate(gomock.Any(), scheduled.WalletID, scheduled.TransferID).Return(cancelled, nil)
+
+	result, err := s.svc.ExecuteScheduledTransfers(context.Background(), now, 10)
+	s.NoError(err)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestExecuteScheduledTransfersContinuesAfterError() {
+	now := time.Now().UTC()
+	first := s.scheduledTransfer(entity.TransferDirectionDebit, 100)
+	second := s.scheduledTransfer(entity.TransferDirectionDebit, 100)
+	second.WalletID = "wallet-2"
+	second.Status = entity.ScheduleStatusExecuted
+
+	s.scheduledRepoMock.EXPECT().ListDue(gomock.Any(), now, uint64(10)).Return([]entity.ScheduledTransfer{first, second}, nil)
+	s.scheduledRepoMock.EXPECT().GetForUpdate(gomock.Any(), first.WalletID, first.TransferID).Return(entity.ScheduledTransfer{}, context.DeadlineExceeded)
+	s.scheduledRepoMock.EXPECT().GetForUpdate(gomock.Any(), second.WalletID, second.TransferID).Return(second, nil)
+
+	result, err := s.svc.ExecuteScheduledTransfers(context.Background(), now, 10)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) TestCancelScheduledTransferSuccess() {
+	req := &request.CancelScheduledTransfer{WalletID: "wallet-id", TransferID: "1234"}
+	scheduled := s.scheduledTransfer(entity.TransferDirectionDebit, 100)
+
+	cancelled := scheduled
+	cancelled.Status = entity.ScheduleStatusCancelled
+
+	s.scheduledRepoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID, req.TransferID).Return(scheduled, nil)
+	s.scheduledRepoMock.EXPECT().Update(gomock.Any(), testutils.NewMatcher(cancelled, cmpopts.IgnoreFields(entity.ScheduledTransfer{}, "UpdatedAt"))).Return(nil)
+
+	result, err := s.svc.CancelScheduledTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.ScheduleStatusCancelled, result.Status)
+}
+
+func (s *WalletServiceTestSuite) TestCancelScheduledTransferNotScheduled() {
+	req := &request.CancelScheduledTransfer{WalletID: "wallet-id", TransferID: "1234"}
+	executed := s.scheduledTransfer(entity.TransferDirectionDebit, 100)
+	executed.Status = entity.ScheduleStatusExecuted
+
+	s.scheduledRepoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID, req.TransferID).Return(executed, nil)
+
+	_, err := s.svc.CancelScheduledTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrTransferNotScheduled)
+}
+
+func (s *WalletServiceTestSuite) TestCreateRecurringTransferSuccess() {
+	startAt := time.Now().UTC().Add(time.Hour)
+	req := &request.CreateRecurringTransfer{
+		WalletID:    "wallet-id",
+		ReferenceID: "rent",
+		Direction:   entity.TransferDirectionCredit,
+		Amount:      decimal.NewFromInt(500),
+		Currency:    "EUR",
+		Rule:        "FREQ=WEEKLY",
+		StartAt:     &startAt,
+	}
+
+	nextRunAt := startAt.Truncate(time.Microsecond)
+	expected := entity.RecurringTransfer{
+		WalletID:    req.WalletID,
+		ReferenceID: req.ReferenceID,
+		Direction:   req.Direction,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Rule:        "FREQ=WEEKLY;INTERVAL=1",
+		StartAt:     nextRunAt,
+		NextRunAt:   &nextRunAt,
+		Status:      entity.RecurringStatusActive,
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.recurringRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(expected, cmpopts.IgnoreFields(entity.RecurringTransfer{}, "ID", "CreatedAt", "UpdatedAt"))).
+		DoAndReturn(func(_ context.Context, transfer entity.RecurringTransfer) (entity.RecurringTransfer, error) {
+			return transfer, nil
+		})
+
+	result, err := s.svc.CreateRecurringTransfer(context.Background(), req)
+	s.NoError(err)
+	s.NotEmpty(result.ID)
+	s.Equal(&nextRunAt, result.NextRunAt)
+}
+
+func (s *WalletServiceTestSuite) TestCreateRecurringTransferInvalid() {
+	past := time.Now().UTC().Add(-time.Minute)
+	startAt := time.Now().UTC().Add(time.Hour)
+	endAt := startAt.Add(-time.Minute)
+
+	tests := []struct {
+		name string
+		req  *request.CreateRecurringTransfer
+		err  error
+	}{
+		{
+			name: "invalid rule",
+			req:  &request.CreateRecurringTransfer{WalletID: "wallet-id", Direction: entity.TransferDirectionDebit, Amount: decimal.NewFromInt(10), Currency: "EUR", Rule: "FREQ=HOURLY"},
+			err:  entity.ErrInvalidRecurrenceRule,
+		},
+		{
+			name: "start in the past",
+			req:  &request.CreateRecurringTransfer{WalletID: "wallet-id", Direction: entity.TransferDirectionDebit, Amount: decimal.NewFromInt(10), Currency: "EUR", Rule: "FREQ=DAILY", StartAt: &past},
+			err:  entity.ErrInvalidRecurrenceStart,
+		},
+		{
+			name: "end before the first occurrence",
+			req:  &request.CreateRecurringTransfer{WalletID: "wallet-id", Direction: entity.TransferDirectionDebit, Amount: decimal.NewFromInt(10), Currency: "EUR", Rule: "FREQ=DAILY", StartAt: &startAt, EndAt: &endAt},
+			err:  entity.ErrInvalidRecurrenceEnd,
+		},
+		{
+			name: "negative amount",
+			req:  &request.CreateRecurringTransfer{WalletID: "wallet-id", Direction: entity.TransferDirectionDebit, Amount: decimal.NewFromInt(-10), Currency: "EUR", Rule: "FREQ=DAILY"},
+			err:  entity.ErrNegativeAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			_, err := s.svc.CreateRecurringTransfer(context.Background(), tt.req)
+			s.ErrorIs(err, tt.err)
+		})
+	}
+}
+
+func (s *WalletServiceTestSuite) recurringTransfer(direction entity.TransferDirection, amount int64) entity.RecurringTransfer {
+	startAt := time.Now().UTC().Add(-time.Minute).Truncate(time.Microsecond)
+
+	return entity.RecurringTransfer{
+		ID:          "recurring-id",
+		WalletID:    "wallet-id",
+		ReferenceID: "ref",
+		Direction:   direction,
+		Amount:      decimal.NewFromInt(amount),
+		Currency:    "EUR",
+		Rule:        "FREQ=DAILY;INTERVAL=1",
+		StartAt:     startAt,
+		NextRunAt:   &startAt,
+		Status:      entity.RecurringStatusActive,
+	}
+}
+
+func (s *WalletServiceTestSuite) TestUpdateRecurringTransferFinished() {
+	finished := s.recurringTransfer(entity.TransferDirectionDebit, 100)
+	finished.Status = entity.RecurringStatusFinished
+	finished.NextRunAt = nil
+
+	req := &request.UpdateRecurringTransfer{WalletID: "wallet-id", RecurringID: finished.ID, Amount: decimal.NewFromInt(10), Rule: finished.Rule, Status: entity.RecurringStatusActive}
+
+	s.recurringRepoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID, req.RecurringID).Return(finished, nil)
+
+	_, err := s.svc.UpdateRecurringTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrRecurrenceFinished)
+}
+
+func (s *WalletServiceTestSuite) TestUpdateRecurringTransferInvalidStatus() {
+	req := &request.UpdateRecurringTransfer{WalletID: "wallet-id", RecurringID: "recurring-id", Amount: decimal.NewFromInt(10), Rule: "FREQ=DAILY", Status: entity.RecurringStatusFinished}
+
+	_, err := s.svc.UpdateRecurringTransfer(context.Background(), req)
+	s.ErrorIs(err, entity.ErrInvalidRecurringStatus)
+}
+
+func (s *WalletServiceTestSuite) TestUpdateRecurringTransferResumeSkipsMissed() {
+	paused := s.recurringTransfer(entity.TransferDirectionDebit, 100)
+	paused.StartAt = paused.StartAt.AddDate(0, 0, -3)
+	missed := paused.StartAt
+	paused.NextRunAt = &missed
+	paused.Status = entity.RecurringStatusPaused
+
+	req := &request.UpdateRecurringTransfer{WalletID: "wallet-id", RecurringID: paused.ID, Amount: decimal.NewFromInt(20), Rule: paused.Rule, Status: entity.RecurringStatusActive}
+
+	nextRunAt := paused.StartAt.AddDate(0, 0, 4)
+	expected := paused
+	expected.Amount = req.Amount
+	expected.Occurrence = 4
+	expected.NextRunAt = &nextRunAt
+	expected.Status = entity.RecurringStatusActive
+
+	s.recurringRepoMock.EXPECT().GetForUpdate(gomock.Any(), req.WalletID, req.RecurringID).Return(paused, nil)
+	s.repoMock.EXPECT().Get(gomock.Any(), req.WalletID).Return(entity.Wallet{ID: req.WalletID, Currency: "EUR"}, nil)
+	s.recurringRepoMock.EXPECT().Update(gomock.Any(), testutils.NewMatcher(expected, cmpopts.IgnoreFields(entity.RecurringTransfer{}, "UpdatedAt"))).Return(nil)
+
+	result, err := s.svc.UpdateRecurringTransfer(context.Background(), req)
+	s.NoError(err)
+	s.Equal(&nextRunAt, result.NextRunAt)
+}
+
+func (s *WalletServiceTestSuite) TestExecuteRecurringTransfersSuccess() {
+	now := time.Now().UTC()
+	recurring := s.recurringTransfer(entity.TransferDirectionDebit, 100)
+	transferID := entity.RecurringTransferID(recurring.ID, *recurring.NextRunAt)
+
+	event := entity.WalletEvent{
+		ID:          "event-id",
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  transferID,
+		ReferenceID: recurring.ReferenceID,
+		WalletID:    recurring.WalletID,
+		Amount:      recurring.Amount,
+		EventType:   entity.EventTypeDebitTransfer,
+		Status:      entity.TransferStatusCompleted,
+	}
+
+	nextRunAt := recurring.StartAt.AddDate(0, 0, 1)
+	advanced := recurring
+	advanced.Occurrence = 1
+	advanced.NextRunAt = &nextRunAt
+	advanced.LastTransferID = transferID
+
+	s.recurringRepoMock.EXPECT().ListDue(gomock.Any(), now, uint64(10)).Return([]entity.RecurringTransfer{recurring}, nil)
+	s.recurringRepoMock.EXPECT().GetForUpdate(gomock.Any(), recurring.WalletID, recurring.ID).Return(recurring, nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), recurring.WalletID, transferID).Return(nil, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), recurring.WalletID).Return(entity.Wallet{ID: recurring.WalletID, Currency: "EUR"}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), recurring.WalletID).Return(int64(3), nil)
+	s.expectAppend(event, 3)
+	s.recurringRepoMock.EXPECT().Update(gomock.Any(), testutils.NewMatcher(advanced, cmpopts.IgnoreFields(entity.RecurringTransfer{}, "UpdatedAt"))).Return(nil)
+
+	result, err := s.svc.ExecuteRecurringTransfers(context.Background(), now, 10)
+	s.NoError(err)
+	s.Require().Len(result, 1)
+	s.Equal(transferID, result[0].LastTransferID)
+	s.Equal(&nextRunAt, result[0].NextRunAt)
+}
+
+func (s *WalletServiceTestSuite) TestExecuteRecurringTransfersAlreadyWritten() {
+	now := time.Now().UTC()
+	recurring := s.recurringTransfer(entity.TransferDirectionDebit, 100)
+	transferID := entity.RecurringTransferID(recurring.ID, *recurring.NextRunAt)
+
+	nextRunAt := recurring.StartAt.AddDate(0, 0, 1)
+	advanced := recurring
+	advanced.Occurrence = 1
+	advanced.NextRunAt = &nextRunAt
+	advanced.LastTransferID = transferID
+
+	s.recurringRepoMock.EXPECT().ListDue(gomock.Any(), now, uint64(10)).Return([]entity.RecurringTransfer{recurring}, nil)
+	s.recurringRepoMock.EXPECT().GetForUpdate(gomock.Any(), recurring.WalletID, recurring.ID).Return(recurring, nil)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), recurring.WalletID, transferID).Return(s.pendingTransferEvents(recurring.WalletID, transferID), nil)
+	s.recurringRepoMock.EXPECT().Update(gomock.Any(), testutils.NewMatcher(advanced, cmpopts.IgnoreFields(entity.RecurringTransfer{}, "UpdatedAt"))).Return(nil)
+
+	result, err := s.svc.ExecuteRecurringTransfers(context.Background(), now, 10)
+	s.NoError(err)
+	s.Require().Len(result, 1)
+	s.Equal(transferID, result[0].LastTransferID)
+}
+
+func (s *WalletServiceTestSuite) TestExecuteRecurringTransfersInsufficientBalance() {
+	now := time.Now().UTC()
+	recurring := s.recurringTransfer(entity.TransferDirectionCredit, 2000)
+	transferID := entity.RecurringTransferID(recurring.ID, *recurring.NextRunAt)
+
+	nextRunAt := recurring.StartAt.AddDate(0, 0, 1)
+	skipped := recurring
+	skipped.Occurrence = 1
+	skipped.NextRunAt = &nextRunAt
+	skipped.LastFailureReason = entity.ErrInsufficientBalance.Error()
+
+	s.recurringRepoMock.EXPECT().ListDue(gomock.Any(), now, uint64(10)).Return([]entity.RecurringTransfer{recurring}, nil)
+	s.recurringRepoMock.EXPECT().GetForUpdate(gomock.Any(), recurring.WalletID, recurring.ID).Return(recurring, nil).Times(2)
+	s.eventRepoMock.EXPECT().ListByTransferID(gomock.Any(), recurring.WalletID, transferID).Return(nil, nil)
+	s.expectFundedWallet(recurring.WalletID, entity.WalletTierStandard)
+	s.recurringRepoMock.EXPECT().Update(gomock.Any(), testutils.NewMatcher(skipped, cmpopts.IgnoreFields(entity.RecurringTransfer{}, "UpdatedAt"))).Return(nil)
+
+	result, err := s.svc.ExecuteRecurringTransfers(context.Background(), now, 10)
+	s.NoError(err)
+	s.Require().Len(result, 1)
+	s.Empty(result[0].LastTransferID)
+	s.Equal(entity.ErrInsufficientBalance.Error(), result[0].LastFailureReason)
+}
+
+func (s *WalletServiceTestSuite) TestExecuteRecurringTransfersSkipsPaused() {
+	now := time.Now().UTC()
+	recurring := s.recurringTransfer(entity.TransferDirectionDebit, 100)
+	paused := recurring
+	paused.Status = entity.RecurringStatusPaused // paused after it was listed
+
+	s.recurringRepoMock.EXPECT().ListDue(gomock.Any(), now, uint64(10)).Return([]entity.RecurringTransfer{recurring}, nil)
+	s.recurringRepoMock.EXPECT().GetForUpdate(gomock.Any(), recurring.WalletID, recurring.ID).Return(paused, nil)
+
+	result, err := s.svc.ExecuteRecurringTransfers(context.Background(), now, 10)
+	s.NoError(err)
+	s.Empty(result)
+}
+
+func (s *WalletServiceTestSuite) fxService() *wallet.Service {
+	svc, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{},
+		wallet.WithFXQuotes(s.fxQuoteRepoMock),
+		wallet.WithFX(wallet.NewStaticRateProvider(entity.FXRates{{From: "EUR", To: "USD"}: decimal.RequireFromString("1.0845")}), time.Minute))
+	s.Require().NoError(err)
+
+	return svc
+}
+
+func (s *WalletServiceTestSuite) TestCreateFXQuoteSuccess() {
+	req := &request.CreateFXQuote{
+		SourceWalletID: "source-wallet-id",
+		TargetWalletID: "target-wallet-id",
+		Amount:         decimal.RequireFromString("10.55"),
+	}
+
+	quote := entity.FXQuote{
+		SourceWalletID: req.SourceWalletID,
+		TargetWalletID: req.TargetWalletID,
+		SourceCurrency: "EUR",
+		TargetCurrency: "USD",
+		SourceAmount:   req.Amount,
+		TargetAmount:   decimal.RequireFromString("11.44"), // 11.441475 rounded to cents
+		Rate:           decimal.RequireFromString("1.0845"),
+		Status:         entity.FXQuoteStatusOpen,
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.SourceWalletID).Return(entity.Wallet{ID: req.SourceWalletID, Currency: "EUR"}, nil)
+	s.repoMock.EXPECT().Get(gomock.Any(), req.TargetWalletID).Return(entity.Wallet{ID: req.TargetWalletID, Currency: "USD"}, nil)
+	s.fxQuoteRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(quote, cmpopts.IgnoreFields(entity.FXQuote{}, "ID", "ExpiresAt", "CreatedAt", "UpdatedAt"))).
+		DoAndReturn(func(_ context.Context, quote entity.FXQuote) (entity.FXQuote, error) {
+			s.Equal(time.Minute, quote.ExpiresAt.Sub(quote.CreatedAt))
+			return quote, nil
+		})
+
+	result, err := s.fxService().CreateFXQuote(context.Background(), req)
+	s.NoError(err)
+	s.NotEmpty(result.ID)
+	s.True(quote.TargetAmount.Equal(result.TargetAmount), result.TargetAmount.String())
+}
+
+func (s *WalletServiceTestSuite) TestCreateFXQuoteInverseRate() {
+	req := &request.CreateFXQuote{
+		SourceWalletID: "source-wallet-id",
+		TargetWalletID: "target-wallet-id",
+		Amount:         decimal.NewFromInt(100),
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), req.SourceWalletID).Return(entity.Wallet{ID: req.SourceWalletID, Currency: "USD"}, nil)
+	s.repoMock.EXPECT().Get(gomock.Any(), req.TargetWalletID).Return(entity.Wallet{ID: req.TargetWalletID, Currency: "EUR"}, nil)
+	s.fxQuoteRepoMock.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, quote entity.FXQuote) (entity.FXQuote, error) {
+		return quote, nil
+	})
+
+	result, err := s.fxService().CreateFXQuote(context.Background(), req)
+	s.NoError(err)
+	s.True(decimal.RequireFromString("92.21").Equal(result.TargetAmount), result.TargetAmount.String())
+}
+
+func (s *WalletServiceTestSuite) TestCreateFXQuoteErrors() {
+	tests := []struct {
+		name           string
+		amount         decimal.Decimal
+		targetCurrency entity.Currency
+		targetStatus   entity.WalletStatus
+		err            error
+	}{
+		{name: "same currency", amount: decimal.NewFromInt(10), targetCurrency: "EUR", err: entity.ErrFXSameCurrency},
+		{name: "no rate", amount: decimal.NewFromInt(10), targetCurrency: "GBP", err: entity.ErrFXRateNotFound},
+		{name: "more decimal places than the currency", amount: decimal.RequireFromString("10.001"), targetCurrency: "USD", err: entity.ErrInvalidAmountScale},
+		{name: "converts to zero", amount: decimal.NewFromInt(0), targetCurrency: "USD", err: entity.ErrInvalidFXAmount},
+		{name: "closed target wallet", amount: decimal.NewFromInt(10), targetCurrency: "USD", targetStatus: entity.WalletStatusClosed, err: entity.ErrWalletClosed},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := &request.CreateFXQuote{SourceWalletID: "source-wallet-id", TargetWalletID: "target-wallet-id", Amount: tt.amount}
+
+			s.repoMock.EXPECT().Get(gomock.Any(), req.SourceWalletID).Return(entity.Wallet{ID: req.SourceWalletID, Currency: "EUR"}, nil)
+			s.repoMock.EXPECT().Get(gomock.Any(), req.TargetWalletID).Return(entity.Wallet{ID: req.TargetWalletID, Currency: tt.targetCurrency, Status: tt.targetStatus}, nil)
+
+			_, err := s.fxService().CreateFXQuote(context.Background(), req)
+			s.ErrorIs(err, tt.err)
+		})
+	}
+}
+
+func (s *WalletServiceTestSuite) TestCreateFXQuoteSameWallet() {
+	_, err := s.svc.CreateFXQuote(context.Background(), &request.CreateFXQuote{SourceWalletID: "wallet-id", TargetWalletID: "wallet-id", Amount: decimal.NewFromInt(10)})
+	s.ErrorIs(err, entity.ErrSameWalletTransfer)
+
+	_, err = s.svc.CreateFXQuote(context.Background(), &request.CreateFXQuote{SourceWalletID: "source-wallet-id", TargetWalletID: "target-wallet-id", Amount: decimal.NewFromInt(-10)})
+	s.ErrorIs(err, entity.ErrNegativeAmount)
+}
+
+func (s *WalletServiceTestSuite) TestWithFXInvalid() {
+	_, err := wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{},
+		wallet.WithFX(nil, time.Minute))
+	s.ErrorIs(err, wallet.ErrMissingFXRateProvider)
+
+	_, err = wallet.NewService(s.repoMock, s.projectionRepoMock, s.eventRepoMock, s.snapshotRepoMock, s.transferRepoMock, s.internalTransferRepoMock, s.ledgerRepoMock, s.refundRepoMock, s.publisherMock, testutils.NoopTransactionManager{},
+		wallet.WithFX(wallet.NewStaticRateProvider(entity.FXRates{}), 0))
+	s.ErrorIs(err, wallet.ErrInvalidFXQuoteTTL)
+}
+
+func (s *WalletServiceTestSuite) openFXQuote() entity.FXQuote {
+	now := time.Now().UTC()
+
+	return entity.FXQuote{
+		ID:             "quote-id",
+		SourceWalletID: "source-wallet-id",
+		TargetWalletID: "target-wallet-id",
+		SourceCurrency: "EUR",
+		TargetCurrency: "USD",
+		SourceAmount:   decimal.NewFromInt(100),
+		TargetAmount:   decimal.RequireFromString("108.45"),
+		Rate:           decimal.RequireFromString("1.0845"),
+		Status:         entity.FXQuoteStatusOpen,
+		ExpiresAt:      now.Add(time.Minute),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+func (s *WalletServiceTestSuite) TestExecuteFXQuoteSuccess() {
+	req := &request.ExecuteFXQuote{QuoteID: "quote-id", TransferID: "1234", ReferenceID: "123"}
+	quote := s.openFXQuote()
+
+	creditEvent := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    quote.SourceWalletID,
+		Amount:      quote.SourceAmount,
+		EventType:   entity.EventTypeCreditTransfer,
+		Status:      entity.TransferStatusCompleted,
+		FXRate:      &quote.Rate,
+	}
+
+	debitEvent := entity.WalletEvent{
+		Version:     entity.WalletEventVersionOne,
+		TransferID:  req.TransferID,
+		ReferenceID: req.ReferenceID,
+		WalletID:    quote.TargetWalletID,
+		Amount:      quote.TargetAmount,
+		EventType:   entity.EventTypeDebitTransfer,
+		Status:      entity.TransferStatusCompleted,
+		FXRate:      &quote.Rate,
+	}
+
+	executed := quote
+	executed.Status = entity.FXQuoteStatusExecuted
+	executed.TransferID = req.TransferID
+	executed.ReferenceID = req.ReferenceID
+
+	s.fxQuoteRepoMock.EXPECT().GetForUpdate(gomock.Any(), req.QuoteID).Return(quote, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), quote.SourceWalletID).Return(entity.Wallet{ID: quote.SourceWalletID, Currency: "EUR"}, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), quote.TargetWalletID).Return(entity.Wallet{ID: quote.TargetWalletID, Currency: "USD"}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), quote.SourceWalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), quote.SourceWalletID, int64(0)).Return([]entity.WalletEvent{
+		{
+			Version:   entity.WalletEventVersionOne,
+			WalletID:  quote.SourceWalletID,
+			Amount:    decimal.NewFromInt(100),
+			EventType: entity.EventTypeDebitTransfer,
+			Status:    entity.TransferStatusCompleted,
+			Sequence:  3,
+		},
+	}, nil)
+	s.eventRepoMock.EXPECT().GetStreamVersion(gomock.Any(), quote.TargetWalletID).Return(int64(7), nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(creditEvent, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(3)).Return(creditEvent, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), creditEvent).Return(nil)
+	s.eventRepoMock.EXPECT().Create(gomock.Any(), testutils.NewMatcher(debitEvent, cmpopts.IgnoreFields(entity.WalletEvent{}, "ID", "CreatedAt")), int64(7)).Return(debitEvent, nil)
+	s.publisherMock.EXPECT().PublishCreated(gomock.Any(), debitEvent).Return(nil)
+	s.fxQuoteRepoMock.EXPECT().Update(gomock.Any(), testutils.NewMatcher(executed, cmpopts.IgnoreFields(entity.FXQuote{}, "UpdatedAt"))).Return(nil)
+
+	result, err := s.svc.ExecuteFXQuote(context.Background(), req)
+	s.NoError(err)
+	s.Equal(entity.FXQuoteStatusExecuted, result.Status)
+	s.Equal(req.TransferID, result.TransferID)
+	s.Equal(creditEvent, result.CreditEvent)
+	s.Equal(debitEvent, result.DebitEvent)
+}
+
+func (s *WalletServiceTestSuite) TestExecuteFXQuoteInsufficientBalance() {
+	req := &request.ExecuteFXQuote{QuoteID: "quote-id", TransferID: "1234"}
+	quote := s.openFXQuote()
+
+	s.fxQuoteRepoMock.EXPECT().GetForUpdate(gomock.Any(), req.QuoteID).Return(quote, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), quote.SourceWalletID).Return(entity.Wallet{ID: quote.SourceWalletID, Currency: "EUR"}, nil)
+	s.repoMock.EXPECT().GetForShare(gomock.Any(), quote.TargetWalletID).Return(entity.Wallet{ID: quote.TargetWalletID, Currency: "USD"}, nil)
+	s.snapshotRepoMock.EXPECT().GetLatest(gomock.Any(), quote.SourceWalletID).Return(entity.WalletSnapshot{}, entity.ErrEntityNotFound)
+	s.eventRepoMock.EXPECT().ListByWalletIDAfter(gomock.Any(), quote.SourceWalletID, int64(0)).Return([]entity.WalletEvent{}, nil)
+
+	_, err := s.svc.ExecuteFXQuote(context.Background(), req)
+	s.ErrorIs(err, entity.ErrInsufficientBalance)
+}
+
+func (s *WalletServiceTestSuite) TestExecuteFXQuoteNotOpen() {
+	expired := s.openFXQuote()
+	expired.ExpiresAt = time.Now().UTC().Add(-time.Second)
+
+	executed := s.openFXQuote()
+	executed.Status = entity.FXQuoteStatusExecuted
+
+	tests := []struct {
+		name  string
+		quote entity.FXQuote
+		err   error
+	}{
+		{name: "expired", quote: expired, err: entity.ErrFXQuoteExpired},
+		{name: "executed", quote: executed, err: entity.ErrFXQuoteExecuted},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := &request.ExecuteFXQuote{QuoteID: tt.quote.ID, TransferID: "1234"}
+
+			s.fxQuoteRepoMock.EXPECT().GetForUpdate(gomock.Any(), req.QuoteID).Return(tt.quote, nil)
+
+			_, err := s.svc.ExecuteFXQuote(context.Background(), req)
+			s.ErrorIs(err, tt.err)
+		})
+	}
+}
+
+func (s *WalletServiceTestSuite) TestGetFXQuoteNotFound() {
+	s.fxQuoteRepoMock.EXPECT().Get(gomock.Any(), "quote-id").Return(entity.FXQuote{}, entity.ErrEntityNotFound)
+
+	_, err := s.svc.GetFXQuote(context.Background(), &request.GetFXQuote{QuoteID: "quote-id"})
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func (s *WalletServiceTestSuite) reconcileEvents(walletID string, createdAt time.Time) []entity.WalletEvent {
+	return []entity.WalletEvent{
+		{
+			Version:    entity.WalletEventVersionOne,
+			ID:         "event-1",
+			TransferID: "transfer-1",
+			WalletID:   walletID,
+			Amount:     decimal.NewFromInt(100),
+			EventType:  entity.EventTypeDebitTransfer,
+			Status:     entity.TransferStatusCompleted,
+			Sequence:   1,
+			CreatedAt:  createdAt,
+		},
+	}
+}
+
+func (s *WalletServiceTestSuite) TestReconcileProjectionsMatch() {
+	stored := entity.NewWalletProjection("wallet", "event-1", decimal.NewFromInt(100), decimal.Zero, decimal.Zero)
+	stored.LastSequence = 1
+
+	s.repoMock.EXPECT().ListIDs(gomock.Any(), "", uint64(10)).Return([]string{"wallet"}, nil)
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), "wallet").Return(stored, nil)
+	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), "wallet").Return(s.reconcileEvents("wallet", time.Now().Add(-time.Hour)), nil)
+
+	result, err := s.svc.ReconcileProjections(context.Background(), entity.ReconcileOptions{Limit: 10, SettledBefore: time.Now()})
+	s.NoError(err)
+	s.Equal(1, result.Checked)
+	s.NotNil(result.Mismatches)
+	s.Empty(result.Mismatches)
+	s.Empty(result.LastWalletID)
+}
+
+func (s *WalletServiceTestSuite) TestReconcileProjectionsMismatch() {
+	stored := entity.NewWalletProjection("wallet", "wallet", decimal.Zero, decimal.Zero, decimal.Zero)
+
+	s.repoMock.EXPECT().ListIDs(gomock.Any(), "previous", uint64(1)).Return([]string{"wallet"}, nil)
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), "wallet").Return(stored, nil)
+	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), "wallet").Return(s.reconcileEvents("wallet", time.Now().Add(-time.Hour)), nil)
+
+	result, err := s.svc.ReconcileProjections(context.Background(), entity.ReconcileOptions{AfterWalletID: "previous", Limit: 1, SettledBefore: time.Now()})
+	s.NoError(err)
+	s.Equal(1, result.Checked)
+	s.Require().Len(result.Mismatches, 1)
+	s.Equal("wallet", result.Mismatches[0].WalletID)
+	s.Equal([]string{"balance", "last_sequence"}, result.Mismatches[0].Fields)
+	s.True(decimal.NewFromInt(100).Equal(result.Mismatches[0].Replayed.Balance))
+	s.False(result.Mismatches[0].Repaired)
+	s.Equal("wallet", result.LastWalletID)
+}
+
+func (s *WalletServiceTestSuite) TestReconcileProjectionsProcessingBehind() {
+	stored := entity.NewWalletProjection("wallet", "wallet", decimal.Zero, decimal.Zero, decimal.Zero)
+
+	s.repoMock.EXPECT().ListIDs(gomock.Any(), "", uint64(10)).Return([]string{"wallet"}, nil)
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), "wallet").Return(stored, nil)
+	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), "wallet").Return(s.reconcileEvents("wallet", time.Now()), nil)
+
+	result, err := s.svc.ReconcileProjections(context.Background(), entity.ReconcileOptions{Limit: 10, SettledBefore: time.Now().Add(-time.Minute)})
+	s.NoError(err)
+	s.Equal(1, result.Checked)
+	s.Empty(result.Mismatches)
+}
+
+func (s *WalletServiceTestSuite) TestReconcileProjectionsRepair() {
+	stored := entity.NewWalletProjection("wallet", "wallet", decimal.NewFromInt(50), decimal.Zero, decimal.Zero)
+	stored.LastSequence = 1
+
+	s.repoMock.EXPECT().ListIDs(gomock.Any(), "", uint64(10)).Return([]string{"wallet"}, nil)
+	s.projectionRepoMock.EXPECT().GetForUpdate(gomock.Any(), "wallet").Return(stored, nil)
+	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), "wallet").Return(s.reconcileEvents("wallet", time.Now()), nil)
+	s.projectionRepoMock.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, projection entity.WalletProjection) (entity.WalletProjection, error) {
+		s.True(decimal.NewFromInt(100).Equal(projection.Balance))
+		s.Equal(int64(1), projection.LastSequence)
+		s.Equal("event-1", projection.LastEventID)
+
+		return projection, nil
+	})
+
+	result, err := s.svc.ReconcileProjections(context.Background(), entity.ReconcileOptions{Limit: 10, SettledBefore: time.Now(), Repair: true})
+	s.NoError(err)
+	s.Require().Len(result.Mismatches, 1)
+	s.Equal([]string{"balance"}, result.Mismatches[0].Fields)
+	s.True(result.Mismatches[0].Repaired)
+}
+
+func (s *WalletServiceTestSuite) TestReconcileProjectionsWalletError() {
+	stored := entity.NewWalletProjection("second", "second", decimal.Zero, decimal.Zero, decimal.Zero)
+
+	s.repoMock.EXPECT().ListIDs(gomock.Any(), "", uint64(2)).Return([]string{"first", "second"}, nil)
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), "first").Return(entity.WalletProjection{}, entity.ErrEntityNotFound)
+	s.projectionRepoMock.EXPECT().Get(gomock.Any(), "second").Return(stored, nil)
+	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), "second").Return([]entity.WalletEvent{}, nil)
+
+	result, err := s.svc.ReconcileProjections(context.Background(), entity.ReconcileOptions{Limit: 2, SettledBefore: time.Now()})
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+	s.Equal(1, result.Checked)
+	s.Empty(result.Mismatches)
+	s.Equal("second", result.LastWalletID)
+}
+
+func (s *WalletServiceTestSuite) TestReconcileProjectionsListIDsError() {
+	s.repoMock.EXPECT().ListIDs(gomock.Any(), "", uint64(10)).Return(nil, context.DeadlineExceeded)
+
+	_, err := s.svc.ReconcileProjections(context.Background(), entity.ReconcileOptions{Limit: 10})
+	s.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (s *WalletServiceTestSuite) TestGetStatementSuccess() {
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	events := []entity.WalletEvent{
+		{Version: entity.WalletEventVersionOne, ID: "event1", TransferID: "transfer1", WalletID: "wallet", Amount: decimal.NewFromInt(100), EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Sequence: 1, CreatedAt: from.Add(-time.Hour)},
+		{Version: entity.WalletEventVersionOne, ID: "event2", TransferID: "transfer2", WalletID: "wallet", Amount: decimal.NewFromInt(30), EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Sequence: 2, CreatedAt: from.Add(time.Hour)},
+	}
+
+	s.repoMock.EXPECT().Get(gomock.Any(), "wallet").Return(entity.Wallet{ID: "wallet", Currency: "EUR", CreatedAt: from.Add(-2 * time.Hour)}, nil)
+	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), "wallet").Return(events, nil)
+
+	statement, err := s.svc.GetStatement(context.Background(), &request.GetStatement{WalletID: "wallet", From: from})
+	s.NoError(err)
+	s.Equal(entity.Currency("EUR"), statement.Currency)
+	s.Equal(from, statement.From)
+	s.WithinDuration(time.Now(), statement.To, time.Second)
+	s.True(decimal.NewFromInt(100).Equal(statement.OpeningBalance))
+	s.True(decimal.NewFromInt(70).Equal(statement.ClosingBalance))
+	s.Require().Len(statement.Lines, 1)
+	s.Equal("event2", statement.Lines[0].EventID)
+}
+
+func (s *WalletServiceTestSuite) TestGetStatementDefaultsToWalletCreation() {
+	createdAt := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	s.repoMock.EXPECT().Get(gomock.Any(), "wallet").Return(entity.Wallet{ID: "wallet", Currency: "EUR", CreatedAt: createdAt}, nil)
+	s.eventRepoMock.EXPECT().ListByWalletID(gomock.Any(), "wallet").Return([]entity.WalletEvent{}, nil)
+
+	statement, err := s.svc.GetStatement(context.Background(), &request.GetStatement{WalletID: "wallet", To: createdAt.AddDate(0, 1, 0)})
+	s.NoError(err)
+	s.Equal(createdAt, statement.From)
+	s.Empty(statement.Lines)
+	s.True(statement.OpeningBalance.IsZero())
+	s.True(statement.ClosingBalance.IsZero())
+}
+
+func (s *WalletServiceTestSuite) TestGetStatementInvalidPeriod() {
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	s.repoMock.EXPECT().Get(gomock.Any(), "wallet").Return(entity.Wallet{ID: "wallet", CreatedAt: from.Add(-time.Hour)}, nil)
+
+	_, err := s.svc.GetStatement(context.Background(), &request.GetStatement{WalletID: "wallet", From: from, To: from})
+	s.ErrorIs(err, entity.ErrInvalidStatementPeriod)
+}
+
+func (s *WalletServiceTestSuite) TestGetStatementNotFound() {
+	s.repoMock.EXPECT().Get(gomock.Any(), "wallet").Return(entity.Wallet{}, entity.ErrEntityNotFound)
+
+	_, err := s.svc.GetStatement(context.Background(), &request.GetStatement{WalletID: "wallet"})
+	s.ErrorIs(err, entity.ErrEntityNotFound)
 }
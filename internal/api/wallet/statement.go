@@ -0,0 +1,305 @@
+package wallet
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// FoldStatement replays the wallet stream (oldest first) into the statement balances and lines, the events created before
+// statement.From make up the opening balance and the replay stops at the first event created at or after statement.To.
+// Credit limit changes are folded but aren't listed, they don't belong to a transfer.
+func FoldStatement(ctx context.Context, statement *entity.Statement, events []entity.WalletEvent) error {
+	projection := entity.WalletProjection{WalletID: statement.WalletID}
+	pending := entity.PendingTransfers{}
+	statement.Lines = []entity.StatementLine{}
+
+	for _, event := range events {
+		if !event.CreatedAt.Before(statement.To) {
+			break
+		}
+
+		direction := transferDirection(event, pending) // has to be looked up before the event settles the transfer
+		before := projection.Balance
+
+		err := ApplyEvents(ctx, &projection, pending, []entity.WalletEvent{event})
+		if err != nil {
+			return fmt.Errorf("failed to apply event %s: %w", event.ID, err)
+		}
+
+		if event.CreatedAt.Before(statement.From) {
+			statement.OpeningBalance = projection.Balance
+			continue
+		}
+
+		if event.EventType == entity.EventTypeCreditLimitChanged {
+			continue
+		}
+
+		statement.Lines = append(statement.Lines, entity.StatementLine{
+			EventID:       event.ID,
+			TransferID:    event.TransferID,
+			ReferenceID:   event.ReferenceID,
+			EventType:     event.EventType,
+			Direction:     direction,
+			Status:        event.Status,
+			Amount:        event.Amount,
+			BalanceChange: projection.Balance.Sub(before),
+			Balance:       projection.Balance,
+			Sequence:      event.Sequence,
+			CreatedAt:     event.CreatedAt,
+		})
+	}
+
+	statement.ClosingBalance = projection.Balance
+
+	return nil
+}
+
+// transferDirection returns the direction of the transfer the event belongs to, status updates take it from the pending transfer
+// they settle, an update of a transfer that isn't pending has no direction.
+func transferDirection(event entity.WalletEvent, pending entity.PendingTransfers) entity.TransferDirection {
+	if event.EventType == entity.EventTypeUpdateTransferStatus {
+		event = pending[event.TransferID]
+	}
+
+	switch event.EventType { //nolint:exhaustive
+	case entity.EventTypeDebitTransfer:
+		return entity.TransferDirectionDebit
+	case entity.EventTypeCreditTransfer:
+		return entity.TransferDirectionCredit
+	default:
+		return entity.TransferDirectionInvalid
+	}
+}
+
+var statementCSVHeader = []string{ //nolint:gochecknoglobals
+	"record", "event_id", "transfer_id", "reference_id", "event_type", "direction", "status",
+	"amount", "balance_change", "balance", "sequence", "created_at",
+}
+
+// WriteStatementCSV writes the statement as a single table, the first row after the header is the opening balance at From,
+// followed by a row per line and the closing balance at To. The record column tells the rows apart. The transfer and reference
+// ids are caller input, so they're passed through csvText before they're written.
+func WriteStatementCSV(w io.Writer, statement entity.Statement) error {
+	writer := csv.NewWriter(w)
+
+	balanceRow := func(record string, balance decimal.Decimal, at time.Time) []string {
+		return []string{record, "", "", "", "", "", "", "", "", balance.String(), "", at.Format(time.RFC3339Nano)}
+	}
+
+	err := writer.Write(statementCSVHeader)
+	if err != nil {
+		return fmt.Errorf("failed to write statement header: %w", err)
+	}
+
+	err = writer.Write(balanceRow("opening_balance", statement.OpeningBalance, statement.From))
+	if err != nil {
+		return fmt.Errorf("failed to write statement row: %w", err)
+	}
+
+	for _, line := range statement.Lines {
+		direction := ""
+		if line.Direction != entity.TransferDirectionInvalid {
+			direction = line.Direction.String()
+		}
+
+		err = writer.Write([]string{
+			"transfer",
+			line.EventID,
+			csvText(line.TransferID),
+			csvText(line.ReferenceID),
+			line.EventType.String(),
+			direction,
+			line.Status.String(),
+			line.Amount.String(),
+			line.BalanceChange.String(),
+			line.Balance.String(),
+			strconv.FormatInt(line.Sequence, 10),
+			line.CreatedAt.Format(time.RFC3339Nano),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write statement row: %w", err)
+		}
+	}
+
+	err = writer.Write(balanceRow("closing_balance", statement.ClosingBalance, statement.To))
+	if err != nil {
+		return fmt.Errorf("failed to write statement row: %w", err)
+	}
+
+	writer.Flush()
+
+	err = writer.Error()
+	if err != nil {
+		return fmt.Errorf("failed to flush statement: %w", err)
+	}
+
+	return nil
+}
+
+// csvText prefixes a free text cell with a quote if it starts with a character spreadsheets read as the start of a formula,
+// e.g. a reference id of "=HYPERLINK(...)" is shown as text instead of being evaluated when the statement is opened.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+const (
+	camtCredit = "CRDT" // money entering the wallet
+	camtDebit  = "DBIT" // money leaving the wallet
+)
+
+type camtDocument struct {
+	XMLName   xml.Name           `xml:"urn:iso:std:iso:20022:tech:xsd:camt.053.001.02 Document"`
+	Statement camtBankToCustomer `xml:"BkToCstmrStmt"`
+}
+
+type camtBankToCustomer struct {
+	MessageID string        `xml:"GrpHdr>MsgId"`
+	CreatedAt string        `xml:"GrpHdr>CreDtTm"`
+	Statement camtStatement `xml:"Stmt"`
+}
+
+type camtStatement struct {
+	ID        string        `xml:"Id"`
+	CreatedAt string        `xml:"CreDtTm"`
+	From      string        `xml:"FrToDt>FrDtTm"`
+	To        string        `xml:"FrToDt>ToDtTm"`
+	AccountID string        `xml:"Acct>Id>Othr>Id"`
+	Currency  string        `xml:"Acct>Ccy"`
+	Balances  []camtBalance `xml:"Bal"`
+	Entries   []camtEntry   `xml:"Ntry"`
+}
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtBalance struct {
+	Type        string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount      camtAmount `xml:"Amt"`
+	CreditDebit string     `xml:"CdtDbtInd"`
+	Date        string     `xml:"Dt>DtTm"`
+}
+
+type camtEntry struct {
+	Reference       string     `xml:"NtryRef"`
+	Amount          camtAmount `xml:"Amt"`
+	CreditDebit     string     `xml:"CdtDbtInd"`
+	Status          string     `xml:"Sts"`
+	BookingDate     string     `xml:"BookgDt>DtTm"`
+	ValueDate       string     `xml:"ValDt>DtTm"`
+	TransactionCode string     `xml:"BkTxCd>Prtry>Cd"`
+	AdditionalInfo  string     `xml:"AddtlNtryInf"`
+}
+
+// WriteStatementCamt053 writes the statement as an ISO 20022 camt.053.001.02 bank to customer statement with an OPBD and CLBD balance.
+// Lines that moved the balance are booked (BOOK) entries of the balance change, so the booked entries add up from the opening to
+// the closing balance, the others are pending (PDNG) or informational (INFO) entries of the event amount. Money entering the wallet
+// (a debit transfer) is a CRDT entry and money leaving it a DBIT one. The ids are written without dashes to fit the 35 characters
+// the standard allows, the transfer and reference ids go to the additional entry information.
+func WriteStatementCamt053(w io.Writer, statement entity.Statement) error {
+	messageID, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("failed to generate statement message id: %w", err)
+	}
+
+	currency := string(statement.Currency)
+	createdAt := statement.CreatedAt.Format(time.RFC3339)
+
+	balance := func(balanceType string, amount decimal.Decimal, at time.Time) camtBalance {
+		return camtBalance{
+			Type:        balanceType,
+			Amount:      camtAmount{Currency: currency, Value: amount.Abs().String()},
+			CreditDebit: camtIndicator(amount.IsNegative()),
+			Date:        at.Format(time.RFC3339),
+		}
+	}
+
+	doc := camtDocument{
+		Statement: camtBankToCustomer{
+			MessageID: camtID(messageID.String()),
+			CreatedAt: createdAt,
+			Statement: camtStatement{
+				ID:        camtID(messageID.String()),
+				CreatedAt: createdAt,
+				From:      statement.From.Format(time.RFC3339),
+				To:        statement.To.Format(time.RFC3339),
+				AccountID: camtID(statement.WalletID),
+				Currency:  currency,
+				Balances: []camtBalance{
+					balance("OPBD", statement.OpeningBalance, statement.From),
+					balance("CLBD", statement.ClosingBalance, statement.To),
+				},
+				Entries: make([]camtEntry, 0, len(statement.Lines)),
+			},
+		},
+	}
+
+	for _, line := range statement.Lines {
+		entry := camtEntry{
+			Reference:       camtID(line.EventID),
+			Amount:          camtAmount{Currency: currency, Value: line.BalanceChange.Abs().String()},
+			CreditDebit:     camtIndicator(line.BalanceChange.IsNegative()),
+			Status:          "BOOK",
+			BookingDate:     line.CreatedAt.Format(time.RFC3339),
+			ValueDate:       line.CreatedAt.Format(time.RFC3339),
+			TransactionCode: line.EventType.String(),
+			AdditionalInfo:  "transfer_id=" + line.TransferID + " reference_id=" + line.ReferenceID + " status=" + line.Status.String(),
+		}
+
+		if line.BalanceChange.IsZero() {
+			entry.Amount.Value = line.Amount.String()
+			entry.CreditDebit = camtIndicator(line.Direction == entity.TransferDirectionCredit)
+			entry.Status = "INFO"
+
+			if line.Status == entity.TransferStatusPending {
+				entry.Status = "PDNG"
+			}
+		}
+
+		doc.Statement.Statement.Entries = append(doc.Statement.Statement.Entries, entry)
+	}
+
+	_, err = io.WriteString(w, xml.Header)
+	if err != nil {
+		return fmt.Errorf("failed to write statement header: %w", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	err = encoder.Encode(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode statement: %w", err)
+	}
+
+	return nil
+}
+
+func camtIndicator(outgoing bool) string {
+	if outgoing {
+		return camtDebit
+	}
+
+	return camtCredit
+}
+
+// camtID drops the dashes of an uuid, the ids of camt messages are limited to 35 characters.
+func camtID(id string) string {
+	return strings.ReplaceAll(id, "-", "")
+}
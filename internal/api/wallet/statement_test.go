@@ -0,0 +1,183 @@
+package wallet_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statementEvents(from time.Time) []entity.WalletEvent {
+	event := func(sequence int64, createdAt time.Time, transferID string, eventType entity.WalletEventType, status entity.TransferStatus, amount int64) entity.WalletEvent {
+		return entity.WalletEvent{
+			Version:    entity.WalletEventVersionOne,
+			ID:         "event" + strconv.FormatInt(sequence, 10),
+			TransferID: transferID,
+			WalletID:   "wallet",
+			Amount:     decimal.NewFromInt(amount),
+			EventType:  eventType,
+			Status:     status,
+			Sequence:   sequence,
+			CreatedAt:  createdAt,
+		}
+	}
+
+	return []entity.WalletEvent{
+		event(1, from.Add(-time.Hour), "1", entity.EventTypeDebitTransfer, entity.TransferStatusCompleted, 100),
+		event(2, from, "2", entity.EventTypeCreditTransfer, entity.TransferStatusPending, 30),
+		event(3, from.Add(time.Hour), "", entity.EventTypeCreditLimitChanged, entity.TransferStatusInvalid, 50),
+		event(4, from.Add(2*time.Hour), "2", entity.EventTypeUpdateTransferStatus, entity.TransferStatusCompleted, 20),
+		event(5, from.Add(3*time.Hour), "5", entity.EventTypeDebitTransfer, entity.TransferStatusPending, 50),
+		event(6, from.Add(24*time.Hour), "6", entity.EventTypeDebitTransfer, entity.TransferStatusCompleted, 10),
+	}
+}
+
+func TestFoldStatement(t *testing.T) {
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	statement := entity.Statement{WalletID: "wallet", From: from, To: from.Add(24 * time.Hour)}
+
+	err := wallet.FoldStatement(context.Background(), &statement, statementEvents(from))
+	require.NoError(t, err)
+
+	assert.True(t, decimal.NewFromInt(100).Equal(statement.OpeningBalance), statement.OpeningBalance.String())
+	assert.True(t, decimal.NewFromInt(80).Equal(statement.ClosingBalance), statement.ClosingBalance.String())
+	require.Len(t, statement.Lines, 3)
+
+	tests := []struct {
+		eventID   string
+		direction entity.TransferDirection
+		change    int64
+		balance   int64
+	}{
+		{eventID: "event2", direction: entity.TransferDirectionCredit, change: -30, balance: 70},
+		{eventID: "event4", direction: entity.TransferDirectionCredit, change: 10, balance: 80}, // the part of the credit that wasn't captured is released
+		{eventID: "event5", direction: entity.TransferDirectionDebit, change: 0, balance: 80},
+	}
+
+	for i, tt := range tests {
+		line := statement.Lines[i]
+		assert.Equal(t, tt.eventID, line.EventID)
+		assert.Equal(t, tt.direction, line.Direction)
+		assert.True(t, decimal.NewFromInt(tt.change).Equal(line.BalanceChange), line.BalanceChange.String())
+		assert.True(t, decimal.NewFromInt(tt.balance).Equal(line.Balance), line.Balance.String())
+	}
+}
+
+func TestFoldStatementEmpty(t *testing.T) {
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	statement := entity.Statement{WalletID: "wallet", From: from, To: from.Add(time.Hour)}
+
+	err := wallet.FoldStatement(context.Background(), &statement, statementEvents(from)[:1])
+	require.NoError(t, err)
+
+	assert.True(t, decimal.NewFromInt(100).Equal(statement.OpeningBalance))
+	assert.True(t, decimal.NewFromInt(100).Equal(statement.ClosingBalance))
+	assert.NotNil(t, statement.Lines)
+	assert.Empty(t, statement.Lines)
+}
+
+func TestWriteStatementCSV(t *testing.T) {
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	line := func(transferID, referenceID string) entity.StatementLine {
+		return entity.StatementLine{
+			EventID:       "event",
+			TransferID:    transferID,
+			ReferenceID:   referenceID,
+			EventType:     entity.EventTypeCreditTransfer,
+			Direction:     entity.TransferDirectionCredit,
+			Status:        entity.TransferStatusCompleted,
+			Amount:        decimal.NewFromInt(10),
+			BalanceChange: decimal.NewFromInt(-10),
+			Balance:       decimal.NewFromInt(-10),
+			Sequence:      1,
+			CreatedAt:     from,
+		}
+	}
+
+	statement := entity.Statement{
+		WalletID: "wallet",
+		From:     from,
+		To:       from.Add(24 * time.Hour),
+		Lines: []entity.StatementLine{
+			line("1", "ref-1"),
+			line("=1+1", "+1"),
+			line("-1", "@SUM(A1)"),
+			line("\tcmd", ""),
+		},
+		ClosingBalance: decimal.NewFromInt(-40),
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, wallet.WriteStatementCSV(buf, statement))
+
+	rows, err := csv.NewReader(buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 7)
+
+	assert.Equal(t, []string{"opening_balance", "", "", "", "", "", "", "", "", "0", "", "2024-07-01T00:00:00Z"}, rows[1])
+	assert.Equal(t, []string{"transfer", "event", "1", "ref-1", "credit_transfer", "credit", "completed", "10", "-10", "-10", "1", "2024-07-01T00:00:00Z"}, rows[2])
+	assert.Equal(t, []string{"'=1+1", "'+1"}, rows[3][2:4])
+	assert.Equal(t, []string{"'-1", "'@SUM(A1)"}, rows[4][2:4])
+	assert.Equal(t, []string{"'\tcmd", ""}, rows[5][2:4])
+	assert.Equal(t, "closing_balance", rows[6][0])
+	assert.Equal(t, "-40", rows[6][9])
+}
+
+func TestWriteStatementCamt053(t *testing.T) {
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	statement := entity.Statement{WalletID: "0190f0d6-7c5e-7b8a-9c1d-2e3f4a5b6c7d", Currency: "EUR", From: from, To: from.Add(24 * time.Hour), CreatedAt: from.Add(25 * time.Hour)}
+
+	err := wallet.FoldStatement(context.Background(), &statement, statementEvents(from))
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, wallet.WriteStatementCamt053(buf, statement))
+
+	type amount struct {
+		Currency string `xml:"Ccy,attr"`
+		Value    string `xml:",chardata"`
+	}
+
+	var doc struct {
+		AccountID string `xml:"BkToCstmrStmt>Stmt>Acct>Id>Othr>Id"`
+		Balances  []struct {
+			Type        string `xml:"Tp>CdOrPrtry>Cd"`
+			Amount      amount `xml:"Amt"`
+			CreditDebit string `xml:"CdtDbtInd"`
+		} `xml:"BkToCstmrStmt>Stmt>Bal"`
+		Entries []struct {
+			Amount      amount `xml:"Amt"`
+			CreditDebit string `xml:"CdtDbtInd"`
+			Status      string `xml:"Sts"`
+		} `xml:"BkToCstmrStmt>Stmt>Ntry"`
+	}
+
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Equal(t, "0190f0d67c5e7b8a9c1d2e3f4a5b6c7d", doc.AccountID)
+	require.Len(t, doc.Balances, 2)
+	assert.Equal(t, "OPBD", doc.Balances[0].Type)
+	assert.Equal(t, amount{Currency: "EUR", Value: "100"}, doc.Balances[0].Amount)
+	assert.Equal(t, "CLBD", doc.Balances[1].Type)
+	assert.Equal(t, amount{Currency: "EUR", Value: "80"}, doc.Balances[1].Amount)
+
+	require.Len(t, doc.Entries, 3)
+	assert.Equal(t, "30", doc.Entries[0].Amount.Value)
+	assert.Equal(t, "DBIT", doc.Entries[0].CreditDebit)
+	assert.Equal(t, "BOOK", doc.Entries[0].Status)
+	assert.Equal(t, "10", doc.Entries[1].Amount.Value)
+	assert.Equal(t, "CRDT", doc.Entries[1].CreditDebit)
+	assert.Equal(t, "BOOK", doc.Entries[1].Status)
+	assert.Equal(t, "50", doc.Entries[2].Amount.Value)
+	assert.Equal(t, "CRDT", doc.Entries[2].CreditDebit)
+	assert.Equal(t, "PDNG", doc.Entries[2].Status)
+}
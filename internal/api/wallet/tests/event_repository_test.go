@@ -59,18 +59,19 @@ func (s *WalletEventRepositoryTestSuite) newRandomWalletEvent() entity.WalletEve
 }
 
 func (s *WalletEventRepositoryTestSuite) TestCreateSuccess() {
-	walletEvent, err := s.repo.Create(s.ctx, s.newRandomWalletEvent())
+	walletEvent, err := s.repo.Create(s.ctx, s.newRandomWalletEvent(), 0)
 	s.NoError(err)
 	s.NotEmpty(walletEvent.ID)
 	s.NotEmpty(walletEvent.CreatedAt)
+	s.Equal(int64(1), walletEvent.Sequence)
 }
 
 func (s *WalletEventRepositoryTestSuite) seedEvents(num int, walletID string) []entity.WalletEvent {
 	result := make([]entity.WalletEvent, 0, num)
-	for range num {
+	for k := range num {
 		walletEvent := s.newRandomWalletEvent()
 		walletEvent.WalletID = walletID
-		_, err := s.repo.Create(s.ctx, walletEvent)
+		walletEvent, err := s.repo.Create(s.ctx, walletEvent, int64(k))
 		s.NoError(err)
 
 		result = append(result, walletEvent)
@@ -80,14 +81,41 @@ func (s *WalletEventRepositoryTestSuite) seedEvents(num int, walletID string) []
 }
 
 func (s *WalletEventRepositoryTestSuite) TestCreateDuplicateTransferIDForEventType() {
-	walletEvent, err := s.repo.Create(s.ctx, s.newRandomWalletEvent())
+	walletEvent, err := s.repo.Create(s.ctx, s.newRandomWalletEvent(), 0)
 	s.NoError(err)
 
 	walletEvent.ID = uuid.Must(uuid.NewV7()).String()
 
-	walletEvent, err = s.repo.Create(s.ctx, walletEvent)
+	walletEvent, err = s.repo.Create(s.ctx, walletEvent, 1)
 
 	s.Error(err)
+	s.NotErrorIs(err, entity.ErrStreamVersionConflict)
+}
+
+func (s *WalletEventRepositoryTestSuite) TestCreateStreamVersionConflict() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+	_ = s.seedEvents(2, walletID)
+
+	walletEvent := s.newRandomWalletEvent()
+	walletEvent.WalletID = walletID
+
+	_, err := s.repo.Create(s.ctx, walletEvent, 1) // stale, the stream is already at version 2
+	s.ErrorIs(err, entity.ErrStreamVersionConflict)
+}
+
+func (s *WalletEventRepositoryTestSuite) TestGetStreamVersion() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+	_ = s.seedEvents(3, walletID)
+
+	version, err := s.repo.GetStreamVersion(s.ctx, walletID)
+	s.NoError(err)
+	s.Equal(int64(3), version)
+}
+
+func (s *WalletEventRepositoryTestSuite) TestGetStreamVersionEmpty() {
+	version, err := s.repo.GetStreamVersion(s.ctx, uuid.Must(uuid.NewV7()).String())
+	s.NoError(err)
+	s.Zero(version)
 }
 
 func (s *WalletEventRepositoryTestSuite) TestListByWalletIDSuccess() {
@@ -100,6 +128,124 @@ func (s *WalletEventRepositoryTestSuite) TestListByWalletIDSuccess() {
 	s.Equal(want, events)
 }
 
+func (s *WalletEventRepositoryTestSuite) TestListByWalletIDAfterSuccess() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+	want := s.seedEvents(5, walletID)
+
+	events, err := s.repo.ListByWalletIDAfter(s.ctx, walletID, 3)
+	s.NoError(err)
+	s.Equal(want[3:], events)
+}
+
+func (s *WalletEventRepositoryTestSuite) TestListByWalletIDSinceSuccess() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	want := make([]entity.WalletEvent, 0, 3)
+	for k, createdAt := range []time.Time{now.Add(-2 * time.Hour), now.Add(-30 * time.Minute), now} {
+		walletEvent := s.newRandomWalletEvent()
+		walletEvent.WalletID = walletID
+		walletEvent.CreatedAt = createdAt
+		walletEvent, err := s.repo.Create(s.ctx, walletEvent, int64(k))
+		s.NoError(err)
+
+		want = append(want, walletEvent)
+	}
+
+	events, err := s.repo.ListByWalletIDSince(s.ctx, walletID, now.Add(-time.Hour))
+	s.NoError(err)
+	s.Equal(want[1:], events)
+
+	events, err = s.repo.ListByWalletIDSince(s.ctx, uuid.Must(uuid.NewV7()).String(), now.Add(-time.Hour))
+	s.NoError(err)
+	s.Empty(events)
+}
+
+func (s *WalletEventRepositoryTestSuite) TestListByTransferIDSuccess() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+	transferID := uuid.Must(uuid.NewV7()).String()
+	_ = s.seedEvents(2, walletID)
+
+	debit := s.newWalletEvent(transferID, "", walletID, entity.EventTypeDebitTransfer, entity.TransferStatusPending, decimal.NewFromInt(10))
+	debit, err := s.repo.Create(s.ctx, debit, 2)
+	s.NoError(err)
+
+	update := s.newWalletEvent(transferID, "", walletID, entity.EventTypeUpdateTransferStatus, entity.TransferStatusCompleted, decimal.NewFromInt(0))
+	update, err = s.repo.Create(s.ctx, update, 3)
+	s.NoError(err)
+
+	events, err := s.repo.ListByTransferID(s.ctx, walletID, transferID)
+	s.NoError(err)
+	s.Equal([]entity.WalletEvent{debit, update}, events)
+
+	events, err = s.repo.ListByTransferID(s.ctx, uuid.Must(uuid.NewV7()).String(), transferID)
+	s.NoError(err)
+	s.Empty(events)
+}
+
+func (s *WalletEventRepositoryTestSuite) TestListPaginated() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+	seeded := s.seedEvents(5, walletID)
+	_ = s.seedEvents(2, uuid.Must(uuid.NewV7()).String())
+
+	firstPage, err := s.repo.List(s.ctx, entity.WalletEventFilter{WalletID: walletID, Limit: 3})
+	s.NoError(err)
+	s.Equal([]entity.WalletEvent{seeded[4], seeded[3], seeded[2]}, firstPage)
+
+	secondPage, err := s.repo.List(s.ctx, entity.WalletEventFilter{WalletID: walletID, Limit: 3, Cursor: firstPage[2].ID})
+	s.NoError(err)
+	s.Equal([]entity.WalletEvent{seeded[1], seeded[0]}, secondPage)
+}
+
+func (s *WalletEventRepositoryTestSuite) TestListFilters() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+
+	debit := s.newWalletEvent(uuid.Must(uuid.NewV7()).String(), "", walletID, entity.EventTypeDebitTransfer, entity.TransferStatusCompleted, decimal.NewFromInt(10))
+	debit, err := s.repo.Create(s.ctx, debit, 0)
+	s.NoError(err)
+
+	credit := s.newWalletEvent(uuid.Must(uuid.NewV7()).String(), "", walletID, entity.EventTypeCreditTransfer, entity.TransferStatusPending, decimal.NewFromInt(5))
+	credit, err = s.repo.Create(s.ctx, credit, 1)
+	s.NoError(err)
+
+	events, err := s.repo.List(s.ctx, entity.WalletEventFilter{WalletID: walletID, EventType: entity.EventTypeCreditTransfer})
+	s.NoError(err)
+	s.Equal([]entity.WalletEvent{credit}, events)
+
+	events, err = s.repo.List(s.ctx, entity.WalletEventFilter{WalletID: walletID, Status: entity.TransferStatusCompleted})
+	s.NoError(err)
+	s.Equal([]entity.WalletEvent{debit}, events)
+
+	events, err = s.repo.List(s.ctx, entity.WalletEventFilter{WalletID: walletID, CreatedAfter: debit.CreatedAt.Add(time.Hour)})
+	s.NoError(err)
+	s.Empty(events)
+
+	events, err = s.repo.List(s.ctx, entity.WalletEventFilter{WalletID: walletID, CreatedBefore: debit.CreatedAt.Add(time.Hour)})
+	s.NoError(err)
+	s.Len(events, 2)
+}
+
+func (s *WalletEventRepositoryTestSuite) TestListMetadata() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+
+	tagged := s.newWalletEvent(uuid.Must(uuid.NewV7()).String(), "", walletID, entity.EventTypeDebitTransfer, entity.TransferStatusCompleted, decimal.NewFromInt(10))
+	tagged.Metadata = entity.Metadata{"order_id": "1", "channel": "web"}
+	tagged, err := s.repo.Create(s.ctx, tagged, 0)
+	s.NoError(err)
+
+	untagged := s.newWalletEvent(uuid.Must(uuid.NewV7()).String(), "", walletID, entity.EventTypeCreditTransfer, entity.TransferStatusCompleted, decimal.NewFromInt(5))
+	_, err = s.repo.Create(s.ctx, untagged, 1)
+	s.NoError(err)
+
+	events, err := s.repo.List(s.ctx, entity.WalletEventFilter{WalletID: walletID, Metadata: entity.Metadata{"order_id": "1"}})
+	s.NoError(err)
+	s.Equal([]entity.WalletEvent{tagged}, events)
+
+	events, err = s.repo.List(s.ctx, entity.WalletEventFilter{WalletID: walletID, Metadata: entity.Metadata{"order_id": "2"}})
+	s.NoError(err)
+	s.Empty(events)
+}
+
 func (s *WalletEventRepositoryTestSuite) TestListByWalletIDEmpty() {
 	walletID := uuid.Must(uuid.NewV7()).String()
 
@@ -0,0 +1,97 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/buni/wallet/internal/pkg/testing/dt"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+type FXQuoteRepositoryTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	pgxPoolWrapper *pgxtx.TxWrapper
+	repo           *wallet.FXQuoteRepository
+}
+
+func (s *FXQuoteRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.pgxPoolWrapper = pgxtx.NewTxWrapper(dt.DB, pgx.TxOptions{})
+	s.repo = wallet.NewFXQuoteRepository(s.pgxPoolWrapper)
+}
+
+func (s *FXQuoteRepositoryTestSuite) TearDownTest() {
+	_, err := s.pgxPoolWrapper.Exec(s.ctx, "TRUNCATE fx_quotes")
+	s.NoError(err)
+}
+
+func (s *FXQuoteRepositoryTestSuite) newQuote() entity.FXQuote {
+	quote, err := entity.NewFXQuote(
+		entity.Wallet{ID: uuid.Must(uuid.NewV7()).String(), Currency: "EUR"},
+		entity.Wallet{ID: uuid.Must(uuid.NewV7()).String(), Currency: "USD"},
+		decimal.NewFromInt(100),
+		decimal.RequireFromString("1.0845"),
+		time.Minute,
+	)
+	s.Require().NoError(err)
+
+	return quote
+}
+
+func (s *FXQuoteRepositoryTestSuite) TestCreateAndGet() {
+	quote, err := s.repo.Create(s.ctx, s.newQuote())
+	s.NoError(err)
+
+	result, err := s.repo.Get(s.ctx, quote.ID)
+	s.NoError(err)
+	s.Equal(quote.ID, result.ID)
+	s.Equal(quote.SourceCurrency, result.SourceCurrency)
+	s.Equal(entity.FXQuoteStatusOpen, result.Status)
+	s.True(quote.TargetAmount.Equal(result.TargetAmount))
+	s.True(quote.Rate.Equal(result.Rate))
+	s.Equal(quote.ExpiresAt, result.ExpiresAt)
+}
+
+func (s *FXQuoteRepositoryTestSuite) TestGetNotFound() {
+	_, err := s.repo.Get(s.ctx, uuid.Must(uuid.NewV7()).String())
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+
+	_, err = s.repo.GetForUpdate(s.ctx, uuid.Must(uuid.NewV7()).String())
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func (s *FXQuoteRepositoryTestSuite) TestUpdate() {
+	quote, err := s.repo.Create(s.ctx, s.newQuote())
+	s.NoError(err)
+
+	quote.Status = entity.FXQuoteStatusExecuted
+	quote.TransferID = "transfer"
+	quote.ReferenceID = "reference"
+	quote.UpdatedAt = quote.UpdatedAt.Add(time.Second)
+
+	err = s.repo.Update(s.ctx, quote)
+	s.NoError(err)
+
+	result, err := s.repo.GetForUpdate(s.ctx, quote.ID)
+	s.NoError(err)
+	s.Equal(entity.FXQuoteStatusExecuted, result.Status)
+	s.Equal("transfer", result.TransferID)
+	s.Equal("reference", result.ReferenceID)
+	s.Equal(quote.UpdatedAt, result.UpdatedAt)
+
+	quote.ID = uuid.Must(uuid.NewV7()).String()
+	err = s.repo.Update(s.ctx, quote)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func TestFXQuoteRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(FXQuoteRepositoryTestSuite))
+}
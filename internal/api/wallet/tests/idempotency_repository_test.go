@@ -0,0 +1,186 @@
+package wallet_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/buni/wallet/internal/pkg/idempotency"
+	"github.com/buni/wallet/internal/pkg/testing/dt"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyRepositoryTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	pgxPoolWrapper *pgxtx.TxWrapper
+	repo           *idempotency.PostgresRepository
+}
+
+func (s *IdempotencyRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.pgxPoolWrapper = pgxtx.NewTxWrapper(dt.DB, pgx.TxOptions{})
+	s.repo = idempotency.NewPostgresRepository(s.pgxPoolWrapper)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TearDownTest() {
+	_, err := s.pgxPoolWrapper.Exec(s.ctx, "TRUNCATE idempotency_keys")
+	s.NoError(err)
+}
+
+// create stores an in progress record last updated at updatedAt, it expires a day after it.
+func (s *IdempotencyRepositoryTestSuite) create(key, fingerprint string, updatedAt time.Time) idempotency.Record {
+	record := idempotency.NewRecord(key, fingerprint)
+	record.CreatedAt = updatedAt
+	record.UpdatedAt = updatedAt
+	record.ExpiresAt = updatedAt.Add(24 * time.Hour)
+
+	result, created, err := s.repo.Create(s.ctx, record, updatedAt.Add(-time.Minute))
+	s.NoError(err)
+	s.True(created)
+	s.Equal(record, result)
+
+	return result
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestCreateExistingKey() {
+	stored := s.create("key", "fingerprint", time.Now().UTC().Truncate(time.Microsecond))
+
+	retry := idempotency.NewRecord("key", "fingerprint")
+
+	result, created, err := s.repo.Create(s.ctx, retry, retry.CreatedAt.Add(-time.Minute))
+	s.NoError(err)
+	s.False(created)
+	s.Equal(stored, result)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestCreateTakesOverStaleLock() {
+	s.create("key", "fingerprint", time.Now().UTC().Add(-time.Hour).Truncate(time.Microsecond))
+
+	retry := idempotency.NewRecord("key", "fingerprint")
+
+	result, created, err := s.repo.Create(s.ctx, retry, retry.CreatedAt.Add(-time.Minute))
+	s.NoError(err)
+	s.True(created)
+	s.Equal(retry, result)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestCreateStaleLockOfDifferentRequest() {
+	stored := s.create("key", "fingerprint", time.Now().UTC().Add(-time.Hour).Truncate(time.Microsecond))
+
+	retry := idempotency.NewRecord("key", "other-fingerprint")
+
+	result, created, err := s.repo.Create(s.ctx, retry, retry.CreatedAt.Add(-time.Minute))
+	s.NoError(err)
+	s.False(created)
+	s.Equal(stored, result)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestCreateCompletedIsNotTakenOver() {
+	stored := s.create("key", "fingerprint", time.Now().UTC().Add(-time.Hour).Truncate(time.Microsecond))
+	stored.StatusCode = http.StatusCreated
+	stored.Body = []byte(`{"id":"1"}`)
+	s.NoError(s.repo.Complete(s.ctx, stored))
+
+	retry := idempotency.NewRecord("key", "fingerprint")
+
+	result, created, err := s.repo.Create(s.ctx, retry, retry.CreatedAt.Add(-time.Minute))
+	s.NoError(err)
+	s.False(created)
+	s.Equal(stored, result)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestDelete() {
+	stored := s.create("key", "fingerprint", time.Now().UTC().Truncate(time.Microsecond))
+
+	s.NoError(s.repo.Delete(s.ctx, stored))
+
+	s.create("key", "fingerprint", time.Now().UTC().Truncate(time.Microsecond))
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestDeleteKeepsCompleted() {
+	stored := s.create("key", "fingerprint", time.Now().UTC().Truncate(time.Microsecond))
+	stored.StatusCode = http.StatusCreated
+	stored.Body = []byte(`{"id":"1"}`)
+	s.NoError(s.repo.Complete(s.ctx, stored))
+
+	s.NoError(s.repo.Delete(s.ctx, stored))
+
+	retry := idempotency.NewRecord("key", "fingerprint")
+
+	result, created, err := s.repo.Create(s.ctx, retry, retry.CreatedAt.Add(-time.Minute))
+	s.NoError(err)
+	s.False(created)
+	s.Equal(stored, result)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestCompleteAfterTakeover() {
+	stale := s.create("key", "fingerprint", time.Now().UTC().Add(-time.Hour).Truncate(time.Microsecond))
+
+	retry := idempotency.NewRecord("key", "fingerprint")
+	_, created, err := s.repo.Create(s.ctx, retry, retry.CreatedAt.Add(-time.Minute))
+	s.NoError(err)
+	s.True(created)
+
+	stale.StatusCode = http.StatusCreated
+	stale.Body = []byte(`{"id":"1"}`)
+	s.ErrorIs(s.repo.Complete(s.ctx, stale), idempotency.ErrLockLost)
+
+	result, created, err := s.repo.Create(s.ctx, idempotency.NewRecord("key", "fingerprint"), retry.CreatedAt.Add(-time.Minute))
+	s.NoError(err)
+	s.False(created)
+	s.Equal(retry, result)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestDeleteAfterTakeover() {
+	stale := s.create("key", "fingerprint", time.Now().UTC().Add(-time.Hour).Truncate(time.Microsecond))
+
+	retry := idempotency.NewRecord("key", "fingerprint")
+	_, created, err := s.repo.Create(s.ctx, retry, retry.CreatedAt.Add(-time.Minute))
+	s.NoError(err)
+	s.True(created)
+
+	s.NoError(s.repo.Delete(s.ctx, stale))
+
+	result, created, err := s.repo.Create(s.ctx, idempotency.NewRecord("key", "fingerprint"), retry.CreatedAt.Add(-time.Minute))
+	s.NoError(err)
+	s.False(created)
+	s.Equal(retry, result)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestCreateTakesOverExpired() {
+	stored := s.create("key", "fingerprint", time.Now().UTC().Add(-48*time.Hour).Truncate(time.Microsecond))
+	stored.StatusCode = http.StatusCreated
+	stored.Body = []byte(`{"id":"1"}`)
+	s.NoError(s.repo.Complete(s.ctx, stored))
+
+	retry := idempotency.NewRecord("key", "other-fingerprint")
+
+	result, created, err := s.repo.Create(s.ctx, retry, retry.CreatedAt.Add(-time.Minute))
+	s.NoError(err)
+	s.True(created)
+	s.Equal(retry, result)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestDeleteExpired() {
+	s.create("expired", "fingerprint", time.Now().UTC().Add(-48*time.Hour).Truncate(time.Microsecond))
+	kept := s.create("kept", "fingerprint", time.Now().UTC().Truncate(time.Microsecond))
+
+	deleted, err := s.repo.DeleteExpired(s.ctx, time.Now().UTC(), 10)
+	s.NoError(err)
+	s.Equal(1, deleted)
+
+	s.create("expired", "fingerprint", time.Now().UTC().Truncate(time.Microsecond))
+
+	result, created, err := s.repo.Create(s.ctx, idempotency.NewRecord("kept", "fingerprint"), kept.CreatedAt.Add(-time.Minute))
+	s.NoError(err)
+	s.False(created)
+	s.Equal(kept, result)
+}
+
+func TestIdempotencyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyRepositoryTestSuite))
+}
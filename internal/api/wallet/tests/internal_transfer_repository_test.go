@@ -0,0 +1,80 @@
+package wallet_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/buni/wallet/internal/pkg/testing/dt"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+type InternalTransferRepositoryTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	pgxPoolWrapper *pgxtx.TxWrapper
+	repo           *wallet.InternalTransferRepository
+}
+
+func (s *InternalTransferRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.pgxPoolWrapper = pgxtx.NewTxWrapper(dt.DB, pgx.TxOptions{})
+	s.repo = wallet.NewInternalTransferRepository(s.pgxPoolWrapper)
+}
+
+func (s *InternalTransferRepositoryTestSuite) TearDownTest() {
+	_, err := s.pgxPoolWrapper.Exec(s.ctx, "TRUNCATE internal_transfers")
+	s.NoError(err)
+}
+
+func (s *InternalTransferRepositoryTestSuite) newRandomInternalTransfer() entity.InternalTransfer {
+	return entity.NewInternalTransfer(
+		uuid.Must(uuid.NewV7()).String(),
+		uuid.Must(uuid.NewV7()).String(),
+		uuid.Must(uuid.NewV7()).String(),
+		uuid.Must(uuid.NewV7()).String(),
+		decimal.NewFromInt(int64(rand.Intn(100000))),
+	)
+}
+
+func (s *InternalTransferRepositoryTestSuite) TestCreateSuccess() {
+	transfer, err := s.repo.Create(s.ctx, s.newRandomInternalTransfer())
+	s.NoError(err)
+	s.NotEmpty(transfer.TransferID)
+	s.NotEmpty(transfer.CreatedAt)
+}
+
+func (s *InternalTransferRepositoryTestSuite) TestCreateDuplicateTransferID() {
+	transfer, err := s.repo.Create(s.ctx, s.newRandomInternalTransfer())
+	s.NoError(err)
+
+	_, err = s.repo.Create(s.ctx, transfer)
+	s.Error(err)
+}
+
+func (s *InternalTransferRepositoryTestSuite) TestGetSuccess() {
+	want, err := s.repo.Create(s.ctx, s.newRandomInternalTransfer())
+	s.NoError(err)
+
+	transfer, err := s.repo.Get(s.ctx, want.TransferID)
+	s.NoError(err)
+	s.Equal(want.TransferID, transfer.TransferID)
+	s.Equal(want.SourceWalletID, transfer.SourceWalletID)
+	s.Equal(want.DestinationWalletID, transfer.DestinationWalletID)
+	s.True(want.Amount.Equal(transfer.Amount))
+}
+
+func (s *InternalTransferRepositoryTestSuite) TestGetNotFound() {
+	_, err := s.repo.Get(s.ctx, uuid.Must(uuid.NewV7()).String())
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func TestInternalTransferRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(InternalTransferRepositoryTestSuite))
+}
@@ -0,0 +1,95 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/buni/wallet/internal/pkg/testing/dt"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+type LedgerRepositoryTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	pgxPoolWrapper *pgxtx.TxWrapper
+	repo           *wallet.LedgerRepository
+}
+
+func (s *LedgerRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.pgxPoolWrapper = pgxtx.NewTxWrapper(dt.DB, pgx.TxOptions{})
+	s.repo = wallet.NewLedgerRepository(s.pgxPoolWrapper)
+}
+
+func (s *LedgerRepositoryTestSuite) TearDownTest() {
+	_, err := s.pgxPoolWrapper.Exec(s.ctx, "TRUNCATE ledger_postings")
+	s.NoError(err)
+}
+
+func (s *LedgerRepositoryTestSuite) newEvent(walletID string) entity.WalletEvent {
+	return entity.WalletEvent{
+		ID:        uuid.Must(uuid.NewV7()).String(),
+		WalletID:  walletID,
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+func (s *LedgerRepositoryTestSuite) TestCreatePostingsIsIdempotent() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+	postings := entity.NewLedgerPostings(s.newEvent(walletID), "EUR", entity.LedgerAccountFunding, entity.WalletLedgerAccount(walletID), decimal.NewFromInt(10))
+
+	s.NoError(s.repo.CreatePostings(s.ctx, postings))
+	s.NoError(s.repo.CreatePostings(s.ctx, postings))
+
+	balances, err := s.repo.AccountBalances(s.ctx)
+	s.NoError(err)
+	s.Require().Len(balances, 2)
+	s.True(decimal.NewFromInt(-10).Equal(balances[0].Balance))
+	s.True(decimal.NewFromInt(10).Equal(balances[1].Balance))
+}
+
+func (s *LedgerRepositoryTestSuite) TestCreatePostingsEmpty() {
+	s.NoError(s.repo.CreatePostings(s.ctx, nil))
+}
+
+func (s *LedgerRepositoryTestSuite) TestAccountBalances() {
+	walletOne := uuid.Must(uuid.NewV7()).String()
+	walletTwo := uuid.Must(uuid.NewV7()).String()
+
+	postings := entity.NewLedgerPostings(s.newEvent(walletOne), "EUR", entity.LedgerAccountFunding, entity.WalletLedgerAccount(walletOne), decimal.NewFromInt(100))
+	postings = append(postings, entity.NewLedgerPostings(s.newEvent(walletTwo), "EUR", entity.LedgerAccountFunding, entity.WalletLedgerAccount(walletTwo), decimal.NewFromInt(50))...)
+	postings = append(postings, entity.NewLedgerPostings(s.newEvent(walletOne), "EUR", entity.WalletLedgerAccount(walletOne), entity.LedgerAccountSettlement, decimal.NewFromInt(30))...)
+	postings = append(postings, entity.NewLedgerPostings(s.newEvent(walletTwo), "USD", entity.LedgerAccountFunding, entity.WalletLedgerAccount(walletTwo), decimal.NewFromInt(5))...)
+
+	s.NoError(s.repo.CreatePostings(s.ctx, postings))
+
+	balances, err := s.repo.AccountBalances(s.ctx)
+	s.NoError(err)
+	s.Require().Len(balances, 5)
+
+	expected := []entity.LedgerAccountBalance{
+		{Account: entity.LedgerAccountFunding, Currency: "EUR", Balance: decimal.NewFromInt(-150)},
+		{Account: entity.LedgerAccountFunding, Currency: "USD", Balance: decimal.NewFromInt(-5)},
+		{Account: entity.LedgerAccountSettlement, Currency: "EUR", Balance: decimal.NewFromInt(30)},
+		{Account: entity.LedgerAccountWallets, Currency: "EUR", Balance: decimal.NewFromInt(120)},
+		{Account: entity.LedgerAccountWallets, Currency: "USD", Balance: decimal.NewFromInt(5)},
+	}
+	for i, balance := range balances {
+		s.Equal(expected[i].Account, balance.Account)
+		s.Equal(expected[i].Currency, balance.Currency)
+		s.True(expected[i].Balance.Equal(balance.Balance), balance.Account)
+	}
+
+	s.True(entity.NewTrialBalance(balances).Balanced())
+}
+
+func TestLedgerRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(LedgerRepositoryTestSuite))
+}
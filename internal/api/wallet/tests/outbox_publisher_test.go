@@ -0,0 +1,187 @@
+package wallet_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/app/request"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/buni/wallet/internal/pkg/pubsub/jetstream"
+	"github.com/buni/wallet/internal/pkg/pubsub/outbox"
+	"github.com/buni/wallet/internal/pkg/testing/dt"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+var errRollback = errors.New("rollback")
+
+// WalletOutboxPublisherSuite runs the service the way the worker does, with the events published through the outbox.
+type WalletOutboxPublisherSuite struct {
+	suite.Suite
+	ctx            context.Context
+	pgxPoolWrapper *pgxtx.TxWrapper
+	txm            *pgxtx.TransactionManager
+	outboxRepo     *outbox.PostgresRepository[pgxscan.Querier]
+	svc            *wallet.Service
+}
+
+func (s *WalletOutboxPublisherSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.pgxPoolWrapper = pgxtx.NewTxWrapper(dt.DB, pgx.TxOptions{})
+	s.txm = pgxtx.NewTransactionManager(dt.DB, pgx.TxOptions{})
+	s.outboxRepo = outbox.NewPGxRepository(s.pgxPoolWrapper)
+
+	svc, err := wallet.NewService(
+		wallet.NewRepository(s.pgxPoolWrapper),
+		wallet.NewProjectionRepository(s.pgxPoolWrapper),
+		wallet.NewEventRepository(s.pgxPoolWrapper),
+		wallet.NewSnapshotRepository(s.pgxPoolWrapper),
+		wallet.NewTransferRepository(s.pgxPoolWrapper),
+		wallet.NewInternalTransferRepository(s.pgxPoolWrapper),
+		wallet.NewLedgerRepository(s.pgxPoolWrapper),
+		wallet.NewRefundRepository(s.pgxPoolWrapper),
+		wallet.NewOutboxPublisher(s.outboxRepo, s.txm),
+		s.txm,
+		wallet.WithScheduledTransfers(wallet.NewScheduledTransferRepository(s.pgxPoolWrapper)),
+		wallet.WithRecurringTransfers(wallet.NewRecurringTransferRepository(s.pgxPoolWrapper)),
+	)
+	s.Require().NoError(err)
+	s.svc = svc
+}
+
+func (s *WalletOutboxPublisherSuite) TearDownTest() {
+	_, err := s.pgxPoolWrapper.Exec(s.ctx, "TRUNCATE wallets, wallet_projections, wallet_events, scheduled_transfers, recurring_transfers, outbox_messages")
+	s.NoError(err)
+}
+
+func (s *WalletOutboxPublisherSuite) createWallet() entity.Wallet {
+	created, err := s.svc.Create(s.ctx, &request.CreateWallet{ReferenceID: uuid.Must(uuid.NewV7()).String(), Currency: "EUR"})
+	s.Require().NoError(err)
+
+	return created
+}
+
+// published returns the queued outbox events of the transfers whose id starts with transferID,
+// the occurrences of a recurring transfer have the id <recurring id>:<occurrence time>.
+func (s *WalletOutboxPublisherSuite) published(transferID string) []entity.WalletEvent {
+	msgs, err := s.outboxRepo.List(s.ctx, 1000, outbox.MessageStatusQueued, jetstream.JetStreamPublisherType, time.Now().UTC())
+	s.Require().NoError(err)
+
+	events := []entity.WalletEvent{}
+
+	for _, msg := range msgs {
+		var event entity.WalletEvent
+		s.Require().NoError(json.Unmarshal(msg.Payload.Payload, &event))
+
+		if strings.HasPrefix(event.TransferID, transferID) {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+func (s *WalletOutboxPublisherSuite) scheduleDebit(walletID string) request.DebitTransfer {
+	executeAt := time.Now().UTC().Add(time.Minute)
+	req := request.DebitTransfer{
+		WalletID:   walletID,
+		TransferID: uuid.Must(uuid.NewV7()).String(),
+		Amount:     decimal.NewFromInt(10),
+		Currency:   "EUR",
+		Status:     entity.TransferStatusCompleted,
+		ExecuteAt:  &executeAt,
+	}
+
+	_, err := s.svc.DebitTransfer(s.ctx, &req)
+	s.Require().NoError(err)
+
+	return req
+}
+
+func (s *WalletOutboxPublisherSuite) TestExecuteScheduledTransfersPublishesThroughOutbox() {
+	req := s.scheduleDebit(s.createWallet().ID)
+	s.Empty(s.published(req.TransferID))
+
+	executed, err := s.svc.ExecuteScheduledTransfers(s.ctx, time.Now().UTC().Add(time.Hour), 10)
+	s.NoError(err)
+	s.Require().Len(executed, 1)
+
+	events := s.published(req.TransferID)
+	s.Require().Len(events, 1)
+	s.Require().NotNil(executed[0].EventID)
+	s.Equal(*executed[0].EventID, events[0].ID)
+}
+
+func (s *WalletOutboxPublisherSuite) TestRolledBackScheduledTransferIsNotPublished() {
+	req := s.scheduleDebit(s.createWallet().ID)
+
+	err := s.txm.Run(s.ctx, func(ctx context.Context) error {
+		executed, err := s.svc.ExecuteScheduledTransfers(ctx, time.Now().UTC().Add(time.Hour), 10)
+		s.NoError(err)
+		s.Len(executed, 1)
+
+		return errRollback
+	})
+	s.ErrorIs(err, errRollback)
+
+	s.Empty(s.published(req.TransferID))
+}
+
+func (s *WalletOutboxPublisherSuite) createRecurringDebit(walletID string) entity.RecurringTransfer {
+	startAt := time.Now().UTC().Add(time.Minute)
+
+	recurring, err := s.svc.CreateRecurringTransfer(s.ctx, &request.CreateRecurringTransfer{
+		WalletID:  walletID,
+		Direction: entity.TransferDirectionDebit,
+		Amount:    decimal.NewFromInt(10),
+		Currency:  "EUR",
+		Rule:      "FREQ=DAILY",
+		StartAt:   &startAt,
+	})
+	s.Require().NoError(err)
+
+	return recurring
+}
+
+func (s *WalletOutboxPublisherSuite) TestExecuteRecurringTransfersPublishesThroughOutbox() {
+	recurring := s.createRecurringDebit(s.createWallet().ID)
+
+	executed, err := s.svc.ExecuteRecurringTransfers(s.ctx, time.Now().UTC().Add(time.Hour), 10)
+	s.NoError(err)
+	s.Len(executed, 1)
+
+	s.Len(s.published(recurring.ID), 1)
+}
+
+func (s *WalletOutboxPublisherSuite) TestRolledBackOccurrenceIsNotPublished() {
+	recurring := s.createRecurringDebit(s.createWallet().ID)
+	now := time.Now().UTC().Add(time.Hour)
+
+	err := s.txm.Run(s.ctx, func(ctx context.Context) error {
+		executed, err := s.svc.ExecuteRecurringTransfers(ctx, now, 10)
+		s.NoError(err)
+		s.Len(executed, 1)
+
+		return errRollback
+	})
+	s.ErrorIs(err, errRollback)
+	s.Empty(s.published(recurring.ID))
+
+	// the re-run writes the occurrence again and it's published once
+	_, err = s.svc.ExecuteRecurringTransfers(s.ctx, now, 10)
+	s.NoError(err)
+	s.Len(s.published(recurring.ID), 1)
+}
+
+func TestWalletOutboxPublisherSuite(t *testing.T) {
+	suite.Run(t, new(WalletOutboxPublisherSuite))
+}
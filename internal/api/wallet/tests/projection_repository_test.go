@@ -44,6 +44,7 @@ func (s *WalletProjectionRepositoryTestSuite) newWalletProjection(walletID strin
 		decimal.NewFromInt(int64(rand.Intn(100000))),
 		decimal.NewFromInt(int64(rand.Intn(100000))),
 	)
+	projection.CreditLimit = decimal.NewFromInt(int64(rand.Intn(100000)))
 	return projection
 }
 
@@ -69,6 +70,18 @@ func (s *WalletProjectionRepositoryTestSuite) TestGetSuccess() {
 func (s *WalletProjectionRepositoryTestSuite) TestGetNotFound() {
 	_, err := s.repo.Get(s.ctx, uuid.Must(uuid.NewV7()).String())
 	s.ErrorIs(err, entity.ErrEntityNotFound)
+
+	_, err = s.repo.GetForUpdate(s.ctx, uuid.Must(uuid.NewV7()).String())
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func (s *WalletProjectionRepositoryTestSuite) TestGetForUpdateSuccess() {
+	want, err := s.repo.Create(s.ctx, s.newRandomProjection())
+	s.NoError(err)
+
+	got, err := s.repo.GetForUpdate(s.ctx, want.WalletID)
+	s.NoError(err)
+	s.Equal(want, got)
 }
 
 func (s *WalletProjectionRepositoryTestSuite) TestUpdateSuccess() {
@@ -78,8 +91,10 @@ func (s *WalletProjectionRepositoryTestSuite) TestUpdateSuccess() {
 
 	want.Balance = decimal.NewFromInt(100)
 	want.LastEventID = uuid.Must(uuid.NewV7()).String()
+	want.LastSequence = 3
 	want.PendingCredit = decimal.NewFromInt(50)
 	want.PendingDebit = decimal.NewFromInt(50)
+	want.CreditLimit = decimal.NewFromInt(25)
 
 	got, err := s.repo.Update(s.ctx, want)
 	s.NoError(err)
@@ -94,6 +109,25 @@ func (s *WalletProjectionRepositoryTestSuite) TestUpdateNotFound() {
 	s.Empty(got)
 }
 
+func (s *WalletProjectionRepositoryTestSuite) TestListByWalletIDs() {
+	first, err := s.repo.Create(s.ctx, s.newRandomProjection())
+	s.NoError(err)
+
+	second, err := s.repo.Create(s.ctx, s.newRandomProjection())
+	s.NoError(err)
+
+	_, err = s.repo.Create(s.ctx, s.newRandomProjection())
+	s.NoError(err)
+
+	got, err := s.repo.ListByWalletIDs(s.ctx, []string{first.WalletID, second.WalletID, uuid.Must(uuid.NewV7()).String()})
+	s.NoError(err)
+	s.ElementsMatch([]entity.WalletProjection{first, second}, got)
+
+	got, err = s.repo.ListByWalletIDs(s.ctx, []string{})
+	s.NoError(err)
+	s.Empty(got)
+}
+
 func TestWalletProjectionRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(WalletProjectionRepositoryTestSuite))
 }
@@ -35,7 +35,8 @@ func (s *WalletPublisherSuite) SetupSuite() {
 }
 
 func (s *WalletPublisherSuite) TestPublishSuccessfully() {
-	ctx, _ := context.WithTimeout(context.Background(), time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
 
 	err := s.walletEventPublisher.PublishCreated(ctx, entity.WalletEvent{
 		ID:          uuid.Must(uuid.NewV7()).String(),
@@ -0,0 +1,169 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/buni/wallet/internal/pkg/testing/dt"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecurringTransferRepositoryTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	pgxPoolWrapper *pgxtx.TxWrapper
+	repo           *wallet.RecurringTransferRepository
+}
+
+func (s *RecurringTransferRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.pgxPoolWrapper = pgxtx.NewTxWrapper(dt.DB, pgx.TxOptions{})
+	s.repo = wallet.NewRecurringTransferRepository(s.pgxPoolWrapper)
+}
+
+func (s *RecurringTransferRepositoryTestSuite) TearDownTest() {
+	_, err := s.pgxPoolWrapper.Exec(s.ctx, "TRUNCATE recurring_transfers")
+	s.NoError(err)
+}
+
+func (s *RecurringTransferRepositoryTestSuite) newRecurringTransfer(walletID string, startAt time.Time) entity.RecurringTransfer {
+	transfer, err := entity.NewRecurringTransfer(
+		walletID,
+		uuid.Must(uuid.NewV7()).String(),
+		entity.TransferDirectionCredit,
+		decimal.NewFromInt(10),
+		"EUR",
+		entity.RecurrenceRule{Frequency: entity.RecurrenceFrequencyDaily, Interval: 1},
+		startAt,
+		nil,
+	)
+	s.Require().NoError(err)
+
+	return transfer
+}
+
+func (s *RecurringTransferRepositoryTestSuite) TestCreateAndGet() {
+	transfer := s.newRecurringTransfer(uuid.Must(uuid.NewV7()).String(), time.Now().Add(time.Hour))
+
+	_, err := s.repo.Create(s.ctx, transfer)
+	s.NoError(err)
+
+	got, err := s.repo.Get(s.ctx, transfer.WalletID, transfer.ID)
+	s.NoError(err)
+	s.Equal(transfer.ID, got.ID)
+	s.Equal(entity.RecurringStatusActive, got.Status)
+	s.Equal(entity.TransferDirectionCredit, got.Direction)
+	s.Equal(transfer.Rule, got.Rule)
+	s.True(transfer.Amount.Equal(got.Amount))
+	s.Equal(transfer.NextRunAt, got.NextRunAt)
+	s.Nil(got.EndAt)
+
+	got, err = s.repo.GetForUpdate(s.ctx, transfer.WalletID, transfer.ID)
+	s.NoError(err)
+	s.Equal(transfer.ID, got.ID)
+
+	_, err = s.repo.Get(s.ctx, uuid.Must(uuid.NewV7()).String(), transfer.ID)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func (s *RecurringTransferRepositoryTestSuite) TestUpdate() {
+	transfer := s.newRecurringTransfer(uuid.Must(uuid.NewV7()).String(), time.Now().Add(time.Hour))
+
+	_, err := s.repo.Create(s.ctx, transfer)
+	s.NoError(err)
+
+	transfer.LastTransferID = entity.RecurringTransferID(transfer.ID, *transfer.NextRunAt)
+	s.Require().NoError(transfer.Advance(time.Now().Add(time.Minute)))
+	transfer.Status = entity.RecurringStatusPaused
+
+	s.NoError(s.repo.Update(s.ctx, transfer))
+
+	got, err := s.repo.Get(s.ctx, transfer.WalletID, transfer.ID)
+	s.NoError(err)
+	s.Equal(entity.RecurringStatusPaused, got.Status)
+	s.Equal(1, got.Occurrence)
+	s.Equal(transfer.NextRunAt, got.NextRunAt)
+	s.Equal(transfer.LastTransferID, got.LastTransferID)
+	s.Equal(transfer.UpdatedAt, got.UpdatedAt)
+
+	missing := s.newRecurringTransfer(uuid.Must(uuid.NewV7()).String(), time.Now())
+	s.ErrorIs(s.repo.Update(s.ctx, missing), entity.ErrEntityNotFound)
+}
+
+func (s *RecurringTransferRepositoryTestSuite) TestDelete() {
+	transfer := s.newRecurringTransfer(uuid.Must(uuid.NewV7()).String(), time.Now().Add(time.Hour))
+
+	_, err := s.repo.Create(s.ctx, transfer)
+	s.NoError(err)
+
+	s.NoError(s.repo.Delete(s.ctx, transfer.WalletID, transfer.ID))
+
+	_, err = s.repo.Get(s.ctx, transfer.WalletID, transfer.ID)
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+
+	s.ErrorIs(s.repo.Delete(s.ctx, transfer.WalletID, transfer.ID), entity.ErrEntityNotFound)
+}
+
+func (s *RecurringTransferRepositoryTestSuite) TestList() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+
+	active := s.newRecurringTransfer(walletID, time.Now().Add(time.Hour))
+	paused := s.newRecurringTransfer(walletID, time.Now().Add(time.Hour))
+	paused.Status = entity.RecurringStatusPaused
+	other := s.newRecurringTransfer(uuid.Must(uuid.NewV7()).String(), time.Now().Add(time.Hour))
+
+	for _, transfer := range []entity.RecurringTransfer{active, paused, other} {
+		_, err := s.repo.Create(s.ctx, transfer)
+		s.NoError(err)
+	}
+
+	transfers, err := s.repo.List(s.ctx, entity.RecurringTransferFilter{WalletID: walletID})
+	s.NoError(err)
+	s.Len(transfers, 2)
+
+	transfers, err = s.repo.List(s.ctx, entity.RecurringTransferFilter{WalletID: walletID, Status: entity.RecurringStatusPaused})
+	s.NoError(err)
+	s.Require().Len(transfers, 1)
+	s.Equal(paused.ID, transfers[0].ID)
+
+	transfers, err = s.repo.List(s.ctx, entity.RecurringTransferFilter{WalletID: uuid.Must(uuid.NewV7()).String()})
+	s.NoError(err)
+	s.NotNil(transfers)
+	s.Empty(transfers)
+}
+
+func (s *RecurringTransferRepositoryTestSuite) TestListDue() {
+	now := time.Now().UTC()
+
+	due := s.newRecurringTransfer(uuid.Must(uuid.NewV7()).String(), now.Add(-time.Minute))
+	dueEarlier := s.newRecurringTransfer(uuid.Must(uuid.NewV7()).String(), now.Add(-time.Hour))
+	notDue := s.newRecurringTransfer(uuid.Must(uuid.NewV7()).String(), now.Add(time.Hour))
+	paused := s.newRecurringTransfer(uuid.Must(uuid.NewV7()).String(), now.Add(-time.Minute))
+	paused.Status = entity.RecurringStatusPaused
+
+	for _, transfer := range []entity.RecurringTransfer{due, dueEarlier, notDue, paused} {
+		_, err := s.repo.Create(s.ctx, transfer)
+		s.NoError(err)
+	}
+
+	transfers, err := s.repo.ListDue(s.ctx, now, 10)
+	s.NoError(err)
+	s.Require().Len(transfers, 2)
+	s.Equal(dueEarlier.ID, transfers[0].ID)
+	s.Equal(due.ID, transfers[1].ID)
+
+	transfers, err = s.repo.ListDue(s.ctx, now, 1)
+	s.NoError(err)
+	s.Len(transfers, 1)
+}
+
+func TestRecurringTransferRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RecurringTransferRepositoryTestSuite))
+}
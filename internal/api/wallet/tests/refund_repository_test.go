@@ -0,0 +1,90 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/buni/wallet/internal/pkg/testing/dt"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+type RefundRepositoryTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	pgxPoolWrapper *pgxtx.TxWrapper
+	repo           *wallet.RefundRepository
+}
+
+func (s *RefundRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.pgxPoolWrapper = pgxtx.NewTxWrapper(dt.DB, pgx.TxOptions{})
+	s.repo = wallet.NewRefundRepository(s.pgxPoolWrapper)
+}
+
+func (s *RefundRepositoryTestSuite) TearDownTest() {
+	_, err := s.pgxPoolWrapper.Exec(s.ctx, "TRUNCATE transfer_refunds")
+	s.NoError(err)
+}
+
+func (s *RefundRepositoryTestSuite) newRefund(walletID, transferID string, n int, amount decimal.Decimal) entity.TransferRefund {
+	return entity.NewTransferRefund(entity.WalletEvent{
+		ID:          uuid.Must(uuid.NewV7()).String(),
+		TransferID:  entity.RefundTransferID(transferID, n),
+		ReferenceID: uuid.Must(uuid.NewV7()).String(),
+		WalletID:    walletID,
+		Amount:      amount,
+	}, transferID)
+}
+
+func (s *RefundRepositoryTestSuite) TestCreateSuccess() {
+	refund, err := s.repo.Create(s.ctx, s.newRefund(uuid.Must(uuid.NewV7()).String(), "transfer", 1, decimal.NewFromInt(10)))
+	s.NoError(err)
+	s.Equal("transfer:refund:1", refund.RefundID)
+	s.NotEmpty(refund.CreatedAt)
+}
+
+func (s *RefundRepositoryTestSuite) TestCreateDuplicateRefundID() {
+	refund, err := s.repo.Create(s.ctx, s.newRefund(uuid.Must(uuid.NewV7()).String(), "transfer", 1, decimal.NewFromInt(10)))
+	s.NoError(err)
+
+	_, err = s.repo.Create(s.ctx, refund)
+	s.Error(err)
+}
+
+func (s *RefundRepositoryTestSuite) TestListByTransferID() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+
+	first := s.newRefund(walletID, "transfer", 1, decimal.NewFromInt(10))
+	second := s.newRefund(walletID, "transfer", 2, decimal.RequireFromString("2.5"))
+	second.CreatedAt = first.CreatedAt.Add(1)
+	otherTransfer := s.newRefund(walletID, "other", 1, decimal.NewFromInt(10))
+	otherWallet := s.newRefund(uuid.Must(uuid.NewV7()).String(), "transfer", 1, decimal.NewFromInt(10))
+
+	for _, refund := range []entity.TransferRefund{second, first, otherTransfer, otherWallet} {
+		_, err := s.repo.Create(s.ctx, refund)
+		s.NoError(err)
+	}
+
+	refunds, err := s.repo.ListByTransferID(s.ctx, walletID, "transfer")
+	s.NoError(err)
+	s.Require().Len(refunds, 2)
+	s.Equal(first.RefundID, refunds[0].RefundID)
+	s.Equal(first.EventID, refunds[0].EventID)
+	s.True(first.Amount.Equal(refunds[0].Amount))
+	s.Equal(second.RefundID, refunds[1].RefundID)
+
+	refunds, err = s.repo.ListByTransferID(s.ctx, walletID, "unknown")
+	s.NoError(err)
+	s.NotNil(refunds)
+	s.Empty(refunds)
+}
+
+func TestRefundRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RefundRepositoryTestSuite))
+}
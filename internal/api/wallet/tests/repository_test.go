@@ -11,6 +11,7 @@ import (
 	"github.com/buni/wallet/internal/pkg/testing/dt"
 	"github.com/gofrs/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -30,12 +31,12 @@ func (s *WalletRepositoryTestSuite) SetupTest() {
 }
 
 func (s *WalletRepositoryTestSuite) TearDownTest() {
-	_, err := s.pgxPoolWrapper.Exec(s.ctx, "TRUNCATE wallets")
+	_, err := s.pgxPoolWrapper.Exec(s.ctx, "TRUNCATE wallets, wallet_projections")
 	s.NoError(err)
 }
 
 func (s *WalletRepositoryTestSuite) newWallet() entity.Wallet {
-	wallet, err := entity.NewWallet(uuid.Must(uuid.NewV7()).String())
+	wallet, err := entity.NewWallet(uuid.Must(uuid.NewV7()).String(), "EUR")
 	s.NoError(err)
 	return wallet
 }
@@ -67,11 +68,208 @@ func (s *WalletRepositoryTestSuite) TestGetSuccess() {
 	s.Equal(want, got)
 }
 
+func (s *WalletRepositoryTestSuite) TestGetForShareSuccess() {
+	want, err := s.repo.Create(s.ctx, s.newWallet())
+	s.NoError(err)
+
+	got, err := s.repo.GetForShare(s.ctx, want.ID)
+	s.NoError(err)
+	s.Equal(want, got)
+}
+
+func (s *WalletRepositoryTestSuite) TestGetForShareNotFound() {
+	_, err := s.repo.GetForShare(s.ctx, uuid.Must(uuid.NewV7()).String())
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
 func (s *WalletRepositoryTestSuite) TestGetNotFound() {
 	_, err := s.repo.Get(s.ctx, uuid.Must(uuid.NewV7()).String())
 	s.ErrorIs(err, entity.ErrEntityNotFound)
 }
 
+func (s *WalletRepositoryTestSuite) TestUpdateStatusSuccess() {
+	want, err := s.repo.Create(s.ctx, s.newWallet())
+	s.NoError(err)
+	s.Equal(entity.WalletStatusActive, want.Status)
+
+	want.Status = entity.WalletStatusFrozen
+	want.UpdatedAt = want.UpdatedAt.Add(time.Second)
+
+	_, err = s.repo.UpdateStatus(s.ctx, want)
+	s.NoError(err)
+
+	got, err := s.repo.GetForUpdate(s.ctx, want.ID)
+	s.NoError(err)
+	s.Equal(want, got)
+}
+
+func (s *WalletRepositoryTestSuite) TestUpdateStatusNotFound() {
+	_, err := s.repo.UpdateStatus(s.ctx, s.newWallet())
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func (s *WalletRepositoryTestSuite) TestUpdateTierSuccess() {
+	want, err := s.repo.Create(s.ctx, s.newWallet())
+	s.NoError(err)
+	s.Equal(entity.WalletTierStandard, want.Tier)
+
+	want.Tier = "premium"
+	want.UpdatedAt = want.UpdatedAt.Add(time.Second)
+
+	_, err = s.repo.UpdateTier(s.ctx, want)
+	s.NoError(err)
+
+	got, err := s.repo.Get(s.ctx, want.ID)
+	s.NoError(err)
+	s.Equal(want, got)
+}
+
+func (s *WalletRepositoryTestSuite) TestUpdateTierNotFound() {
+	_, err := s.repo.UpdateTier(s.ctx, s.newWallet())
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func (s *WalletRepositoryTestSuite) TestListIDs() {
+	var ids []string
+	for range 3 {
+		wallet, err := s.repo.Create(s.ctx, s.newWallet())
+		s.NoError(err)
+		ids = append(ids, wallet.ID)
+	}
+
+	got, err := s.repo.ListIDs(s.ctx, "", 2)
+	s.NoError(err)
+	s.Equal(ids[:2], got)
+
+	got, err = s.repo.ListIDs(s.ctx, got[1], 2)
+	s.NoError(err)
+	s.Equal(ids[2:], got)
+
+	got, err = s.repo.ListIDs(s.ctx, ids[2], 2)
+	s.NoError(err)
+	s.NotNil(got)
+	s.Empty(got)
+}
+
+func (s *WalletRepositoryTestSuite) TestGetByReferenceIDSuccess() {
+	want, err := s.repo.Create(s.ctx, s.newWallet())
+	s.NoError(err)
+
+	got, err := s.repo.GetByReferenceID(s.ctx, want.ReferenceID)
+	s.NoError(err)
+	s.Equal(want, got)
+}
+
+func (s *WalletRepositoryTestSuite) TestGetByReferenceIDNotFound() {
+	_, err := s.repo.GetByReferenceID(s.ctx, uuid.Must(uuid.NewV7()).String())
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func (s *WalletRepositoryTestSuite) createWallet(referenceID string, balance int64) entity.Wallet {
+	want, err := entity.NewWallet(referenceID, "EUR")
+	s.NoError(err)
+
+	want, err = s.repo.Create(s.ctx, want)
+	s.NoError(err)
+
+	projection := entity.NewWalletProjection(want.ID, uuid.Must(uuid.NewV7()).String(), decimal.NewFromInt(balance), decimal.Zero, decimal.Zero)
+	_, err = wallet.NewProjectionRepository(s.pgxPoolWrapper).Create(s.ctx, projection)
+	s.NoError(err)
+
+	return want
+}
+
+func (s *WalletRepositoryTestSuite) TestListPagination() {
+	first := s.createWallet("ref1", 10)
+	second := s.createWallet("ref2", 20)
+	third := s.createWallet("ref3", 30)
+
+	got, err := s.repo.List(s.ctx, entity.WalletFilter{Limit: 2})
+	s.NoError(err)
+	s.Equal([]entity.Wallet{third, second}, got)
+
+	got, err = s.repo.List(s.ctx, entity.WalletFilter{Cursor: second.ID, Limit: 2})
+	s.NoError(err)
+	s.Equal([]entity.Wallet{first}, got)
+
+	got, err = s.repo.List(s.ctx, entity.WalletFilter{Cursor: first.ID, Limit: 2})
+	s.NoError(err)
+	s.NotNil(got)
+	s.Empty(got)
+}
+
+func (s *WalletRepositoryTestSuite) TestListReferenceID() {
+	first := s.createWallet("order_1", 0)
+	second := s.createWallet("order%2", 0)
+	s.createWallet("orderx1", 0)
+	s.createWallet("other", 0)
+
+	got, err := s.repo.List(s.ctx, entity.WalletFilter{ReferenceID: "order_1", Limit: 10})
+	s.NoError(err)
+	s.Equal([]entity.Wallet{first}, got)
+
+	got, err = s.repo.List(s.ctx, entity.WalletFilter{ReferenceIDPrefix: "order_", Limit: 10}) // the underscore is matched literally
+	s.NoError(err)
+	s.Equal([]entity.Wallet{first}, got)
+
+	got, err = s.repo.List(s.ctx, entity.WalletFilter{ReferenceIDPrefix: "order%", Limit: 10})
+	s.NoError(err)
+	s.Equal([]entity.Wallet{second}, got)
+}
+
+func (s *WalletRepositoryTestSuite) TestListCreatedAt() {
+	first := s.createWallet("ref1", 0)
+	second := s.createWallet("ref2", 0)
+
+	got, err := s.repo.List(s.ctx, entity.WalletFilter{CreatedAfter: second.CreatedAt, Limit: 10})
+	s.NoError(err)
+	s.Equal([]entity.Wallet{second}, got)
+
+	got, err = s.repo.List(s.ctx, entity.WalletFilter{CreatedBefore: second.CreatedAt, Limit: 10})
+	s.NoError(err)
+	s.Equal([]entity.Wallet{first}, got)
+}
+
+func (s *WalletRepositoryTestSuite) TestListBalance() {
+	s.createWallet("ref1", 10)
+	second := s.createWallet("ref2", 20)
+	s.createWallet("ref3", 30)
+
+	minBalance := decimal.NewFromInt(15)
+	maxBalance := decimal.NewFromInt(20)
+
+	got, err := s.repo.List(s.ctx, entity.WalletFilter{MinBalance: &minBalance, MaxBalance: &maxBalance, Limit: 10})
+	s.NoError(err)
+	s.Equal([]entity.Wallet{second}, got)
+}
+
+func (s *WalletRepositoryTestSuite) TestListMetadata() {
+	want, err := entity.NewWallet("ref1", "EUR")
+	s.NoError(err)
+	want.Metadata = entity.Metadata{"channel": "web", "order_id": "1"}
+
+	want, err = s.repo.Create(s.ctx, want)
+	s.NoError(err)
+
+	projection := entity.NewWalletProjection(want.ID, uuid.Must(uuid.NewV7()).String(), decimal.Zero, decimal.Zero, decimal.Zero)
+	_, err = wallet.NewProjectionRepository(s.pgxPoolWrapper).Create(s.ctx, projection)
+	s.NoError(err)
+
+	s.createWallet("ref2", 10)
+
+	got, err := s.repo.Get(s.ctx, want.ID)
+	s.NoError(err)
+	s.Equal(want.Metadata, got.Metadata)
+
+	wallets, err := s.repo.List(s.ctx, entity.WalletFilter{Metadata: entity.Metadata{"channel": "web"}, Limit: 10})
+	s.NoError(err)
+	s.Equal([]entity.Wallet{want}, wallets)
+
+	wallets, err = s.repo.List(s.ctx, entity.WalletFilter{Metadata: entity.Metadata{"channel": "app"}, Limit: 10})
+	s.NoError(err)
+	s.Empty(wallets)
+}
+
 func TestWalletRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(WalletRepositoryTestSuite))
 }
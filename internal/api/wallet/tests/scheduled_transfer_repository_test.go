@@ -0,0 +1,165 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/buni/wallet/internal/pkg/testing/dt"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+type ScheduledTransferRepositoryTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	pgxPoolWrapper *pgxtx.TxWrapper
+	repo           *wallet.ScheduledTransferRepository
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.pgxPoolWrapper = pgxtx.NewTxWrapper(dt.DB, pgx.TxOptions{})
+	s.repo = wallet.NewScheduledTransferRepository(s.pgxPoolWrapper)
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) TearDownTest() {
+	_, err := s.pgxPoolWrapper.Exec(s.ctx, "TRUNCATE scheduled_transfers")
+	s.NoError(err)
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) newScheduledTransfer(walletID string, executeAt time.Time) entity.ScheduledTransfer {
+	transfer, err := entity.NewScheduledTransfer(
+		walletID,
+		uuid.Must(uuid.NewV7()).String(),
+		uuid.Must(uuid.NewV7()).String(),
+		entity.TransferDirectionCredit,
+		decimal.NewFromInt(10),
+		"EUR",
+		entity.TransferStatusCompleted,
+		nil,
+		executeAt,
+	)
+	s.Require().NoError(err)
+
+	return transfer
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) TestCreateAndGetForUpdate() {
+	transfer := s.newScheduledTransfer(uuid.Must(uuid.NewV7()).String(), time.Now().Add(time.Hour))
+
+	_, err := s.repo.Create(s.ctx, transfer)
+	s.NoError(err)
+
+	got, err := s.repo.GetForUpdate(s.ctx, transfer.WalletID, transfer.TransferID)
+	s.NoError(err)
+	s.Equal(transfer.ID, got.ID)
+	s.Equal(entity.ScheduleStatusScheduled, got.Status)
+	s.Equal(entity.TransferDirectionCredit, got.Direction)
+	s.True(transfer.Amount.Equal(got.Amount))
+	s.Equal(transfer.ExecuteAt, got.ExecuteAt)
+	s.Nil(got.EventID)
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) TestCreateDuplicateTransferID() {
+	transfer := s.newScheduledTransfer(uuid.Must(uuid.NewV7()).String(), time.Now().Add(time.Hour))
+
+	_, err := s.repo.Create(s.ctx, transfer)
+	s.NoError(err)
+
+	duplicate := s.newScheduledTransfer(transfer.WalletID, time.Now().Add(time.Hour))
+	duplicate.TransferID = transfer.TransferID
+
+	_, err = s.repo.Create(s.ctx, duplicate)
+	s.ErrorContains(err, "idx_scheduled_transfers_wallet_id_transfer_id")
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) TestGetForUpdateNotFound() {
+	_, err := s.repo.GetForUpdate(s.ctx, uuid.Must(uuid.NewV7()).String(), uuid.Must(uuid.NewV7()).String())
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) TestUpdate() {
+	transfer := s.newScheduledTransfer(uuid.Must(uuid.NewV7()).String(), time.Now().Add(time.Hour))
+
+	_, err := s.repo.Create(s.ctx, transfer)
+	s.NoError(err)
+
+	eventID := uuid.Must(uuid.NewV7()).String()
+	transfer.Settle(entity.ScheduleStatusExecuted, &eventID, "", time.Now().Add(time.Minute))
+
+	s.NoError(s.repo.Update(s.ctx, transfer))
+
+	got, err := s.repo.GetForUpdate(s.ctx, transfer.WalletID, transfer.TransferID)
+	s.NoError(err)
+	s.Equal(entity.ScheduleStatusExecuted, got.Status)
+	s.Equal(&eventID, got.EventID)
+	s.Equal(transfer.UpdatedAt, got.UpdatedAt)
+
+	missing := s.newScheduledTransfer(uuid.Must(uuid.NewV7()).String(), time.Now())
+	s.ErrorIs(s.repo.Update(s.ctx, missing), entity.ErrEntityNotFound)
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) TestList() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+
+	later := s.newScheduledTransfer(walletID, time.Now().Add(2*time.Hour))
+	sooner := s.newScheduledTransfer(walletID, time.Now().Add(time.Hour))
+	cancelled := s.newScheduledTransfer(walletID, time.Now().Add(time.Hour))
+	cancelled.Settle(entity.ScheduleStatusCancelled, nil, "", time.Now())
+	other := s.newScheduledTransfer(uuid.Must(uuid.NewV7()).String(), time.Now().Add(time.Hour))
+
+	for _, transfer := range []entity.ScheduledTransfer{later, sooner, cancelled, other} {
+		_, err := s.repo.Create(s.ctx, transfer)
+		s.NoError(err)
+	}
+
+	transfers, err := s.repo.List(s.ctx, entity.ScheduledTransferFilter{WalletID: walletID})
+	s.NoError(err)
+	s.Len(transfers, 3)
+
+	transfers, err = s.repo.List(s.ctx, entity.ScheduledTransferFilter{WalletID: walletID, Status: entity.ScheduleStatusScheduled})
+	s.NoError(err)
+	s.Require().Len(transfers, 2)
+	s.Equal(sooner.TransferID, transfers[0].TransferID)
+	s.Equal(later.TransferID, transfers[1].TransferID)
+
+	transfers, err = s.repo.List(s.ctx, entity.ScheduledTransferFilter{WalletID: uuid.Must(uuid.NewV7()).String()})
+	s.NoError(err)
+	s.NotNil(transfers)
+	s.Empty(transfers)
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) TestListDue() {
+	now := time.Now().UTC()
+
+	due := s.newScheduledTransfer(uuid.Must(uuid.NewV7()).String(), now.Add(-time.Minute))
+	dueEarlier := s.newScheduledTransfer(uuid.Must(uuid.NewV7()).String(), now.Add(-time.Hour))
+	notDue := s.newScheduledTransfer(uuid.Must(uuid.NewV7()).String(), now.Add(time.Hour))
+	executed := s.newScheduledTransfer(uuid.Must(uuid.NewV7()).String(), now.Add(-time.Minute))
+	executed.Settle(entity.ScheduleStatusExecuted, nil, "", now)
+
+	for _, transfer := range []entity.ScheduledTransfer{due, dueEarlier, notDue, executed} {
+		_, err := s.repo.Create(s.ctx, transfer)
+		s.NoError(err)
+	}
+
+	transfers, err := s.repo.ListDue(s.ctx, now, 10)
+	s.NoError(err)
+	s.Require().Len(transfers, 2)
+	s.Equal(dueEarlier.TransferID, transfers[0].TransferID)
+	s.Equal(due.TransferID, transfers[1].TransferID)
+
+	transfers, err = s.repo.ListDue(s.ctx, now, 1)
+	s.NoError(err)
+	s.Len(transfers, 1)
+}
+
+func TestScheduledTransferRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduledTransferRepositoryTestSuite))
+}
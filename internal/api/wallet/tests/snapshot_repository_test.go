@@ -0,0 +1,97 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/buni/wallet/internal/pkg/testing/dt"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+type WalletSnapshotRepositoryTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	pgxPoolWrapper *pgxtx.TxWrapper
+	repo           *wallet.SnapshotRepository
+}
+
+func (s *WalletSnapshotRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.pgxPoolWrapper = pgxtx.NewTxWrapper(dt.DB, pgx.TxOptions{})
+	s.repo = wallet.NewSnapshotRepository(s.pgxPoolWrapper)
+}
+
+func (s *WalletSnapshotRepositoryTestSuite) TearDownTest() {
+	_, err := s.pgxPoolWrapper.Exec(s.ctx, "TRUNCATE wallet_snapshots")
+	s.NoError(err)
+}
+
+func (s *WalletSnapshotRepositoryTestSuite) newSnapshot(walletID string, sequence int64) entity.WalletSnapshot {
+	pendingEvent, err := entity.NewWalletEvent(uuid.Must(uuid.NewV7()).String(), "", walletID, decimal.NewFromInt(25), entity.EventTypeDebitTransfer, entity.TransferStatusPending)
+	s.NoError(err)
+	pendingEvent.Sequence = sequence
+
+	projection := entity.NewWalletProjection(walletID, pendingEvent.ID, decimal.NewFromInt(100), decimal.NewFromInt(25), decimal.NewFromInt(0))
+	projection.LastSequence = sequence
+	projection.CreditLimit = decimal.NewFromInt(40)
+
+	return entity.NewWalletSnapshot(projection, entity.PendingTransfers{pendingEvent.TransferID: pendingEvent})
+}
+
+func (s *WalletSnapshotRepositoryTestSuite) TestCreateSuccess() {
+	snapshot, err := s.repo.Create(s.ctx, s.newSnapshot(uuid.Must(uuid.NewV7()).String(), 1))
+	s.NoError(err)
+	s.NotEmpty(snapshot.WalletID)
+}
+
+func (s *WalletSnapshotRepositoryTestSuite) TestCreateSameSequenceIsNoop() {
+	snapshot := s.newSnapshot(uuid.Must(uuid.NewV7()).String(), 1)
+
+	_, err := s.repo.Create(s.ctx, snapshot)
+	s.NoError(err)
+
+	_, err = s.repo.Create(s.ctx, snapshot)
+	s.NoError(err)
+}
+
+func (s *WalletSnapshotRepositoryTestSuite) TestGetLatestSuccess() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+
+	_, err := s.repo.Create(s.ctx, s.newSnapshot(walletID, 100))
+	s.NoError(err)
+
+	want, err := s.repo.Create(s.ctx, s.newSnapshot(walletID, 200))
+	s.NoError(err)
+
+	_, err = s.repo.Create(s.ctx, s.newSnapshot(uuid.Must(uuid.NewV7()).String(), 300))
+	s.NoError(err)
+
+	got, err := s.repo.GetLatest(s.ctx, walletID)
+	s.NoError(err)
+	s.Equal(want.LastSequence, got.LastSequence)
+	s.Equal(want.LastEventID, got.LastEventID)
+	s.True(want.Balance.Equal(got.Balance))
+	s.True(want.CreditLimit.Equal(got.CreditLimit))
+	s.Len(got.PendingTransfers, 1)
+
+	for transferID, event := range want.PendingTransfers {
+		s.Equal(event.ID, got.PendingTransfers[transferID].ID)
+		s.True(event.Amount.Equal(got.PendingTransfers[transferID].Amount))
+		s.Equal(event.EventType, got.PendingTransfers[transferID].EventType)
+	}
+}
+
+func (s *WalletSnapshotRepositoryTestSuite) TestGetLatestNotFound() {
+	_, err := s.repo.GetLatest(s.ctx, uuid.Must(uuid.NewV7()).String())
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func TestWalletSnapshotRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(WalletSnapshotRepositoryTestSuite))
+}
@@ -0,0 +1,186 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/buni/wallet/internal/pkg/testing/dt"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+type WalletTransferRepositoryTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	pgxPoolWrapper *pgxtx.TxWrapper
+	repo           *wallet.TransferRepository
+}
+
+func (s *WalletTransferRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.pgxPoolWrapper = pgxtx.NewTxWrapper(dt.DB, pgx.TxOptions{})
+	s.repo = wallet.NewTransferRepository(s.pgxPoolWrapper)
+}
+
+func (s *WalletTransferRepositoryTestSuite) TearDownTest() {
+	_, err := s.pgxPoolWrapper.Exec(s.ctx, "TRUNCATE wallet_transfers")
+	s.NoError(err)
+}
+
+func (s *WalletTransferRepositoryTestSuite) newTransfer(walletID string, status entity.TransferStatus, sequence int64) entity.WalletTransfer {
+	tt := time.Now().UTC().Truncate(time.Microsecond)
+
+	return entity.WalletTransfer{
+		WalletID:     walletID,
+		TransferID:   uuid.Must(uuid.NewV7()).String(),
+		ReferenceID:  uuid.Must(uuid.NewV7()).String(),
+		Amount:       decimal.NewFromInt(10),
+		Direction:    entity.TransferDirectionDebit,
+		Status:       status,
+		LastSequence: sequence,
+		CreatedAt:    tt,
+		UpdatedAt:    tt,
+	}
+}
+
+func (s *WalletTransferRepositoryTestSuite) TestUpsertAndGet() {
+	transfer := s.newTransfer(uuid.Must(uuid.NewV7()).String(), entity.TransferStatusPending, 1)
+
+	err := s.repo.Upsert(s.ctx, transfer)
+	s.NoError(err)
+
+	transfer.Status = entity.TransferStatusCompleted
+	transfer.CapturedAmount = transfer.Amount.Sub(decimal.NewFromInt(1)) // partial capture
+	transfer.LastSequence = 2
+	transfer.UpdatedAt = transfer.UpdatedAt.Add(time.Second)
+
+	err = s.repo.Upsert(s.ctx, transfer)
+	s.NoError(err)
+
+	got, err := s.repo.Get(s.ctx, transfer.WalletID, transfer.TransferID)
+	s.NoError(err)
+	s.Equal(transfer.Status, got.Status)
+	s.Equal(transfer.LastSequence, got.LastSequence)
+	s.Equal(transfer.Direction, got.Direction)
+	s.True(transfer.Amount.Equal(got.Amount))
+	s.True(transfer.CapturedAmount.Equal(got.CapturedAmount))
+	s.Equal(transfer.CreatedAt, got.CreatedAt)
+	s.Equal(transfer.UpdatedAt, got.UpdatedAt)
+}
+
+func (s *WalletTransferRepositoryTestSuite) TestListMetadata() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+
+	tagged := s.newTransfer(walletID, entity.TransferStatusPending, 1)
+	tagged.Metadata = entity.Metadata{"order_id": "1"}
+	untagged := s.newTransfer(walletID, entity.TransferStatusPending, 2)
+
+	for _, transfer := range []entity.WalletTransfer{tagged, untagged} {
+		s.NoError(s.repo.Upsert(s.ctx, transfer))
+	}
+
+	got, err := s.repo.Get(s.ctx, walletID, tagged.TransferID)
+	s.NoError(err)
+	s.Equal(tagged.Metadata, got.Metadata)
+
+	transfers, err := s.repo.List(s.ctx, entity.WalletTransferFilter{WalletID: walletID, Metadata: entity.Metadata{"order_id": "1"}})
+	s.NoError(err)
+	s.Len(transfers, 1)
+	s.Equal(tagged.TransferID, transfers[0].TransferID)
+}
+
+func (s *WalletTransferRepositoryTestSuite) TestUpsertOlderStateIsIgnored() {
+	transfer := s.newTransfer(uuid.Must(uuid.NewV7()).String(), entity.TransferStatusCompleted, 2)
+
+	err := s.repo.Upsert(s.ctx, transfer)
+	s.NoError(err)
+
+	stale := transfer
+	stale.Status = entity.TransferStatusPending
+	stale.LastSequence = 1
+
+	err = s.repo.Upsert(s.ctx, stale)
+	s.NoError(err)
+
+	got, err := s.repo.Get(s.ctx, transfer.WalletID, transfer.TransferID)
+	s.NoError(err)
+	s.Equal(entity.TransferStatusCompleted, got.Status)
+	s.Equal(int64(2), got.LastSequence)
+}
+
+func (s *WalletTransferRepositoryTestSuite) TestGetNotFound() {
+	_, err := s.repo.Get(s.ctx, uuid.Must(uuid.NewV7()).String(), uuid.Must(uuid.NewV7()).String())
+	s.ErrorIs(err, entity.ErrEntityNotFound)
+}
+
+func (s *WalletTransferRepositoryTestSuite) TestListByStatus() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+
+	pending := s.newTransfer(walletID, entity.TransferStatusPending, 1)
+	completed := s.newTransfer(walletID, entity.TransferStatusCompleted, 2)
+	other := s.newTransfer(uuid.Must(uuid.NewV7()).String(), entity.TransferStatusPending, 1)
+
+	for _, transfer := range []entity.WalletTransfer{pending, completed, other} {
+		s.NoError(s.repo.Upsert(s.ctx, transfer))
+	}
+
+	transfers, err := s.repo.List(s.ctx, entity.WalletTransferFilter{WalletID: walletID})
+	s.NoError(err)
+	s.Len(transfers, 2)
+
+	transfers, err = s.repo.List(s.ctx, entity.WalletTransferFilter{WalletID: walletID, Status: entity.TransferStatusPending})
+	s.NoError(err)
+	s.Len(transfers, 1)
+	s.Equal(pending.TransferID, transfers[0].TransferID)
+
+	transfers, err = s.repo.List(s.ctx, entity.WalletTransferFilter{WalletID: uuid.Must(uuid.NewV7()).String()})
+	s.NoError(err)
+	s.Empty(transfers)
+}
+
+func (s *WalletTransferRepositoryTestSuite) TestListExpired() {
+	walletID := uuid.Must(uuid.NewV7()).String()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	expiredAt := now.Add(-time.Minute)
+	expiredEarlierAt := now.Add(-time.Hour)
+	expiresLaterAt := now.Add(time.Hour)
+
+	expired := s.newTransfer(walletID, entity.TransferStatusPending, 1)
+	expired.ExpiresAt = &expiredAt
+
+	expiredEarlier := s.newTransfer(walletID, entity.TransferStatusPending, 2)
+	expiredEarlier.ExpiresAt = &expiredEarlierAt
+
+	notExpired := s.newTransfer(walletID, entity.TransferStatusPending, 3)
+	notExpired.ExpiresAt = &expiresLaterAt
+
+	settled := s.newTransfer(walletID, entity.TransferStatusCompleted, 4)
+	settled.ExpiresAt = &expiredAt
+
+	noExpiry := s.newTransfer(walletID, entity.TransferStatusPending, 5)
+
+	for _, transfer := range []entity.WalletTransfer{expired, expiredEarlier, notExpired, settled, noExpiry} {
+		s.Require().NoError(s.repo.Upsert(s.ctx, transfer))
+	}
+
+	transfers, err := s.repo.ListExpired(s.ctx, now, 10)
+	s.NoError(err)
+	s.Require().Len(transfers, 2)
+	s.Equal(expiredEarlier.TransferID, transfers[0].TransferID)
+	s.Equal(expiredEarlierAt, *transfers[0].ExpiresAt)
+	s.Equal(expired.TransferID, transfers[1].TransferID)
+
+	transfers, err = s.repo.ListExpired(s.ctx, now, 1)
+	s.NoError(err)
+	s.Len(transfers, 1)
+}
+
+func TestWalletTransferRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(WalletTransferRepositoryTestSuite))
+}
@@ -0,0 +1,44 @@
+package wallet
+
+import (
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/shopspring/decimal"
+)
+
+// CreditUsage sums the credit transfers created at or after since, both pending and completed ones count against the limits,
+// failed (reverted) transfers and the fees charged on credits are left out. A partially captured credit counts with the captured amount.
+func CreditUsage(events []entity.WalletEvent, since time.Time) (amount decimal.Decimal, count int64) {
+	failed := map[string]struct{}{}
+	completed := map[string]entity.WalletEvent{}
+
+	for _, event := range events {
+		if event.EventType != entity.EventTypeUpdateTransferStatus {
+			continue
+		}
+
+		switch event.Status {
+		case entity.TransferStatusFailed:
+			failed[event.TransferID] = struct{}{}
+		case entity.TransferStatusCompleted:
+			completed[event.TransferID] = event
+		default: // pending status updates don't change the transfer
+		}
+	}
+
+	for _, event := range events {
+		if event.EventType != entity.EventTypeCreditTransfer || event.CreatedAt.Before(since) || entity.IsFeeTransferID(event.TransferID) {
+			continue
+		}
+
+		if _, ok := failed[event.TransferID]; ok {
+			continue
+		}
+
+		amount = amount.Add(completed[event.TransferID].CapturedAmount(event.Amount))
+		count++
+	}
+
+	return amount, count
+}
@@ -0,0 +1,143 @@
+package wallet_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/buni/wallet/internal/api/app/entity"
+	"github.com/buni/wallet/internal/api/wallet"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreditUsage(t *testing.T) {
+	now := time.Date(2024, 7, 23, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		events         []entity.WalletEvent
+		since          time.Time
+		expectedAmount decimal.Decimal
+		expectedCount  int64
+	}{
+		{
+			name:           "no events",
+			events:         []entity.WalletEvent{},
+			since:          now.Add(-time.Hour),
+			expectedAmount: decimal.Decimal{},
+		},
+		{
+			name: "pending and completed credits count",
+			events: []entity.WalletEvent{
+				{TransferID: "t1", EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(10), CreatedAt: now.Add(-30 * time.Minute)},
+				{TransferID: "t2", EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusPending, Amount: decimal.NewFromInt(5), CreatedAt: now.Add(-10 * time.Minute)},
+				{TransferID: "t2", EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted, CreatedAt: now.Add(-5 * time.Minute)},
+			},
+			since:          now.Add(-time.Hour),
+			expectedAmount: decimal.NewFromInt(15),
+			expectedCount:  2,
+		},
+		{
+			name: "debits, failed credits and credits before the window are left out",
+			events: []entity.WalletEvent{
+				{TransferID: "t1", EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(10), CreatedAt: now.Add(-2 * time.Hour)},
+				{TransferID: "t2", EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(100), CreatedAt: now.Add(-10 * time.Minute)},
+				{TransferID: "t3", EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusPending, Amount: decimal.NewFromInt(20), CreatedAt: now.Add(-10 * time.Minute)},
+				{TransferID: "t3", EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusFailed, CreatedAt: now.Add(-5 * time.Minute)},
+				{TransferID: "t4", EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(7), CreatedAt: now.Add(-time.Hour)},
+			},
+			since:          now.Add(-time.Hour),
+			expectedAmount: decimal.NewFromInt(7),
+			expectedCount:  1,
+		},
+		{
+			name: "partially captured credits count with the captured amount",
+			events: []entity.WalletEvent{
+				{TransferID: "t1", EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusPending, Amount: decimal.NewFromInt(100), CreatedAt: now.Add(-10 * time.Minute)},
+				{TransferID: "t1", EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(80), CreatedAt: now.Add(-5 * time.Minute)},
+			},
+			since:          now.Add(-time.Hour),
+			expectedAmount: decimal.NewFromInt(80),
+			expectedCount:  1,
+		},
+		{
+			name: "fees charged on credits are left out",
+			events: []entity.WalletEvent{
+				{TransferID: "t1", EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(10), CreatedAt: now.Add(-10 * time.Minute)},
+				{TransferID: entity.FeeTransferID("t1"), EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Amount: decimal.NewFromInt(1), CreatedAt: now.Add(-10 * time.Minute)},
+			},
+			since:          now.Add(-time.Hour),
+			expectedAmount: decimal.NewFromInt(10),
+			expectedCount:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, count := wallet.CreditUsage(tt.events, tt.since)
+			assert.True(t, tt.expectedAmount.Equal(amount), "expected %s, got %s", tt.expectedAmount, amount)
+			assert.Equal(t, tt.expectedCount, count)
+		})
+	}
+}
+
+func TestParseVelocityLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		expected    entity.VelocityLimits
+		expectedErr error
+	}{
+		{
+			name:     "empty spec",
+			spec:     "",
+			expected: entity.VelocityLimits{},
+		},
+		{
+			name: "multiple tiers",
+			spec: "standard:credit_amount:24h:1000, standard:credit_count:1h:10,premium:credit_amount:24h:5000.50",
+			expected: entity.VelocityLimits{
+				"standard": {
+					{Kind: entity.LimitKindCreditAmount, Window: 24 * time.Hour, Max: decimal.NewFromInt(1000)},
+					{Kind: entity.LimitKindCreditCount, Window: time.Hour, Max: decimal.NewFromInt(10)},
+				},
+				"premium": {
+					{Kind: entity.LimitKindCreditAmount, Window: 24 * time.Hour, Max: decimal.RequireFromString("5000.50")},
+				},
+			},
+		},
+		{
+			name:        "missing part",
+			spec:        "standard:credit_amount:24h",
+			expectedErr: entity.ErrInvalidVelocityLimit,
+		},
+		{
+			name:        "unknown kind",
+			spec:        "standard:debit_amount:24h:100",
+			expectedErr: entity.ErrInvalidVelocityLimit,
+		},
+		{
+			name:        "invalid window",
+			spec:        "standard:credit_amount:0s:100",
+			expectedErr: entity.ErrInvalidVelocityLimit,
+		},
+		{
+			name:        "fractional count",
+			spec:        "standard:credit_count:1h:1.5",
+			expectedErr: entity.ErrInvalidVelocityLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits, err := entity.ParseVelocityLimits(tt.spec)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, limits)
+		})
+	}
+}
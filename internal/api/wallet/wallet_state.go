@@ -9,14 +9,18 @@ import (
 )
 
 func ProcessEvents(ctx context.Context, projection *entity.WalletProjection, events []entity.WalletEvent) error {
+	return ApplyEvents(ctx, projection, entity.PendingTransfers{}, events)
+}
+
+// ApplyEvents folds events on top of an already built projection, pending holds the transfers that are still open at that point
+// and is updated in place, this allows resuming a replay from a snapshot.
+func ApplyEvents(ctx context.Context, projection *entity.WalletProjection, pending entity.PendingTransfers, events []entity.WalletEvent) error {
 	logger := sloglog.FromContext(ctx)
 	if len(events) == 0 {
 		return nil
 	}
 
-	eventMapping := map[string]int{}
-
-	for k, event := range events {
+	for _, event := range events {
 		if event.Version > entity.WalletEventVersionOne { // assumes we would do backwards compatibility, but for event versions we can't support in this build we return an error early
 			return entity.ErrUnsupportedEventVersion
 		}
@@ -24,11 +28,11 @@ func ProcessEvents(ctx context.Context, projection *entity.WalletProjection, eve
 		switch event.EventType {
 		case entity.EventTypeDebitTransfer:
 			if event.Status == entity.TransferStatusPending {
-				if _, ok := eventMapping[event.TransferID]; ok {
+				if _, ok := pending[event.TransferID]; ok {
 					logger.WarnContext(ctx, "transfer id already exists", slog.String("transfer_id", event.TransferID), slog.Any("event", event))
 					continue
 				}
-				eventMapping[event.TransferID] = k
+				pending[event.TransferID] = event
 				projection.PendingDebit = projection.PendingDebit.Add(event.Amount)
 				continue
 			}
@@ -43,11 +47,11 @@ func ProcessEvents(ctx context.Context, projection *entity.WalletProjection, eve
 
 		case entity.EventTypeCreditTransfer:
 			if event.Status == entity.TransferStatusPending {
-				if _, ok := eventMapping[event.TransferID]; ok {
+				if _, ok := pending[event.TransferID]; ok {
 					logger.WarnContext(ctx, "transfer id already exists", slog.String("transfer_id", event.TransferID), slog.Any("event", event))
 					continue
 				}
-				eventMapping[event.TransferID] = k
+				pending[event.TransferID] = event
 				projection.PendingCredit = projection.PendingCredit.Add(event.Amount)
 			}
 
@@ -58,14 +62,12 @@ func ProcessEvents(ctx context.Context, projection *entity.WalletProjection, eve
 			projection.Balance = projection.Balance.Sub(event.Amount) // as long as the transfer hasn't failed we remove the amount from the balance
 
 		case entity.EventTypeUpdateTransferStatus:
-			idx, ok := eventMapping[event.TransferID]
+			desiredEvent, ok := pending[event.TransferID]
 			if !ok {
 				logger.WarnContext(ctx, "transfer id not found", slog.String("transfer_id", event.TransferID), slog.Any("event", event))
 				continue // can't find the transfer id, skip since we mostly likely already processed this event, there is a chance that the event is out of order too and if that is a possibility we can add a safe guard
 			}
 
-			desiredEvent := events[idx]
-
 			if desiredEvent.EventType == entity.EventTypeDebitTransfer {
 				if event.Status == entity.TransferStatusPending {
 					continue
@@ -77,10 +79,10 @@ func ProcessEvents(ctx context.Context, projection *entity.WalletProjection, eve
 
 				if event.Status == entity.TransferStatusCompleted {
 					projection.PendingDebit = projection.PendingDebit.Sub(desiredEvent.Amount)
-					projection.Balance = projection.Balance.Add(desiredEvent.Amount)
+					projection.Balance = projection.Balance.Add(event.CapturedAmount(desiredEvent.Amount)) // only the captured part is added, the rest is dropped
 				}
 
-				delete(eventMapping, event.TransferID) // remove the transfer id from the map since we have processed it and we want to avoid overriding the status of the transfer
+				delete(pending, event.TransferID) // remove the transfer id from the map since we have processed it and we want to avoid overriding the status of the transfer
 			}
 
 			if desiredEvent.EventType == entity.EventTypeCreditTransfer {
@@ -95,11 +97,15 @@ func ProcessEvents(ctx context.Context, projection *entity.WalletProjection, eve
 
 				if event.Status == entity.TransferStatusCompleted {
 					projection.PendingCredit = projection.PendingCredit.Sub(desiredEvent.Amount)
+					projection.Balance = projection.Balance.Add(desiredEvent.Amount.Sub(event.CapturedAmount(desiredEvent.Amount))) // release the part that wasn't captured
 				}
 
-				delete(eventMapping, event.TransferID)
+				delete(pending, event.TransferID)
 			}
 
+		case entity.EventTypeCreditLimitChanged:
+			projection.CreditLimit = event.Amount
+
 		case entity.EventTypeInvalid:
 			return entity.ErrInvalidEventType
 		default:
@@ -110,6 +116,80 @@ func ProcessEvents(ctx context.Context, projection *entity.WalletProjection, eve
 	lastIdx := len(events) - 1
 	projection.WalletID = events[lastIdx].WalletID
 	projection.LastEventID = events[lastIdx].ID
+	projection.LastSequence = events[lastIdx].Sequence
 
 	return nil
 }
+
+// BuildTransfer folds the events of a single transfer into its current state, it follows the same rules as ApplyEvents,
+// the first debit/credit event opens the transfer and only a pending transfer can be completed or reverted.
+// If none of the events opens a transfer entity.ErrEntityNotFound is returned.
+func BuildTransfer(ctx context.Context, events []entity.WalletEvent) (transfer entity.WalletTransfer, err error) {
+	logger := sloglog.FromContext(ctx)
+	opened := false
+
+	for _, event := range events {
+		if event.Version > entity.WalletEventVersionOne {
+			return entity.WalletTransfer{}, entity.ErrUnsupportedEventVersion
+		}
+
+		switch event.EventType {
+		case entity.EventTypeDebitTransfer, entity.EventTypeCreditTransfer:
+			if opened {
+				logger.WarnContext(ctx, "transfer id already exists", slog.String("transfer_id", event.TransferID), slog.Any("event", event))
+				continue
+			}
+
+			direction := entity.TransferDirectionDebit
+			if event.EventType == entity.EventTypeCreditTransfer {
+				direction = entity.TransferDirectionCredit
+			}
+
+			opened = true
+			transfer = entity.WalletTransfer{
+				WalletID:     event.WalletID,
+				TransferID:   event.TransferID,
+				ReferenceID:  event.ReferenceID,
+				Amount:       event.Amount,
+				Direction:    direction,
+				Status:       event.Status,
+				LastSequence: event.Sequence,
+				ExpiresAt:    event.ExpiresAt,
+				Metadata:     event.Metadata,
+				CreatedAt:    event.CreatedAt,
+				UpdatedAt:    event.CreatedAt,
+			}
+
+			if event.Status == entity.TransferStatusCompleted {
+				transfer.CapturedAmount = event.Amount
+			}
+
+		case entity.EventTypeUpdateTransferStatus:
+			if !opened || transfer.Status != entity.TransferStatusPending || event.Status == entity.TransferStatusPending {
+				continue // same as ApplyEvents, the status of a transfer that isn't pending can't be overridden
+			}
+
+			if event.Status == entity.TransferStatusCompleted {
+				transfer.CapturedAmount = event.CapturedAmount(transfer.Amount)
+			}
+
+			transfer.Status = event.Status
+			transfer.LastSequence = event.Sequence
+			transfer.UpdatedAt = event.CreatedAt
+
+		case entity.EventTypeCreditLimitChanged:
+			continue // not part of a transfer
+
+		case entity.EventTypeInvalid:
+			return entity.WalletTransfer{}, entity.ErrInvalidEventType
+		default:
+			return entity.WalletTransfer{}, entity.ErrUnsupportedEventType
+		}
+	}
+
+	if !opened {
+		return entity.WalletTransfer{}, entity.ErrEntityNotFound
+	}
+
+	return transfer, nil
+}
@@ -2,7 +2,9 @@ package wallet_test
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/buni/wallet/internal/api/app/entity"
 	"github.com/buni/wallet/internal/api/wallet"
@@ -64,6 +66,7 @@ func TestProcessEvents(t *testing.T) {
 					TransferID: "debit",
 					Amount:     decimal.NewFromInt(50),
 					Status:     entity.TransferStatusFailed,
+					Sequence:   1,
 				},
 				{
 					ID:         "debit0",
@@ -72,6 +75,7 @@ func TestProcessEvents(t *testing.T) {
 					TransferID: "credit123",
 					Amount:     decimal.NewFromInt(50),
 					Status:     entity.TransferStatusFailed,
+					Sequence:   2,
 				},
 				{
 					ID:         "debit1",
@@ -80,6 +84,7 @@ func TestProcessEvents(t *testing.T) {
 					TransferID: "debit1",
 					Amount:     decimal.NewFromInt(100),
 					Status:     entity.TransferStatusCompleted,
+					Sequence:   3,
 				},
 				{
 					ID:         "debit2",
@@ -88,12 +93,54 @@ func TestProcessEvents(t *testing.T) {
 					TransferID: "debit2",
 					Amount:     decimal.NewFromInt(50),
 					Status:     entity.TransferStatusCompleted,
+					Sequence:   4,
 				},
 			},
 			expected: entity.WalletProjection{
-				WalletID:    "wallet1",
-				Balance:     decimal.NewFromInt(150),
-				LastEventID: "debit2",
+				WalletID:     "wallet1",
+				Balance:      decimal.NewFromInt(150),
+				LastEventID:  "debit2",
+				LastSequence: 4,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "credit limit changed event",
+			events: []entity.WalletEvent{
+				{
+					ID:         "limit1",
+					WalletID:   "wallet1",
+					EventType:  entity.EventTypeCreditLimitChanged,
+					TransferID: "limit1",
+					Amount:     decimal.NewFromInt(100),
+					Status:     entity.TransferStatusCompleted,
+					Sequence:   1,
+				},
+				{
+					ID:         "credit1",
+					WalletID:   "wallet1",
+					EventType:  entity.EventTypeCreditTransfer,
+					TransferID: "credit1",
+					Amount:     decimal.NewFromInt(30),
+					Status:     entity.TransferStatusCompleted,
+					Sequence:   2,
+				},
+				{
+					ID:         "limit2",
+					WalletID:   "wallet1",
+					EventType:  entity.EventTypeCreditLimitChanged,
+					TransferID: "limit2",
+					Amount:     decimal.NewFromInt(20),
+					Status:     entity.TransferStatusCompleted,
+					Sequence:   3,
+				},
+			},
+			expected: entity.WalletProjection{
+				WalletID:     "wallet1",
+				Balance:      decimal.NewFromInt(-30),
+				CreditLimit:  decimal.NewFromInt(20),
+				LastEventID:  "limit2",
+				LastSequence: 3,
 			},
 			expectedErr: nil,
 		},
@@ -342,6 +389,53 @@ func TestProcessEvents(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "partial captures",
+			events: []entity.WalletEvent{
+				{
+					WalletID:   "wallet1",
+					EventType:  entity.EventTypeDebitTransfer,
+					TransferID: "debit1",
+					Amount:     decimal.NewFromInt(100),
+					Status:     entity.TransferStatusCompleted,
+				},
+				{
+					WalletID:   "wallet1",
+					EventType:  entity.EventTypeCreditTransfer,
+					TransferID: "credit1",
+					Amount:     decimal.NewFromInt(50),
+					Status:     entity.TransferStatusPending,
+				},
+				{
+					WalletID:   "wallet1",
+					EventType:  entity.EventTypeUpdateTransferStatus,
+					TransferID: "credit1",
+					Amount:     decimal.NewFromInt(30), // the other 20 are released back to the balance
+					Status:     entity.TransferStatusCompleted,
+				},
+				{
+					WalletID:   "wallet1",
+					EventType:  entity.EventTypeDebitTransfer,
+					TransferID: "debit2",
+					Amount:     decimal.NewFromInt(40),
+					Status:     entity.TransferStatusPending,
+				},
+				{
+					WalletID:   "wallet1",
+					EventType:  entity.EventTypeUpdateTransferStatus,
+					TransferID: "debit2",
+					Amount:     decimal.NewFromInt(25),
+					Status:     entity.TransferStatusCompleted,
+				},
+			},
+			expected: entity.WalletProjection{
+				WalletID:      "wallet1",
+				Balance:       decimal.NewFromInt(95),
+				PendingDebit:  decimal.NewFromInt(40).Sub(decimal.NewFromInt(40)),
+				PendingCredit: decimal.NewFromInt(50).Sub(decimal.NewFromInt(50)),
+			},
+			expectedErr: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -360,3 +454,217 @@ func TestProcessEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyEventsFromSnapshotMatchesFullReplay(t *testing.T) {
+	events := []entity.WalletEvent{
+		{ID: "1", WalletID: "wallet1", Version: entity.WalletEventVersionOne, EventType: entity.EventTypeDebitTransfer, TransferID: "debit1", Amount: decimal.NewFromInt(500), Status: entity.TransferStatusCompleted, Sequence: 1},
+		{ID: "2", WalletID: "wallet1", Version: entity.WalletEventVersionOne, EventType: entity.EventTypeDebitTransfer, TransferID: "debit2", Amount: decimal.NewFromInt(120), Status: entity.TransferStatusPending, Sequence: 2},
+		{ID: "3", WalletID: "wallet1", Version: entity.WalletEventVersionOne, EventType: entity.EventTypeCreditTransfer, TransferID: "credit1", Amount: decimal.NewFromInt(80), Status: entity.TransferStatusPending, Sequence: 3},
+		{ID: "4", WalletID: "wallet1", Version: entity.WalletEventVersionOne, EventType: entity.EventTypeCreditTransfer, TransferID: "credit2", Amount: decimal.NewFromInt(30), Status: entity.TransferStatusPending, Sequence: 4},
+		{ID: "5", WalletID: "wallet1", Version: entity.WalletEventVersionOne, EventType: entity.EventTypeUpdateTransferStatus, TransferID: "debit2", Status: entity.TransferStatusCompleted, Sequence: 5},
+		{ID: "6", WalletID: "wallet1", Version: entity.WalletEventVersionOne, EventType: entity.EventTypeUpdateTransferStatus, TransferID: "credit1", Status: entity.TransferStatusFailed, Sequence: 6},
+		{ID: "7", WalletID: "wallet1", Version: entity.WalletEventVersionOne, EventType: entity.EventTypeDebitTransfer, TransferID: "debit3", Amount: decimal.NewFromInt(45), Status: entity.TransferStatusPending, Sequence: 7},
+		{ID: "8", WalletID: "wallet1", Version: entity.WalletEventVersionOne, EventType: entity.EventTypeUpdateTransferStatus, TransferID: "credit2", Status: entity.TransferStatusCompleted, Sequence: 8},
+		{ID: "9", WalletID: "wallet1", Version: entity.WalletEventVersionOne, EventType: entity.EventTypeCreditTransfer, TransferID: "credit3", Amount: decimal.NewFromInt(10), Status: entity.TransferStatusCompleted, Sequence: 9},
+	}
+
+	full := entity.WalletProjection{}
+	err := wallet.ProcessEvents(context.Background(), &full, events)
+	assert.NoError(t, err)
+
+	for split := range len(events) + 1 {
+		head := entity.WalletProjection{}
+		pending := entity.PendingTransfers{}
+		err := wallet.ApplyEvents(context.Background(), &head, pending, events[:split])
+		assert.NoError(t, err)
+
+		// round trip the snapshot through json, the same way it is stored in the database
+		data, err := json.Marshal(entity.NewWalletSnapshot(head, pending).PendingTransfers)
+		assert.NoError(t, err)
+
+		snapshot := entity.NewWalletSnapshot(head, nil)
+		err = json.Unmarshal(data, &snapshot.PendingTransfers)
+		assert.NoError(t, err)
+
+		got := snapshot.Projection()
+		err = wallet.ApplyEvents(context.Background(), &got, snapshot.PendingTransfers, events[split:])
+		assert.NoError(t, err)
+
+		assert.Equal(t, full.WalletID, got.WalletID, "split %d", split)
+		assert.Equal(t, full.LastEventID, got.LastEventID, "split %d", split)
+		assert.Equal(t, full.LastSequence, got.LastSequence, "split %d", split)
+		assert.True(t, full.Balance.Equal(got.Balance), "split %d: balance %s != %s", split, full.Balance, got.Balance)
+		assert.True(t, full.PendingDebit.Equal(got.PendingDebit), "split %d: pending debit %s != %s", split, full.PendingDebit, got.PendingDebit)
+		assert.True(t, full.PendingCredit.Equal(got.PendingCredit), "split %d: pending credit %s != %s", split, full.PendingCredit, got.PendingCredit)
+	}
+}
+
+func TestBuildTransfer(t *testing.T) {
+	openedAt := time.Date(2024, 7, 17, 10, 0, 0, 0, time.UTC)
+	closedAt := openedAt.Add(time.Minute)
+	expiresAt := openedAt.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		events      []entity.WalletEvent
+		expected    entity.WalletTransfer
+		expectedErr error
+	}{
+		{
+			name:        "no events",
+			events:      []entity.WalletEvent{},
+			expectedErr: entity.ErrEntityNotFound,
+		},
+		{
+			name: "only a status update",
+			events: []entity.WalletEvent{
+				{EventType: entity.EventTypeUpdateTransferStatus, TransferID: "transfer1", Status: entity.TransferStatusCompleted, Version: entity.WalletEventVersionOne},
+			},
+			expectedErr: entity.ErrEntityNotFound,
+		},
+		{
+			name: "unsupported event version",
+			events: []entity.WalletEvent{
+				{EventType: entity.EventTypeDebitTransfer, Version: entity.WalletEventVersionOne + 1},
+			},
+			expectedErr: entity.ErrUnsupportedEventVersion,
+		},
+		{
+			name: "invalid event type",
+			events: []entity.WalletEvent{
+				{EventType: entity.EventTypeInvalid},
+			},
+			expectedErr: entity.ErrInvalidEventType,
+		},
+		{
+			name: "pending debit",
+			events: []entity.WalletEvent{
+				{
+					WalletID: "wallet1", TransferID: "transfer1", ReferenceID: "ref1", Amount: decimal.NewFromInt(10), Sequence: 1, CreatedAt: openedAt,
+					EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusPending, Version: entity.WalletEventVersionOne,
+				},
+			},
+			expected: entity.WalletTransfer{
+				WalletID: "wallet1", TransferID: "transfer1", ReferenceID: "ref1", Amount: decimal.NewFromInt(10), LastSequence: 1,
+				Direction: entity.TransferDirectionDebit, Status: entity.TransferStatusPending, CreatedAt: openedAt, UpdatedAt: openedAt,
+			},
+		},
+		{
+			name: "expiry of the opening event",
+			events: []entity.WalletEvent{
+				{
+					WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(10), Sequence: 1, CreatedAt: openedAt, ExpiresAt: &expiresAt,
+					EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusPending, Version: entity.WalletEventVersionOne,
+				},
+				{
+					WalletID: "wallet1", TransferID: "transfer1", Sequence: 2, CreatedAt: closedAt,
+					EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusFailed, Version: entity.WalletEventVersionOne,
+				},
+			},
+			expected: entity.WalletTransfer{
+				WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(10), LastSequence: 2, ExpiresAt: &expiresAt,
+				Direction: entity.TransferDirectionCredit, Status: entity.TransferStatusFailed, CreatedAt: openedAt, UpdatedAt: closedAt,
+			},
+		},
+		{
+			name: "metadata of the opening event",
+			events: []entity.WalletEvent{
+				{
+					WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(10), Sequence: 1, CreatedAt: openedAt, Metadata: entity.Metadata{"order_id": "1"},
+					EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusPending, Version: entity.WalletEventVersionOne,
+				},
+				{
+					WalletID: "wallet1", TransferID: "transfer1", Sequence: 2, CreatedAt: closedAt,
+					EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted, Version: entity.WalletEventVersionOne,
+				},
+			},
+			expected: entity.WalletTransfer{
+				WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(10), CapturedAmount: decimal.NewFromInt(10), LastSequence: 2,
+				Metadata: entity.Metadata{"order_id": "1"}, Direction: entity.TransferDirectionDebit, Status: entity.TransferStatusCompleted, CreatedAt: openedAt, UpdatedAt: closedAt,
+			},
+		},
+		{
+			name: "completed credit",
+			events: []entity.WalletEvent{
+				{
+					WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(10), Sequence: 1, CreatedAt: openedAt,
+					EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusPending, Version: entity.WalletEventVersionOne,
+				},
+				{
+					WalletID: "wallet1", TransferID: "transfer1", Sequence: 3, CreatedAt: closedAt,
+					EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted, Version: entity.WalletEventVersionOne,
+				},
+			},
+			expected: entity.WalletTransfer{
+				WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(10), CapturedAmount: decimal.NewFromInt(10), LastSequence: 3,
+				Direction: entity.TransferDirectionCredit, Status: entity.TransferStatusCompleted, CreatedAt: openedAt, UpdatedAt: closedAt,
+			},
+		},
+		{
+			name: "partially captured credit",
+			events: []entity.WalletEvent{
+				{
+					WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(10), Sequence: 1, CreatedAt: openedAt,
+					EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusPending, Version: entity.WalletEventVersionOne,
+				},
+				{
+					WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(8), Sequence: 3, CreatedAt: closedAt,
+					EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusCompleted, Version: entity.WalletEventVersionOne,
+				},
+			},
+			expected: entity.WalletTransfer{
+				WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(10), CapturedAmount: decimal.NewFromInt(8), LastSequence: 3,
+				Direction: entity.TransferDirectionCredit, Status: entity.TransferStatusCompleted, CreatedAt: openedAt, UpdatedAt: closedAt,
+			},
+		},
+		{
+			name: "status of a transfer that wasn't pending is not overridden",
+			events: []entity.WalletEvent{
+				{
+					WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(10), Sequence: 1, CreatedAt: openedAt,
+					EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusCompleted, Version: entity.WalletEventVersionOne,
+				},
+				{
+					WalletID: "wallet1", TransferID: "transfer1", Sequence: 2, CreatedAt: closedAt,
+					EventType: entity.EventTypeUpdateTransferStatus, Status: entity.TransferStatusFailed, Version: entity.WalletEventVersionOne,
+				},
+			},
+			expected: entity.WalletTransfer{
+				WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(10), CapturedAmount: decimal.NewFromInt(10), LastSequence: 1,
+				Direction: entity.TransferDirectionDebit, Status: entity.TransferStatusCompleted, CreatedAt: openedAt, UpdatedAt: openedAt,
+			},
+		},
+		{
+			name: "second transfer with the same id is ignored",
+			events: []entity.WalletEvent{
+				{
+					WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(10), Sequence: 1, CreatedAt: openedAt,
+					EventType: entity.EventTypeDebitTransfer, Status: entity.TransferStatusPending, Version: entity.WalletEventVersionOne,
+				},
+				{
+					WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(99), Sequence: 2, CreatedAt: closedAt,
+					EventType: entity.EventTypeCreditTransfer, Status: entity.TransferStatusCompleted, Version: entity.WalletEventVersionOne,
+				},
+			},
+			expected: entity.WalletTransfer{
+				WalletID: "wallet1", TransferID: "transfer1", Amount: decimal.NewFromInt(10), LastSequence: 1,
+				Direction: entity.TransferDirectionDebit, Status: entity.TransferStatusPending, CreatedAt: openedAt, UpdatedAt: openedAt,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer, err := wallet.BuildTransfer(context.Background(), tt.events)
+
+			if tt.expectedErr != nil {
+				assert.Empty(t, transfer)
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, transfer)
+		})
+	}
+}
@@ -5,16 +5,19 @@ import (
 	"log/slog"
 	"net"
 	"runtime/debug"
+	"time"
 )
 
 type Configuration struct {
 	Database `mapstructure:",squash"`
 	Service  `mapstructure:",squash"`
 	NATS     `mapstructure:",squash"`
+	Wallet   `mapstructure:",squash"`
 }
 
 func (c *Configuration) SetDefaults() {
 	c.Service.SetDefaults()
+	c.Wallet.SetDefaults()
 }
 
 type Database struct {
@@ -87,3 +90,44 @@ func (n NATS) ToURL() string {
 		n.Port,
 	)
 }
+
+type Wallet struct {
+	SnapshotInterval     int64         `json:"wallet_snapshot_interval" mapstructure:"wallet_snapshot_interval"`           // number of events between projection snapshots, 0 disables them
+	ExpiryInterval       time.Duration `json:"wallet_expiry_interval" mapstructure:"wallet_expiry_interval"`               // how often expired pending transfers are reverted, 0 disables the expiry job
+	ExpiryBatchSize      uint64        `json:"wallet_expiry_batch_size" mapstructure:"wallet_expiry_batch_size"`           // max number of transfers expired per run
+	VelocityLimits       string        `json:"wallet_velocity_limits" mapstructure:"wallet_velocity_limits"`               // comma separated key:kind:window:max credit limits keyed by wallet id or tier, empty means no limits
+	MaxBatchSize         int           `json:"wallet_max_batch_size" mapstructure:"wallet_max_batch_size"`                 // max number of items in a batch transfer request
+	LedgerCheckInterval  time.Duration `json:"wallet_ledger_check_interval" mapstructure:"wallet_ledger_check_interval"`   // how often the worker checks that the ledger balances, 0 disables the check
+	FeeSchedules         string        `json:"wallet_fee_schedules" mapstructure:"wallet_fee_schedules"`                   // comma separated key:flat:percent:min:max fee schedules keyed by wallet id or tier, empty means no fees
+	FeeWallets           string        `json:"wallet_fee_wallets" mapstructure:"wallet_fee_wallets"`                       // comma separated currency:wallet id wallets the charged fees are moved to, one per currency
+	ScheduleInterval     time.Duration `json:"wallet_schedule_interval" mapstructure:"wallet_schedule_interval"`           // how often due scheduled transfers are executed, 0 disables the schedule job
+	ScheduleBatchSize    uint64        `json:"wallet_schedule_batch_size" mapstructure:"wallet_schedule_batch_size"`       // max number of scheduled transfers executed per run
+	RecurringInterval    time.Duration `json:"wallet_recurring_interval" mapstructure:"wallet_recurring_interval"`         // how often due recurring transfer occurrences are written, 0 disables the recurring job
+	RecurringBatchSize   uint64        `json:"wallet_recurring_batch_size" mapstructure:"wallet_recurring_batch_size"`     // max number of recurring transfers executed per run
+	FXRates              string        `json:"wallet_fx_rates" mapstructure:"wallet_fx_rates"`                             // comma separated from:to:rate fx rates, the inverse rate is used for the opposite pair
+	FXRatesFile          string        `json:"wallet_fx_rates_file" mapstructure:"wallet_fx_rates_file"`                   // file with one from:to:rate fx rate per line, re-read on every quote, takes precedence over the fx rates
+	FXQuoteTTL           time.Duration `json:"wallet_fx_quote_ttl" mapstructure:"wallet_fx_quote_ttl"`                     // how long a created fx quote can be executed
+	ReconcileInterval    time.Duration `json:"wallet_reconcile_interval" mapstructure:"wallet_reconcile_interval"`         // how often the worker checks the stored projections against their events, 0 disables the reconcile job
+	ReconcileBatchSize   uint64        `json:"wallet_reconcile_batch_size" mapstructure:"wallet_reconcile_batch_size"`     // number of wallets checked per query
+	ReconcileGrace       time.Duration `json:"wallet_reconcile_grace" mapstructure:"wallet_reconcile_grace"`               // how long a projection can lag behind new events before it's reported
+	ReconcileRepair      bool          `json:"wallet_reconcile_repair" mapstructure:"wallet_reconcile_repair"`             // overwrite the mismatched projections with the replayed state
+	IdempotencyInterval  time.Duration `json:"wallet_idempotency_interval" mapstructure:"wallet_idempotency_interval"`     // how often the worker deletes expired idempotency keys, 0 disables the cleanup job
+	IdempotencyBatchSize uint64        `json:"wallet_idempotency_batch_size" mapstructure:"wallet_idempotency_batch_size"` // max number of idempotency keys deleted per query
+}
+
+func (w *Wallet) SetDefaults() {
+	w.SnapshotInterval = 100
+	w.ExpiryInterval = 10 * time.Second
+	w.ExpiryBatchSize = 100
+	w.MaxBatchSize = 100
+	w.LedgerCheckInterval = time.Minute
+	w.ScheduleInterval = 10 * time.Second
+	w.ScheduleBatchSize = 100
+	w.RecurringInterval = time.Minute
+	w.RecurringBatchSize = 100
+	w.FXQuoteTTL = 30 * time.Second
+	w.ReconcileBatchSize = 100
+	w.ReconcileGrace = time.Minute
+	w.IdempotencyInterval = time.Minute
+	w.IdempotencyBatchSize = 1000
+}
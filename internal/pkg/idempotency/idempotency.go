@@ -0,0 +1,168 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/buni/wallet/internal/pkg/render"
+	"github.com/buni/wallet/internal/pkg/sloglog"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/gofrs/uuid"
+)
+
+const (
+	// HeaderKey is the request header holding the client chosen idempotency key.
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed is set on responses that were replayed from a stored record.
+	HeaderReplayed = "Idempotent-Replayed"
+
+	// lockTimeout is how long a request can hold a key before a retry is allowed to take it over,
+	// it covers requests that never finished (e.g. the instance crashed) so their key isn't blocked forever.
+	lockTimeout = time.Minute
+	// retention is how long a key is kept after it was first used, after it the key can be reused and the record is cleaned up.
+	retention = 24 * time.Hour
+)
+
+var (
+	ErrKeyReused         = errors.New("idempotency key was already used for a different request")
+	ErrRequestInProgress = errors.New("a request with the same idempotency key is still in progress")
+	ErrLockLost          = errors.New("idempotency key was taken over by another request")
+)
+
+// Record is the stored outcome of a request made with an idempotency key.
+type Record struct {
+	Key         string    `db:"key"`
+	Fingerprint string    `db:"fingerprint"` // hash of the method, path and body of the request
+	StatusCode  int       `db:"status_code"` // 0 while the request is in progress
+	Body        []byte    `db:"response_body"`
+	LockID      string    `db:"lock_id"` // owner of the key, a request whose key was taken over can't complete or release it anymore
+	ExpiresAt   time.Time `db:"expires_at"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func NewRecord(key, fingerprint string) Record {
+	tt := time.Now().UTC().Truncate(time.Microsecond)
+
+	return Record{
+		Key:         key,
+		Fingerprint: fingerprint,
+		LockID:      uuid.Must(uuid.NewV7()).String(),
+		ExpiresAt:   tt.Add(retention),
+		CreatedAt:   tt,
+		UpdatedAt:   tt,
+	}
+}
+
+type Repository interface {
+	// Create stores the in progress record, if the key is already taken the stored record is returned and created is false.
+	// An in progress record for the same request that wasn't updated since staleBefore is taken over, as is an expired record.
+	Create(ctx context.Context, record Record, staleBefore time.Time) (result Record, created bool, err error)
+	// Complete stores the response of the request, ErrLockLost is returned if the key is no longer held by the record's lock id.
+	Complete(ctx context.Context, record Record) error
+	// Delete releases an in progress key held by the record's lock id, so the request can be retried.
+	Delete(ctx context.Context, record Record) error
+}
+
+// Middleware makes POST requests carrying an Idempotency-Key header safe to retry. The first response for a key is stored
+// and replayed for retries with the same method, path and body, reusing the key for a different request returns 422.
+// Server errors aren't stored, the key is released so the request can be retried. A key can be reused once its record expired.
+func Middleware(repo Repository) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderKey)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			logger := sloglog.FromContext(ctx)
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				render.NewBadRequestErrorResponse(ctx, w, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			record := NewRecord(key, fingerprint(r, body))
+
+			stored, created, err := repo.Create(ctx, record, record.CreatedAt.Add(-lockTimeout))
+			if err != nil {
+				logger.ErrorContext(ctx, "failed to store idempotency key", sloglog.Error(err))
+				render.NewInternalServerErrorResponse(ctx, w, err)
+				return
+			}
+
+			if !created {
+				replay(ctx, w, record, stored)
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			buf := &bytes.Buffer{}
+			ww.Tee(buf)
+
+			next.ServeHTTP(ww, r)
+
+			ctx = context.WithoutCancel(ctx) // the outcome has to be stored even if the client went away
+
+			record.StatusCode = ww.Status()
+			if record.StatusCode == 0 {
+				record.StatusCode = http.StatusOK
+			}
+
+			if record.StatusCode >= http.StatusInternalServerError {
+				err = repo.Delete(ctx, record)
+				if err != nil {
+					logger.ErrorContext(ctx, "failed to release idempotency key", slog.String("key", key), sloglog.Error(err))
+				}
+				return
+			}
+
+			record.Body = buf.Bytes()
+			record.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+			err = repo.Complete(ctx, record)
+			if err != nil {
+				logger.ErrorContext(ctx, "failed to store idempotent response", slog.String("key", key), sloglog.Error(err))
+			}
+		})
+	}
+}
+
+func replay(ctx context.Context, w http.ResponseWriter, record, stored Record) {
+	if stored.Fingerprint != record.Fingerprint {
+		render.NewErrorResponse(ctx, w, http.StatusUnprocessableEntity, render.RequestValidationError, render.NewValidationError(&render.FieldError{
+			Field:   HeaderKey,
+			Message: ErrKeyReused.Error(),
+		}))
+		return
+	}
+
+	if stored.StatusCode == 0 {
+		render.NewErrorResponse(ctx, w, http.StatusConflict, render.ConflictError, ErrRequestInProgress)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(stored.StatusCode)
+	_, _ = w.Write(stored.Body)
+}
+
+func fingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
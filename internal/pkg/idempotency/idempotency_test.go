@@ -0,0 +1,248 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+var errFake = errors.New("fake error")
+
+// fakeRepository keeps the records in memory and takes over stale locks and expired records the same way the postgres repository does.
+type fakeRepository struct {
+	mu        sync.Mutex
+	records   map[string]Record
+	createErr error
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{records: map[string]Record{}}
+}
+
+func (r *fakeRepository) Create(_ context.Context, record Record, staleBefore time.Time) (Record, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.createErr != nil {
+		return Record{}, false, r.createErr
+	}
+
+	stored, ok := r.records[record.Key]
+	stale := stored.StatusCode == 0 && stored.Fingerprint == record.Fingerprint && stored.UpdatedAt.Before(staleBefore)
+	if ok && !stale && stored.ExpiresAt.After(record.CreatedAt) {
+		return stored, false, nil
+	}
+
+	r.records[record.Key] = record
+
+	return record, true, nil
+}
+
+func (r *fakeRepository) Complete(_ context.Context, record Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.records[record.Key].LockID != record.LockID {
+		return ErrLockLost
+	}
+
+	r.records[record.Key] = record
+
+	return nil
+}
+
+func (r *fakeRepository) Delete(_ context.Context, record Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.records[record.Key]
+	if stored.StatusCode == 0 && stored.LockID == record.LockID {
+		delete(r.records, record.Key)
+	}
+
+	return nil
+}
+
+type MiddlewareTestSuite struct {
+	suite.Suite
+	repo  *fakeRepository
+	calls int
+}
+
+func (s *MiddlewareTestSuite) SetupTest() {
+	s.repo = newFakeRepository()
+	s.calls = 0
+}
+
+func (s *MiddlewareTestSuite) serve(next http.HandlerFunc, method, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/v1/wallets", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+
+	rr := httptest.NewRecorder()
+	Middleware(s.repo)(next).ServeHTTP(rr, req)
+
+	return rr
+}
+
+func (s *MiddlewareTestSuite) respond(code int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		s.calls++
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func (s *MiddlewareTestSuite) seed(key, body string, updatedAt time.Time) Record {
+	req := httptest.NewRequest(http.MethodPost, "/v1/wallets", nil)
+
+	record := NewRecord(key, fingerprint(req, []byte(body)))
+	record.UpdatedAt = updatedAt
+	s.repo.records[key] = record
+
+	return record
+}
+
+func (s *MiddlewareTestSuite) TestWithoutKey() {
+	next := s.respond(http.StatusCreated, `{"id":"1"}`)
+
+	s.serve(next, http.MethodPost, "", `{}`)
+	rr := s.serve(next, http.MethodPost, "", `{}`)
+
+	s.Equal(http.StatusCreated, rr.Code)
+	s.Empty(rr.Header().Get(HeaderReplayed))
+	s.Equal(2, s.calls)
+	s.Empty(s.repo.records)
+}
+
+func (s *MiddlewareTestSuite) TestNotPost() {
+	next := s.respond(http.StatusOK, `{"id":"1"}`)
+
+	s.serve(next, http.MethodGet, "key", "")
+	s.serve(next, http.MethodGet, "key", "")
+
+	s.Equal(2, s.calls)
+	s.Empty(s.repo.records)
+}
+
+func (s *MiddlewareTestSuite) TestReplay() {
+	next := s.respond(http.StatusCreated, `{"id":"1"}`)
+
+	rr := s.serve(next, http.MethodPost, "key", `{"amount":"10"}`)
+	s.Equal(http.StatusCreated, rr.Code)
+	s.Empty(rr.Header().Get(HeaderReplayed))
+
+	rr = s.serve(next, http.MethodPost, "key", `{"amount":"10"}`)
+	s.Equal(http.StatusCreated, rr.Code)
+	s.Equal(`{"id":"1"}`, rr.Body.String())
+	s.Equal("true", rr.Header().Get(HeaderReplayed))
+	s.Equal(1, s.calls)
+}
+
+func (s *MiddlewareTestSuite) TestReplayClientError() {
+	next := s.respond(http.StatusBadRequest, `{"error":"bad request"}`)
+
+	s.serve(next, http.MethodPost, "key", `{}`)
+	rr := s.serve(next, http.MethodPost, "key", `{}`)
+
+	s.Equal(http.StatusBadRequest, rr.Code)
+	s.Equal(`{"error":"bad request"}`, rr.Body.String())
+	s.Equal("true", rr.Header().Get(HeaderReplayed))
+	s.Equal(1, s.calls)
+}
+
+func (s *MiddlewareTestSuite) TestKeyReusedForDifferentBody() {
+	next := s.respond(http.StatusCreated, `{"id":"1"}`)
+
+	s.serve(next, http.MethodPost, "key", `{"amount":"10"}`)
+	rr := s.serve(next, http.MethodPost, "key", `{"amount":"20"}`)
+
+	s.Equal(http.StatusUnprocessableEntity, rr.Code)
+	s.Contains(rr.Body.String(), HeaderKey)
+	s.Empty(rr.Header().Get(HeaderReplayed))
+	s.Equal(1, s.calls)
+}
+
+func (s *MiddlewareTestSuite) TestRequestInProgress() {
+	s.seed("key", `{}`, time.Now().UTC())
+
+	rr := s.serve(s.respond(http.StatusCreated, `{"id":"1"}`), http.MethodPost, "key", `{}`)
+
+	s.Equal(http.StatusConflict, rr.Code)
+	s.Zero(s.calls)
+}
+
+func (s *MiddlewareTestSuite) TestStaleLockTakenOver() {
+	s.seed("key", `{}`, time.Now().UTC().Add(-2*lockTimeout))
+
+	rr := s.serve(s.respond(http.StatusCreated, `{"id":"1"}`), http.MethodPost, "key", `{}`)
+
+	s.Equal(http.StatusCreated, rr.Code)
+	s.Equal(1, s.calls)
+	s.Equal(http.StatusCreated, s.repo.records["key"].StatusCode)
+}
+
+func (s *MiddlewareTestSuite) TestTakenOverRequestDoesNotCompleteKey() {
+	var retry *httptest.ResponseRecorder
+
+	rr := s.serve(func(w http.ResponseWriter, r *http.Request) {
+		// the request stalls until its lock is stale and a retry takes the key over
+		record := s.repo.records["key"]
+		record.UpdatedAt = record.UpdatedAt.Add(-2 * lockTimeout)
+		s.repo.records["key"] = record
+
+		retry = s.serve(s.respond(http.StatusCreated, `{"id":"2"}`), http.MethodPost, "key", `{}`)
+
+		s.respond(http.StatusCreated, `{"id":"1"}`)(w, r)
+	}, http.MethodPost, "key", `{}`)
+
+	s.Equal(http.StatusCreated, rr.Code)
+	s.Equal(`{"id":"2"}`, retry.Body.String())
+	s.Equal(2, s.calls)
+	s.Equal(`{"id":"2"}`, string(s.repo.records["key"].Body))
+}
+
+func (s *MiddlewareTestSuite) TestExpiredKeyReused() {
+	record := s.seed("key", `{}`, time.Now().UTC().Add(-2*retention))
+	record.StatusCode = http.StatusCreated
+	record.ExpiresAt = record.UpdatedAt.Add(retention)
+	s.repo.records["key"] = record
+
+	rr := s.serve(s.respond(http.StatusAccepted, `{"id":"2"}`), http.MethodPost, "key", `{"amount":"20"}`)
+
+	s.Equal(http.StatusAccepted, rr.Code)
+	s.Empty(rr.Header().Get(HeaderReplayed))
+	s.Equal(1, s.calls)
+}
+
+func (s *MiddlewareTestSuite) TestServerErrorReleasesKey() {
+	rr := s.serve(s.respond(http.StatusInternalServerError, `{}`), http.MethodPost, "key", `{}`)
+	s.Equal(http.StatusInternalServerError, rr.Code)
+	s.NotContains(s.repo.records, "key")
+
+	rr = s.serve(s.respond(http.StatusCreated, `{"id":"1"}`), http.MethodPost, "key", `{}`)
+	s.Equal(http.StatusCreated, rr.Code)
+	s.Empty(rr.Header().Get(HeaderReplayed))
+	s.Equal(2, s.calls)
+}
+
+func (s *MiddlewareTestSuite) TestCreateError() {
+	s.repo.createErr = errFake
+
+	rr := s.serve(s.respond(http.StatusCreated, `{"id":"1"}`), http.MethodPost, "key", `{}`)
+
+	s.Equal(http.StatusInternalServerError, rr.Code)
+	s.Zero(s.calls)
+}
+
+func TestMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(MiddlewareTestSuite))
+}
@@ -0,0 +1,131 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/buni/wallet/internal/pkg/database/pgxtx"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/iZettle/structextract"
+	"github.com/jackc/pgx/v5"
+)
+
+const db = "db"
+
+var _ Repository = (*PostgresRepository)(nil)
+
+type PostgresRepository struct {
+	pgxpool *pgxtx.TxWrapper
+	table   string
+}
+
+func NewPostgresRepository(pgxpool *pgxtx.TxWrapper) *PostgresRepository {
+	return &PostgresRepository{
+		pgxpool: pgxpool,
+		table:   "idempotency_keys",
+	}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, record Record, staleBefore time.Time) (result Record, created bool, err error) {
+	fvMap, err := structextract.New(&record).FieldValueFromTagMap(db)
+	if err != nil {
+		return Record{}, false, fmt.Errorf("failed to extract field value map: %w", err)
+	}
+
+	columns, err := structextract.New(&Record{}).NamesFromTag(db)
+	if err != nil {
+		return Record{}, false, fmt.Errorf("failed to extract columns: %w", err)
+	}
+
+	// a stale lock of the same request or an expired record is taken over, the new lock id makes the previous owner's Complete and Delete no-ops
+	query, args, err := sq.Insert(r.table).SetMap(fvMap).
+		Suffix("ON CONFLICT (key) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, status_code = EXCLUDED.status_code, response_body = EXCLUDED.response_body,"+
+			" lock_id = EXCLUDED.lock_id, expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at"+
+			" WHERE ("+r.table+".status_code = 0 AND "+r.table+".fingerprint = EXCLUDED.fingerprint AND "+r.table+".updated_at < ?)"+
+			" OR "+r.table+".expires_at <= EXCLUDED.created_at", staleBefore).
+		Suffix("RETURNING " + strings.Join(columns, ", ")).
+		PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return Record{}, false, fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.pgxpool, &result, query, args...)
+	if err == nil {
+		return result, true, nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return Record{}, false, fmt.Errorf("failed to execute insert query: %w", err)
+	}
+
+	query, args, err = sq.Select(columns...).From(r.table).Where(sq.Eq{"key": record.Key}).PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return Record{}, false, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.pgxpool, &result, query, args...)
+	if err != nil {
+		return Record{}, false, fmt.Errorf("failed to execute select query: %w", err)
+	}
+
+	return result, false, nil
+}
+
+func (r *PostgresRepository) Complete(ctx context.Context, record Record) error {
+	query, args, err := sq.Update(r.table).PlaceholderFormat(sq.Dollar).
+		Set("status_code", record.StatusCode).
+		Set("response_body", record.Body).
+		Set("updated_at", record.UpdatedAt).
+		Where(sq.Eq{"key": record.Key, "lock_id": record.LockID}).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	tag, err := r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute update query: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, record Record) error {
+	query, args, err := sq.Delete(r.table).PlaceholderFormat(sq.Dollar).
+		Where(sq.Eq{"key": record.Key, "lock_id": record.LockID, "status_code": 0}).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build delete query: %w", err)
+	}
+
+	_, err = r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute delete query: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes up to limit records that expired before now and returns how many it deleted.
+func (r *PostgresRepository) DeleteExpired(ctx context.Context, now time.Time, limit uint64) (int, error) {
+	expired := sq.Select("key").From(r.table).Where(sq.Lt{"expires_at": now}).OrderBy("expires_at").Limit(limit)
+
+	query, args, err := sq.Delete(r.table).PlaceholderFormat(sq.Dollar).
+		Where(sq.Expr("key IN (?)", expired)).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build delete query: %w", err)
+	}
+
+	tag, err := r.pgxpool.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute delete query: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
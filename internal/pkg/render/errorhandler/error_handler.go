@@ -2,9 +2,11 @@ package errorhandler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 
 	"github.com/buni/wallet/internal/api/app/entity"
@@ -62,6 +64,21 @@ func NewDefaultErrorResponse(ctx context.Context, w http.ResponseWriter, err err
 	eh.NewErrorResponse(ctx, w, err)
 }
 
+// ResolveError returns the status code and error the globally registered error handlers would respond with,
+// it's used when an error is a part of the response instead of being the response, e.g. batch item results.
+func ResolveError(ctx context.Context, err error) (int, *render.Error) {
+	rec := httptest.NewRecorder()
+	NewDefaultErrorResponse(ctx, rec, err)
+
+	var resp render.ErrorResponse
+	decodeErr := json.NewDecoder(rec.Body).Decode(&resp)
+	if decodeErr != nil || resp.Error == nil {
+		return http.StatusInternalServerError, render.NewError(render.InternalServerError, "internal server error")
+	}
+
+	return rec.Code, resp.Error
+}
+
 // ValidationErrorHandler handles validation errors, if err is of type render.Error it will be handled as a validation error.
 func ValidationErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
 	var validationError *render.Error
@@ -102,6 +119,259 @@ func InsufficientBalanceErrorHandler(ctx context.Context, w http.ResponseWriter,
 	return false
 }
 
+func SameWalletTransferErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	if errors.Is(err, entity.ErrSameWalletTransfer) {
+		render.NewValidationErrorResponse(ctx, w, render.NewValidationError(&render.FieldError{
+			Field:   "destination_wallet_id",
+			Message: "destination wallet must be different from the source wallet",
+		}))
+		return true
+	}
+	return false
+}
+
+func StreamVersionConflictErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	if errors.Is(err, entity.ErrStreamVersionConflict) {
+		render.NewConflictErrorResponse(ctx, w, err)
+		return true
+	}
+	return false
+}
+
+func TransferNotFoundErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	if errors.Is(err, entity.ErrTransferNotFound) {
+		render.NewNotFoundErrorResponse(ctx, w, err)
+		return true
+	}
+	return false
+}
+
+func TransferAlreadySettledErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	for _, settledErr := range []error{entity.ErrTransferAlreadyCompleted, entity.ErrTransferAlreadyFailed} {
+		if errors.Is(err, settledErr) {
+			render.NewErrorResponse(ctx, w, http.StatusConflict, render.ConflictError, settledErr) // only the sentinel is rendered, it tells the client why retrying won't help
+			return true
+		}
+	}
+	return false
+}
+
+func CurrencyErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	var fieldError *render.FieldError
+
+	switch {
+	case errors.Is(err, entity.ErrUnsupportedCurrency):
+		fieldError = &render.FieldError{Field: "currency", Message: "currency must be a supported ISO 4217 code"}
+	case errors.Is(err, entity.ErrCurrencyMismatch):
+		fieldError = &render.FieldError{Field: "currency", Message: "currency must match the wallet currency"}
+	case errors.Is(err, entity.ErrInvalidAmountScale):
+		fieldError = &render.FieldError{Field: "amount", Message: "amount has more decimal places than the currency allows"}
+	default:
+		return false
+	}
+
+	render.NewValidationErrorResponse(ctx, w, render.NewValidationError(fieldError))
+	return true
+}
+
+func TransferExpiryErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	if errors.Is(err, entity.ErrInvalidTransferExpiry) {
+		render.NewValidationErrorResponse(ctx, w, render.NewValidationError(&render.FieldError{
+			Field:   "expires_at",
+			Message: "expires_at must be in the future and can only be set on pending transfers",
+		}))
+		return true
+	}
+
+	if errors.Is(err, entity.ErrTransferExpired) {
+		render.NewErrorResponse(ctx, w, http.StatusConflict, render.ConflictError, entity.ErrTransferExpired)
+		return true
+	}
+	return false
+}
+
+func CaptureAmountErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	if errors.Is(err, entity.ErrInvalidCaptureAmount) {
+		render.NewValidationErrorResponse(ctx, w, render.NewValidationError(&render.FieldError{
+			Field:   "amount",
+			Message: "amount must be positive and can't exceed the pending amount",
+		}))
+		return true
+	}
+	return false
+}
+
+func RefundErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	if errors.Is(err, entity.ErrInvalidRefundAmount) {
+		render.NewValidationErrorResponse(ctx, w, render.NewValidationError(&render.FieldError{
+			Field:   "amount",
+			Message: "amount must be positive and can't exceed the amount left to refund",
+		}))
+		return true
+	}
+
+	if errors.Is(err, entity.ErrTransferNotRefundable) {
+		render.NewErrorResponse(ctx, w, http.StatusConflict, render.ConflictError, entity.ErrTransferNotRefundable)
+		return true
+	}
+	return false
+}
+
+func FXErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	var fieldError *render.FieldError
+
+	switch {
+	case errors.Is(err, entity.ErrFXSameCurrency):
+		fieldError = &render.FieldError{Field: "target_wallet_id", Message: "target wallet must hold a different currency than the source wallet"}
+	case errors.Is(err, entity.ErrFXRateNotFound):
+		fieldError = &render.FieldError{Field: "target_wallet_id", Message: "no fx rate between the source and target wallet currencies"}
+	case errors.Is(err, entity.ErrInvalidFXAmount):
+		fieldError = &render.FieldError{Field: "amount", Message: "amount is too small to be converted into the target currency"}
+	}
+
+	if fieldError != nil {
+		render.NewValidationErrorResponse(ctx, w, render.NewValidationError(fieldError))
+		return true
+	}
+
+	for _, quoteErr := range []error{entity.ErrFXQuoteExpired, entity.ErrFXQuoteExecuted} {
+		if errors.Is(err, quoteErr) {
+			render.NewErrorResponse(ctx, w, http.StatusConflict, render.ConflictError, quoteErr)
+			return true
+		}
+	}
+	return false
+}
+
+func StatementErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	if errors.Is(err, entity.ErrInvalidStatementPeriod) {
+		render.NewValidationErrorResponse(ctx, w, render.NewValidationError(&render.FieldError{
+			Field:   "to",
+			Message: "to must be after from and the wallet creation",
+		}))
+		return true
+	}
+	return false
+}
+
+func WalletStatusErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	for _, statusErr := range []error{entity.ErrWalletFrozen, entity.ErrWalletClosed, entity.ErrWalletNotEmpty} {
+		if errors.Is(err, statusErr) {
+			render.NewErrorResponse(ctx, w, http.StatusConflict, render.ConflictError, statusErr)
+			return true
+		}
+	}
+	return false
+}
+
+func CreditLimitErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	if errors.Is(err, entity.ErrInvalidCreditLimit) {
+		render.NewValidationErrorResponse(ctx, w, render.NewValidationError(&render.FieldError{
+			Field:   "credit_limit",
+			Message: "credit limit must not be negative and can't have more decimal places than the wallet currency allows",
+		}))
+		return true
+	}
+	return false
+}
+
+// LimitExceededErrorHandler renders the velocity limit that was hit, so the client knows what it ran into and for how long.
+func LimitExceededErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	var limitErr *entity.LimitExceededError
+	if errors.As(err, &limitErr) {
+		render.NewErrorResponse(ctx, w, http.StatusConflict, render.ConflictError, render.NewError(render.ConflictError, entity.ErrLimitExceeded.Error(), &render.FieldError{
+			Field:   limitErr.Limit.Kind.String(),
+			Message: fmt.Sprintf("at most %s per %s", limitErr.Limit.Max, limitErr.Limit.Window),
+		}))
+		return true
+	}
+	return false
+}
+
+func WalletTierErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	if errors.Is(err, entity.ErrUnknownWalletTier) {
+		render.NewValidationErrorResponse(ctx, w, render.NewValidationError(&render.FieldError{
+			Field:   "tier",
+			Message: "tier must be standard or one of the tiers with configured limits",
+		}))
+		return true
+	}
+	return false
+}
+
+func BalanceAsOfErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	if errors.Is(err, entity.ErrInvalidBalanceAsOf) {
+		render.NewValidationErrorResponse(ctx, w, render.NewValidationError(&render.FieldError{
+			Field:   "as_of",
+			Message: "as_of must be an RFC3339 timestamp or the id of an event of the wallet",
+		}))
+		return true
+	}
+	return false
+}
+
+func BatchSizeErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	if errors.Is(err, entity.ErrInvalidBatchSize) {
+		render.NewValidationErrorResponse(ctx, w, render.NewValidationError(&render.FieldError{
+			Field:   "items",
+			Message: "batch must have at least one item and can't exceed the max batch size",
+		}))
+		return true
+	}
+	return false
+}
+
+func ScheduledTransferErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	if errors.Is(err, entity.ErrInvalidExecuteAt) {
+		render.NewValidationErrorResponse(ctx, w, render.NewValidationError(&render.FieldError{
+			Field:   "execute_at",
+			Message: "execute_at must be in the future and before expires_at",
+		}))
+		return true
+	}
+
+	if errors.Is(err, entity.ErrTransferAlreadyExists) {
+		render.NewValidationErrorResponse(ctx, w, render.NewValidationError(&render.FieldError{
+			Field:   "transfer_id",
+			Message: "value already used for different transfer",
+		}))
+		return true
+	}
+
+	if errors.Is(err, entity.ErrTransferNotScheduled) {
+		render.NewErrorResponse(ctx, w, http.StatusConflict, render.ConflictError, entity.ErrTransferNotScheduled)
+		return true
+	}
+	return false
+}
+
+func RecurringTransferErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
+	for _, fieldErr := range []struct {
+		err     error
+		field   string
+		message string
+	}{
+		{err: entity.ErrInvalidRecurrenceRule, field: "rule", message: "rule must be FREQ=DAILY, WEEKLY or MONTHLY with optional INTERVAL and BYMONTHDAY (monthly only) parts"},
+		{err: entity.ErrInvalidRecurrenceStart, field: "start_at", message: "start_at can't be in the past"},
+		{err: entity.ErrInvalidRecurrenceEnd, field: "end_at", message: "end_at must be after the next occurrence"},
+		{err: entity.ErrInvalidRecurringStatus, field: "status", message: "status must be active or paused"},
+	} {
+		if errors.Is(err, fieldErr.err) {
+			render.NewValidationErrorResponse(ctx, w, render.NewValidationError(&render.FieldError{
+				Field:   fieldErr.field,
+				Message: fieldErr.message,
+			}))
+			return true
+		}
+	}
+
+	if errors.Is(err, entity.ErrRecurrenceFinished) {
+		render.NewErrorResponse(ctx, w, http.StatusConflict, render.ConflictError, entity.ErrRecurrenceFinished)
+		return true
+	}
+	return false
+}
+
 func ConflictErrorHandler(ctx context.Context, w http.ResponseWriter, err error) bool {
 	errStr := err.Error()
 	if strings.Contains(errStr, "unique constraint") {
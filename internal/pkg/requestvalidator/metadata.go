@@ -0,0 +1,101 @@
+package requestvalidator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	MaxMetadataKeys        = 20
+	MaxMetadataKeyLength   = 40
+	MaxMetadataValueLength = 500
+)
+
+// validateMetadata checks a map[string]string against the metadata limits, the keys are made of letters, digits, '_', '-' and '.'.
+func validateMetadata(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.Map {
+		return false
+	}
+
+	if field.Len() > MaxMetadataKeys {
+		return false
+	}
+
+	iter := field.MapRange()
+	for iter.Next() {
+		if !validMetadataKey(iter.Key().String()) || len(iter.Value().String()) > MaxMetadataValueLength {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateMetadataFilter checks a single key:value metadata filter.
+func validateMetadataFilter(fl validator.FieldLevel) bool {
+	key, value, ok := strings.Cut(fl.Field().String(), ":")
+
+	return ok && validMetadataKey(key) && len(value) <= MaxMetadataValueLength
+}
+
+func validMetadataKey(key string) bool {
+	if key == "" || len(key) > MaxMetadataKeyLength {
+		return false
+	}
+
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func registerMetadataValidations(reqValidator *validator.Validate, translator ut.Translator) error {
+	validations := []struct {
+		tag      string
+		fn       validator.Func
+		template string
+	}{
+		{
+			tag: "metadata",
+			fn:  validateMetadata,
+			template: fmt.Sprintf(
+				"{0} can have up to %d keys of up to %d letters, digits, '_', '-' or '.' and values of up to %d characters",
+				MaxMetadataKeys, MaxMetadataKeyLength, MaxMetadataValueLength,
+			),
+		},
+		{
+			tag:      "metadata_filter",
+			fn:       validateMetadataFilter,
+			template: "{0} has to be a key:value pair",
+		},
+	}
+
+	for _, v := range validations {
+		err := reqValidator.RegisterValidation(v.tag, v.fn)
+		if err != nil {
+			return fmt.Errorf("failed to register %s validation: %w", v.tag, err)
+		}
+
+		err = reqValidator.RegisterTranslation(v.tag, translator, func(ut ut.Translator) error {
+			return ut.Add(v.tag, v.template, true) //nolint:wrapcheck
+		}, func(ut ut.Translator, fe validator.FieldError) string {
+			t, _ := ut.T(v.tag, fe.Field()) //nolint:errcheck
+			return t
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register %s translation: %w", v.tag, err)
+		}
+	}
+
+	return nil
+}
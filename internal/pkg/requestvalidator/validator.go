@@ -47,6 +47,11 @@ func NewValidator() (*PlaygroundValidator, error) {
 		return nil, fmt.Errorf("failed to register required translation: %w", err)
 	}
 
+	err = registerMetadataValidations(reqValidator, enTranslator)
+	if err != nil {
+		return nil, err
+	}
+
 	return &PlaygroundValidator{validator: reqValidator, translator: enTranslator}, nil
 }
 
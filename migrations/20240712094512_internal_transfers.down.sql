@@ -0,0 +1,2 @@
+-- reverse: create "internal_transfers" table
+DROP TABLE "public"."internal_transfers";
@@ -0,0 +1,11 @@
+-- create "internal_transfers" table
+CREATE TABLE "public"."internal_transfers" (
+  "transfer_id" text NOT NULL,
+  "reference_id" text NOT NULL,
+  "source_wallet_id" uuid NOT NULL,
+  "destination_wallet_id" uuid NOT NULL,
+  "amount" numeric NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT statement_timestamp(),
+  CONSTRAINT "internal_transfers_transfer_id_pkey" PRIMARY KEY ("transfer_id"),
+  CONSTRAINT "internal_transfers_amount_check" CHECK (amount >= (0)::numeric)
+);
@@ -0,0 +1,6 @@
+-- reverse: modify "wallet_projections" table
+ALTER TABLE "public"."wallet_projections" DROP COLUMN "last_sequence";
+-- reverse: create index "idx_wallet_events_wallet_id_sequence" to table: "wallet_events"
+DROP INDEX "public"."idx_wallet_events_wallet_id_sequence";
+-- reverse: modify "wallet_events" table
+ALTER TABLE "public"."wallet_events" DROP COLUMN "sequence";
@@ -0,0 +1,12 @@
+-- modify "wallet_events" table
+ALTER TABLE "public"."wallet_events" ADD COLUMN "sequence" bigint NULL;
+-- backfill "sequence" of the existing events in id order
+UPDATE "public"."wallet_events" AS "e" SET "sequence" = "s"."sequence" FROM (SELECT "id", row_number() OVER (PARTITION BY "wallet_id" ORDER BY "id") AS "sequence" FROM "public"."wallet_events") AS "s" WHERE "e"."id" = "s"."id";
+-- modify "wallet_events" table
+ALTER TABLE "public"."wallet_events" ALTER COLUMN "sequence" SET NOT NULL;
+-- create index "idx_wallet_events_wallet_id_sequence" to table: "wallet_events"
+CREATE UNIQUE INDEX "idx_wallet_events_wallet_id_sequence" ON "public"."wallet_events" ("wallet_id", "sequence");
+-- modify "wallet_projections" table
+ALTER TABLE "public"."wallet_projections" ADD COLUMN "last_sequence" bigint NOT NULL DEFAULT 0;
+-- backfill "last_sequence" of the existing projections
+UPDATE "public"."wallet_projections" AS "p" SET "last_sequence" = (SELECT COALESCE(MAX("sequence"), 0) FROM "public"."wallet_events" AS "e" WHERE "e"."wallet_id" = "p"."wallet_id");
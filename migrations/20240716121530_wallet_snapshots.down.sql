@@ -0,0 +1,2 @@
+-- reverse: create "wallet_snapshots" table
+DROP TABLE "public"."wallet_snapshots";
@@ -0,0 +1,12 @@
+-- create "wallet_snapshots" table
+CREATE TABLE "public"."wallet_snapshots" (
+  "wallet_id" uuid NOT NULL,
+  "last_event_id" uuid NOT NULL,
+  "last_sequence" bigint NOT NULL,
+  "balance" numeric NOT NULL,
+  "pending_debit" numeric NOT NULL,
+  "pending_credit" numeric NOT NULL,
+  "pending_transfers" jsonb NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT statement_timestamp(),
+  PRIMARY KEY ("wallet_id", "last_sequence")
+);
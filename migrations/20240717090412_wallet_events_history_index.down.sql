@@ -0,0 +1,2 @@
+-- reverse: create index "idx_wallet_events_wallet_id_id" to table: "wallet_events"
+DROP INDEX "public"."idx_wallet_events_wallet_id_id";
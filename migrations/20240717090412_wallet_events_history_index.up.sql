@@ -0,0 +1,2 @@
+-- create index "idx_wallet_events_wallet_id_id" to table: "wallet_events"
+CREATE INDEX "idx_wallet_events_wallet_id_id" ON "public"."wallet_events" ("wallet_id", "id");
@@ -0,0 +1,4 @@
+-- reverse: create index "idx_wallet_transfers_wallet_id_transfer_status" to table: "wallet_transfers"
+DROP INDEX "public"."idx_wallet_transfers_wallet_id_transfer_status";
+-- reverse: create "wallet_transfers" table
+DROP TABLE "public"."wallet_transfers";
@@ -0,0 +1,20 @@
+-- create "wallet_transfers" table
+CREATE TABLE "public"."wallet_transfers" (
+  "wallet_id" uuid NOT NULL,
+  "transfer_id" text NOT NULL,
+  "reference_id" text NOT NULL,
+  "amount" numeric NOT NULL,
+  "direction" text NOT NULL,
+  "transfer_status" text NOT NULL,
+  "last_sequence" bigint NOT NULL,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp NOT NULL,
+  PRIMARY KEY ("wallet_id", "transfer_id")
+);
+-- create index "idx_wallet_transfers_wallet_id_transfer_status" to table: "wallet_transfers"
+CREATE INDEX "idx_wallet_transfers_wallet_id_transfer_status" ON "public"."wallet_transfers" ("wallet_id", "transfer_status");
+-- backfill "wallet_transfers" from the existing events, the first debit/credit event opens the transfer and only a pending transfer can be completed or reverted
+INSERT INTO "public"."wallet_transfers" ("wallet_id", "transfer_id", "reference_id", "amount", "direction", "transfer_status", "last_sequence", "created_at", "updated_at")
+SELECT "o"."wallet_id", "o"."transfer_id", "o"."reference_id", "o"."amount", CASE "o"."event_type" WHEN 'debit_transfer' THEN 'debit' ELSE 'credit' END, COALESCE("u"."transfer_status", "o"."transfer_status"), COALESCE("u"."sequence", "o"."sequence"), "o"."created_at", COALESCE("u"."created_at", "o"."created_at")
+FROM (SELECT DISTINCT ON ("wallet_id", "transfer_id") * FROM "public"."wallet_events" WHERE "event_type" IN ('debit_transfer', 'credit_transfer') ORDER BY "wallet_id", "transfer_id", "sequence") AS "o"
+LEFT JOIN LATERAL (SELECT "e"."transfer_status", "e"."sequence", "e"."created_at" FROM "public"."wallet_events" AS "e" WHERE "o"."transfer_status" = 'pending' AND "e"."wallet_id" = "o"."wallet_id" AND "e"."transfer_id" = "o"."transfer_id" AND "e"."event_type" = 'update_transfer_status' AND "e"."transfer_status" <> 'pending' AND "e"."sequence" > "o"."sequence" ORDER BY "e"."sequence" LIMIT 1) AS "u" ON true;
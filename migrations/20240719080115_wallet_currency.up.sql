@@ -0,0 +1,4 @@
+-- modify "wallets" table, wallets created before currencies were introduced get "XXX" (ISO 4217 no currency)
+ALTER TABLE "public"."wallets" ADD COLUMN "currency" text NOT NULL DEFAULT 'XXX';
+-- modify "wallets" table
+ALTER TABLE "public"."wallets" ALTER COLUMN "currency" DROP DEFAULT;
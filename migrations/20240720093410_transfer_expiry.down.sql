@@ -0,0 +1,6 @@
+-- reverse: create index "idx_wallet_transfers_pending_expires_at" to table: "wallet_transfers"
+DROP INDEX "public"."idx_wallet_transfers_pending_expires_at";
+-- reverse: modify "wallet_transfers" table
+ALTER TABLE "public"."wallet_transfers" DROP COLUMN "expires_at";
+-- reverse: modify "wallet_events" table
+ALTER TABLE "public"."wallet_events" DROP COLUMN "expires_at";
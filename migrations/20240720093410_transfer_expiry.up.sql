@@ -0,0 +1,6 @@
+-- modify "wallet_events" table
+ALTER TABLE "public"."wallet_events" ADD COLUMN "expires_at" timestamp NULL;
+-- modify "wallet_transfers" table
+ALTER TABLE "public"."wallet_transfers" ADD COLUMN "expires_at" timestamp NULL;
+-- create index "idx_wallet_transfers_pending_expires_at" to table: "wallet_transfers"
+CREATE INDEX "idx_wallet_transfers_pending_expires_at" ON "public"."wallet_transfers" ("expires_at") WHERE (("transfer_status" = 'pending'::text) AND ("expires_at" IS NOT NULL));
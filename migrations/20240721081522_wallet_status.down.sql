@@ -0,0 +1,2 @@
+-- reverse: modify "wallets" table
+ALTER TABLE "public"."wallets" DROP COLUMN "status";
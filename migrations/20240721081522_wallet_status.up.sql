@@ -0,0 +1,4 @@
+-- modify "wallets" table, all the existing wallets are active
+ALTER TABLE "public"."wallets" ADD COLUMN "status" text NOT NULL DEFAULT 'active';
+-- modify "wallets" table
+ALTER TABLE "public"."wallets" ALTER COLUMN "status" DROP DEFAULT;
@@ -0,0 +1,4 @@
+-- reverse: modify "wallet_snapshots" table
+ALTER TABLE "public"."wallet_snapshots" DROP COLUMN "credit_limit";
+-- reverse: modify "wallet_projections" table
+ALTER TABLE "public"."wallet_projections" DROP COLUMN "credit_limit";
@@ -0,0 +1,4 @@
+-- modify "wallet_projections" table
+ALTER TABLE "public"."wallet_projections" ADD COLUMN "credit_limit" numeric NOT NULL DEFAULT 0;
+-- modify "wallet_snapshots" table
+ALTER TABLE "public"."wallet_snapshots" ADD COLUMN "credit_limit" numeric NOT NULL DEFAULT 0;
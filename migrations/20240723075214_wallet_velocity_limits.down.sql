@@ -0,0 +1,4 @@
+-- reverse: create index "idx_wallet_events_wallet_id_created_at" to table: "wallet_events"
+DROP INDEX "public"."idx_wallet_events_wallet_id_created_at";
+-- reverse: modify "wallets" table
+ALTER TABLE "public"."wallets" DROP COLUMN "tier";
@@ -0,0 +1,6 @@
+-- modify "wallets" table, all the existing wallets start in the standard tier
+ALTER TABLE "public"."wallets" ADD COLUMN "tier" text NOT NULL DEFAULT 'standard';
+-- modify "wallets" table
+ALTER TABLE "public"."wallets" ALTER COLUMN "tier" DROP DEFAULT;
+-- create index "idx_wallet_events_wallet_id_created_at" to table: "wallet_events"
+CREATE INDEX "idx_wallet_events_wallet_id_created_at" ON "public"."wallet_events" ("wallet_id", "created_at");
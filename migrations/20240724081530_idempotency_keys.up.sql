@@ -0,0 +1,10 @@
+-- create "idempotency_keys" table
+CREATE TABLE "public"."idempotency_keys" (
+  "key" text NOT NULL,
+  "fingerprint" text NOT NULL,
+  "status_code" integer NOT NULL DEFAULT 0,
+  "response_body" bytea NULL,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp NOT NULL,
+  PRIMARY KEY ("key")
+);
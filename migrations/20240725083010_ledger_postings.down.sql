@@ -0,0 +1,2 @@
+-- reverse: create "ledger_postings" table
+DROP TABLE "public"."ledger_postings";
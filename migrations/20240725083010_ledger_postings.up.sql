@@ -0,0 +1,25 @@
+-- create "ledger_postings" table
+CREATE TABLE "public"."ledger_postings" (
+  "event_id" uuid NOT NULL,
+  "wallet_id" uuid NOT NULL,
+  "account" text NOT NULL,
+  "currency" text NOT NULL,
+  "amount" numeric NOT NULL,
+  "created_at" timestamp NOT NULL,
+  PRIMARY KEY ("event_id", "account")
+);
+-- backfill "ledger_postings" from the existing events, the opening debit/credit event and the first status update settling a pending transfer are posted as a balanced pair
+INSERT INTO "public"."ledger_postings" ("event_id", "wallet_id", "account", "currency", "amount", "created_at")
+SELECT "t"."event_id", "t"."wallet_id", "p"."account", "w"."currency", "p"."amount", "t"."created_at"
+FROM (
+  SELECT "o"."id" AS "event_id", "o"."wallet_id", "o"."amount", "o"."created_at", CASE WHEN "o"."event_type" = 'debit_transfer' THEN 'system:funding' ELSE 'wallet:' || "o"."wallet_id" END AS "from_account", CASE WHEN "o"."event_type" = 'debit_transfer' THEN 'wallet:' || "o"."wallet_id" WHEN "o"."transfer_status" = 'pending' THEN 'system:settlement' ELSE 'system:funding' END AS "to_account"
+  FROM (SELECT DISTINCT ON ("wallet_id", "transfer_id") * FROM "public"."wallet_events" WHERE "event_type" IN ('debit_transfer', 'credit_transfer') ORDER BY "wallet_id", "transfer_id", "sequence") AS "o"
+  WHERE "o"."transfer_status" = 'completed' OR ("o"."event_type" = 'credit_transfer' AND "o"."transfer_status" = 'pending')
+  UNION ALL
+  SELECT "u"."id", "o"."wallet_id", "o"."amount", "u"."created_at", CASE WHEN "o"."event_type" = 'debit_transfer' THEN 'system:funding' ELSE 'system:settlement' END, CASE WHEN "o"."event_type" = 'credit_transfer' AND "u"."transfer_status" = 'completed' THEN 'system:funding' ELSE 'wallet:' || "o"."wallet_id" END
+  FROM (SELECT DISTINCT ON ("wallet_id", "transfer_id") * FROM "public"."wallet_events" WHERE "event_type" IN ('debit_transfer', 'credit_transfer') ORDER BY "wallet_id", "transfer_id", "sequence") AS "o"
+  JOIN LATERAL (SELECT "e"."id", "e"."transfer_status", "e"."created_at" FROM "public"."wallet_events" AS "e" WHERE "e"."wallet_id" = "o"."wallet_id" AND "e"."transfer_id" = "o"."transfer_id" AND "e"."event_type" = 'update_transfer_status' AND "e"."transfer_status" <> 'pending' AND "e"."sequence" > "o"."sequence" ORDER BY "e"."sequence" LIMIT 1) AS "u" ON true
+  WHERE "o"."transfer_status" = 'pending' AND ("o"."event_type" = 'credit_transfer' OR "u"."transfer_status" = 'completed')
+) AS "t"
+JOIN "public"."wallets" AS "w" ON "w"."id" = "t"."wallet_id"
+CROSS JOIN LATERAL (VALUES ("t"."from_account", -"t"."amount"), ("t"."to_account", "t"."amount")) AS "p" ("account", "amount");
@@ -0,0 +1,2 @@
+-- reverse: modify "wallet_events" table
+ALTER TABLE "public"."wallet_events" DROP COLUMN "fee";
@@ -0,0 +1,2 @@
+-- modify "wallet_events" table
+ALTER TABLE "public"."wallet_events" ADD COLUMN "fee" numeric NOT NULL DEFAULT 0;
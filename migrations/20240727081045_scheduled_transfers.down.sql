@@ -0,0 +1,6 @@
+-- reverse: create index "idx_scheduled_transfers_scheduled_execute_at" to table: "scheduled_transfers"
+DROP INDEX "public"."idx_scheduled_transfers_scheduled_execute_at";
+-- reverse: create index "idx_scheduled_transfers_wallet_id_transfer_id" to table: "scheduled_transfers"
+DROP INDEX "public"."idx_scheduled_transfers_wallet_id_transfer_id";
+-- reverse: create "scheduled_transfers" table
+DROP TABLE "public"."scheduled_transfers";
@@ -0,0 +1,23 @@
+-- create "scheduled_transfers" table
+CREATE TABLE "public"."scheduled_transfers" (
+  "id" uuid NOT NULL,
+  "wallet_id" uuid NOT NULL,
+  "transfer_id" text NOT NULL,
+  "reference_id" text NOT NULL,
+  "direction" text NOT NULL,
+  "amount" numeric NOT NULL,
+  "currency" text NOT NULL,
+  "transfer_status" text NOT NULL,
+  "expires_at" timestamp NULL,
+  "execute_at" timestamp NOT NULL,
+  "status" text NOT NULL,
+  "event_id" uuid NULL,
+  "failure_reason" text NOT NULL DEFAULT '',
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp NOT NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_scheduled_transfers_wallet_id_transfer_id" to table: "scheduled_transfers"
+CREATE UNIQUE INDEX "idx_scheduled_transfers_wallet_id_transfer_id" ON "public"."scheduled_transfers" ("wallet_id", "transfer_id");
+-- create index "idx_scheduled_transfers_scheduled_execute_at" to table: "scheduled_transfers"
+CREATE INDEX "idx_scheduled_transfers_scheduled_execute_at" ON "public"."scheduled_transfers" ("execute_at") WHERE ("status" = 'scheduled'::text);
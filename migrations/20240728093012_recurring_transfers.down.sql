@@ -0,0 +1,6 @@
+-- reverse: create index "idx_recurring_transfers_active_next_run_at" to table: "recurring_transfers"
+DROP INDEX "public"."idx_recurring_transfers_active_next_run_at";
+-- reverse: create index "idx_recurring_transfers_wallet_id" to table: "recurring_transfers"
+DROP INDEX "public"."idx_recurring_transfers_wallet_id";
+-- reverse: create "recurring_transfers" table
+DROP TABLE "public"."recurring_transfers";
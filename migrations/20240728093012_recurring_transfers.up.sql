@@ -0,0 +1,24 @@
+-- create "recurring_transfers" table
+CREATE TABLE "public"."recurring_transfers" (
+  "id" uuid NOT NULL,
+  "wallet_id" uuid NOT NULL,
+  "reference_id" text NOT NULL,
+  "direction" text NOT NULL,
+  "amount" numeric NOT NULL,
+  "currency" text NOT NULL,
+  "rule" text NOT NULL,
+  "start_at" timestamp NOT NULL,
+  "end_at" timestamp NULL,
+  "occurrence" integer NOT NULL,
+  "next_run_at" timestamp NULL,
+  "status" text NOT NULL,
+  "last_transfer_id" text NOT NULL DEFAULT '',
+  "last_failure_reason" text NOT NULL DEFAULT '',
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp NOT NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_recurring_transfers_wallet_id" to table: "recurring_transfers"
+CREATE INDEX "idx_recurring_transfers_wallet_id" ON "public"."recurring_transfers" ("wallet_id");
+-- create index "idx_recurring_transfers_active_next_run_at" to table: "recurring_transfers"
+CREATE INDEX "idx_recurring_transfers_active_next_run_at" ON "public"."recurring_transfers" ("next_run_at") WHERE ("status" = 'active'::text);
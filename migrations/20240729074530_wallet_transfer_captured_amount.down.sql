@@ -0,0 +1,2 @@
+-- reverse: modify "wallet_transfers" table
+ALTER TABLE "public"."wallet_transfers" DROP COLUMN "captured_amount";
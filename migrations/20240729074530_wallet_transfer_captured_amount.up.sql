@@ -0,0 +1,4 @@
+-- modify "wallet_transfers" table
+ALTER TABLE "public"."wallet_transfers" ADD COLUMN "captured_amount" numeric NOT NULL DEFAULT 0;
+-- transfers completed before partial captures captured their whole amount
+UPDATE "public"."wallet_transfers" SET "captured_amount" = "amount" WHERE "transfer_status" = 'completed';
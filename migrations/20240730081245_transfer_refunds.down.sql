@@ -0,0 +1,4 @@
+-- reverse: create index "idx_transfer_refunds_wallet_id_transfer_id" to table: "transfer_refunds"
+DROP INDEX "public"."idx_transfer_refunds_wallet_id_transfer_id";
+-- reverse: create "transfer_refunds" table
+DROP TABLE "public"."transfer_refunds";
@@ -0,0 +1,14 @@
+-- create "transfer_refunds" table
+CREATE TABLE "public"."transfer_refunds" (
+  "refund_id" text NOT NULL,
+  "wallet_id" uuid NOT NULL,
+  "transfer_id" text NOT NULL,
+  "reference_id" text NOT NULL,
+  "amount" numeric NOT NULL,
+  "event_id" uuid NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT statement_timestamp(),
+  CONSTRAINT "transfer_refunds_wallet_id_refund_id_pkey" PRIMARY KEY ("wallet_id", "refund_id"),
+  CONSTRAINT "transfer_refunds_amount_check" CHECK (amount > (0)::numeric)
+);
+-- create index "idx_transfer_refunds_wallet_id_transfer_id" to table: "transfer_refunds"
+CREATE INDEX "idx_transfer_refunds_wallet_id_transfer_id" ON "public"."transfer_refunds" ("wallet_id", "transfer_id");
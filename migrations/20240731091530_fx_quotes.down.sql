@@ -0,0 +1,4 @@
+-- reverse: create "fx_quotes" table
+DROP TABLE "public"."fx_quotes";
+-- reverse: modify "wallet_events" table
+ALTER TABLE "public"."wallet_events" DROP COLUMN "fx_rate";
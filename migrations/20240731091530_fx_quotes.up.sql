@@ -0,0 +1,21 @@
+-- modify "wallet_events" table
+ALTER TABLE "public"."wallet_events" ADD COLUMN "fx_rate" numeric NULL;
+-- create "fx_quotes" table
+CREATE TABLE "public"."fx_quotes" (
+  "id" uuid NOT NULL,
+  "source_wallet_id" uuid NOT NULL,
+  "target_wallet_id" uuid NOT NULL,
+  "source_currency" text NOT NULL,
+  "target_currency" text NOT NULL,
+  "source_amount" numeric NOT NULL,
+  "target_amount" numeric NOT NULL,
+  "rate" numeric NOT NULL,
+  "status" text NOT NULL,
+  "transfer_id" text NOT NULL DEFAULT '',
+  "reference_id" text NOT NULL DEFAULT '',
+  "expires_at" timestamp NOT NULL,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fx_quotes_rate_check" CHECK (rate > (0)::numeric)
+);
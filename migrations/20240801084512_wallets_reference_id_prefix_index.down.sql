@@ -0,0 +1,2 @@
+-- reverse: create index "idx_wallets_reference_id_pattern" to table: "wallets"
+DROP INDEX "public"."idx_wallets_reference_id_pattern";
@@ -0,0 +1,2 @@
+-- create index "idx_wallets_reference_id_pattern" to table: "wallets"
+CREATE INDEX "idx_wallets_reference_id_pattern" ON "public"."wallets" ("reference_id" text_pattern_ops);
@@ -0,0 +1,10 @@
+-- reverse: modify "scheduled_transfers" table
+ALTER TABLE "public"."scheduled_transfers" DROP COLUMN "metadata";
+-- reverse: modify "wallet_transfers" table
+ALTER TABLE "public"."wallet_transfers" DROP COLUMN "metadata";
+-- reverse: modify "wallet_events" table
+ALTER TABLE "public"."wallet_events" DROP COLUMN "metadata";
+-- reverse: create index "idx_wallets_metadata" to table: "wallets"
+DROP INDEX "public"."idx_wallets_metadata";
+-- reverse: modify "wallets" table
+ALTER TABLE "public"."wallets" DROP COLUMN "metadata";
@@ -0,0 +1,10 @@
+-- modify "wallets" table
+ALTER TABLE "public"."wallets" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
+-- create index "idx_wallets_metadata" to table: "wallets"
+CREATE INDEX "idx_wallets_metadata" ON "public"."wallets" USING gin ("metadata" jsonb_path_ops);
+-- modify "wallet_events" table
+ALTER TABLE "public"."wallet_events" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
+-- modify "wallet_transfers" table
+ALTER TABLE "public"."wallet_transfers" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
+-- modify "scheduled_transfers" table
+ALTER TABLE "public"."scheduled_transfers" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
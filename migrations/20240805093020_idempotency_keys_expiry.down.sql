@@ -0,0 +1,4 @@
+-- reverse: create index "idx_idempotency_keys_expires_at" to table: "idempotency_keys"
+DROP INDEX "public"."idx_idempotency_keys_expires_at";
+-- reverse: modify "idempotency_keys" table
+ALTER TABLE "public"."idempotency_keys" DROP COLUMN "expires_at", DROP COLUMN "lock_id";
@@ -0,0 +1,4 @@
+-- modify "idempotency_keys" table
+ALTER TABLE "public"."idempotency_keys" ADD COLUMN "lock_id" text NOT NULL DEFAULT '', ADD COLUMN "expires_at" timestamp NOT NULL DEFAULT (timezone('utc', now()) + '1 day'::interval);
+-- create index "idx_idempotency_keys_expires_at" to table: "idempotency_keys"
+CREATE INDEX "idx_idempotency_keys_expires_at" ON "public"."idempotency_keys" ("expires_at");
@@ -2,18 +2,35 @@ CREATE TABLE wallets (
     id uuid PRIMARY KEY,
     reference_id text NOT NULL UNIQUE,
     -- for simplicity sake lets assume that we can have only one wallet per user/reference_id
+    currency text NOT NULL,
+    -- ISO 4217 code, wallets created before currencies were introduced have "XXX"
+    status text NOT NULL,
+    -- active, frozen or closed, only active wallets accept new transfers
+    tier text NOT NULL,
+    -- picks the velocity limits configured for the wallet
+    metadata jsonb NOT NULL DEFAULT '{}',
+    -- key value pairs set by the caller, not interpreted by the service
     created_at timestamp DEFAULT statement_timestamp(),
     updated_at timestamp DEFAULT statement_timestamp()
 );
 
 CREATE INDEX idx_wallet_user_id ON wallets (reference_id);
 
+CREATE INDEX idx_wallets_reference_id_pattern ON wallets (reference_id text_pattern_ops);
+-- prefix searches (LIKE 'prefix%') can't use the default collation index
+
+CREATE INDEX idx_wallets_metadata ON wallets USING gin (metadata jsonb_path_ops);
+-- wallets are listed across all of them by metadata containment (@>), the events and transfers are filtered within a wallet
+
 CREATE TABLE wallet_projections (
     wallet_id uuid PRIMARY KEY,
     balance decimal NOT NULL,
     pending_debit decimal NOT NULL,
     pending_credit decimal NOT NULL,
     last_event_id uuid NOT NULL,
+    last_sequence bigint NOT NULL DEFAULT 0,
+    credit_limit decimal NOT NULL DEFAULT 0,
+    -- set through credit_limit_changed events, the balance can go down to -credit_limit
     created_at timestamp DEFAULT statement_timestamp(),
     updated_at timestamp DEFAULT statement_timestamp()
 );
@@ -27,11 +44,27 @@ CREATE TABLE wallet_events (
     amount decimal NOT NULL DEFAULT 0 CHECK (amount >= 0),
     event_type text NOT NULL,
     transfer_status text NOT NULL,
+    sequence bigint NOT NULL,
+    -- position of the event in the wallet stream, used for optimistic concurrency on appends
+    expires_at timestamp,
+    -- only set on pending debit/credit events, the transfer is reverted if it is still pending after it
+    fee decimal NOT NULL DEFAULT 0,
+    -- charged on credits, the linked <transfer id>:fee transfer moves it to the fee wallet
+    fx_rate decimal,
+    -- only set on the legs of an executed fx quote, the rate the amount was converted at
+    metadata jsonb NOT NULL DEFAULT '{}',
+    -- only set on debit/credit events, as given by the caller
     created_at timestamp NOT NULL DEFAULT statement_timestamp()
 );
 
 CREATE UNIQUE INDEX idx_wallet_events_wallet_id_transfer_id_event_type ON wallet_events (wallet_id, transfer_id, event_type);
 
+CREATE UNIQUE INDEX idx_wallet_events_wallet_id_sequence ON wallet_events (wallet_id, sequence);
+
+CREATE INDEX idx_wallet_events_wallet_id_id ON wallet_events (wallet_id, id);
+
+CREATE INDEX idx_wallet_events_wallet_id_created_at ON wallet_events (wallet_id, created_at);
+
 CREATE TABLE IF NOT EXISTS outbox_messages (
     id uuid NOT NULL PRIMARY KEY,
     payload jsonb NOT NULL,
@@ -42,4 +75,173 @@ CREATE TABLE IF NOT EXISTS outbox_messages (
     updated_at timestamp NOT NULL
 );
 
-CREATE INDEX IF NOT EXISTS idx_outbox_messages_status_publisher_type ON outbox_messages (status, publisher_type);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_status_publisher_type ON outbox_messages (status, publisher_type);
+
+CREATE TABLE internal_transfers (
+    transfer_id text NOT NULL,
+    reference_id text NOT NULL,
+    source_wallet_id uuid NOT NULL,
+    destination_wallet_id uuid NOT NULL,
+    amount decimal NOT NULL CHECK (amount >= 0),
+    created_at timestamp NOT NULL DEFAULT statement_timestamp(),
+    CONSTRAINT internal_transfers_transfer_id_pkey PRIMARY KEY (transfer_id)
+);
+
+CREATE TABLE wallet_snapshots (
+    wallet_id uuid NOT NULL,
+    last_event_id uuid NOT NULL,
+    last_sequence bigint NOT NULL,
+    balance decimal NOT NULL,
+    pending_debit decimal NOT NULL,
+    pending_credit decimal NOT NULL,
+    pending_transfers jsonb NOT NULL,
+    -- transfers that were still pending at last_sequence, keyed by transfer_id
+    credit_limit decimal NOT NULL DEFAULT 0,
+    created_at timestamp NOT NULL DEFAULT statement_timestamp(),
+    PRIMARY KEY (wallet_id, last_sequence)
+);
+
+CREATE TABLE wallet_transfers (
+    wallet_id uuid NOT NULL,
+    transfer_id text NOT NULL,
+    reference_id text NOT NULL,
+    amount decimal NOT NULL,
+    captured_amount decimal NOT NULL DEFAULT 0,
+    -- less than amount after a partial capture, 0 until the transfer is completed
+    direction text NOT NULL,
+    transfer_status text NOT NULL,
+    last_sequence bigint NOT NULL,
+    -- sequence of the last event that changed the transfer, guards against older events overriding a newer state
+    expires_at timestamp,
+    metadata jsonb NOT NULL DEFAULT '{}',
+    -- of the event that opened the transfer
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY (wallet_id, transfer_id)
+);
+
+CREATE INDEX idx_wallet_transfers_wallet_id_transfer_status ON wallet_transfers (wallet_id, transfer_status);
+
+CREATE INDEX idx_wallet_transfers_pending_expires_at ON wallet_transfers (expires_at) WHERE transfer_status = 'pending' AND expires_at IS NOT NULL;
+
+CREATE TABLE idempotency_keys (
+    key text NOT NULL,
+    fingerprint text NOT NULL,
+    -- hash of the method, path and body of the request the key was first used with
+    status_code integer NOT NULL DEFAULT 0,
+    -- 0 while the request is still in progress
+    response_body bytea,
+    lock_id text NOT NULL DEFAULT '',
+    -- random id of the request holding the key, changes when a stale lock is taken over
+    expires_at timestamp NOT NULL DEFAULT (timezone('utc', now()) + '1 day'::interval),
+    -- the key can be reused after it and the record is cleaned up by the worker
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY (key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+CREATE TABLE ledger_postings (
+    event_id uuid NOT NULL,
+    wallet_id uuid NOT NULL,
+    account text NOT NULL,
+    -- system:funding, system:fees, system:settlement or wallet:<wallet id>
+    currency text NOT NULL,
+    amount decimal NOT NULL,
+    -- the postings of an event sum up to 0
+    created_at timestamp NOT NULL,
+    PRIMARY KEY (event_id, account)
+);
+
+CREATE TABLE scheduled_transfers (
+    id uuid NOT NULL,
+    wallet_id uuid NOT NULL,
+    transfer_id text NOT NULL,
+    reference_id text NOT NULL,
+    direction text NOT NULL,
+    amount decimal NOT NULL,
+    currency text NOT NULL,
+    transfer_status text NOT NULL,
+    -- status the transfer is opened with once it's executed
+    expires_at timestamp,
+    execute_at timestamp NOT NULL,
+    status text NOT NULL,
+    -- scheduled, executed, failed or cancelled
+    event_id uuid,
+    -- the debit/credit event written on execution
+    failure_reason text NOT NULL DEFAULT '',
+    metadata jsonb NOT NULL DEFAULT '{}',
+    -- passed on to the transfer when it's executed
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX idx_scheduled_transfers_wallet_id_transfer_id ON scheduled_transfers (wallet_id, transfer_id);
+
+CREATE INDEX idx_scheduled_transfers_scheduled_execute_at ON scheduled_transfers (execute_at) WHERE status = 'scheduled';
+
+CREATE TABLE recurring_transfers (
+    id uuid NOT NULL,
+    wallet_id uuid NOT NULL,
+    reference_id text NOT NULL,
+    direction text NOT NULL,
+    amount decimal NOT NULL,
+    currency text NOT NULL,
+    rule text NOT NULL,
+    -- canonical RRULE, e.g. FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=1
+    start_at timestamp NOT NULL,
+    end_at timestamp,
+    occurrence integer NOT NULL,
+    next_run_at timestamp,
+    -- NULL once the recurrence is finished
+    status text NOT NULL,
+    -- active, paused or finished
+    last_transfer_id text NOT NULL DEFAULT '',
+    last_failure_reason text NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_recurring_transfers_wallet_id ON recurring_transfers (wallet_id);
+
+CREATE INDEX idx_recurring_transfers_active_next_run_at ON recurring_transfers (next_run_at) WHERE status = 'active';
+
+CREATE TABLE transfer_refunds (
+    refund_id text NOT NULL,
+    -- transfer id of the refund, <transfer id>:refund:<n>
+    wallet_id uuid NOT NULL,
+    transfer_id text NOT NULL,
+    -- the refunded transfer
+    reference_id text NOT NULL,
+    amount decimal NOT NULL CHECK (amount > 0),
+    event_id uuid NOT NULL,
+    -- the completed debit/credit event written for the refund
+    created_at timestamp NOT NULL DEFAULT statement_timestamp(),
+    PRIMARY KEY (wallet_id, refund_id)
+);
+
+CREATE INDEX idx_transfer_refunds_wallet_id_transfer_id ON transfer_refunds (wallet_id, transfer_id);
+
+CREATE TABLE fx_quotes (
+    id uuid NOT NULL,
+    source_wallet_id uuid NOT NULL,
+    target_wallet_id uuid NOT NULL,
+    source_currency text NOT NULL,
+    target_currency text NOT NULL,
+    source_amount decimal NOT NULL,
+    target_amount decimal NOT NULL,
+    -- source_amount * rate rounded to the target currency minor units
+    rate decimal NOT NULL CHECK (rate > 0),
+    status text NOT NULL,
+    -- open or executed
+    transfer_id text NOT NULL DEFAULT '',
+    -- transfer id shared by the credit and debit legs once executed
+    reference_id text NOT NULL DEFAULT '',
+    expires_at timestamp NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY (id)
+);